@@ -0,0 +1,245 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containers
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Binary format of the containers
+//
+//	version byte | count uvarint | item * count
+//
+// An item of a container with values is the encoded value, an item of a container with keys is the encoded key
+// followed by the encoded value. Every encoded key or value is prefixed by its length as uvarint.
+
+const binaryVersion = 1
+
+// Structure format of the containers
+//
+//	version byte | count uvarint | node *
+//
+// The layout of a node is up to the container, which records its shape in uvarints around its entries,
+// so that it can be restored node by node. Entries are encoded as the items of the binary format.
+
+const structureVersion = 2
+
+// EncodeValues returns the binary representation of the values, encoding every value with the codec.
+func EncodeValues[T any](codec Codec[T], values []T) ([]byte, error) {
+	data := appendUvarint([]byte{binaryVersion}, uint64(len(values)))
+	for _, value := range values {
+		encoded, err := codec.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+		data = appendBytes(data, encoded)
+	}
+	return data, nil
+}
+
+// DecodeValues parses the binary representation of values, decoding every value with the codec.
+// Returns an error wrapping ErrInvalidBinary if the data is not in the binary format.
+func DecodeValues[T any](codec Codec[T], data []byte) ([]T, error) {
+	count, data, err := readHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]T, 0, count)
+	for i := 0; i < count; i++ {
+		var encoded []byte
+		if encoded, data, err = readBytes(data); err != nil {
+			return nil, err
+		}
+		value, err := codec.Decode(encoded)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, readEnd(data)
+}
+
+// EncodeEntries returns the binary representation of the key-value pairs, keys[i] holding values[i].
+func EncodeEntries[K any, V any](keyCodec Codec[K], valueCodec Codec[V], keys []K, values []V) ([]byte, error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("containers: %d keys but %d values", len(keys), len(values))
+	}
+	data := appendUvarint([]byte{binaryVersion}, uint64(len(keys)))
+	for i, key := range keys {
+		encodedKey, err := keyCodec.Encode(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := valueCodec.Encode(values[i])
+		if err != nil {
+			return nil, err
+		}
+		data = appendBytes(appendBytes(data, encodedKey), encodedValue)
+	}
+	return data, nil
+}
+
+// DecodeEntries parses the binary representation of key-value pairs.
+// Returns an error wrapping ErrInvalidBinary if the data is not in the binary format.
+func DecodeEntries[K any, V any](keyCodec Codec[K], valueCodec Codec[V], data []byte) ([]K, []V, error) {
+	count, data, err := readHeader(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys, values := make([]K, 0, count), make([]V, 0, count)
+	for i := 0; i < count; i++ {
+		var encodedKey, encodedValue []byte
+		if encodedKey, data, err = readBytes(data); err != nil {
+			return nil, nil, err
+		}
+		if encodedValue, data, err = readBytes(data); err != nil {
+			return nil, nil, err
+		}
+		key, err := keyCodec.Decode(encodedKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := valueCodec.Decode(encodedValue)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys, values = append(keys, key), append(values, value)
+	}
+	return keys, values, readEnd(data)
+}
+
+// StructureEncoder appends the nodes of a container to its structure format.
+type StructureEncoder[K any, V any] struct {
+	data       []byte
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+}
+
+// NewStructureEncoder instantiates an encoder for a container of count entries,
+// encoding keys and values with the codecs of CodecFor.
+func NewStructureEncoder[K any, V any](count int) *StructureEncoder[K, V] {
+	return &StructureEncoder[K, V]{
+		data:       appendUvarint([]byte{structureVersion}, uint64(count)),
+		keyCodec:   CodecFor[K](),
+		valueCodec: CodecFor[V](),
+	}
+}
+
+// Uvarint appends a number describing the shape of a node.
+func (encoder *StructureEncoder[K, V]) Uvarint(n uint64) {
+	encoder.data = appendUvarint(encoder.data, n)
+}
+
+// Entry appends a key-value pair.
+func (encoder *StructureEncoder[K, V]) Entry(key K, value V) error {
+	encodedKey, err := encoder.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	encodedValue, err := encoder.valueCodec.Encode(value)
+	if err != nil {
+		return err
+	}
+	encoder.data = appendBytes(appendBytes(encoder.data, encodedKey), encodedValue)
+	return nil
+}
+
+// Bytes returns the structure format holding the nodes appended so far.
+func (encoder *StructureEncoder[K, V]) Bytes() []byte {
+	return encoder.data
+}
+
+// StructureDecoder reads the nodes of a container from its structure format in the order they were appended.
+// All methods return errors wrapping ErrInvalidBinary if the data is not in the structure format.
+type StructureDecoder[K any, V any] struct {
+	data       []byte
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+}
+
+// NewStructureDecoder instantiates a decoder for the data and returns it with the count of entries of the container.
+func NewStructureDecoder[K any, V any](data []byte) (*StructureDecoder[K, V], int, error) {
+	if len(data) == 0 || data[0] != structureVersion {
+		return nil, 0, fmt.Errorf("containers: unknown structure version: %w", ErrInvalidBinary)
+	}
+	count, data, err := readCount(data[1:])
+	if err != nil {
+		return nil, 0, err
+	}
+	return &StructureDecoder[K, V]{data: data, keyCodec: CodecFor[K](), valueCodec: CodecFor[V]()}, count, nil
+}
+
+// Uvarint reads a number describing the shape of a node.
+func (decoder *StructureDecoder[K, V]) Uvarint() (uint64, error) {
+	n, size := binary.Uvarint(decoder.data)
+	if size <= 0 {
+		return 0, fmt.Errorf("containers: truncated node: %w", ErrInvalidBinary)
+	}
+	decoder.data = decoder.data[size:]
+	return n, nil
+}
+
+// Entry reads a key-value pair.
+func (decoder *StructureDecoder[K, V]) Entry() (key K, value V, err error) {
+	var encodedKey, encodedValue []byte
+	if encodedKey, decoder.data, err = readBytes(decoder.data); err != nil {
+		return key, value, err
+	}
+	if encodedValue, decoder.data, err = readBytes(decoder.data); err != nil {
+		return key, value, err
+	}
+	if key, err = decoder.keyCodec.Decode(encodedKey); err != nil {
+		return key, value, err
+	}
+	value, err = decoder.valueCodec.Decode(encodedValue)
+	return key, value, err
+}
+
+// End returns an error if there is data left after the last node.
+func (decoder *StructureDecoder[K, V]) End() error {
+	return readEnd(decoder.data)
+}
+
+func appendUvarint(data []byte, n uint64) []byte {
+	var encoded [binary.MaxVarintLen64]byte
+	return append(data, encoded[:binary.PutUvarint(encoded[:], n)]...)
+}
+
+func appendBytes(data []byte, bytes []byte) []byte {
+	return append(appendUvarint(data, uint64(len(bytes))), bytes...)
+}
+
+func readHeader(data []byte) (int, []byte, error) {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return 0, nil, fmt.Errorf("containers: unknown version: %w", ErrInvalidBinary)
+	}
+	return readCount(data[1:])
+}
+
+func readCount(data []byte) (int, []byte, error) {
+	count, size := binary.Uvarint(data)
+	// every item takes at least one byte
+	if size <= 0 || count > uint64(len(data)) {
+		return 0, nil, fmt.Errorf("containers: invalid count: %w", ErrInvalidBinary)
+	}
+	return int(count), data[size:], nil
+}
+
+func readBytes(data []byte) ([]byte, []byte, error) {
+	length, size := binary.Uvarint(data)
+	if size <= 0 || length > uint64(len(data)-size) {
+		return nil, nil, fmt.Errorf("containers: truncated item: %w", ErrInvalidBinary)
+	}
+	data = data[size:]
+	return data[:length], data[length:], nil
+}
+
+func readEnd(data []byte) error {
+	if len(data) > 0 {
+		return fmt.Errorf("containers: %d trailing bytes: %w", len(data), ErrInvalidBinary)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containers_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+type celsius float32
+
+type name string
+
+type pair struct {
+	First  int
+	Second string
+}
+
+func roundTrip[T any](t *testing.T, values ...T) {
+	t.Helper()
+	data, err := containers.EncodeValues(containers.CodecFor[T](), values)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	decoded, err := containers.DecodeValues(containers.CodecFor[T](), data)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(decoded), fmt.Sprint(values); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestCodecFor(t *testing.T) {
+	roundTrip(t, true, false)
+	roundTrip(t, 0, 1, -1, math.MaxInt64, math.MinInt64)
+	roundTrip[int8](t, math.MaxInt8, math.MinInt8)
+	roundTrip[uint64](t, 0, math.MaxUint64)
+	roundTrip[uint8](t, 0, 255)
+	roundTrip(t, 1.5, math.Inf(-1), math.SmallestNonzeroFloat64)
+	roundTrip[celsius](t, -40, 36.6)
+	roundTrip(t, "", "a", "日本語")
+	roundTrip[name](t, "x", "y")
+	roundTrip(t, []byte{}, []byte{0, 1, 2})
+	roundTrip(t, time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC))
+	roundTrip(t, pair{1, "a"}, pair{2, "b"})
+	roundTrip(t, [2]int{1, 2})
+}
+
+func TestCodecForCompact(t *testing.T) {
+	data, err := containers.EncodeValues(containers.CodecFor[int](), []int{1, 2, 3})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	// version, count and three values of one byte prefixed by their lengths
+	if actualValue, expectedValue := len(data), 8; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestCodecForOverflow(t *testing.T) {
+	data, _ := containers.EncodeValues(containers.CodecFor[int](), []int{1000})
+	if _, err := containers.DecodeValues(containers.CodecFor[int8](), data); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestRegisterCodec(t *testing.T) {
+	containers.RegisterCodec[pair](containers.JSONCodec[pair]{})
+	data, err := containers.EncodeValues(containers.CodecFor[pair](), []pair{{1, "a"}})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data[3:]), `{"First":1,"Second":"a"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	roundTrip(t, pair{1, "a"})
+}
+
+func TestJSONAndGobCodec(t *testing.T) {
+	value := pair{1, "a"}
+
+	jsonCodec := containers.JSONCodec[pair]{}
+	data, _ := jsonCodec.Encode(value)
+	if actualValue, expectedValue := string(data), `{"First":1,"Second":"a"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, err := jsonCodec.Decode(data); actualValue != value || err != nil {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, err, value, nil)
+	}
+
+	gobCodec := containers.GobCodec[pair]{}
+	data, _ = gobCodec.Encode(value)
+	if actualValue, err := gobCodec.Decode(data); actualValue != value || err != nil {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, err, value, nil)
+	}
+	if _, err := gobCodec.Decode([]byte("garbage")); err == nil {
+		t.Errorf("Got %v expected error", err)
+	}
+}
+
+func TestEncodeEntries(t *testing.T) {
+	keys, values := []string{"a", "b"}, []int{1, 2}
+	data, err := containers.EncodeEntries(containers.CodecFor[string](), containers.CodecFor[int](), keys, values)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	decodedKeys, decodedValues, err := containers.DecodeEntries(containers.CodecFor[string](), containers.CodecFor[int](), data)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(decodedKeys, decodedValues), "[a b] [1 2]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, err := containers.EncodeEntries(containers.CodecFor[string](), containers.CodecFor[int](), keys, values[:1]); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	data, _ := containers.EncodeValues(containers.CodecFor[string](), []string{"a", "b"})
+	tests := [][]byte{
+		nil,
+		{},
+		{2, 0},                                // unknown version
+		{1},                                   // missing count
+		{1, 200},                              // count larger than the data
+		data[:len(data)-1],                    // truncated value
+		append(data[:len(data):len(data)], 0), // trailing byte
+	}
+	for _, test := range tests {
+		if _, err := containers.DecodeValues(containers.CodecFor[string](), test); !errors.Is(err, containers.ErrInvalidBinary) {
+			t.Errorf("Got %v expected %v for %v", err, containers.ErrInvalidBinary, test)
+		}
+	}
+	if _, _, err := containers.DecodeEntries(containers.CodecFor[string](), containers.CodecFor[string](), data); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestStructureEncoder(t *testing.T) {
+	encoder := containers.NewStructureEncoder[string, int](2)
+	encoder.Uvarint(300)
+	if err := encoder.Entry("a", 1); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	encoder.Uvarint(0)
+	if err := encoder.Entry("b", 2); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	data := encoder.Bytes()
+
+	decoder, count, err := containers.NewStructureDecoder[string, int](data)
+	if err != nil || count != 2 {
+		t.Fatalf("Got %v, %v expected %v", count, err, 2)
+	}
+	var parts []interface{}
+	for i := 0; i < count; i++ {
+		n, err := decoder.Uvarint()
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+		key, value, err := decoder.Entry()
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+		parts = append(parts, n, key, value)
+	}
+	if actualValue, expectedValue := fmt.Sprint(parts), "[300 a 1 0 b 2]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := decoder.End(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if _, err := decoder.Uvarint(); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	if _, _, err := decoder.Entry(); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+
+	// the binary format is not the structure format
+	binary, _ := containers.EncodeValues(containers.CodecFor[int](), []int{1})
+	if _, _, err := containers.NewStructureDecoder[string, int](binary); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containers
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// Codec converts keys or values of type T to bytes and back.
+// Decode must accept any output of Encode and must not retain data, which may be reused by the caller.
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+var _ Codec[interface{}] = JSONCodec[interface{}]{}
+var _ Codec[interface{}] = GobCodec[interface{}]{}
+
+// codecs holds the registered codecs by the reflect.Type of their values
+var codecs sync.Map
+
+// RegisterCodec makes the codec the one used by the binary serialization of all containers
+// for keys or values of type T, replacing any codec registered before.
+//
+// The registry is global and safe for concurrent use, but a codec takes effect for every container at once,
+// including containers being encoded or decoded concurrently, which may then use either codec.
+// Replacing a codec also makes data encoded by the previous one unreadable, so codecs are best registered
+// once, during initialization, before any data is encoded.
+func RegisterCodec[T any](codec Codec[T]) {
+	codecs.Store(reflect.TypeOf((*T)(nil)).Elem(), codec)
+}
+
+// CodecFor returns the codec registered for type T, or a default codec:
+// a codec for types implementing encoding.BinaryMarshaler and encoding.BinaryUnmarshaler (by pointer),
+// compact codecs for booleans, numbers, strings and byte slices, including types defined on them,
+// and GobCodec for all other types.
+func CodecFor[T any]() Codec[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if codec, ok := codecs.Load(typ); ok {
+		return codec.(Codec[T])
+	}
+	if typ.Implements(binaryMarshalerType) && reflect.PointerTo(typ).Implements(binaryUnmarshalerType) {
+		return binaryMarshalerCodec[T]{}
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return kindCodec[T]{kind: typ.Kind()}
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return kindCodec[T]{kind: reflect.Slice}
+		}
+	}
+	return GobCodec[T]{}
+}
+
+// JSONCodec encodes values with encoding/json.
+type JSONCodec[T any] struct{}
+
+// Encode returns the JSON representation of the value.
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode parses the JSON representation of a value.
+func (JSONCodec[T]) Decode(data []byte) (value T, err error) {
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+// GobCodec encodes values with encoding/gob.
+// Every value carries its own type information, so the codec is best suited for occasional values of complex types.
+type GobCodec[T any] struct{}
+
+// Encode returns the gob representation of the value.
+func (GobCodec[T]) Encode(value T) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(&value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decode parses the gob representation of a value.
+func (GobCodec[T]) Decode(data []byte) (value T, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
+
+var (
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// binaryMarshalerCodec encodes values with their own MarshalBinary and UnmarshalBinary methods.
+type binaryMarshalerCodec[T any] struct{}
+
+func (binaryMarshalerCodec[T]) Encode(value T) ([]byte, error) {
+	return any(value).(encoding.BinaryMarshaler).MarshalBinary()
+}
+
+func (binaryMarshalerCodec[T]) Decode(data []byte) (value T, err error) {
+	err = any(&value).(encoding.BinaryUnmarshaler).UnmarshalBinary(append([]byte(nil), data...))
+	return value, err
+}
+
+// kindCodec encodes values by their kind: booleans as a byte, integers as varints, floats by their IEEE 754 bits,
+// strings and byte slices as their bytes.
+type kindCodec[T any] struct {
+	kind reflect.Kind
+}
+
+func (codec kindCodec[T]) Encode(value T) ([]byte, error) {
+	// fast paths for the most common types, which skip reflection
+	switch v := any(value).(type) {
+	case int:
+		data := make([]byte, binary.MaxVarintLen64)
+		return data[:binary.PutVarint(data, int64(v))], nil
+	case string:
+		return []byte(v), nil
+	}
+	v := reflect.ValueOf(&value).Elem()
+	switch codec.kind {
+	case reflect.Bool:
+		if v.Bool() {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		data := make([]byte, binary.MaxVarintLen64)
+		return data[:binary.PutVarint(data, v.Int())], nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		data := make([]byte, binary.MaxVarintLen64)
+		return data[:binary.PutUvarint(data, v.Uint())], nil
+	case reflect.Float32:
+		data := make([]byte, 4)
+		binary.BigEndian.PutUint32(data, math.Float32bits(float32(v.Float())))
+		return data, nil
+	case reflect.Float64:
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, math.Float64bits(v.Float()))
+		return data, nil
+	case reflect.String:
+		return []byte(v.String()), nil
+	default:
+		return append([]byte(nil), v.Bytes()...), nil
+	}
+}
+
+func (codec kindCodec[T]) Decode(data []byte) (value T, err error) {
+	switch v := any(&value).(type) {
+	case *int:
+		n, size := binary.Varint(data)
+		if size != len(data) || size == 0 || n != int64(int(n)) {
+			return value, errors.New("containers: invalid int")
+		}
+		*v = int(n)
+		return value, nil
+	case *string:
+		*v = string(data)
+		return value, nil
+	}
+	v := reflect.ValueOf(&value).Elem()
+	switch codec.kind {
+	case reflect.Bool:
+		if len(data) != 1 {
+			return value, fmt.Errorf("containers: boolean codec expects 1 byte, got %d", len(data))
+		}
+		v.SetBool(data[0] != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, size := binary.Varint(data)
+		if size != len(data) || size == 0 || v.OverflowInt(n) {
+			return value, fmt.Errorf("containers: invalid %v", v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, size := binary.Uvarint(data)
+		if size != len(data) || size == 0 || v.OverflowUint(n) {
+			return value, fmt.Errorf("containers: invalid %v", v.Type())
+		}
+		v.SetUint(n)
+	case reflect.Float32:
+		if len(data) != 4 {
+			return value, fmt.Errorf("containers: float32 codec expects 4 bytes, got %d", len(data))
+		}
+		v.SetFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(data))))
+	case reflect.Float64:
+		if len(data) != 8 {
+			return value, fmt.Errorf("containers: float64 codec expects 8 bytes, got %d", len(data))
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data)))
+	case reflect.String:
+		v.SetString(string(data))
+	default:
+		v.SetBytes(append([]byte(nil), data...))
+	}
+	return value, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package containertest provides conformance test suites for implementations of the container interfaces.
+//
+// Each suite takes a factory of empty containers holding int values, or int keys mapped to string values,
+// and runs the checks of the interface as subtests, so that a custom container can certify the contract
+// of the interface it implements from a test of its own package:
+//
+//	func TestListConformance(t *testing.T) {
+//		containertest.TestList(t, func() lists.List[int] { return mylist.New[int]() })
+//	}
+//
+// The Fuzz functions are fuzz targets of the same kind, comparing the container to a model, a slice or a built-in map,
+// after every operation of a random sequence decoded from the fuzz input.
+package containertest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// size is the number of elements the suites put into a container.
+const size = 100
+
+// permutation returns the n integers from 0 to n-1 in a scrambled order.
+func permutation(n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = (i * 37) % n
+	}
+	return values
+}
+
+// ascending returns the n integers from 0 to n-1 in ascending order.
+func ascending(n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i
+	}
+	return values
+}
+
+// label returns the value a map suite puts for the key.
+func label(key int) string {
+	return strconv.Itoa(key)
+}
+
+// sortedStrings returns the formatted values in ascending order, to compare containers regardless of their order.
+func sortedStrings[V any](values []V) []string {
+	strings := make([]string, len(values))
+	for i, value := range values {
+		strings[i] = fmt.Sprint(value)
+	}
+	sort.Strings(strings)
+	return strings
+}
+
+// keys returns the formatted keys of the container if it has a Keys method returning a slice.
+func keys(container interface{}) ([]string, bool) {
+	method := reflect.ValueOf(container).MethodByName("Keys")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 || method.Type().Out(0).Kind() != reflect.Slice {
+		return nil, false
+	}
+	result := method.Call(nil)[0]
+	strings := make([]string, result.Len())
+	for i := range strings {
+		strings[i] = fmt.Sprint(result.Index(i).Interface())
+	}
+	return strings, true
+}
@@ -0,0 +1,595 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containertest
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/lists"
+	"github.com/monitor1379/yagods/maps"
+	"github.com/monitor1379/yagods/sets"
+	"github.com/monitor1379/yagods/stacks"
+)
+
+// Order is the order in which a set or a map holds its elements, which the fuzz targets check Values and Keys against.
+type Order int
+
+const (
+	// Unordered containers hold their elements in any order, such as hashset.Set.
+	Unordered Order = iota
+	// InsertionOrder containers hold their elements in the order they were first added, such as linkedhashset.Set.
+	InsertionOrder
+	// SortedOrder containers hold their elements in ascending order, such as treeset.Set.
+	SortedOrder
+)
+
+// Heap is a container of ints whose Pop returns the smallest value, such as binaryheap.Heap[int] with an int comparator.
+type Heap interface {
+	Push(values ...int)
+	Pop() (value int, ok bool)
+	Peek() (value int, ok bool)
+	containers.Container[int]
+}
+
+// maxOperations bounds the number of operations run for one input, keeping every input fast.
+const maxOperations = 256
+
+// operation is an operation of a fuzz target, decoded from three bytes of the input: its code and two arguments.
+type operation struct {
+	code, x, y byte
+}
+
+// decode splits the input of a fuzz target into operations, ignoring trailing bytes.
+func decode(data []byte) []operation {
+	var operations []operation
+	for i := 0; i+2 < len(data) && len(operations) < maxOperations; i += 3 {
+		operations = append(operations, operation{data[i], data[i+1], data[i+2]})
+	}
+	return operations
+}
+
+// small returns the argument as a value from 0 to 15, so that random operations often meet the same values.
+func small(b byte) int {
+	return int(b % 16)
+}
+
+// position returns the argument as an index from -2 to 21, so that random operations often go out of bounds.
+func position(b byte) int {
+	return int(b%24) - 2
+}
+
+// validator is implemented by containers checking their own invariants, such as the balanced trees.
+type validator interface {
+	Validate() error
+}
+
+// checkContainer compares the container to the expected values, in the same order if ordered is true,
+// and validates the container if it checks its invariants. The step names the last operation in messages.
+func checkContainer[V any](t *testing.T, step string, container containers.Container[V], expected []V, ordered bool) {
+	t.Helper()
+	if actualValue, expectedValue := container.Size(), len(expected); actualValue != expectedValue {
+		t.Fatalf("%s: Got size %v expected %v", step, actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := container.Empty(), len(expected) == 0; actualValue != expectedValue {
+		t.Fatalf("%s: Got empty %v expected %v", step, actualValue, expectedValue)
+	}
+	actualValues, actualInterfaceValues, expectedValues := fmt.Sprint(sortedStrings(container.Values())), fmt.Sprint(sortedStrings(container.InterfaceValues())), fmt.Sprint(sortedStrings(expected))
+	if ordered {
+		actualValues, actualInterfaceValues, expectedValues = fmt.Sprint(container.Values()), fmt.Sprint(container.InterfaceValues()), fmt.Sprint(expected)
+	}
+	if actualValues != expectedValues {
+		t.Fatalf("%s: Got %v expected %v", step, actualValues, expectedValues)
+	}
+	if actualInterfaceValues != expectedValues {
+		t.Fatalf("%s: Got %v expected %v", step, actualInterfaceValues, expectedValues)
+	}
+	if validator, ok := container.(validator); ok {
+		if err := validator.Validate(); err != nil {
+			t.Fatalf("%s: Got error %v", step, err)
+		}
+	}
+}
+
+// checkEachWithIndex checks that Each of the container, if it has one, visits the values in the order of Values.
+func checkEachWithIndex(t *testing.T, container containers.Container[int]) {
+	t.Helper()
+	enumerable, ok := container.(interface {
+		Each(func(index int, value int))
+	})
+	if !ok {
+		return
+	}
+	var values []int
+	enumerable.Each(func(index int, value int) {
+		if index != len(values) {
+			t.Fatalf("Each: Got index %v expected %v", index, len(values))
+		}
+		values = append(values, value)
+	})
+	if actualValue, expectedValue := fmt.Sprint(values), fmt.Sprint(container.Values()); actualValue != expectedValue {
+		t.Fatalf("Each: Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+// FuzzList checks the lists of the factory against a slice, running random sequences of the functions of lists.List
+// and comparing the results and values of the list after each one, together with IndexOf and IndexFunc if the lists have them.
+// Each and the round trips of TestSerialization must keep the order of the list. The lists of the factory must be empty.
+func FuzzList(f *testing.F, factory func() lists.List[int]) {
+	f.Add([]byte{0, 1, 0, 1, 2, 3, 2, 3, 4, 4, 2, 0, 8, 2, 3})
+	f.Add([]byte{1, 5, 6, 3, 24, 7, 5, 4, 9, 6, 2, 4, 9, 0, 0, 10, 0, 0, 0, 3, 3})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		list := factory()
+		var model []int
+		for i, op := range decode(data) {
+			var step string
+			switch op.code % 12 {
+			case 0:
+				step = fmt.Sprintf("Add(%d)", small(op.x))
+				list.Add(small(op.x))
+				model = append(model, small(op.x))
+			case 1:
+				step = fmt.Sprintf("Add(%d, %d)", small(op.x), small(op.y))
+				list.Add(small(op.x), small(op.y))
+				model = append(model, small(op.x), small(op.y))
+			case 2:
+				step = fmt.Sprintf("Insert(%d, %d)", position(op.x), small(op.y))
+				list.Insert(position(op.x), small(op.y))
+				model = insert(model, position(op.x), small(op.y))
+			case 3:
+				step = fmt.Sprintf("Insert(%d, %d, %d)", position(op.x), small(op.y), small(op.x))
+				list.Insert(position(op.x), small(op.y), small(op.x))
+				model = insert(model, position(op.x), small(op.y), small(op.x))
+			case 4:
+				step = fmt.Sprintf("Remove(%d)", position(op.x))
+				list.Remove(position(op.x))
+				if index := position(op.x); index >= 0 && index < len(model) {
+					model = append(model[:index], model[index+1:]...)
+				}
+			case 5:
+				step = fmt.Sprintf("Set(%d, %d)", position(op.x), small(op.y))
+				list.Set(position(op.x), small(op.y))
+				if index := position(op.x); index >= 0 && index < len(model) {
+					model[index] = small(op.y)
+				} else if index == len(model) {
+					model = append(model, small(op.y))
+				}
+			case 6:
+				step = fmt.Sprintf("Swap(%d, %d)", position(op.x), position(op.y))
+				list.Swap(position(op.x), position(op.y))
+				if i, j := position(op.x), position(op.y); i >= 0 && i < len(model) && j >= 0 && j < len(model) {
+					model[i], model[j] = model[j], model[i]
+				}
+			case 7:
+				step = fmt.Sprintf("Get(%d)", position(op.x))
+				value, ok := list.Get(position(op.x))
+				expectedValue, expectedOk := 0, false
+				if index := position(op.x); index >= 0 && index < len(model) {
+					expectedValue, expectedOk = model[index], true
+				}
+				if ok != expectedOk || ok && value != expectedValue {
+					t.Fatalf("operation %d %s: Got %v %v expected %v %v", i, step, value, ok, expectedValue, expectedOk)
+				}
+			case 8:
+				step = fmt.Sprintf("Contains(%d, %d)", small(op.x), small(op.y))
+				if actualValue, expectedValue := list.Contains(small(op.x), small(op.y)), indexOf(model, small(op.x)) >= 0 && indexOf(model, small(op.y)) >= 0; actualValue != expectedValue {
+					t.Fatalf("operation %d %s: Got %v expected %v", i, step, actualValue, expectedValue)
+				}
+				if indexer, ok := list.(interface{ IndexOf(int) int }); ok {
+					if actualValue, expectedValue := indexer.IndexOf(small(op.x)), indexOf(model, small(op.x)); actualValue != expectedValue {
+						t.Fatalf("operation %d IndexOf(%d): Got %v expected %v", i, small(op.x), actualValue, expectedValue)
+					}
+				}
+				if finder, ok := list.(interface{ IndexFunc(func(int) bool) int }); ok {
+					greater := func(value int) bool { return value > small(op.y) }
+					expectedValue := -1
+					for index, value := range model {
+						if greater(value) {
+							expectedValue = index
+							break
+						}
+					}
+					if actualValue := finder.IndexFunc(greater); actualValue != expectedValue {
+						t.Fatalf("operation %d IndexFunc(> %d): Got %v expected %v", i, small(op.y), actualValue, expectedValue)
+					}
+				}
+			case 9:
+				step = "Sort"
+				list.Sort(func(a, b int) int { return a - b })
+				sort.Ints(model)
+			case 10:
+				step = "Clear"
+				list.Clear()
+				model = nil
+			case 11:
+				step = fmt.Sprintf("Insert(%d)", position(op.x))
+				list.Insert(position(op.x))
+			}
+			checkContainer[int](t, fmt.Sprintf("operation %d %s", i, step), list, model, true)
+		}
+		checkEachWithIndex(t, list)
+		roundTrips(t, list, factory, func(t *testing.T, restored lists.List[int]) {
+			checkContainer[int](t, "round trip", restored, model, true)
+		})
+	})
+}
+
+// checkTop compares the result of a Pop or Peek at the operation to the last of the model, if any.
+func checkTop(t *testing.T, operation int, step string, value int, ok bool, model []int) {
+	t.Helper()
+	expectedValue, expectedOk := 0, len(model) > 0
+	if expectedOk {
+		expectedValue = model[len(model)-1]
+	}
+	if ok != expectedOk || ok && value != expectedValue {
+		t.Fatalf("operation %d %s: Got %v %v expected %v %v", operation, step, value, ok, expectedValue, expectedOk)
+	}
+}
+
+// insert returns the values with the inserted values at the index as by lists.List, appended if the index is the size.
+func insert(values []int, index int, inserted ...int) []int {
+	if index < 0 || index > len(values) {
+		return values
+	}
+	return append(values[:index], append(inserted, values[index:]...)...)
+}
+
+// indexOf returns the index of the first occurrence of the value in the values, or -1 if it does not occur.
+func indexOf(values []int, value int) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// FuzzStack checks the stacks of the factory against a slice, running random sequences of the functions of stacks.Stack
+// and comparing the results and values of the stack after each one. Values, Each and the round trips of
+// TestSerialization must hold the values from the top of the stack down. The stacks of the factory must be empty.
+func FuzzStack(f *testing.F, factory func() stacks.Stack[int]) {
+	f.Add([]byte{0, 1, 0, 0, 2, 0, 3, 0, 0, 2, 0, 0, 1, 5, 0, 2, 0, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		stack := factory()
+		var model []int // bottom first
+		top := func() []int {
+			values := make([]int, len(model))
+			for i, value := range model {
+				values[len(model)-1-i] = value
+			}
+			return values
+		}
+		for i, op := range decode(data) {
+			var step string
+			switch op.code % 5 {
+			case 0, 1:
+				step = fmt.Sprintf("Push(%d)", small(op.x))
+				stack.Push(small(op.x))
+				model = append(model, small(op.x))
+			case 2:
+				step = "Pop"
+				value, ok := stack.Pop()
+				checkTop(t, i, step, value, ok, model)
+				if len(model) > 0 {
+					model = model[:len(model)-1]
+				}
+			case 3:
+				step = "Peek"
+				value, ok := stack.Peek()
+				checkTop(t, i, step, value, ok, model)
+			case 4:
+				step = "Clear"
+				stack.Clear()
+				model = nil
+			}
+			checkContainer[int](t, fmt.Sprintf("operation %d %s", i, step), stack, top(), true)
+		}
+		checkEachWithIndex(t, stack)
+		roundTrips(t, stack, factory, func(t *testing.T, restored stacks.Stack[int]) {
+			checkContainer[int](t, "round trip", restored, top(), true)
+		})
+	})
+}
+
+// FuzzSet checks the sets of the factory against a slice, running random sequences of the functions of sets.Set
+// and comparing the results and values of the set after each one. Values, Each and the round trips of
+// TestSerialization must hold the elements in the order of the set. The sets of the factory must be empty.
+func FuzzSet(f *testing.F, factory func() sets.Set[int], order Order) {
+	f.Add([]byte{0, 3, 0, 0, 1, 0, 2, 3, 5, 3, 1, 0, 5, 3, 5, 0, 3, 0, 6, 0, 0, 0, 7, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		set := factory()
+		var model []int // in insertion order
+		add := func(values ...int) {
+			for _, value := range values {
+				if indexOf(model, value) < 0 {
+					model = append(model, value)
+				}
+			}
+		}
+		remove := func(values ...int) {
+			for _, value := range values {
+				if index := indexOf(model, value); index >= 0 {
+					model = append(model[:index], model[index+1:]...)
+				}
+			}
+		}
+		ordered := func() []int {
+			if order != SortedOrder {
+				return model
+			}
+			values := append([]int(nil), model...)
+			sort.Ints(values)
+			return values
+		}
+		for i, op := range decode(data) {
+			var step string
+			switch op.code % 7 {
+			case 0, 1:
+				step = fmt.Sprintf("Add(%d)", small(op.x))
+				set.Add(small(op.x))
+				add(small(op.x))
+			case 2:
+				step = fmt.Sprintf("Add(%d, %d)", small(op.x), small(op.y))
+				set.Add(small(op.x), small(op.y))
+				add(small(op.x), small(op.y))
+			case 3:
+				step = fmt.Sprintf("Remove(%d)", small(op.x))
+				set.Remove(small(op.x))
+				remove(small(op.x))
+			case 4:
+				step = fmt.Sprintf("Remove(%d, %d)", small(op.x), small(op.y))
+				set.Remove(small(op.x), small(op.y))
+				remove(small(op.x), small(op.y))
+			case 5:
+				step = fmt.Sprintf("Contains(%d, %d)", small(op.x), small(op.y))
+				if actualValue, expectedValue := set.Contains(small(op.x), small(op.y)), indexOf(model, small(op.x)) >= 0 && indexOf(model, small(op.y)) >= 0; actualValue != expectedValue {
+					t.Fatalf("operation %d %s: Got %v expected %v", i, step, actualValue, expectedValue)
+				}
+			case 6:
+				step = "Clear"
+				set.Clear()
+				model = nil
+			}
+			checkContainer[int](t, fmt.Sprintf("operation %d %s", i, step), set, ordered(), order != Unordered)
+		}
+		checkEachWithIndex(t, set)
+		roundTrips(t, set, factory, func(t *testing.T, restored sets.Set[int]) {
+			checkContainer[int](t, "round trip", restored, ordered(), order != Unordered)
+		})
+	})
+}
+
+// FuzzMap checks the maps of the factory against a built-in map, running random sequences of the functions of maps.Map
+// and comparing the results, keys and values of the map after each one. Keys, Values, Each and the round trips of
+// TestSerialization must hold the entries in the order of the map. Sorted maps with the Min, Max, Floor or Ceiling
+// functions of treemap.Map are checked for them as well. A maps.BidiMap must keep its values unique,
+// a put replacing the entry holding the value, and its values may be ordered by themselves.
+// The maps of the factory must be empty.
+func FuzzMap(f *testing.F, factory func() maps.Map[int, string], order Order) {
+	f.Add([]byte{0, 3, 1, 0, 1, 1, 1, 2, 3, 2, 1, 0, 3, 1, 0, 0, 5, 1, 5, 4, 0, 6, 2, 0, 4, 0, 0, 0, 3, 3})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := factory()
+		bidi, isBidi := m.(maps.BidiMap[int, string])
+		model := make(map[int]string)
+		var keys []int // in insertion order
+		remove := func(key int) {
+			if _, found := model[key]; found {
+				delete(model, key)
+				keys = append(keys[:indexOf(keys, key)], keys[indexOf(keys, key)+1:]...)
+			}
+		}
+		ordered := func() []int {
+			if order != SortedOrder {
+				return keys
+			}
+			sorted := append([]int(nil), keys...)
+			sort.Ints(sorted)
+			return sorted
+		}
+		for i, op := range decode(data) {
+			var step string
+			switch op.code % 7 {
+			case 0, 1:
+				key, value := small(op.x), label(small(op.y))
+				step = fmt.Sprintf("Put(%d, %q)", key, value)
+				m.Put(key, value)
+				if isBidi {
+					for k, v := range model {
+						if v == value && k != key {
+							remove(k)
+						}
+					}
+				}
+				if _, found := model[key]; !found {
+					keys = append(keys, key)
+				}
+				model[key] = value
+			case 2:
+				step = fmt.Sprintf("Remove(%d)", small(op.x))
+				m.Remove(small(op.x))
+				remove(small(op.x))
+			case 3:
+				step = fmt.Sprintf("Get(%d)", small(op.x))
+				value, found := m.Get(small(op.x))
+				expectedValue, expectedFound := model[small(op.x)]
+				if found != expectedFound || value != expectedValue {
+					t.Fatalf("operation %d %s: Got %q %v expected %q %v", i, step, value, found, expectedValue, expectedFound)
+				}
+			case 4:
+				step = "Clear"
+				m.Clear()
+				model = make(map[int]string)
+				keys = nil
+			case 5:
+				step = fmt.Sprintf("Floor(%d) and Ceiling(%d)", small(op.x), small(op.x))
+				if order == SortedOrder {
+					checkFloorCeiling(t, fmt.Sprintf("operation %d", i), m, ordered(), model, small(op.x))
+				}
+			case 6:
+				step = fmt.Sprintf("GetKey(%q)", label(small(op.y)))
+				if isBidi {
+					key, found := bidi.GetKey(label(small(op.y)))
+					expectedKey, expectedFound := 0, false
+					for k, v := range model {
+						if v == label(small(op.y)) {
+							expectedKey, expectedFound = k, true
+						}
+					}
+					if found != expectedFound || key != expectedKey {
+						t.Fatalf("operation %d %s: Got %v %v expected %v %v", i, step, key, found, expectedKey, expectedFound)
+					}
+				}
+			}
+			checkMapModel(t, fmt.Sprintf("operation %d %s", i, step), m, ordered(), model, order != Unordered, order != Unordered && !isBidi)
+			if order == SortedOrder {
+				checkMinMax(t, fmt.Sprintf("operation %d %s", i, step), m, ordered(), model)
+			}
+		}
+		if enumerable, ok := m.(interface {
+			Each(func(key int, value string))
+		}); ok {
+			var keys []int
+			enumerable.Each(func(key int, value string) {
+				if value != model[key] {
+					t.Fatalf("Each: Got %q expected %q", value, model[key])
+				}
+				keys = append(keys, key)
+			})
+			if actualValue, expectedValue := fmt.Sprint(keys), fmt.Sprint(m.Keys()); actualValue != expectedValue {
+				t.Fatalf("Each: Got %v expected %v", actualValue, expectedValue)
+			}
+		}
+		roundTrips(t, m, factory, func(t *testing.T, restored maps.Map[int, string]) {
+			checkMapModel(t, "round trip", restored, ordered(), model, order != Unordered, order != Unordered && !isBidi)
+		})
+	})
+}
+
+// checkMapModel compares the map to the model, its keys in the order of the keys if orderedKeys is true
+// and its values in the order of the keys if orderedValues is true.
+func checkMapModel(t *testing.T, step string, m maps.Map[int, string], keys []int, model map[int]string, orderedKeys, orderedValues bool) {
+	t.Helper()
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = model[key]
+	}
+	checkContainer[string](t, step, m, values, orderedValues)
+	actualKeys, expectedKeys := fmt.Sprint(sortedStrings(m.Keys())), fmt.Sprint(sortedStrings(keys))
+	if orderedKeys {
+		actualKeys, expectedKeys = fmt.Sprint(m.Keys()), fmt.Sprint(keys)
+	}
+	if actualKeys != expectedKeys {
+		t.Fatalf("%s: Got %v expected %v", step, actualKeys, expectedKeys)
+	}
+	for _, key := range keys {
+		if value, found := m.Get(key); !found || value != model[key] {
+			t.Fatalf("%s: Got %q %v expected %q %v", step, value, found, model[key], true)
+		}
+	}
+}
+
+// checkMinMax compares Min and Max of the map, if it has them, to the first and last of the sorted keys.
+func checkMinMax(t *testing.T, step string, m maps.Map[int, string], keys []int, model map[int]string) {
+	t.Helper()
+	minMax, ok := m.(interface {
+		Min() (int, string)
+		Max() (int, string)
+	})
+	if !ok || len(keys) == 0 {
+		return
+	}
+	if key, value := minMax.Min(); key != keys[0] || value != model[keys[0]] {
+		t.Fatalf("%s: Got Min %v %q expected %v %q", step, key, value, keys[0], model[keys[0]])
+	}
+	if key, value := minMax.Max(); key != keys[len(keys)-1] || value != model[keys[len(keys)-1]] {
+		t.Fatalf("%s: Got Max %v %q expected %v %q", step, key, value, keys[len(keys)-1], model[keys[len(keys)-1]])
+	}
+}
+
+// checkFloorCeiling compares Floor and Ceiling of the map for the key, if it has them, to a scan of the sorted keys.
+func checkFloorCeiling(t *testing.T, step string, m maps.Map[int, string], keys []int, model map[int]string, key int) {
+	t.Helper()
+	floorCeiling, ok := m.(interface {
+		Floor(int) (int, string, bool)
+		Ceiling(int) (int, string, bool)
+	})
+	if !ok {
+		return
+	}
+	expectedFloor, expectedCeiling, floorFound, ceilingFound := 0, 0, false, false
+	for _, k := range keys {
+		if k <= key {
+			expectedFloor, floorFound = k, true
+		}
+		if k >= key && !ceilingFound {
+			expectedCeiling, ceilingFound = k, true
+		}
+	}
+	if actualKey, value, found := floorCeiling.Floor(key); found != floorFound || found && (actualKey != expectedFloor || value != model[expectedFloor]) {
+		t.Fatalf("%s Floor(%d): Got %v %q %v expected %v %q %v", step, key, actualKey, value, found, expectedFloor, model[expectedFloor], floorFound)
+	}
+	if actualKey, value, found := floorCeiling.Ceiling(key); found != ceilingFound || found && (actualKey != expectedCeiling || value != model[expectedCeiling]) {
+		t.Fatalf("%s Ceiling(%d): Got %v %q %v expected %v %q %v", step, key, actualKey, value, found, expectedCeiling, model[expectedCeiling], ceilingFound)
+	}
+}
+
+// FuzzHeap checks the heaps of the factory against a slice, running random sequences of Push, Pop, Peek and Clear
+// and comparing the results and values of the heap after each one, regardless of their order.
+// The round trips of TestSerialization must keep the order of the values, and popping every value
+// at the end must return them in ascending order. The heaps of the factory must be empty.
+func FuzzHeap(f *testing.F, factory func() Heap) {
+	f.Add([]byte{0, 5, 0, 0, 3, 0, 2, 7, 1, 3, 0, 0, 4, 0, 0, 0, 3, 0, 5, 0, 0, 1, 9, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		heap := factory()
+		var model []int // in descending order, the smallest value last
+		push := func(values ...int) {
+			model = append(model, values...)
+			sort.Sort(sort.Reverse(sort.IntSlice(model)))
+		}
+		for i, op := range decode(data) {
+			var step string
+			switch op.code % 6 {
+			case 0, 1:
+				step = fmt.Sprintf("Push(%d)", small(op.x))
+				heap.Push(small(op.x))
+				push(small(op.x))
+			case 2:
+				step = fmt.Sprintf("Push(%d, %d)", small(op.x), small(op.y))
+				heap.Push(small(op.x), small(op.y))
+				push(small(op.x), small(op.y))
+			case 3:
+				step = "Pop"
+				value, ok := heap.Pop()
+				checkTop(t, i, step, value, ok, model)
+				if len(model) > 0 {
+					model = model[:len(model)-1]
+				}
+			case 4:
+				step = "Peek"
+				value, ok := heap.Peek()
+				checkTop(t, i, step, value, ok, model)
+			case 5:
+				step = "Clear"
+				heap.Clear()
+				model = nil
+			}
+			checkContainer[int](t, fmt.Sprintf("operation %d %s", i, step), heap, model, false)
+		}
+		checkEachWithIndex(t, heap)
+		values := heap.Values()
+		roundTrips(t, heap, factory, func(t *testing.T, restored Heap) {
+			checkContainer[int](t, "round trip", restored, values, true)
+		})
+		var popped []int
+		for value, ok := heap.Pop(); ok; value, ok = heap.Pop() {
+			popped = append([]int{value}, popped...)
+		}
+		if actualValue, expectedValue := fmt.Sprint(popped), fmt.Sprint(model); actualValue != expectedValue {
+			t.Fatalf("Pop: Got %v expected %v", actualValue, expectedValue)
+		}
+	})
+}
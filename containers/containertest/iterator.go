@@ -0,0 +1,269 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containertest
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// TestIteratorWithIndex checks the iterators of the factory, each one over a new container holding the values.
+// An iteration visits every value once with the indexes 0, 1, 2 and so on, and can be repeated after Begin or First.
+// If the iterators are containers.FailFastIterator, removing elements while iterating must not skip any element.
+func TestIteratorWithIndex(t *testing.T, factory func(values []int) containers.IteratorWithIndex[int]) {
+	t.Helper()
+	testIterator(t, func(values []int) cursor {
+		return indexCursor(factory(values), nil)
+	}, checkIndexes)
+}
+
+// TestReverseIteratorWithIndex checks the iterators of the factory as in TestIteratorWithIndex and iterating backwards:
+// after End or Last, Prev visits the values in the reverse order of Next.
+func TestReverseIteratorWithIndex(t *testing.T, factory func(values []int) containers.ReverseIteratorWithIndex[int]) {
+	t.Helper()
+	testIterator(t, func(values []int) cursor {
+		iterator := factory(values)
+		return indexCursor(iterator, iterator)
+	}, checkIndexes)
+}
+
+// TestIteratorWithKey checks the iterators of the factory, each one over a new container holding the keys,
+// every key mapped to its decimal representation as by strconv.Itoa.
+// An iteration visits every key once with its value, and can be repeated after Begin or First.
+// If the iterators are containers.FailFastIterator, removing elements while iterating must not skip any element.
+func TestIteratorWithKey(t *testing.T, factory func(keys []int) containers.IteratorWithKey[int, string]) {
+	t.Helper()
+	testIterator(t, func(keys []int) cursor {
+		return keyCursor(factory(keys), nil)
+	}, checkKeys)
+}
+
+// TestReverseIteratorWithKey checks the iterators of the factory as in TestIteratorWithKey and iterating backwards:
+// after End or Last, Prev visits the keys in the reverse order of Next.
+func TestReverseIteratorWithKey(t *testing.T, factory func(keys []int) containers.ReverseIteratorWithKey[int, string]) {
+	t.Helper()
+	testIterator(t, func(keys []int) cursor {
+		iterator := factory(keys)
+		return keyCursor(iterator, iterator)
+	}, checkKeys)
+}
+
+// element is the position of an iterator, an index and a value or a key and a value.
+type element struct {
+	position int
+	value    string
+}
+
+// cursor abstracts the kinds of iterators away for the checks shared by all of them.
+type cursor struct {
+	iterator interface {
+		Next() bool
+		Begin()
+		First() bool
+	}
+	element func() element
+	reverse interface {
+		Prev() bool
+		End()
+		Last() bool
+	} // nil if the iterator only goes forward
+	failFast containers.FailFastIterator // nil if the iterator is not fail-fast
+}
+
+func indexCursor(it containers.IteratorWithIndex[int], reverse containers.ReverseIteratorWithIndex[int]) cursor {
+	c := cursor{
+		iterator: it,
+		element:  func() element { return element{it.Index(), strconv.Itoa(it.Value())} },
+	}
+	if reverse != nil {
+		c.reverse = reverse
+	}
+	c.failFast, _ = it.(containers.FailFastIterator)
+	return c
+}
+
+func keyCursor(it containers.IteratorWithKey[int, string], reverse containers.ReverseIteratorWithKey[int, string]) cursor {
+	c := cursor{
+		iterator: it,
+		element:  func() element { return element{it.Key(), it.Value()} },
+	}
+	if reverse != nil {
+		c.reverse = reverse
+	}
+	c.failFast, _ = it.(containers.FailFastIterator)
+	return c
+}
+
+// checkIndexes checks that the elements of a forward iteration have the indexes 0, 1, 2 and so on
+// and hold the values regardless of their order.
+func checkIndexes(t *testing.T, elements []element, values []int) {
+	t.Helper()
+	actualValues := make([]string, len(elements))
+	for i, element := range elements {
+		if element.position != i {
+			t.Errorf("Got %v expected %v", element.position, i)
+		}
+		actualValues[i] = element.value
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(actualValues)), fmt.Sprint(sortedStrings(values)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+// checkKeys checks that the elements of a forward iteration hold the keys regardless of their order, each one with its label.
+func checkKeys(t *testing.T, elements []element, keys []int) {
+	t.Helper()
+	actualKeys := make([]int, len(elements))
+	for i, element := range elements {
+		if element.value != label(element.position) {
+			t.Errorf("Got %v expected %v", element.value, label(element.position))
+		}
+		actualKeys[i] = element.position
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(actualKeys)), fmt.Sprint(sortedStrings(keys)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+// forward returns the elements visited by Next until the end.
+func forward(c cursor) []element {
+	var elements []element
+	for c.iterator.Next() {
+		elements = append(elements, c.element())
+	}
+	return elements
+}
+
+func testIterator(t *testing.T, factory func(values []int) cursor, check func(t *testing.T, elements []element, values []int)) {
+	t.Helper()
+
+	t.Run("Empty", func(t *testing.T) {
+		c := factory(nil)
+		if actualValue := c.iterator.Next(); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+		c.iterator.Begin()
+		if actualValue := c.iterator.First(); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+		if c.reverse == nil {
+			return
+		}
+		c.reverse.End()
+		if actualValue := c.reverse.Prev(); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+		if actualValue := c.reverse.Last(); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+	})
+
+	t.Run("Next", func(t *testing.T) {
+		values := permutation(size)
+		c := factory(values)
+		elements := forward(c)
+		check(t, elements, values)
+		if actualValue := c.iterator.Next(); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+
+		c.iterator.Begin()
+		if actualValue, expectedValue := fmt.Sprint(forward(c)), fmt.Sprint(elements); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue := c.iterator.First(); actualValue != true {
+			t.Errorf("Got %v expected %v", actualValue, true)
+		}
+		if actualValue, expectedValue := fmt.Sprint(append([]element{c.element()}, forward(c)...)), fmt.Sprint(elements); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	})
+
+	t.Run("Prev", func(t *testing.T) {
+		if c := factory(nil); c.reverse == nil {
+			t.Skip("no reverse iteration")
+		}
+		values := permutation(size)
+		c := factory(values)
+		elements := forward(c)
+		var reversed []element
+		for i := len(elements) - 1; i >= 0; i-- {
+			reversed = append(reversed, elements[i])
+		}
+		backward := func() []element {
+			var elements []element
+			for c.reverse.Prev() {
+				elements = append(elements, c.element())
+			}
+			return elements
+		}
+
+		c.reverse.End()
+		if actualValue, expectedValue := fmt.Sprint(backward()), fmt.Sprint(reversed); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue := c.reverse.Prev(); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+		if actualValue := c.reverse.Last(); actualValue != true {
+			t.Errorf("Got %v expected %v", actualValue, true)
+		}
+		if actualValue, expectedValue := fmt.Sprint(append([]element{c.element()}, backward()...)), fmt.Sprint(reversed); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		// Prev stops before the first element, from which Next starts over
+		if actualValue, expectedValue := fmt.Sprint(forward(c)), fmt.Sprint(elements); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		if c := factory(nil); c.failFast == nil {
+			t.Skip("no fail-fast iteration")
+		}
+		values := permutation(size)
+		c := factory(values)
+		var visited, kept []string
+		for c.iterator.Next() {
+			element := c.element()
+			visited = append(visited, element.value)
+			// the value of an element is the label of a value or of a key
+			if value, _ := strconv.Atoi(element.value); value%2 == 1 {
+				c.failFast.Remove()
+				continue
+			}
+			kept = append(kept, element.value)
+		}
+		if err := c.failFast.Err(); err != nil {
+			t.Errorf("Got error %v", err)
+		}
+		if actualValue, expectedValue := fmt.Sprint(sortedStrings(visited)), fmt.Sprint(sortedStrings(labels(values))); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		c.iterator.Begin()
+		var remaining []string
+		for _, element := range forward(c) {
+			remaining = append(remaining, element.value)
+		}
+		if actualValue, expectedValue := fmt.Sprint(sortedStrings(remaining)), fmt.Sprint(sortedStrings(kept)); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := len(remaining), size/2; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	})
+}
+
+// labels returns the labels of the values.
+func labels(values []int) []string {
+	strings := make([]string, len(values))
+	for i, value := range values {
+		strings[i] = label(value)
+	}
+	return strings
+}
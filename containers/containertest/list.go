@@ -0,0 +1,160 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containertest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/monitor1379/yagods/lists"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// TestList checks that the lists of the factory implement lists.List: elements are kept in the order they are added,
+// indexes out of bounds are ignored, and an index equal to the size appends for Insert and Set.
+// The lists of the factory must be empty. Lists must keep their order through the round trips of TestSerialization.
+func TestList(t *testing.T, factory func() lists.List[int]) {
+	t.Helper()
+	check := func(t *testing.T, list lists.List[int], expected ...int) {
+		t.Helper()
+		if actualValue, expectedValue := list.Size(), len(expected); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := list.Empty(), len(expected) == 0; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(list.Values()), fmt.Sprint(expected); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(list.InterfaceValues()), fmt.Sprint(expected); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		list := factory()
+		check(t, list)
+		if _, ok := list.Get(0); ok {
+			t.Errorf("Got %v expected %v", ok, false)
+		}
+		if actualValue := list.Contains(); actualValue != true {
+			t.Errorf("Got %v expected %v", actualValue, true)
+		}
+		if actualValue := list.Contains(0); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		list := factory()
+		list.Add(0, 1)
+		list.Add()
+		list.Add(2, 3, 4)
+		check(t, list, 0, 1, 2, 3, 4)
+		for index := -1; index <= 5; index++ {
+			value, ok := list.Get(index)
+			if expectedValue := index >= 0 && index < 5; ok != expectedValue {
+				t.Errorf("Got %v expected %v", ok, expectedValue)
+			}
+			if ok && value != index {
+				t.Errorf("Got %v expected %v", value, index)
+			}
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		list := factory()
+		list.Add(0, 1, 2, 3, 4)
+		list.Remove(-1)
+		list.Remove(5)
+		check(t, list, 0, 1, 2, 3, 4)
+		list.Remove(4)
+		list.Remove(0)
+		list.Remove(1)
+		check(t, list, 1, 3)
+		list.Remove(0)
+		list.Remove(0)
+		check(t, list)
+		list.Remove(0)
+		check(t, list)
+	})
+
+	t.Run("Contains", func(t *testing.T) {
+		list := factory()
+		list.Add(0, 1, 2)
+		tests := []struct {
+			values   []int
+			expected bool
+		}{
+			{nil, true},
+			{[]int{0}, true},
+			{[]int{2, 0, 1}, true},
+			{[]int{3}, false},
+			{[]int{0, 3}, false},
+		}
+		for _, test := range tests {
+			if actualValue := list.Contains(test.values...); actualValue != test.expected {
+				t.Errorf("Got %v expected %v", actualValue, test.expected)
+			}
+		}
+	})
+
+	t.Run("Insert", func(t *testing.T) {
+		list := factory()
+		list.Insert(0, 2)
+		list.Insert(0, 0, 1)
+		list.Insert(3, 4)
+		list.Insert(3, 3)
+		list.Insert(-1, -1)
+		list.Insert(6, 6)
+		list.Insert(5)
+		check(t, list, 0, 1, 2, 3, 4)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		list := factory()
+		list.Set(0, 1)
+		list.Set(1, 2)
+		list.Set(0, 0)
+		list.Set(1, 1)
+		list.Set(-1, -1)
+		list.Set(3, 3)
+		check(t, list, 0, 1)
+	})
+
+	t.Run("Swap", func(t *testing.T) {
+		list := factory()
+		list.Add(0, 1, 2)
+		list.Swap(0, 2)
+		list.Swap(1, 1)
+		check(t, list, 2, 1, 0)
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		list := factory()
+		list.Sort(utils.NumberComparator[int])
+		check(t, list)
+		list.Add(permutation(size)...)
+		list.Sort(utils.NumberComparator[int])
+		check(t, list, ascending(size)...)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		list := factory()
+		list.Add(0, 1, 2)
+		list.Clear()
+		check(t, list)
+		list.Add(3)
+		check(t, list, 3)
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		list := factory()
+		list.Add(permutation(size)...)
+		roundTrips(t, list, factory, func(t *testing.T, restored lists.List[int]) {
+			check(t, restored, permutation(size)...)
+		})
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containertest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/monitor1379/yagods/maps"
+)
+
+// TestMap checks that the maps of the factory implement maps.Map: a key holds the last value put for it
+// until it is removed, and removing a missing key does nothing. Keys are compared regardless of their order.
+// The maps of the factory must be empty. Maps must hold the same entries after the round trips of TestSerialization.
+// The suite never puts the same value for two keys, so that it applies to maps.BidiMap as well.
+func TestMap(t *testing.T, factory func() maps.Map[int, string]) {
+	t.Helper()
+	testMap(t, factory, false)
+}
+
+// TestSortedMap checks that the maps of the factory implement maps.Map as in TestMap and keep their keys
+// in ascending order: Keys lists them in ascending order, before and after the round trips of TestSerialization.
+// The order of Values is left to the map, a bidirectional map may order its values by themselves.
+// Maps with the Min, Max, Floor or Ceiling functions of treemap.Map are checked for them as well.
+func TestSortedMap(t *testing.T, factory func() maps.Map[int, string]) {
+	t.Helper()
+	testMap(t, factory, true)
+
+	t.Run("Order", func(t *testing.T) {
+		m := factory()
+		for _, key := range permutation(size) {
+			m.Put(key, label(key))
+		}
+		for key := 0; key < size; key += 2 {
+			m.Remove(key)
+		}
+		checkMap(t, m, true, odd(size)...)
+	})
+
+	t.Run("MinMax", func(t *testing.T) {
+		m := factory()
+		minMax, ok := m.(interface {
+			Min() (int, string)
+			Max() (int, string)
+		})
+		if !ok {
+			t.Skip("no Min and Max")
+		}
+		for _, key := range permutation(size) {
+			m.Put(key, label(key))
+		}
+		if key, value := minMax.Min(); key != 0 || value != label(0) {
+			t.Errorf("Got %v %v expected %v %v", key, value, 0, label(0))
+		}
+		if key, value := minMax.Max(); key != size-1 || value != label(size-1) {
+			t.Errorf("Got %v %v expected %v %v", key, value, size-1, label(size-1))
+		}
+	})
+
+	t.Run("FloorCeiling", func(t *testing.T) {
+		m := factory()
+		floorCeiling, ok := m.(interface {
+			Floor(int) (int, string, bool)
+			Ceiling(int) (int, string, bool)
+		})
+		if !ok {
+			t.Skip("no Floor and Ceiling")
+		}
+		if _, _, found := floorCeiling.Floor(0); found {
+			t.Errorf("Got %v expected %v", found, false)
+		}
+		for _, key := range odd(size) {
+			m.Put(key, label(key))
+		}
+		for key := -1; key <= size; key++ {
+			expectedFloor, expectedCeiling := -1, -1
+			for _, k := range odd(size) {
+				if k <= key {
+					expectedFloor = k
+				}
+				if k >= key && expectedCeiling == -1 {
+					expectedCeiling = k
+				}
+			}
+			floor, value, found := floorCeiling.Floor(key)
+			if expectedFound := expectedFloor != -1; found != expectedFound || found && (floor != expectedFloor || value != label(floor)) {
+				t.Errorf("Got %v %v %v expected %v %v", floor, value, found, expectedFloor, expectedFound)
+			}
+			ceiling, value, found := floorCeiling.Ceiling(key)
+			if expectedFound := expectedCeiling != -1; found != expectedFound || found && (ceiling != expectedCeiling || value != label(ceiling)) {
+				t.Errorf("Got %v %v %v expected %v %v", ceiling, value, found, expectedCeiling, expectedFound)
+			}
+		}
+	})
+}
+
+// odd returns the odd integers below n in ascending order.
+func odd(n int) []int {
+	var values []int
+	for value := 1; value < n; value += 2 {
+		values = append(values, value)
+	}
+	return values
+}
+
+// checkMap checks that the map holds exactly the keys, each one with its label, and lists them in the order given if sorted.
+func checkMap(t *testing.T, m maps.Map[int, string], sorted bool, keys ...int) {
+	t.Helper()
+	if actualValue, expectedValue := m.Size(), len(keys); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := m.Empty(), len(keys) == 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for _, key := range keys {
+		if value, found := m.Get(key); !found || value != label(key) {
+			t.Errorf("Got %v %v expected %v %v", value, found, label(key), true)
+		}
+	}
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = label(key)
+	}
+	actualKeys, expectedKeys := fmt.Sprint(m.Keys()), fmt.Sprint(keys)
+	if !sorted {
+		actualKeys, expectedKeys = fmt.Sprint(sortedStrings(m.Keys())), fmt.Sprint(sortedStrings(keys))
+	}
+	if actualKeys != expectedKeys {
+		t.Errorf("Got %v expected %v", actualKeys, expectedKeys)
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Values())), fmt.Sprint(sortedStrings(values)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.InterfaceValues())), fmt.Sprint(sortedStrings(values)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func testMap(t *testing.T, factory func() maps.Map[int, string], sorted bool) {
+	t.Helper()
+
+	t.Run("Empty", func(t *testing.T) {
+		m := factory()
+		checkMap(t, m, sorted)
+		if value, found := m.Get(0); found {
+			t.Errorf("Got %v %v expected %v %v", value, found, "", false)
+		}
+	})
+
+	t.Run("Put", func(t *testing.T) {
+		m := factory()
+		for _, key := range permutation(size) {
+			m.Put(key, label(key))
+		}
+		checkMap(t, m, sorted, ascending(size)...)
+		if value, found := m.Get(size); found {
+			t.Errorf("Got %v %v expected %v %v", value, found, "", false)
+		}
+		m.Put(0, "x")
+		m.Put(0, "y")
+		if value, found := m.Get(0); !found || value != "y" {
+			t.Errorf("Got %v %v expected %v %v", value, found, "y", true)
+		}
+		if actualValue, expectedValue := m.Size(), size; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		m := factory()
+		for _, key := range permutation(size) {
+			m.Put(key, label(key))
+		}
+		m.Remove(-1)
+		m.Remove(size)
+		for key := 0; key < size; key += 2 {
+			m.Remove(key)
+			m.Remove(key)
+		}
+		checkMap(t, m, sorted, odd(size)...)
+		if value, found := m.Get(0); found {
+			t.Errorf("Got %v %v expected %v %v", value, found, "", false)
+		}
+		for _, key := range odd(size) {
+			m.Remove(key)
+		}
+		checkMap(t, m, sorted)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		m := factory()
+		m.Put(1, label(1))
+		m.Put(2, label(2))
+		m.Clear()
+		checkMap(t, m, sorted)
+		m.Put(3, label(3))
+		checkMap(t, m, sorted, 3)
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		m := factory()
+		for _, key := range permutation(size) {
+			m.Put(key, label(key))
+		}
+		roundTrips(t, m, factory, func(t *testing.T, restored maps.Map[int, string]) {
+			checkMap(t, restored, sorted, ascending(size)...)
+		})
+	})
+}
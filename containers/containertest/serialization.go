@@ -0,0 +1,163 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containertest
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// structureMarshaler is implemented by containers marshaling their exact structure, such as the balanced trees.
+type structureMarshaler interface {
+	MarshalStructure() ([]byte, error)
+}
+
+// structureUnmarshaler is implemented by containers restoring their exact structure, such as the balanced trees.
+type structureUnmarshaler interface {
+	UnmarshalStructure([]byte) error
+}
+
+// format is a serialization a container may implement.
+type format struct {
+	name string
+	// roundTrip serializes from and deserializes the result into to.
+	// Returns false if from or to does not implement the format.
+	roundTrip func(from, to interface{}) (bool, error)
+}
+
+var formats = []format{
+	{"JSONSerializer", func(from, to interface{}) (bool, error) {
+		serializer, ok1 := from.(containers.JSONSerializer)
+		deserializer, ok2 := to.(containers.JSONDeserializer)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		data, err := serializer.ToJSON()
+		if err != nil {
+			return true, err
+		}
+		return true, deserializer.FromJSON(data)
+	}},
+	{"JSONMarshaler", func(from, to interface{}) (bool, error) {
+		_, ok1 := from.(json.Marshaler)
+		_, ok2 := to.(json.Unmarshaler)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		data, err := json.Marshal(from)
+		if err != nil {
+			return true, err
+		}
+		return true, json.Unmarshal(data, to)
+	}},
+	{"JSONWriter", func(from, to interface{}) (bool, error) {
+		writer, ok1 := from.(containers.JSONWriter)
+		reader, ok2 := to.(containers.JSONReader)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		var buffer bytes.Buffer
+		if err := writer.WriteJSON(&buffer); err != nil {
+			return true, err
+		}
+		return true, reader.ReadJSON(&buffer)
+	}},
+	{"BinaryMarshaler", func(from, to interface{}) (bool, error) {
+		marshaler, ok1 := from.(encoding.BinaryMarshaler)
+		unmarshaler, ok2 := to.(encoding.BinaryUnmarshaler)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		data, err := marshaler.MarshalBinary()
+		if err != nil {
+			return true, err
+		}
+		return true, unmarshaler.UnmarshalBinary(data)
+	}},
+	{"GobEncoder", func(from, to interface{}) (bool, error) {
+		_, ok1 := from.(gob.GobEncoder)
+		_, ok2 := to.(gob.GobDecoder)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		var buffer bytes.Buffer
+		if err := gob.NewEncoder(&buffer).Encode(from); err != nil {
+			return true, err
+		}
+		return true, gob.NewDecoder(&buffer).Decode(to)
+	}},
+	{"Structure", func(from, to interface{}) (bool, error) {
+		marshaler, ok1 := from.(structureMarshaler)
+		unmarshaler, ok2 := to.(structureUnmarshaler)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		data, err := marshaler.MarshalStructure()
+		if err != nil {
+			return true, err
+		}
+		return true, unmarshaler.UnmarshalStructure(data)
+	}},
+}
+
+// roundTrips runs a subtest for every format the container implements, restoring it into a container of the factory
+// which the check function compares to the container.
+func roundTrips[C any](t *testing.T, container C, factory func() C, check func(t *testing.T, restored C)) {
+	t.Helper()
+	for _, format := range formats {
+		restored := factory()
+		implemented, err := format.roundTrip(container, restored)
+		if !implemented {
+			continue
+		}
+		t.Run(format.name, func(t *testing.T) {
+			if err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			check(t, restored)
+		})
+	}
+}
+
+// TestSerialization checks that the container survives a round trip through every serialization it implements:
+// containers.JSONSerializer and containers.JSONDeserializer, json.Marshaler and json.Unmarshaler,
+// containers.JSONWriter and containers.JSONReader, encoding.BinaryMarshaler and encoding.BinaryUnmarshaler,
+// gob.GobEncoder and gob.GobDecoder, and the MarshalStructure and UnmarshalStructure functions of the trees.
+// Each round trip restores into an empty container of the factory, which must then hold the values of the container,
+// in the same order if ordered is true. A container with a Keys function must hold the same keys as well.
+func TestSerialization[V any](t *testing.T, container containers.Container[V], factory func() containers.Container[V], ordered bool) {
+	t.Helper()
+	roundTrips(t, container, factory, func(t *testing.T, restored containers.Container[V]) {
+		if actualValue, expectedValue := restored.Size(), container.Size(); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		actualValues, expectedValues := fmt.Sprint(sortedStrings(restored.Values())), fmt.Sprint(sortedStrings(container.Values()))
+		if ordered {
+			actualValues, expectedValues = fmt.Sprint(restored.Values()), fmt.Sprint(container.Values())
+		}
+		if actualValues != expectedValues {
+			t.Errorf("Got %v expected %v", actualValues, expectedValues)
+		}
+		actualKeys, ok := keys(restored)
+		if !ok {
+			return
+		}
+		expectedKeys, _ := keys(container)
+		if !ordered {
+			sort.Strings(actualKeys)
+			sort.Strings(expectedKeys)
+		}
+		if actualValue, expectedValue := fmt.Sprint(actualKeys), fmt.Sprint(expectedKeys); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containertest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/monitor1379/yagods/sets"
+)
+
+// TestSet checks that the sets of the factory implement sets.Set: an element is held once however often it is added,
+// and removing a missing element does nothing. Elements are compared regardless of their order.
+// The sets of the factory must be empty. Sets must hold the same elements after the round trips of TestSerialization.
+func TestSet(t *testing.T, factory func() sets.Set[int]) {
+	t.Helper()
+	check := func(t *testing.T, set sets.Set[int], expected ...int) {
+		t.Helper()
+		if actualValue, expectedValue := set.Size(), len(expected); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := set.Empty(), len(expected) == 0; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue := set.Contains(expected...); actualValue != true {
+			t.Errorf("Got %v expected %v", actualValue, true)
+		}
+		if actualValue, expectedValue := fmt.Sprint(sortedStrings(set.Values())), fmt.Sprint(sortedStrings(expected)); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(sortedStrings(set.InterfaceValues())), fmt.Sprint(sortedStrings(expected)); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		set := factory()
+		check(t, set)
+		if actualValue := set.Contains(0); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		set := factory()
+		set.Add()
+		set.Add(permutation(size)...)
+		set.Add(0, 0, 1)
+		check(t, set, ascending(size)...)
+		if actualValue := set.Contains(0, size); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		set := factory()
+		set.Add(permutation(size)...)
+		set.Remove()
+		set.Remove(-1, size)
+		for value := 0; value < size; value += 2 {
+			set.Remove(value, value)
+		}
+		check(t, set, odd(size)...)
+		if actualValue := set.Contains(0); actualValue != false {
+			t.Errorf("Got %v expected %v", actualValue, false)
+		}
+		set.Remove(odd(size)...)
+		check(t, set)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		set := factory()
+		set.Add(1, 2)
+		set.Clear()
+		check(t, set)
+		set.Add(3)
+		check(t, set, 3)
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		set := factory()
+		set.Add(permutation(size)...)
+		roundTrips(t, set, factory, func(t *testing.T, restored sets.Set[int]) {
+			check(t, restored, ascending(size)...)
+		})
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containertest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/monitor1379/yagods/stacks"
+)
+
+// TestStack checks that the stacks of the factory implement stacks.Stack: elements are popped in the reverse order
+// of their pushes, which is also the order of Values. Peek and Pop report an empty stack.
+// The stacks of the factory must be empty. Stacks must keep their order through the round trips of TestSerialization.
+func TestStack(t *testing.T, factory func() stacks.Stack[int]) {
+	t.Helper()
+	check := func(t *testing.T, stack stacks.Stack[int], expected ...int) {
+		t.Helper()
+		if actualValue, expectedValue := stack.Size(), len(expected); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := stack.Empty(), len(expected) == 0; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(stack.Values()), fmt.Sprint(expected); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(stack.InterfaceValues()), fmt.Sprint(expected); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		value, ok := stack.Peek()
+		if expectedValue := len(expected) > 0; ok != expectedValue {
+			t.Errorf("Got %v expected %v", ok, expectedValue)
+		}
+		if ok && value != expected[0] {
+			t.Errorf("Got %v expected %v", value, expected[0])
+		}
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		stack := factory()
+		check(t, stack)
+		if _, ok := stack.Pop(); ok {
+			t.Errorf("Got %v expected %v", ok, false)
+		}
+	})
+
+	t.Run("PushPop", func(t *testing.T) {
+		stack := factory()
+		stack.Push(1)
+		stack.Push(2)
+		stack.Push(3)
+		check(t, stack, 3, 2, 1)
+		for _, expectedValue := range []int{3, 2} {
+			if value, ok := stack.Pop(); !ok || value != expectedValue {
+				t.Errorf("Got %v %v expected %v %v", value, ok, expectedValue, true)
+			}
+		}
+		check(t, stack, 1)
+		stack.Push(4)
+		check(t, stack, 4, 1)
+		stack.Pop()
+		stack.Pop()
+		check(t, stack)
+		if _, ok := stack.Pop(); ok {
+			t.Errorf("Got %v expected %v", ok, false)
+		}
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		stack := factory()
+		stack.Push(1)
+		stack.Push(2)
+		stack.Clear()
+		check(t, stack)
+		stack.Push(3)
+		check(t, stack, 3)
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		stack := factory()
+		for _, value := range permutation(size) {
+			stack.Push(value)
+		}
+		expected := stack.Values()
+		roundTrips(t, stack, factory, func(t *testing.T, restored stacks.Stack[int]) {
+			check(t, restored, expected...)
+		})
+	})
+}
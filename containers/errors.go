@@ -0,0 +1,30 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containers
+
+import "errors"
+
+// ErrConcurrentModification is reported by a FailFastIterator whose container was structurally modified
+// other than through the iterator itself.
+var ErrConcurrentModification = errors.New("ConcurrentModification: container was modified during iteration")
+
+// ErrNotSorted is returned by bulk loading constructors whose input is not in strictly ascending order,
+// i.e. is unsorted or contains duplicates.
+var ErrNotSorted = errors.New("NotSorted: input is not in strictly ascending order")
+
+// ErrInvalidBinary is returned when unmarshaling data that is truncated or not in the binary format of the containers.
+var ErrInvalidBinary = errors.New("InvalidBinary: data is not in the binary format of the containers")
+
+// ErrNoComparator is returned when unmarshaling into the zero value of a container that orders its elements
+// by a type that has no default comparator (see utils.DefaultComparator).
+var ErrNoComparator = errors.New("NoComparator: container was not instantiated by a constructor and has no comparator")
+
+// ErrInvalidStructure is returned when restoring or validating a tree or heap whose nodes violate its invariants,
+// e.g. keys out of order or an unbalanced tree.
+var ErrInvalidStructure = errors.New("InvalidStructure: nodes violate the invariants of the container")
+
+// ErrNoHasher is returned when unmarshaling into the zero value of a container that hashes its elements
+// by the hasher given to its constructor (see utils.Hasher).
+var ErrNoHasher = errors.New("NoHasher: container was not instantiated by a constructor and has no hasher")
@@ -0,0 +1,183 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DOTWriter provides export of the structure of a container as a Graphviz graph
+type DOTWriter interface {
+	// ToDOT writes the structure of the container to the writer in the DOT language of Graphviz.
+	ToDOT(io.Writer) error
+}
+
+// MermaidWriter provides export of the structure of a container as a Mermaid flowchart
+type MermaidWriter interface {
+	// ToMermaid writes the structure of the container to the writer as a Mermaid flowchart.
+	ToMermaid(io.Writer) error
+}
+
+// Graph is the structure of a container as a directed graph of labeled nodes,
+// which is written in the DOT language by WriteDOT and as a Mermaid flowchart by WriteMermaid.
+// Containers build it in their ToGraph function, which takes a hook formatting the labels of their elements.
+type Graph struct {
+	Name       string // kind of the container, such as "redblacktree"
+	Horizontal bool   // laid out from left to right instead of top down, such as the elements of a linked list
+	Nodes      []GraphNode
+	Edges      []GraphEdge
+}
+
+// GraphNode is a node of a graph, named by its index in the nodes of the graph.
+type GraphNode struct {
+	Label     string   // label of the node, lines separated by "\n"
+	Fields    []string // labels of the fields of a node holding several elements, such as a B-tree node, replacing Label if not empty
+	Color     string   // fill color, empty for none
+	FontColor string   // font color, empty for the default
+	Invisible bool     // placeholder keeping the layout of the graph, such as the missing left child of a binary tree node
+}
+
+// GraphEdge is an edge between two nodes of a graph, given by their indexes.
+type GraphEdge struct {
+	From, To int
+	Both     bool // edge in both directions, such as the links of a doubly-linked list
+}
+
+// AddNode adds the node to the graph and returns its index.
+func (graph *Graph) AddNode(node GraphNode) int {
+	graph.Nodes = append(graph.Nodes, node)
+	return len(graph.Nodes) - 1
+}
+
+// AddEdge adds an edge from the node at index from to the node at index to.
+func (graph *Graph) AddEdge(from, to int) {
+	graph.Edges = append(graph.Edges, GraphEdge{From: from, To: to})
+}
+
+// WriteDOT writes the graph to the writer in the DOT language of Graphviz.
+// Nodes with fields are record nodes and children are laid out in the order of their edges.
+func (graph *Graph) WriteDOT(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "digraph %s {\n", dotID(graph.Name))
+	writer.WriteString("\tordering=out;\n")
+	if graph.Horizontal {
+		writer.WriteString("\trankdir=LR;\n")
+	}
+	for i, node := range graph.Nodes {
+		var attributes []string
+		if len(node.Fields) > 0 {
+			fields := make([]string, len(node.Fields))
+			for i, field := range node.Fields {
+				fields[i] = dotRecordEscaper.Replace(field)
+			}
+			attributes = append(attributes, "shape=record", `label="`+strings.Join(fields, "|")+`"`)
+		} else {
+			attributes = append(attributes, "label="+dotString(node.Label))
+		}
+		if node.Color != "" {
+			attributes = append(attributes, "style=filled", "fillcolor="+dotString(node.Color))
+		}
+		if node.FontColor != "" {
+			attributes = append(attributes, "fontcolor="+dotString(node.FontColor))
+		}
+		if node.Invisible {
+			attributes = append(attributes, "style=invis")
+		}
+		fmt.Fprintf(writer, "\tn%d [%s];\n", i, strings.Join(attributes, ", "))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(writer, "\tn%d -> n%d", edge.From, edge.To)
+		switch {
+		case graph.Nodes[edge.To].Invisible:
+			writer.WriteString(" [style=invis]")
+		case edge.Both:
+			writer.WriteString(" [dir=both]")
+		}
+		writer.WriteString(";\n")
+	}
+	writer.WriteString("}\n")
+	return writer.Flush()
+}
+
+// WriteMermaid writes the graph to the writer as a Mermaid flowchart.
+// Invisible nodes and the edges to them are left out, since Mermaid has no invisible nodes.
+func (graph *Graph) WriteMermaid(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	if graph.Horizontal {
+		writer.WriteString("flowchart LR\n")
+	} else {
+		writer.WriteString("flowchart TD\n")
+	}
+	if graph.Name != "" {
+		fmt.Fprintf(writer, "\t%%%% %s\n", graph.Name)
+	}
+	for i, node := range graph.Nodes {
+		if node.Invisible {
+			continue
+		}
+		label := node.Label
+		if len(node.Fields) > 0 {
+			label = strings.Join(node.Fields, " | ")
+		}
+		fmt.Fprintf(writer, "\tn%d[%s]\n", i, mermaidString(label))
+	}
+	for _, edge := range graph.Edges {
+		if graph.Nodes[edge.To].Invisible {
+			continue
+		}
+		arrow := "-->"
+		if edge.Both {
+			arrow = "<-->"
+		}
+		fmt.Fprintf(writer, "\tn%d %s n%d\n", edge.From, arrow, edge.To)
+	}
+	for i, node := range graph.Nodes {
+		if node.Invisible || node.Color == "" && node.FontColor == "" {
+			continue
+		}
+		var styles []string
+		if node.Color != "" {
+			styles = append(styles, "fill:"+node.Color)
+		}
+		if node.FontColor != "" {
+			styles = append(styles, "color:"+node.FontColor)
+		}
+		fmt.Fprintf(writer, "\tstyle n%d %s\n", i, strings.Join(styles, ","))
+	}
+	return writer.Flush()
+}
+
+// dotID returns the name as a DOT identifier, quoted unless it is a plain word.
+func dotID(name string) string {
+	if name == "" {
+		return `""`
+	}
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return dotString(name)
+		}
+	}
+	return name
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotString returns the label as a quoted DOT string.
+func dotString(label string) string {
+	return `"` + dotEscaper.Replace(label) + `"`
+}
+
+// dotRecordEscaper escapes a field of a record label, which additionally gives meaning to braces, bars and angle brackets.
+var dotRecordEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, `{`, `\{`, `}`, `\}`, `|`, `\|`, `<`, `\<`, `>`, `\>`)
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", "<br>")
+
+// mermaidString returns the label as a quoted Mermaid string.
+func mermaidString(label string) string {
+	return `"` + mermaidEscaper.Replace(label) + `"`
+}
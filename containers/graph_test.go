@@ -0,0 +1,78 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containers_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+func newGraph() *containers.Graph {
+	graph := &containers.Graph{Name: "example graph"}
+	root := graph.AddNode(containers.GraphNode{Label: "say \"hi\"\n<root>", Color: "black", FontColor: "white"})
+	graph.AddEdge(root, graph.AddNode(containers.GraphNode{Invisible: true}))
+	record := graph.AddNode(containers.GraphNode{Fields: []string{"a|b", `{c}`}})
+	graph.AddEdge(root, record)
+	graph.Edges = append(graph.Edges, containers.GraphEdge{From: record, To: root, Both: true})
+	return graph
+}
+
+func TestGraphWriteDOT(t *testing.T) {
+	var builder strings.Builder
+	if err := newGraph().WriteDOT(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue := `digraph "example graph" {
+	ordering=out;
+	n0 [label="say \"hi\"\n<root>", style=filled, fillcolor="black", fontcolor="white"];
+	n1 [label="", style=invis];
+	n2 [shape=record, label="a\|b|\{c\}"];
+	n0 -> n1 [style=invis];
+	n0 -> n2;
+	n2 -> n0 [dir=both];
+}
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	builder.Reset()
+	graph := &containers.Graph{Name: "list", Horizontal: true}
+	if err := graph.WriteDOT(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := builder.String(), "digraph list {\n\tordering=out;\n\trankdir=LR;\n}\n"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestGraphWriteMermaid(t *testing.T) {
+	var builder strings.Builder
+	if err := newGraph().WriteMermaid(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue := `flowchart TD
+	%% example graph
+	n0["say #quot;hi#quot;<br>#lt;root#gt;"]
+	n2["a|b | {c}"]
+	n0 --> n2
+	n2 <--> n0
+	style n0 fill:black,color:white
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	builder.Reset()
+	graph := &containers.Graph{Horizontal: true}
+	if err := graph.WriteMermaid(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := builder.String(), "flowchart LR\n"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
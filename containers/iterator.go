@@ -95,3 +95,24 @@ type ReverseIteratorWithKey[K any, V any] interface {
 	// Modifies the state of the iterator.
 	Last() bool
 }
+
+// FailFastIterator is a stateful iterator that detects structural modifications of its container
+// made other than through the iterator itself.
+//
+// Next() and Prev() panic with ErrConcurrentModification once such a modification is detected.
+// Callers that prefer not to panic can check Err() before moving the iterator.
+//
+// Only an iterator positioned one-before-first, such as a new iterator, is not affected by modifications,
+// as Next() starts over from the first element. Once past the last element or after Remove(), an iterator reports them as well.
+// Begin(), End(), First() and Last() re-synchronize the iterator with its container.
+type FailFastIterator interface {
+	// Err returns ErrConcurrentModification if the container was structurally modified
+	// since the iterator last synchronized with it, otherwise nil.
+	// Does not modify the state of the iterator.
+	Err() error
+
+	// Remove removes the current element from the container and keeps the iterator valid.
+	// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+	// Does nothing if the iterator is not positioned on an element.
+	Remove()
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// MarshalJSONEntries returns a JSON object holding the key-value pairs in the given order, keys[i] holding values[i].
+// Keys become object keys the way encoding/json converts map keys: keys of string kind are used as is,
+// keys implementing encoding.TextMarshaler by their text and integers by their decimal representation.
+// Floats, which encoding/json rejects as map keys, become their shortest representation that parses back to the same value.
+func MarshalJSONEntries[K any, V any](keys []K, values []V) ([]byte, error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("containers: %d keys but %d values", len(keys), len(values))
+	}
+	data := []byte{'{'}
+	for i, key := range keys {
+		if i > 0 {
+			data = append(data, ',')
+		}
+		var err error
+		if data, err = appendJSONEntry(data, key, values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return append(data, '}'), nil
+}
+
+// UnmarshalJSONEntries parses a JSON object into its key-value pairs in the order of the input.
+// Object keys are converted back to keys the way encoding/json converts map keys, see MarshalJSONEntries.
+// A JSON null holds no pairs.
+func UnmarshalJSONEntries[K any, V any](data []byte) ([]K, []V, error) {
+	var keys []K
+	var values []V
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	err := readJSONObject(decoder, func(key K, value V) {
+		keys, values = append(keys, key), append(values, value)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys, values, readJSONEnd(decoder)
+}
+
+// WriteJSONValues writes the remaining values of the iterator to the writer as a JSON array,
+// encoding one value at a time.
+func WriteJSONValues[V any](w io.Writer, it Iterator[V]) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteByte('[')
+	for first := true; it.Next(); first = false {
+		if !first {
+			writer.WriteByte(',')
+		}
+		data, err := json.Marshal(it.Value())
+		if err != nil {
+			return err
+		}
+		writer.Write(data)
+	}
+	writer.WriteByte(']')
+	return writer.Flush()
+}
+
+// WriteJSONEntries writes the remaining key-value pairs of the iterator to the writer as a JSON object,
+// encoding one pair at a time. Keys become object keys as in MarshalJSONEntries.
+func WriteJSONEntries[K any, V any](w io.Writer, it IteratorWithKey[K, V]) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteByte('{')
+	var data []byte
+	for first := true; it.Next(); first = false {
+		if !first {
+			writer.WriteByte(',')
+		}
+		var err error
+		if data, err = appendJSONEntry(data[:0], it.Key(), it.Value()); err != nil {
+			return err
+		}
+		writer.Write(data)
+	}
+	writer.WriteByte('}')
+	return writer.Flush()
+}
+
+// ReadJSONValues reads a JSON array from the reader and passes its elements to add in order,
+// each one as soon as it is decoded, so that arrays larger than the memory can be consumed.
+// A JSON null holds no elements. On error, add has been called with the elements read before the error.
+// As encoding/json.Decoder, it may read data beyond the array from the reader.
+func ReadJSONValues[V any](r io.Reader, add func(value V)) error {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil || token == nil {
+		return err
+	}
+	if token != json.Delim('[') {
+		return fmt.Errorf("containers: expected JSON array, got %v", token)
+	}
+	for decoder.More() {
+		var value V
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		add(value)
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// ReadJSONEntries reads a JSON object from the reader and passes its key-value pairs to put in order,
+// each one as soon as it is decoded. Object keys are converted back to keys as in UnmarshalJSONEntries.
+// A JSON null holds no pairs. On error, put has been called with the pairs read before the error.
+// As encoding/json.Decoder, it may read data beyond the object from the reader.
+func ReadJSONEntries[K any, V any](r io.Reader, put func(key K, value V)) error {
+	return readJSONObject(json.NewDecoder(r), put)
+}
+
+func readJSONObject[K any, V any](decoder *json.Decoder, put func(key K, value V)) error {
+	token, err := decoder.Token()
+	if err != nil || token == nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("containers: expected JSON object, got %v", token)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, err := unmarshalJSONKey[K](token.(string))
+		if err != nil {
+			return err
+		}
+		var value V
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		put(key, value)
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+func appendJSONEntry[K any, V any](data []byte, key K, value V) ([]byte, error) {
+	text, err := marshalJSONKey(key)
+	if err != nil {
+		return nil, err
+	}
+	encodedKey, err := json.Marshal(text)
+	if err != nil {
+		return nil, err
+	}
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	data = append(append(data, encodedKey...), ':')
+	return append(data, encodedValue...), nil
+}
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func marshalJSONKey[K any](key K) (string, error) {
+	v := reflect.ValueOf(&key).Elem()
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := any(key).(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("containers: unsupported JSON object key type %v", v.Type())
+}
+
+func unmarshalJSONKey[K any](text string) (key K, err error) {
+	v := reflect.ValueOf(&key).Elem()
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		err = any(&key).(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+		return key, err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+		return key, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return key, fmt.Errorf("containers: invalid JSON object key %q for type %v", text, v.Type())
+		}
+		v.SetInt(n)
+		return key, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(text, 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return key, fmt.Errorf("containers: invalid JSON object key %q for type %v", text, v.Type())
+		}
+		v.SetUint(n)
+		return key, nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("containers: invalid JSON object key %q for type %v", text, v.Type())
+		}
+		v.SetFloat(f)
+		return key, nil
+	}
+	return key, fmt.Errorf("containers: unsupported JSON object key type %v", v.Type())
+}
+
+func readJSONEnd(decoder *json.Decoder) error {
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("containers: invalid data after top-level JSON value")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package containers_test
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+func TestMarshalJSONEntries(t *testing.T) {
+	data, err := containers.MarshalJSONEntries([]string{"b", "a", `"q"`}, []int{2, 1, 3})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"b":2,"a":1,"\"q\"":3}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	keys, values, err := containers.UnmarshalJSONEntries[string, int](data)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(keys, values), `[b a "q"] [2 1 3]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	data, err = containers.MarshalJSONEntries([]int8{-1, 10}, [][]int{{1}, nil})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"-1":[1],"10":null}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, err := containers.MarshalJSONEntries([]int{1}, []int{}); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if _, err := containers.MarshalJSONEntries([]complex128{1.5}, []int{1}); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestMarshalJSONEntriesFloat(t *testing.T) {
+	data, err := containers.MarshalJSONEntries([]float64{-2.5, 0.1, 1e21, 3}, []string{"a", "b", "c", "d"})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"-2.5":"a","0.1":"b","1e+21":"c","3":"d"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	keys, values, err := containers.UnmarshalJSONEntries[float64, string](data)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(keys, values), "[-2.5 0.1 1e+21 3] [a b c d]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	data, err = containers.MarshalJSONEntries([]float32{0.1}, []int{1})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"0.1":1}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for _, test := range []string{`{"x":1}`, `{"1e39":1}`} {
+		if _, _, err := containers.UnmarshalJSONEntries[float32, int]([]byte(test)); err == nil {
+			t.Errorf("Got %v expected an error for %v", err, test)
+		}
+	}
+}
+
+func TestMarshalJSONEntriesTextMarshaler(t *testing.T) {
+	data, err := containers.MarshalJSONEntries([]net.IP{net.IPv4(10, 0, 0, 1)}, []string{"gateway"})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"10.0.0.1":"gateway"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	keys, _, err := containers.UnmarshalJSONEntries[net.IP, string](data)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := keys[0].String(), "10.0.0.1"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestUnmarshalJSONEntriesInvalid(t *testing.T) {
+	keys, values, err := containers.UnmarshalJSONEntries[string, int]([]byte(" null "))
+	if err != nil || len(keys) != 0 || len(values) != 0 {
+		t.Errorf("Got %v, %v, %v expected no entries", keys, values, err)
+	}
+	tests := []string{
+		``,
+		`[]`,
+		`{"a":1`,
+		`{"a":"b"}`,
+		`{"a":1} {}`,
+		`{"a":1}]`,
+	}
+	for _, test := range tests {
+		if _, _, err := containers.UnmarshalJSONEntries[string, int]([]byte(test)); err == nil {
+			t.Errorf("Got %v expected an error for %v", err, test)
+		}
+	}
+	for _, test := range []string{`{"x":1}`, `{"300":1}`, `{"-1":1}`} {
+		if _, _, err := containers.UnmarshalJSONEntries[uint8, int]([]byte(test)); err == nil {
+			t.Errorf("Got %v expected an error for %v", err, test)
+		}
+	}
+}
+
+// pairs is an iterator with key over pairs of keys and values
+type pairs struct {
+	keys   []string
+	values []int
+	index  int
+}
+
+func (it *pairs) Next() bool    { it.index++; return it.index < len(it.keys) }
+func (it *pairs) Value() int    { return it.values[it.index] }
+func (it *pairs) Key() string   { return it.keys[it.index] }
+func (it *pairs) Begin()        { it.index = -1 }
+func (it *pairs) First() bool   { it.Begin(); return it.Next() }
+func (it *pairs) Reset() *pairs { it.Begin(); return it }
+
+func TestWriteJSON(t *testing.T) {
+	it := &pairs{keys: []string{"b", "a"}, values: []int{2, 1}}
+
+	var buffer bytes.Buffer
+	if err := containers.WriteJSONValues[int](&buffer, it.Reset()); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), "[2,1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	buffer.Reset()
+	if err := containers.WriteJSONEntries[string, int](&buffer, it.Reset()); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"b":2,"a":1}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	buffer.Reset()
+	if err := containers.WriteJSONEntries[string, int](&buffer, it); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	var values []int
+	err := containers.ReadJSONValues(strings.NewReader(" [3, 1, 2] "), func(value int) {
+		values = append(values, value)
+	})
+	if actualValue, expectedValue := fmt.Sprint(values, err), "[3 1 2] <nil>"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var keys []string
+	err = containers.ReadJSONEntries(strings.NewReader(`{"b":2,"a":1,"b":3}`), func(key string, value int) {
+		keys = append(keys, key)
+	})
+	if actualValue, expectedValue := fmt.Sprint(keys, err), "[b a b] <nil>"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for _, test := range []string{``, `{}`, `[1,`, `[1 2]`, `["a"]`} {
+		if err := containers.ReadJSONValues(strings.NewReader(test), func(int) {}); err == nil {
+			t.Errorf("Got %v expected an error for %v", err, test)
+		}
+	}
+	for _, test := range []string{`[]`, `{"a":1,}`, `{"a":"b"}`} {
+		if err := containers.ReadJSONEntries(strings.NewReader(test), func(string, int) {}); err == nil {
+			t.Errorf("Got %v expected an error for %v", err, test)
+		}
+	}
+}
@@ -4,6 +4,8 @@
 
 package containers
 
+import "io"
+
 // JSONSerializer provides JSON serialization
 type JSONSerializer interface {
 	// ToJSON outputs the JSON representation of containers's elements.
@@ -15,3 +17,15 @@ type JSONDeserializer interface {
 	// FromJSON populates containers's elements from the input JSON representation.
 	FromJSON([]byte) error
 }
+
+// JSONWriter provides streaming JSON serialization
+type JSONWriter interface {
+	// WriteJSON writes the JSON representation of containers's elements to the writer.
+	WriteJSON(io.Writer) error
+}
+
+// JSONReader provides streaming JSON deserialization
+type JSONReader interface {
+	// ReadJSON populates containers's elements from the JSON representation read from the reader.
+	ReadJSON(io.Reader) error
+}
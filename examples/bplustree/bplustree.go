@@ -0,0 +1,79 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/monitor1379/yagods/trees/bplustree"
+)
+
+// BPlusTreeExample to demonstrate basic usage of BPlusTree
+func main() {
+	tree := bplustree.NewWithIntComparator[string](3) // empty (keys are of type int)
+
+	tree.Put(1, "x") // 1->x
+	tree.Put(2, "b") // 1->x, 2->b (in order)
+	tree.Put(1, "a") // 1->a, 2->b (in order, replacement)
+	tree.Put(3, "c") // 1->a, 2->b, 3->c (in order)
+	tree.Put(4, "d") // 1->a, 2->b, 3->c, 4->d (in order)
+	tree.Put(5, "e") // 1->a, 2->b, 3->c, 4->d, 5->e (in order)
+	tree.Put(6, "f") // 1->a, 2->b, 3->c, 4->d, 5->e, 6->f (in order)
+	tree.Put(7, "g") // 1->a, 2->b, 3->c, 4->d, 5->e, 6->f, 7->g (in order)
+
+	fmt.Println(tree)
+	// BPlusTree
+	//         1
+	//     2
+	//         2
+	// 3
+	//         3
+	//     4
+	//         4
+	// 5
+	//         5
+	//     6
+	//         6
+	//         7
+
+	_ = tree.Values() // []string {}{"a", "b", "c", "d", "e", "f", "g"} (in order)
+	_ = tree.Keys()   // []int {}{1, 2, 3, 4, 5, 6, 7} (in order)
+
+	// range scan over [3, 6)
+	for it := tree.IteratorAt(3); it.Key() < 6; it.Next() {
+		fmt.Print(it.Key(), " ") // 3 4 5
+	}
+	fmt.Println()
+
+	tree.Floor(0)   // 0, "", false
+	tree.Ceiling(0) // 1, "a", true
+
+	tree.Remove(2) // 1->a, 3->c, 4->d, 5->e, 6->f, 7->g (in order)
+	fmt.Println(tree)
+	// BPlusTree
+	//         1
+	//     3
+	//         3
+	//     4
+	//         4
+	// 5
+	//         5
+	//     6
+	//         6
+	//         7
+
+	tree.Clear() // empty
+	tree.Empty() // true
+	tree.Size()  // 0
+
+	// Other:
+	tree.Height()     // gets the height of the tree
+	tree.Left()       // gets the left-most (min) leaf
+	tree.LeftKey()    // get the left-most (min) key
+	tree.LeftValue()  // get the left-most (min) key's value
+	tree.Right()      // get the right-most (max) leaf
+	tree.RightKey()   // get the right-most (max) key
+	tree.RightValue() // get the right-most (max) key's value
+}
@@ -11,8 +11,10 @@ package arraylist
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/monitor1379/yagods/containers"
 	"github.com/monitor1379/yagods/lists"
 	"github.com/monitor1379/yagods/utils"
 )
@@ -20,25 +22,59 @@ import (
 var _ lists.List[int] = (*List[int])(nil)
 
 // List holds the values in a slice
-type List[V comparable] struct {
-	values []V
-	size   int
+type List[V any] struct {
+	values   []V
+	size     int
+	modCount int            // number of structural modifications, checked by iterators
+	equal    utils.Equal[V] // equality of the values, utils.InterfaceEqual if nil
+
+	growthFactor float32 // DefaultGrowthFactor if 0
+	shrinkFactor float32 // DefaultShrinkFactor if 0, never shrink if negative
 }
 
+// Factors of the lists whose factors are not set, see SetGrowthFactor and SetShrinkFactor.
 const (
-	growthFactor = float32(2.0)  // growth by 100%
-	shrinkFactor = float32(0.25) // shrink when size is 25% of capacity (0 means never shrink)
+	DefaultGrowthFactor = float32(2.0)  // growth by 100%
+	DefaultShrinkFactor = float32(0.25) // shrink when size is 25% of capacity
 )
 
 // New instantiates a new list and adds the passed values, if any, to the list
 func New[V comparable](values ...V) *List[V] {
-	l := &List[V]{}
+	return NewWithEqual(utils.ComparableEqual[V], values...)
+}
+
+// NewWithEqual instantiates a new list searching its values with the equality function and adds the passed values,
+// if any, to the list. The values need not be comparable with ==.
+func NewWithEqual[V any](equal utils.Equal[V], values ...V) *List[V] {
+	l := &List[V]{equal: equal}
 	if len(values) > 0 {
 		l.Add(values...)
 	}
 	return l
 }
 
+// SetGrowthFactor sets the factor by which the capacity of the list grows once it is reached, DefaultGrowthFactor by default.
+// Panics if the factor is less than 1.
+func (l *List[V]) SetGrowthFactor(factor float32) {
+	if factor < 1 {
+		panic(fmt.Sprintf("arraylist: growth factor %v is less than 1", factor))
+	}
+	l.growthFactor = factor
+}
+
+// SetShrinkFactor sets the ratio of size to capacity at or below which removing values shrinks the capacity to the size,
+// DefaultShrinkFactor by default. A factor of 0 means never shrink.
+// Panics if the factor is not in [0, 1).
+func (l *List[V]) SetShrinkFactor(factor float32) {
+	if factor < 0 || factor >= 1 {
+		panic(fmt.Sprintf("arraylist: shrink factor %v is not in [0, 1)", factor))
+	}
+	if factor == 0 {
+		factor = -1
+	}
+	l.shrinkFactor = factor
+}
+
 // Add appends a value at the end of the list
 func (l *List[V]) Add(values ...V) {
 	l.growBy(len(values))
@@ -46,6 +82,12 @@ func (l *List[V]) Add(values ...V) {
 		l.values[l.size] = value
 		l.size++
 	}
+	l.modCount++
+}
+
+// AddAll appends the values of the container at the end of the list, in the order of its Values().
+func (l *List[V]) AddAll(container containers.Container[V]) {
+	l.Add(container.Values()...)
 }
 
 // Get returns the value at index.
@@ -67,22 +109,66 @@ func (l *List[V]) Remove(index int) {
 	}
 
 	var zeroV V
-	l.values[index] = zeroV                          // cleanup reference
 	copy(l.values[index:], l.values[index+1:l.size]) // shift to the left by one (slow operation, need ways to optimize this)
 	l.size--
+	l.values[l.size] = zeroV // cleanup reference
+	l.modCount++
 
 	l.shrink()
 }
 
+// RemoveRange removes the values at the indexes within [from, to) from the list, shifting the values that follow once.
+// The range is clipped to the bounds of the list.
+func (l *List[V]) RemoveRange(from, to int) {
+	from, to = l.clip(from, to)
+	if from == to {
+		return
+	}
+	copy(l.values[from:], l.values[to:l.size])
+	l.truncate(l.size - (to - from))
+}
+
+// RemoveIf removes all values for which the function returns true in a single pass over the list,
+// keeping the order of the other values, and returns the number of removed values.
+func (l *List[V]) RemoveIf(f func(value V) bool) int {
+	kept := 0
+	for _, value := range l.values[:l.size] {
+		if !f(value) {
+			l.values[kept] = value
+			kept++
+		}
+	}
+	removed := l.size - kept
+	if removed > 0 {
+		l.truncate(kept)
+	}
+	return removed
+}
+
+// RetainAll removes all values that are not equal to any of the given values and returns the number of removed values.
+// Performance time complexity of n*m, m being the number of given values.
+func (l *List[V]) RetainAll(values ...V) int {
+	equal := l.equality()
+	return l.RemoveIf(func(value V) bool {
+		for _, retained := range values {
+			if equal(value, retained) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
 // Contains checks if values (one or more) are present in the set.
 // All values have to be present in the set for the method to return true.
 // Performance time complexity of n^2.
 // Returns true if no arguments are passed at all, i.e. set is always super-set of empty set.
 func (l *List[V]) Contains(values ...V) bool {
+	equal := l.equality()
 	for _, searchValue := range values {
 		found := false
-		for _, value := range l.values {
-			if value == searchValue {
+		for _, value := range l.values[:l.size] {
+			if equal(value, searchValue) {
 				found = true
 				break
 			}
@@ -115,8 +201,24 @@ func (l *List[V]) IndexOf(v V) int {
 	if l.size == 0 {
 		return -1
 	}
-	for index, value := range l.values {
-		if value == v {
+	equal := l.equality()
+	for index, value := range l.values[:l.size] {
+		if equal(value, v) {
+			return index
+		}
+	}
+	return -1
+}
+
+// ContainsFunc returns true if the function returns true for any value in the list.
+func (l *List[V]) ContainsFunc(f func(value V) bool) bool {
+	return l.IndexFunc(f) >= 0
+}
+
+// IndexFunc returns the index of the first value for which the function returns true, or -1 if there is none.
+func (l *List[V]) IndexFunc(f func(value V) bool) int {
+	for index, value := range l.values[:l.size] {
+		if f(value) {
 			return index
 		}
 	}
@@ -137,6 +239,7 @@ func (l *List[V]) Size() int {
 func (l *List[V]) Clear() {
 	l.size = 0
 	l.values = []V{}
+	l.modCount++
 }
 
 // Sort sorts values (in-place) using.
@@ -172,6 +275,57 @@ func (l *List[V]) Insert(index int, values ...V) {
 	l.size += n
 	copy(l.values[index+n:], l.values[index:l.size-n])
 	copy(l.values[index:], values)
+	l.modCount++
+}
+
+// Reverse reverses the order of the values in-place.
+func (l *List[V]) Reverse() {
+	reverse(l.values[:l.size])
+}
+
+// Rotate moves the value at every index i to index (i + distance) modulo size in-place.
+// A negative distance rotates the values towards the front of the list.
+func (l *List[V]) Rotate(distance int) {
+	if l.size < 2 {
+		return
+	}
+	distance %= l.size
+	if distance < 0 {
+		distance += l.size
+	}
+	// the last distance values move to the front: reverse all, then both parts on their own
+	reverse(l.values[:l.size])
+	reverse(l.values[:distance])
+	reverse(l.values[distance:l.size])
+}
+
+// BinarySearch searches the value in the list sorted by the comparator, see Sort, and returns the index of its first
+// occurrence and true, or the index at which it would be inserted to keep the list sorted and false if it is not found.
+func (l *List[V]) BinarySearch(value V, comparator utils.Comparator[V]) (int, bool) {
+	index := sort.Search(l.size, func(i int) bool {
+		return comparator(l.values[i], value) >= 0
+	})
+	return index, index < l.size && comparator(l.values[index], value) == 0
+}
+
+// EnsureCapacity grows the capacity of the list, if needed, so that adding up to capacity - size values does not reallocate.
+// As the list grows when adding values would fill its array, the array gets one slot more than the given capacity.
+func (l *List[V]) EnsureCapacity(capacity int) {
+	if capacity >= cap(l.values) {
+		l.resize(capacity + 1)
+	}
+}
+
+// TrimToSize shrinks the capacity of the list to its size.
+func (l *List[V]) TrimToSize() {
+	if cap(l.values) > l.size {
+		l.resize(l.size)
+	}
+}
+
+// Capacity returns the length of the array holding the values; the list grows when adding values would fill it.
+func (l *List[V]) Capacity() int {
+	return cap(l.values)
 }
 
 // Set the value at specified index
@@ -201,11 +355,50 @@ func (l *List[V]) String() string {
 	return str
 }
 
+// equality returns the equality function searching the values of the list.
+func (l *List[V]) equality() utils.Equal[V] {
+	if l.equal == nil {
+		return utils.InterfaceEqual[V]
+	}
+	return l.equal
+}
+
 // Check that the index is within bounds of the list
 func (l *List[V]) withinRange(index int) bool {
 	return index >= 0 && index < l.size
 }
 
+// clip clips the range [from, to) to the bounds of the list.
+func (l *List[V]) clip(from, to int) (int, int) {
+	if from < 0 {
+		from = 0
+	}
+	if to > l.size {
+		to = l.size
+	}
+	if from > to {
+		from = to
+	}
+	return from, to
+}
+
+// truncate cuts the list down to the given size, cleaning up the references beyond it.
+func (l *List[V]) truncate(size int) {
+	var zeroV V
+	for index := size; index < l.size; index++ {
+		l.values[index] = zeroV
+	}
+	l.size = size
+	l.modCount++
+	l.shrink()
+}
+
+func reverse[V any](values []V) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
+
 func (l *List[V]) resize(cap int) {
 	newValues := make([]V, cap, cap)
 	copy(newValues, l.values)
@@ -217,6 +410,10 @@ func (l *List[V]) growBy(n int) {
 	// When capacity is reached, grow by a factor of growthFactor and add number of values
 	currentCapacity := cap(l.values)
 	if l.size+n >= currentCapacity {
+		growthFactor := l.growthFactor
+		if growthFactor == 0 {
+			growthFactor = DefaultGrowthFactor
+		}
 		newCapacity := int(growthFactor * float32(currentCapacity+n))
 		l.resize(newCapacity)
 	}
@@ -224,9 +421,13 @@ func (l *List[V]) growBy(n int) {
 
 // Shrink the array if necessary, i.e. when size is shrinkFactor percent of current capacity
 func (l *List[V]) shrink() {
-	if shrinkFactor == 0.0 {
+	shrinkFactor := l.shrinkFactor
+	if shrinkFactor < 0 {
 		return
 	}
+	if shrinkFactor == 0 {
+		shrinkFactor = DefaultShrinkFactor
+	}
 	// Shrink when size is at shrinkFactor * capacity
 	currentCapacity := cap(l.values)
 	if l.size <= int(float32(currentCapacity)*shrinkFactor) {
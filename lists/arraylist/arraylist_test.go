@@ -5,9 +5,19 @@
 package arraylist_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/lists"
 	"github.com/monitor1379/yagods/lists/arraylist"
 	"github.com/monitor1379/yagods/utils"
 )
@@ -180,6 +190,55 @@ func TestListContains(t *testing.T) {
 	}
 }
 
+func TestListNewWithEqual(t *testing.T) {
+	list := arraylist.NewWithEqual(bytes.Equal, []byte("a"), []byte("b"))
+	list.Add([]byte("c"))
+	if actualValue := list.Contains([]byte("c"), []byte("a")); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := list.Contains([]byte("d")); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue := list.IndexOf([]byte("b")); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	selected := list.Select(func(index int, value []byte) bool { return index > 0 })
+	if actualValue := selected.IndexOf([]byte("c")); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	lengths := arraylist.Map(list, func(index int, value []byte) []int { return []int{index, len(value)} })
+	if actualValue, expectedValue := fmt.Sprint(lengths.Values()), "[[0 1] [1 1] [2 1]]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var zero arraylist.List[string]
+	zero.Add("a", "b")
+	if actualValue := zero.IndexOf("b"); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+}
+
+func TestListContainsFuncIndexFunc(t *testing.T) {
+	list := arraylist.New[string]()
+	isB := func(value string) bool { return value == "b" }
+	if actualValue := list.ContainsFunc(isB); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue := list.IndexFunc(isB); actualValue != -1 {
+		t.Errorf("Got %v expected %v", actualValue, -1)
+	}
+	list.Add("a", "b", "c", "b")
+	if actualValue := list.ContainsFunc(isB); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := list.IndexFunc(isB); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	if actualValue := list.IndexFunc(func(value string) bool { return value > "b" }); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+}
+
 func TestListValues(t *testing.T) {
 	list := arraylist.New[string]()
 	list.Add("a")
@@ -227,6 +286,248 @@ func TestListSet(t *testing.T) {
 	}
 }
 
+func TestListRemoveRange(t *testing.T) {
+	list := arraylist.New("a", "b", "c", "d", "e")
+	list.RemoveRange(1, 3)
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[a d e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.RemoveRange(2, 1) // ignore
+	list.RemoveRange(-5, 1)
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[d e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.RemoveRange(1, 10)
+	if actualValue, expectedValue := fmt.Sprint(list.Values(), list.Size()), "[d] 1"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.Add("f")
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[d f]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListRemoveIfRetainAll(t *testing.T) {
+	list := arraylist.New(1, 2, 3, 4, 5, 6, 7, 8)
+	if actualValue, expectedValue := list.RemoveIf(func(value int) bool { return value%3 == 0 }), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[1 2 4 5 7 8]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := list.RemoveIf(func(value int) bool { return value > 8 }), 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := list.RetainAll(8, 2, 5, 9), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[2 5 8]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := list.RetainAll(), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := list.Empty(), true; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	points := arraylist.NewWithEqual(func(a, b []int) bool { return fmt.Sprint(a) == fmt.Sprint(b) }, []int{1}, []int{2}, []int{1})
+	points.RetainAll([]int{1})
+	if actualValue, expectedValue := fmt.Sprint(points.Values()), "[[1] [1]]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListReverseRotate(t *testing.T) {
+	list := arraylist.New(1, 2, 3, 4, 5)
+	list.Reverse()
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[5 4 3 2 1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.Reverse()
+	tests := [][]interface{}{
+		{1, "[5 1 2 3 4]"},
+		{-1, "[1 2 3 4 5]"},
+		{7, "[4 5 1 2 3]"},
+		{-7, "[1 2 3 4 5]"},
+		{5, "[1 2 3 4 5]"},
+		{0, "[1 2 3 4 5]"},
+	}
+	for _, test := range tests {
+		list.Rotate(test[0].(int))
+		if actualValue, expectedValue := fmt.Sprint(list.Values()), test[1]; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	empty := arraylist.New[int]()
+	empty.Reverse()
+	empty.Rotate(3)
+	if actualValue, expectedValue := empty.Size(), 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListBinarySearch(t *testing.T) {
+	list := arraylist.New(1, 3, 3, 3, 7)
+	tests := [][]interface{}{
+		{0, 0, false},
+		{1, 0, true},
+		{3, 1, true},
+		{4, 4, false},
+		{7, 4, true},
+		{8, 5, false},
+	}
+	for _, test := range tests {
+		index, found := list.BinarySearch(test[0].(int), utils.NumberComparator[int])
+		if index != test[1] || found != test[2] {
+			t.Errorf("Got %v %v expected %v %v", index, found, test[1], test[2])
+		}
+	}
+	if index, found := arraylist.New[int]().BinarySearch(1, utils.NumberComparator[int]); index != 0 || found {
+		t.Errorf("Got %v %v expected %v %v", index, found, 0, false)
+	}
+}
+
+func TestListCapacity(t *testing.T) {
+	list := arraylist.New[int]()
+	list.EnsureCapacity(100)
+	if actualValue, expectedValue := list.Capacity(), 101; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for n := 0; n < 100; n++ {
+		list.Add(n)
+	}
+	if actualValue, expectedValue := list.Capacity(), 101; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.Add(100) // grows once full to 2 * (101 + 1)
+	if actualValue, expectedValue := list.Capacity(), 204; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.EnsureCapacity(10)
+	list.RemoveRange(60, 101)
+	if actualValue, expectedValue := list.Capacity(), 204; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.TrimToSize()
+	if actualValue, expectedValue := fmt.Sprint(list.Capacity(), list.Size()), "60 60"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.RemoveRange(0, 45) // shrinks at 25% of capacity by default
+	if actualValue, expectedValue := list.Capacity(), 15; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListFactors(t *testing.T) {
+	list := arraylist.New[int]()
+	list.SetGrowthFactor(1.5)
+	list.SetShrinkFactor(0)
+	list.Add(1, 2, 3, 4)
+	if actualValue, expectedValue := list.Capacity(), 6; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.Add(5, 6, 7) // grows to 1.5 * (6 + 3)
+	if actualValue, expectedValue := list.Capacity(), 13; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.RemoveRange(0, 7)
+	if actualValue, expectedValue := list.Capacity(), 13; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	mapped := list.Map(func(index int, value int) int { return value })
+	mapped.Add(1, 2, 3, 4)
+	if actualValue, expectedValue := mapped.Capacity(), 6; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	list.SetShrinkFactor(0.5)
+	list.Add(1, 2, 3, 4, 5, 6)
+	list.Remove(0)
+	if actualValue, expectedValue := list.Capacity(), 5; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	for _, set := range []func(){
+		func() { list.SetGrowthFactor(0.5) },
+		func() { list.SetShrinkFactor(-0.5) },
+		func() { list.SetShrinkFactor(1) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Got %v expected a panic", r)
+				}
+			}()
+			set()
+		}()
+	}
+}
+
+func TestListAddAll(t *testing.T) {
+	list := arraylist.New("a")
+	list.AddAll(arraylist.New("b", "c"))
+	list.AddAll(list)
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[a b c a b c]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListSubList(t *testing.T) {
+	list := arraylist.New("a", "b", "c", "d", "e", "f")
+	sub := list.SubList(1, 4)
+	if actualValue, expectedValue := fmt.Sprint(sub.Values(), sub.Size()), "[b c d] 3"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, found := sub.Get(0); actualValue != "b" || !found {
+		t.Errorf("Got %v %v expected %v %v", actualValue, found, "b", true)
+	}
+	if _, found := sub.Get(3); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if actualValue, expectedValue := sub.IndexOf("e"), -1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	list.Set(2, "C") // not a structural modification
+	sub.Set(2, "D")
+	sub.Add("x")
+	sub.Insert(0, "y")
+	if actualValue, expectedValue := fmt.Sprint(sub.Values()), "[y b C D x]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[a y b C D x e f]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	nested := sub.SubList(1, 3)
+	nested.Sort(utils.StringComparator)
+	nested.Remove(0)
+	if actualValue, expectedValue := fmt.Sprint(nested.Values(), sub.Values()), "[b] [y b D x]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	nested.Clear()
+	if actualValue, expectedValue := fmt.Sprint(nested.Empty(), sub.Values(), list.Values()), "true [y D x] [a y D x e f]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := sub.String(), "SubList\ny, D, x"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.SubList(4, 10).Values(), list.SubList(3, 1).Size()), "[e f] 0"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	sub.Remove(0)
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	list.Add("g")
+	sub.Size()
+	t.Errorf("Shouldn't reach here")
+}
+
 func TestListEach(t *testing.T) {
 	list := arraylist.New[string]()
 	list.Add("a", "b", "c")
@@ -523,6 +824,82 @@ func TestListIteratorLast(t *testing.T) {
 	}
 }
 
+func TestListIteratorRemove(t *testing.T) {
+	list := arraylist.New("a", "b", "c", "d", "e")
+	it := list.Iterator()
+	for it.Next() {
+		if value := it.Value(); value == "b" || value == "d" {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[a c e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+	it.Begin()
+	it.Next()
+	it.Remove()
+	if actualValue := it.Next(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue, expectedValue := it.Value(), "c"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[c e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListIteratorConcurrentModification(t *testing.T) {
+	list := arraylist.New("a", "b", "c")
+	it := list.Iterator()
+	it.Next()
+	list.Add("d")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Next()
+	t.Errorf("Shouldn't reach here")
+}
+
+func TestListIteratorConcurrentModificationAtEnd(t *testing.T) {
+	list := arraylist.New("a", "b", "c")
+	it := list.Iterator()
+	list.Add("d") // a new iterator starts over from the first element
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+	for it.Next() {
+	}
+	list.Remove(0)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	// so does an iterator whose first element was removed through it
+	it.First()
+	it.Remove()
+	list.Add("e")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it.End()
+	list.Remove(0)
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Prev()
+	t.Errorf("Shouldn't reach here")
+}
+
 func TestListSerialization(t *testing.T) {
 	list := arraylist.New[string]()
 	list.Add("a", "b", "c")
@@ -549,6 +926,184 @@ func TestListSerialization(t *testing.T) {
 	assert()
 }
 
+func TestListBinarySerialization(t *testing.T) {
+	list := arraylist.New[string]()
+	list.Add("a", "b", "c")
+
+	data, err := list.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := arraylist.New[string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(list.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(list); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = arraylist.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(list.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestListJSONMarshaler(t *testing.T) {
+	list := arraylist.New[string]()
+	list.Add("b", "a", "c")
+
+	data, err := json.Marshal(struct{ List *arraylist.List[string] }{list})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"List":["b","a","c"]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ List arraylist.List[string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.List.Values()), fmt.Sprint(list.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"List":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestListJSONStream(t *testing.T) {
+	list := arraylist.New[string]()
+	list.Add("b", "a", "c")
+
+	var buffer bytes.Buffer
+	if err := list.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `["b","a","c"]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored arraylist.List[string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(list.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+// numbersReader generates the JSON array [0,1,...,n-1] as it is read
+type numbersReader struct {
+	next, n int
+	pending []byte
+}
+
+func (r *numbersReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		switch {
+		case r.next > r.n:
+			return 0, io.EOF
+		case r.next == r.n:
+			r.pending = []byte("]")
+		case r.next == 0:
+			r.pending = []byte("[0")
+		default:
+			r.pending = []byte("," + strconv.Itoa(r.next))
+		}
+		r.next++
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func TestListReadJSONStreaming(t *testing.T) {
+	list := arraylist.New[int]()
+	if err := list.ReadJSON(&numbersReader{n: 100000}); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := list.Size(), 100000; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, _ := list.Get(99999); actualValue != 99999 {
+		t.Errorf("Got %v expected %v", actualValue, 99999)
+	}
+
+	// the elements before an error are kept
+	if err := list.ReadJSON(strings.NewReader(`[1,2,"x",4]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[1 2]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListGraph(t *testing.T) {
+	list := arraylist.New("a", "b|c")
+
+	var builder strings.Builder
+	if err := list.ToDOT(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue := "digraph arraylist {\n\tordering=out;\n\tn0 [shape=record, label=\"a|b\\|c\"];\n}\n"
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	builder.Reset()
+	if err := list.ToMermaid(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue = "flowchart TD\n\t%% arraylist\n\tn0[\"a | b|c\"]\n"
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	graph := list.ToGraph(strings.ToUpper)
+	if actualValue, expectedValue := fmt.Sprint(graph.Nodes[0].Fields), "[A B|C]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.Clear()
+	if actualValue := len(list.ToGraph(nil).Nodes); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+}
+
+func TestListConformance(t *testing.T) {
+	containertest.TestList(t, func() lists.List[int] { return arraylist.New[int]() })
+	containertest.TestReverseIteratorWithIndex(t, func(values []int) containers.ReverseIteratorWithIndex[int] {
+		return arraylist.New(values...).Iterator()
+	})
+}
+
+func TestSubListConformance(t *testing.T) {
+	containertest.TestList(t, func() lists.List[int] { return arraylist.New(-1, -2).SubList(1, 1) })
+}
+
+func FuzzList(f *testing.F) {
+	containertest.FuzzList(f, func() lists.List[int] { return arraylist.New[int]() })
+}
+
+func FuzzSubList(f *testing.F) {
+	containertest.FuzzList(f, func() lists.List[int] { return arraylist.New(-1, -2).SubList(1, 1).SubList(0, 0) })
+}
+
 func benchmarkGet(b *testing.B, list *arraylist.List[int], size int) {
 	for i := 0; i < b.N; i++ {
 		for n := 0; n < size; n++ {
@@ -701,3 +1256,31 @@ func BenchmarkArrayListRemove100000(b *testing.B) {
 	b.StartTimer()
 	benchmarkRemove(b, list, size)
 }
+
+func BenchmarkArrayListRemoveIf10000(b *testing.B) {
+	size := 10000
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		list := arraylist.New[int]()
+		for n := 0; n < size; n++ {
+			list.Add(n)
+		}
+		b.StartTimer()
+		list.RemoveIf(func(value int) bool { return value%2 == 0 })
+	}
+}
+
+func BenchmarkArrayListRemoveLoop10000(b *testing.B) {
+	size := 10000
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		list := arraylist.New[int]()
+		for n := 0; n < size; n++ {
+			list.Add(n)
+		}
+		b.StartTimer()
+		for index := 0; index < list.Size(); index++ {
+			list.Remove(index)
+		}
+	}
+}
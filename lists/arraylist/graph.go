@@ -0,0 +1,44 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package arraylist
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Assert Graph implementation
+var _ containers.DOTWriter = (*List[int])(nil)
+var _ containers.MermaidWriter = (*List[int])(nil)
+
+// ToGraph returns the list as a graph of a single node, a record of the elements in the order of the array.
+// An empty list has no node. The label hook formats the label of an element, nil formats it with fmt.Sprint.
+func (l *List[V]) ToGraph(label func(value V) string) *containers.Graph {
+	if label == nil {
+		label = func(value V) string { return fmt.Sprint(value) }
+	}
+	graph := &containers.Graph{Name: "arraylist"}
+	if l.size == 0 {
+		return graph
+	}
+	fields := make([]string, l.size)
+	for i, value := range l.values[:l.size] {
+		fields[i] = label(value)
+	}
+	graph.AddNode(containers.GraphNode{Fields: fields})
+	return graph
+}
+
+// ToDOT writes the list to the writer in the DOT language of Graphviz, see ToGraph.
+func (l *List[V]) ToDOT(w io.Writer) error {
+	return l.ToGraph(nil).WriteDOT(w)
+}
+
+// ToMermaid writes the list to the writer as a Mermaid flowchart, see ToGraph.
+func (l *List[V]) ToMermaid(w io.Writer) error {
+	return l.ToGraph(nil).WriteMermaid(w)
+}
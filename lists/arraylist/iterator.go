@@ -9,16 +9,19 @@ import "github.com/monitor1379/yagods/containers"
 var _ containers.Iterator[int] = (*Iterator[int])(nil)
 var _ containers.IteratorWithIndex[int] = (*Iterator[int])(nil)
 var _ containers.ReverseIteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
 
 // Iterator holding the iterator's state
-type Iterator[V comparable] struct {
-	list  *List[V]
-	index int
+type Iterator[V any] struct {
+	list     *List[V]
+	index    int
+	modCount int  // list's modification count the iterator is synchronized with
+	removed  bool // current element was removed, index points to its predecessor
 }
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
 func (l *List[V]) Iterator() *Iterator[V] {
-	return &Iterator[V]{list: l, index: -1}
+	return &Iterator[V]{list: l, index: -1, modCount: l.modCount}
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -26,6 +29,8 @@ func (l *List[V]) Iterator() *Iterator[V] {
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
 // Modifies the state of the iterator.
 func (i *Iterator[V]) Next() bool {
+	i.checkModification()
+	i.removed = false
 	if i.index < i.list.size {
 		i.index++
 	}
@@ -36,6 +41,11 @@ func (i *Iterator[V]) Next() bool {
 // If Prev() returns true, then previous element's index and value can be retrieved by Index() and Value().
 // Modifies the state of the iterator.
 func (i *Iterator[V]) Prev() bool {
+	i.checkModification()
+	if i.removed {
+		i.removed = false
+		return i.list.withinRange(i.index)
+	}
 	if i.index >= 0 {
 		i.index--
 	}
@@ -58,12 +68,16 @@ func (i *Iterator[V]) Index() int {
 // Call Next() to fetch the first element if any.
 func (i *Iterator[V]) Begin() {
 	i.index = -1
+	i.modCount = i.list.modCount
+	i.removed = false
 }
 
 // End moves the iterator past the last element (one-past-the-end).
 // Call Prev() to fetch the last element if any.
 func (i *Iterator[V]) End() {
 	i.index = i.list.size
+	i.modCount = i.list.modCount
+	i.removed = false
 }
 
 // First moves the iterator to the first element and returns true if there was a first element in the container.
@@ -81,3 +95,36 @@ func (i *Iterator[V]) Last() bool {
 	i.End()
 	return i.Prev()
 }
+
+// Err returns containers.ErrConcurrentModification if the list was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (i *Iterator[V]) Err() error {
+	if i.modCount != i.list.modCount && (i.index != -1 || i.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current element from the list and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (i *Iterator[V]) Remove() {
+	i.checkModification()
+	if i.removed || !i.list.withinRange(i.index) {
+		return
+	}
+	i.list.Remove(i.index)
+	i.modCount = i.list.modCount
+	i.index--
+	i.removed = true
+}
+
+// checkModification panics if the list was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the list.
+func (i *Iterator[V]) checkModification() {
+	if err := i.Err(); err != nil {
+		panic(err)
+	}
+	i.modCount = i.list.modCount
+}
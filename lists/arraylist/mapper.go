@@ -5,8 +5,9 @@
 package arraylist
 
 // Map maps values from one list to another list with new type.
-func Map[V1 comparable, V2 comparable](l *List[V1], f func(index int, value V1) V2) *List[V2] {
-	newList := New[V2]()
+// The new list searches its values with the == operator, see utils.InterfaceEqual, and grows and shrinks like l.
+func Map[V1 any, V2 any](l *List[V1], f func(index int, value V1) V2) *List[V2] {
+	newList := &List[V2]{growthFactor: l.growthFactor, shrinkFactor: l.shrinkFactor}
 	iterator := l.Iterator()
 	for iterator.Next() {
 		newList.Add(f(iterator.Index(), iterator.Value()))
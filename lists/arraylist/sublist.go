@@ -0,0 +1,222 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package arraylist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/lists"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ lists.List[int] = (*SubList[int])(nil)
+
+// SubList is a live view of a range of a list. Reading the view reads the list and changes made through the view,
+// including adding and removing values, are made to the list, the view growing and shrinking along.
+// Any other structural change of the list, e.g. adding a value to the list itself, invalidates the view:
+// using it afterwards panics with containers.ErrConcurrentModification.
+type SubList[V any] struct {
+	list     *List[V]
+	parent   *SubList[V] // view the view was taken from, nil if taken from the list
+	offset   int         // index of the first value of the view in the list
+	size     int
+	modCount int // list's modification count the view is synchronized with
+}
+
+// SubList returns a live view of the values at the indexes within [from, to) of the list.
+// The range is clipped to the bounds of the list.
+func (l *List[V]) SubList(from, to int) *SubList[V] {
+	from, to = l.clip(from, to)
+	return &SubList[V]{list: l, offset: from, size: to - from, modCount: l.modCount}
+}
+
+// SubList returns a live view of the values at the indexes within [from, to) of the view.
+// The range is clipped to the bounds of the view. Changes made through the returned view update this view as well.
+func (s *SubList[V]) SubList(from, to int) *SubList[V] {
+	s.checkModification()
+	from, to = s.clip(from, to)
+	return &SubList[V]{list: s.list, parent: s, offset: s.offset + from, size: to - from, modCount: s.modCount}
+}
+
+// Add appends values at the end of the view, inserting them into the list after the last value of the view.
+func (s *SubList[V]) Add(values ...V) {
+	s.checkModification()
+	s.list.Insert(s.offset+s.size, values...)
+	s.resized(len(values))
+}
+
+// Get returns the value at the index of the view.
+// Second return parameter is true if index is within bounds of the view, otherwise false.
+func (s *SubList[V]) Get(index int) (V, bool) {
+	s.checkModification()
+	if !s.withinRange(index) {
+		var zeroV V
+		return zeroV, false
+	}
+	return s.list.values[s.offset+index], true
+}
+
+// Remove removes the value at the index of the view from the list.
+func (s *SubList[V]) Remove(index int) {
+	s.checkModification()
+	if !s.withinRange(index) {
+		return
+	}
+	s.list.Remove(s.offset + index)
+	s.resized(-1)
+}
+
+// Contains checks if values (one or more) are present in the view.
+// All values have to be present in the view for the method to return true.
+// Returns true if no arguments are passed at all.
+func (s *SubList[V]) Contains(values ...V) bool {
+	for _, value := range values {
+		if s.IndexOf(value) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexOf returns the index of the first occurrence of the value in the view, or -1 if there is none.
+func (s *SubList[V]) IndexOf(value V) int {
+	s.checkModification()
+	equal := s.list.equality()
+	for index, v := range s.window() {
+		if equal(v, value) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Sort sorts the values of the view (in-place) using the comparator, leaving the rest of the list as is.
+func (s *SubList[V]) Sort(comparator utils.Comparator[V]) {
+	s.checkModification()
+	if s.size < 2 {
+		return
+	}
+	utils.Sort(s.window(), comparator)
+}
+
+// Swap swaps the two values at the specified indexes of the view.
+func (s *SubList[V]) Swap(i, j int) {
+	s.checkModification()
+	if s.withinRange(i) && s.withinRange(j) {
+		s.list.Swap(s.offset+i, s.offset+j)
+	}
+}
+
+// Insert inserts values at the index of the view shifting the value at that index (if any) and any subsequent values
+// to the right. Does not do anything if index is negative or bigger than view's size.
+// Note: index equal to view's size is valid, i.e. append.
+func (s *SubList[V]) Insert(index int, values ...V) {
+	s.checkModification()
+	if index < 0 || index > s.size {
+		return
+	}
+	s.list.Insert(s.offset+index, values...)
+	s.resized(len(values))
+}
+
+// Set sets the value at the index of the view.
+// Does not do anything if index is negative or bigger than view's size.
+// Note: index equal to view's size is valid, i.e. append.
+func (s *SubList[V]) Set(index int, value V) {
+	s.checkModification()
+	if index == s.size {
+		s.Add(value)
+		return
+	}
+	if s.withinRange(index) {
+		s.list.Set(s.offset+index, value)
+	}
+}
+
+// Empty returns true if the view does not contain any values.
+func (s *SubList[V]) Empty() bool {
+	return s.Size() == 0
+}
+
+// Size returns number of values within the view.
+func (s *SubList[V]) Size() int {
+	s.checkModification()
+	return s.size
+}
+
+// Clear removes all values of the view from the list.
+func (s *SubList[V]) Clear() {
+	s.checkModification()
+	s.list.RemoveRange(s.offset, s.offset+s.size)
+	s.resized(-s.size)
+}
+
+// Values returns all values in the view.
+func (s *SubList[V]) Values() []V {
+	s.checkModification()
+	return append(make([]V, 0, s.size), s.window()...)
+}
+
+// InterfaceValues returns all values in the view with type interface{}.
+func (s *SubList[V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, 0, s.size)
+	for _, value := range s.Values() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// String returns a string representation of container
+func (s *SubList[V]) String() string {
+	str := "SubList\n"
+	values := []string{}
+	for _, value := range s.Values() {
+		values = append(values, fmt.Sprintf("%v", value))
+	}
+	str += strings.Join(values, ", ")
+	return str
+}
+
+// window returns the values of the view, sharing the memory of the list.
+func (s *SubList[V]) window() []V {
+	return s.list.values[s.offset : s.offset+s.size]
+}
+
+// Check that the index is within bounds of the view
+func (s *SubList[V]) withinRange(index int) bool {
+	return index >= 0 && index < s.size
+}
+
+// clip clips the range [from, to) to the bounds of the view.
+func (s *SubList[V]) clip(from, to int) (int, int) {
+	if from < 0 {
+		from = 0
+	}
+	if to > s.size {
+		to = s.size
+	}
+	if from > to {
+		from = to
+	}
+	return from, to
+}
+
+// resized adds delta to the size of the view and the views it was taken from,
+// after a change made through the view, and synchronizes them with the list.
+func (s *SubList[V]) resized(delta int) {
+	for view := s; view != nil; view = view.parent {
+		view.size += delta
+		view.modCount = s.list.modCount
+	}
+}
+
+// checkModification panics if the list was structurally modified other than through the view.
+func (s *SubList[V]) checkModification() {
+	if s.modCount != s.list.modCount {
+		panic(containers.ErrConcurrentModification)
+	}
+}
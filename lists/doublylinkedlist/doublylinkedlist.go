@@ -20,13 +20,15 @@ import (
 var _ lists.List[int] = (*List[int])(nil)
 
 // List holds the elements, where each element points to the next and previous element
-type List[V comparable] struct {
-	first *element[V]
-	last  *element[V]
-	size  int
+type List[V any] struct {
+	first    *element[V]
+	last     *element[V]
+	size     int
+	modCount int            // number of structural modifications, checked by iterators
+	equal    utils.Equal[V] // equality of the values, utils.InterfaceEqual if nil
 }
 
-type element[V comparable] struct {
+type element[V any] struct {
 	value V
 	prev  *element[V]
 	next  *element[V]
@@ -34,7 +36,13 @@ type element[V comparable] struct {
 
 // New instantiates a new list and adds the passed values, if any, to the list
 func New[V comparable](values ...V) *List[V] {
-	list := &List[V]{}
+	return NewWithEqual(utils.ComparableEqual[V], values...)
+}
+
+// NewWithEqual instantiates a new list searching its values with the equality function and adds the passed values,
+// if any, to the list. The values need not be comparable with ==.
+func NewWithEqual[V any](equal utils.Equal[V], values ...V) *List[V] {
+	list := &List[V]{equal: equal}
 	if len(values) > 0 {
 		list.Add(values...)
 	}
@@ -54,6 +62,7 @@ func (l *List[V]) Add(values ...V) {
 		}
 		l.size++
 	}
+	l.modCount++
 }
 
 // Append appends a value (one or more) at the end of the list (same as Add())
@@ -75,6 +84,7 @@ func (l *List[V]) Prepend(values ...V) {
 		}
 		l.size++
 	}
+	l.modCount++
 }
 
 // Get returns the element at index.
@@ -123,22 +133,7 @@ func (l *List[V]) Remove(index int) {
 		}
 	}
 
-	if element == l.first {
-		l.first = element.next
-	}
-	if element == l.last {
-		l.last = element.prev
-	}
-	if element.prev != nil {
-		element.prev.next = element.next
-	}
-	if element.next != nil {
-		element.next.prev = element.prev
-	}
-
-	element = nil
-
-	l.size--
+	l.removeElement(element)
 }
 
 // Contains check if values (one or more) are present in the set.
@@ -153,10 +148,11 @@ func (l *List[V]) Contains(values ...V) bool {
 	if l.size == 0 {
 		return false
 	}
+	equal := l.equality()
 	for _, value := range values {
 		found := false
 		for element := l.first; element != nil; element = element.next {
-			if element.value == value {
+			if equal(element.value, value) {
 				found = true
 				break
 			}
@@ -191,8 +187,24 @@ func (l *List[V]) IndexOf(value V) int {
 	if l.size == 0 {
 		return -1
 	}
+	equal := l.equality()
 	for index, element := range l.Values() {
-		if element == value {
+		if equal(element, value) {
+			return index
+		}
+	}
+	return -1
+}
+
+// ContainsFunc returns true if the function returns true for any element in the list.
+func (l *List[V]) ContainsFunc(f func(value V) bool) bool {
+	return l.IndexFunc(f) >= 0
+}
+
+// IndexFunc returns the index of the first element for which the function returns true, or -1 if there is none.
+func (l *List[V]) IndexFunc(f func(value V) bool) int {
+	for index, element := 0, l.first; element != nil; index, element = index+1, element.next {
+		if f(element.value) {
 			return index
 		}
 	}
@@ -214,6 +226,7 @@ func (l *List[V]) Clear() {
 	l.size = 0
 	l.first = nil
 	l.last = nil
+	l.modCount++
 }
 
 // Sort sorts values (in-place) using.
@@ -261,22 +274,25 @@ func (l *List[V]) Insert(index int, values ...V) {
 		return
 	}
 
-	l.size += len(values)
+	if len(values) == 0 {
+		return
+	}
 
-	var beforeElement *element[V]
 	var foundElement *element[V]
 	// determine traversal direction, last to first or first to last
 	if l.size-index < index {
 		foundElement = l.last
 		for e := l.size - 1; e != index; e, foundElement = e-1, foundElement.prev {
-			beforeElement = foundElement.prev
 		}
 	} else {
 		foundElement = l.first
 		for e := 0; e != index; e, foundElement = e+1, foundElement.next {
-			beforeElement = foundElement
 		}
 	}
+	beforeElement := foundElement.prev
+
+	l.size += len(values)
+	l.modCount++
 
 	if foundElement == l.first {
 		oldNextElement := l.first
@@ -347,6 +363,32 @@ func (l *List[V]) String() string {
 	return str
 }
 
+// removeElement unlinks the element from the list
+func (l *List[V]) removeElement(element *element[V]) {
+	if element == l.first {
+		l.first = element.next
+	}
+	if element == l.last {
+		l.last = element.prev
+	}
+	if element.prev != nil {
+		element.prev.next = element.next
+	}
+	if element.next != nil {
+		element.next.prev = element.prev
+	}
+	l.size--
+	l.modCount++
+}
+
+// equality returns the equality function searching the elements of the list.
+func (l *List[V]) equality() utils.Equal[V] {
+	if l.equal == nil {
+		return utils.InterfaceEqual[V]
+	}
+	return l.equal
+}
+
 // Check that the index is within bounds of the list
 func (l *List[V]) withinRange(index int) bool {
 	return index >= 0 && index < l.size
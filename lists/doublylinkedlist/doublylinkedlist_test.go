@@ -5,9 +5,17 @@
 package doublylinkedlist_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/lists"
 	"github.com/monitor1379/yagods/lists/doublylinkedlist"
 	"github.com/monitor1379/yagods/utils"
 )
@@ -152,6 +160,55 @@ func TestListContains(t *testing.T) {
 	}
 }
 
+func TestListNewWithEqual(t *testing.T) {
+	list := doublylinkedlist.NewWithEqual(bytes.Equal, []byte("a"), []byte("b"))
+	list.Add([]byte("c"))
+	if actualValue := list.Contains([]byte("c"), []byte("a")); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := list.Contains([]byte("d")); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue := list.IndexOf([]byte("b")); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	selected := list.Select(func(index int, value []byte) bool { return index > 0 })
+	if actualValue := selected.IndexOf([]byte("c")); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	lengths := doublylinkedlist.Map(list, func(index int, value []byte) []int { return []int{index, len(value)} })
+	if actualValue, expectedValue := fmt.Sprint(lengths.Values()), "[[0 1] [1 1] [2 1]]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var zero doublylinkedlist.List[string]
+	zero.Add("a", "b")
+	if actualValue := zero.IndexOf("b"); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+}
+
+func TestListContainsFuncIndexFunc(t *testing.T) {
+	list := doublylinkedlist.New[string]()
+	isB := func(value string) bool { return value == "b" }
+	if actualValue := list.ContainsFunc(isB); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue := list.IndexFunc(isB); actualValue != -1 {
+		t.Errorf("Got %v expected %v", actualValue, -1)
+	}
+	list.Add("a", "b", "c", "b")
+	if actualValue := list.ContainsFunc(isB); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := list.IndexFunc(isB); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	if actualValue := list.IndexFunc(func(value string) bool { return value > "b" }); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+}
+
 func TestListValues(t *testing.T) {
 	list := doublylinkedlist.New[string]()
 	list.Add("a")
@@ -527,6 +584,64 @@ func TestListIteratorLast(t *testing.T) {
 	}
 }
 
+func TestListIteratorRemove(t *testing.T) {
+	list := doublylinkedlist.New("a", "b", "c", "d", "e")
+	it := list.Iterator()
+	for it.Next() {
+		if value := it.Value(); value == "b" || value == "d" {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[a c e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+	it.Begin()
+	it.Next()
+	it.Remove()
+	if actualValue := it.Next(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue, expectedValue := it.Value(), "c"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[c e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListIteratorConcurrentModification(t *testing.T) {
+	list := doublylinkedlist.New("a", "b", "c")
+	it := list.Iterator()
+	it.Next()
+	list.Add("d")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Next()
+	t.Errorf("Shouldn't reach here")
+}
+
+func TestListIteratorRemoveReverse(t *testing.T) {
+	list := doublylinkedlist.New("a", "b", "c", "d", "e")
+	it := list.Iterator()
+	for it.End(); it.Prev(); {
+		if value := it.Value(); value == "a" || value == "c" || value == "e" {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[b d]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestListSerialization(t *testing.T) {
 	list := doublylinkedlist.New[string]()
 	list.Add("a", "b", "c")
@@ -553,6 +668,145 @@ func TestListSerialization(t *testing.T) {
 	assert()
 }
 
+func TestListBinarySerialization(t *testing.T) {
+	list := doublylinkedlist.New[string]()
+	list.Add("a", "b", "c")
+
+	data, err := list.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := doublylinkedlist.New[string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(list.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(list); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = doublylinkedlist.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(list.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestListJSONMarshaler(t *testing.T) {
+	list := doublylinkedlist.New[string]()
+	list.Add("b", "a", "c")
+
+	data, err := json.Marshal(struct {
+		List *doublylinkedlist.List[string]
+	}{list})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"List":["b","a","c"]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ List doublylinkedlist.List[string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.List.Values()), fmt.Sprint(list.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"List":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestListJSONStream(t *testing.T) {
+	list := doublylinkedlist.New[string]()
+	list.Add("b", "a", "c")
+
+	var buffer bytes.Buffer
+	if err := list.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `["b","a","c"]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored doublylinkedlist.List[string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(list.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestListGraph(t *testing.T) {
+	list := doublylinkedlist.New("a", "b", "c")
+
+	var builder strings.Builder
+	if err := list.ToDOT(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue := `digraph doublylinkedlist {
+	ordering=out;
+	rankdir=LR;
+	n0 [label="a"];
+	n1 [label="b"];
+	n2 [label="c"];
+	n0 -> n1 [dir=both];
+	n1 -> n2 [dir=both];
+}
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	builder.Reset()
+	if err := list.ToMermaid(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue = `flowchart LR
+	%% doublylinkedlist
+	n0["a"]
+	n1["b"]
+	n2["c"]
+	n0 <--> n1
+	n1 <--> n2
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	graph := list.ToGraph(strings.ToUpper)
+	if actualValue, expectedValue := graph.Nodes[2].Label, "C"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListConformance(t *testing.T) {
+	containertest.TestList(t, func() lists.List[int] { return doublylinkedlist.New[int]() })
+	containertest.TestReverseIteratorWithIndex(t, func(values []int) containers.ReverseIteratorWithIndex[int] {
+		it := doublylinkedlist.New(values...).Iterator()
+		return &it
+	})
+}
+
+func FuzzList(f *testing.F) {
+	containertest.FuzzList(f, func() lists.List[int] { return doublylinkedlist.New[int]() })
+}
+
 func benchmarkGet(b *testing.B, list *doublylinkedlist.List[int], size int) {
 	for i := 0; i < b.N; i++ {
 		for n := 0; n < size; n++ {
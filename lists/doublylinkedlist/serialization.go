@@ -5,13 +5,24 @@
 package doublylinkedlist
 
 import (
+	"encoding"
+	"encoding/gob"
 	"encoding/json"
+	"io"
 
 	"github.com/monitor1379/yagods/containers"
 )
 
 var _ containers.JSONSerializer = (*List[int])(nil)
 var _ containers.JSONDeserializer = (*List[int])(nil)
+var _ encoding.BinaryMarshaler = (*List[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*List[int])(nil)
+var _ gob.GobEncoder = (*List[int])(nil)
+var _ gob.GobDecoder = (*List[int])(nil)
+var _ json.Marshaler = (*List[int])(nil)
+var _ json.Unmarshaler = (*List[int])(nil)
+var _ containers.JSONWriter = (*List[int])(nil)
+var _ containers.JSONReader = (*List[int])(nil)
 
 // ToJSON outputs the JSON representation of list's elements.
 func (l *List[V]) ToJSON() ([]byte, error) {
@@ -28,3 +39,61 @@ func (l *List[V]) FromJSON(data []byte) error {
 	}
 	return err
 }
+
+// MarshalBinary outputs the binary representation of list's elements.
+// Elements are encoded by the codec of containers.CodecFor.
+func (l *List[V]) MarshalBinary() ([]byte, error) {
+	return containers.EncodeValues(containers.CodecFor[V](), l.Values())
+}
+
+// UnmarshalBinary populates list's elements from the input binary representation.
+func (l *List[V]) UnmarshalBinary(data []byte) error {
+	values, err := containers.DecodeValues(containers.CodecFor[V](), data)
+	if err == nil {
+		l.Clear()
+		l.Add(values...)
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the list for encoding/gob, see MarshalBinary.
+func (l *List[V]) GobEncode() ([]byte, error) {
+	return l.MarshalBinary()
+}
+
+// GobDecode populates the list from the binary representation for encoding/gob, see UnmarshalBinary.
+func (l *List[V]) GobDecode(data []byte) error {
+	return l.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the list for encoding/json: an array of its elements in order.
+func (l *List[V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Values())
+}
+
+// UnmarshalJSON populates the list from the JSON representation for encoding/json, see MarshalJSON.
+func (l *List[V]) UnmarshalJSON(data []byte) error {
+	var values []V
+	err := json.Unmarshal(data, &values)
+	if err == nil {
+		l.Clear()
+		l.Add(values...)
+	}
+	return err
+}
+
+// WriteJSON writes the JSON representation of the list to the writer, encoding one element at a time.
+func (l *List[V]) WriteJSON(w io.Writer) error {
+	it := l.Iterator()
+	return containers.WriteJSONValues[V](w, &it)
+}
+
+// ReadJSON populates the list from the JSON representation read from the reader, adding every element
+// as soon as it is decoded, so that the input does not need to fit in memory next to the list.
+// On error, the list holds the elements read before the error.
+func (l *List[V]) ReadJSON(r io.Reader) error {
+	l.Clear()
+	return containers.ReadJSONValues(r, func(value V) {
+		l.Add(value)
+	})
+}
@@ -19,7 +19,7 @@ func (l *List[V]) Each(f func(index int, value V)) {
 // Map invokes the given function once for each element and returns a
 // container containing the values returned by the given function.
 func (l *List[V]) Map(f func(index int, value V) V) *List[V] {
-	newList := &List[V]{}
+	newList := &List[V]{equal: l.equal}
 	iterator := l.Iterator()
 	for iterator.Next() {
 		newList.Add(f(iterator.Index(), iterator.Value()))
@@ -29,7 +29,7 @@ func (l *List[V]) Map(f func(index int, value V) V) *List[V] {
 
 // Select returns a new container containing all elements for which the given function returns a true value.
 func (l *List[V]) Select(f func(index int, value V) bool) *List[V] {
-	newList := &List[V]{}
+	newList := &List[V]{equal: l.equal}
 	iterator := l.Iterator()
 	for iterator.Next() {
 		if f(iterator.Index(), iterator.Value()) {
@@ -0,0 +1,42 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package singlylinkedlist
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Assert Graph implementation
+var _ containers.DOTWriter = (*List[int])(nil)
+var _ containers.MermaidWriter = (*List[int])(nil)
+
+// ToGraph returns the list as a chain of its elements, each one with an edge to the next.
+// The label hook formats the label of an element, nil formats it with fmt.Sprint.
+func (l *List[V]) ToGraph(label func(value V) string) *containers.Graph {
+	if label == nil {
+		label = func(value V) string { return fmt.Sprint(value) }
+	}
+	graph := &containers.Graph{Name: "singlylinkedlist", Horizontal: true}
+	for element := l.first; element != nil; element = element.next {
+		index := graph.AddNode(containers.GraphNode{Label: label(element.value)})
+		if index > 0 {
+			graph.AddEdge(index-1, index)
+		}
+	}
+	return graph
+}
+
+// ToDOT writes the list to the writer in the DOT language of Graphviz, see ToGraph.
+func (l *List[V]) ToDOT(w io.Writer) error {
+	return l.ToGraph(nil).WriteDOT(w)
+}
+
+// ToMermaid writes the list to the writer as a Mermaid flowchart, see ToGraph.
+func (l *List[V]) ToMermaid(w io.Writer) error {
+	return l.ToGraph(nil).WriteMermaid(w)
+}
@@ -7,17 +7,21 @@ package singlylinkedlist
 import "github.com/monitor1379/yagods/containers"
 
 var _ containers.IteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
 
 // Iterator holding the iterator's state
-type Iterator[V comparable] struct {
-	list    *List[V]
-	index   int
-	element *element[V]
+type Iterator[V any] struct {
+	list     *List[V]
+	index    int
+	element  *element[V]
+	previous *element[V] // predecessor of element, needed to unlink it
+	modCount int         // list's modification count the iterator is synchronized with
+	removed  bool        // current element was removed, element points to its predecessor
 }
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
 func (l *List[V]) Iterator() Iterator[V] {
-	return Iterator[V]{list: l, index: -1, element: nil}
+	return Iterator[V]{list: l, index: -1, element: nil, modCount: l.modCount}
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -25,16 +29,21 @@ func (l *List[V]) Iterator() Iterator[V] {
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
 // Modifies the state of the iterator.
 func (iterator *Iterator[V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
 	if iterator.index < iterator.list.size {
 		iterator.index++
 	}
 	if !iterator.list.withinRange(iterator.index) {
 		iterator.element = nil
+		iterator.previous = nil
 		return false
 	}
 	if iterator.index == 0 {
+		iterator.previous = nil
 		iterator.element = iterator.list.first
 	} else {
+		iterator.previous = iterator.element
 		iterator.element = iterator.element.next
 	}
 	return true
@@ -57,6 +66,9 @@ func (iterator *Iterator[V]) Index() int {
 func (iterator *Iterator[V]) Begin() {
 	iterator.index = -1
 	iterator.element = nil
+	iterator.previous = nil
+	iterator.modCount = iterator.list.modCount
+	iterator.removed = false
 }
 
 // First moves the iterator to the first element and returns true if there was a first element in the container.
@@ -66,3 +78,38 @@ func (iterator *Iterator[V]) First() bool {
 	iterator.Begin()
 	return iterator.Next()
 }
+
+// Err returns containers.ErrConcurrentModification if the list was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Err() error {
+	if iterator.modCount != iterator.list.modCount && (iterator.index != -1 || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current element from the list and keeps the iterator valid.
+// Next() moves to the element that followed the removed one.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || !iterator.list.withinRange(iterator.index) {
+		return
+	}
+	iterator.list.removeElement(iterator.previous, iterator.element)
+	iterator.modCount = iterator.list.modCount
+	iterator.element = iterator.previous
+	iterator.previous = nil
+	iterator.index--
+	iterator.removed = true
+}
+
+// checkModification panics if the list was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the list.
+func (iterator *Iterator[V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.list.modCount
+}
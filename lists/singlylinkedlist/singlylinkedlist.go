@@ -20,20 +20,28 @@ import (
 var _ lists.List[int] = (*List[int])(nil)
 
 // List holds the elements, where each element points to the next element
-type List[V comparable] struct {
-	first *element[V]
-	last  *element[V]
-	size  int
+type List[V any] struct {
+	first    *element[V]
+	last     *element[V]
+	size     int
+	modCount int            // number of structural modifications, checked by iterators
+	equal    utils.Equal[V] // equality of the values, utils.InterfaceEqual if nil
 }
 
-type element[V comparable] struct {
+type element[V any] struct {
 	value V
 	next  *element[V]
 }
 
 // New instantiates a new list and adds the passed values, if any, to the list
 func New[V comparable](values ...V) *List[V] {
-	list := &List[V]{}
+	return NewWithEqual(utils.ComparableEqual[V], values...)
+}
+
+// NewWithEqual instantiates a new list searching its values with the equality function and adds the passed values,
+// if any, to the list. The values need not be comparable with ==.
+func NewWithEqual[V any](equal utils.Equal[V], values ...V) *List[V] {
+	list := &List[V]{equal: equal}
 	if len(values) > 0 {
 		list.Add(values...)
 	}
@@ -53,6 +61,7 @@ func (l *List[V]) Add(values ...V) {
 		}
 		l.size++
 	}
+	l.modCount++
 }
 
 // Append appends a value (one or more) at the end of the list (same as Add())
@@ -71,6 +80,7 @@ func (l *List[V]) Prepend(values ...V) {
 		}
 		l.size++
 	}
+	l.modCount++
 }
 
 // Get returns the element at index.
@@ -106,19 +116,7 @@ func (l *List[V]) Remove(index int) {
 		beforeElement = element
 	}
 
-	if element == l.first {
-		l.first = element.next
-	}
-	if element == l.last {
-		l.last = beforeElement
-	}
-	if beforeElement != nil {
-		beforeElement.next = element.next
-	}
-
-	element = nil
-
-	l.size--
+	l.removeElement(beforeElement, element)
 }
 
 // Contains checks if values (one or more) are present in the set.
@@ -133,10 +131,11 @@ func (l *List[V]) Contains(values ...V) bool {
 	if l.size == 0 {
 		return false
 	}
+	equal := l.equality()
 	for _, value := range values {
 		found := false
 		for element := l.first; element != nil; element = element.next {
-			if element.value == value {
+			if equal(element.value, value) {
 				found = true
 				break
 			}
@@ -171,8 +170,24 @@ func (l *List[V]) IndexOf(value V) int {
 	if l.size == 0 {
 		return -1
 	}
+	equal := l.equality()
 	for index, element := range l.Values() {
-		if element == value {
+		if equal(element, value) {
+			return index
+		}
+	}
+	return -1
+}
+
+// ContainsFunc returns true if the function returns true for any element in the list.
+func (l *List[V]) ContainsFunc(f func(value V) bool) bool {
+	return l.IndexFunc(f) >= 0
+}
+
+// IndexFunc returns the index of the first element for which the function returns true, or -1 if there is none.
+func (l *List[V]) IndexFunc(f func(value V) bool) int {
+	for index, element := 0, l.first; element != nil; index, element = index+1, element.next {
+		if f(element.value) {
 			return index
 		}
 	}
@@ -194,6 +209,7 @@ func (l *List[V]) Clear() {
 	l.size = 0
 	l.first = nil
 	l.last = nil
+	l.modCount++
 }
 
 // Sort sort values (in-place) using.
@@ -241,7 +257,12 @@ func (l *List[V]) Insert(index int, values ...V) {
 		return
 	}
 
+	if len(values) == 0 {
+		return
+	}
+
 	l.size += len(values)
+	l.modCount++
 
 	var beforeElement *element[V]
 	foundElement := l.first
@@ -303,6 +324,29 @@ func (l *List[V]) String() string {
 	return str
 }
 
+// removeElement unlinks the element from the list, beforeElement is its predecessor (nil for the first element)
+func (l *List[V]) removeElement(beforeElement *element[V], element *element[V]) {
+	if element == l.first {
+		l.first = element.next
+	}
+	if element == l.last {
+		l.last = beforeElement
+	}
+	if beforeElement != nil {
+		beforeElement.next = element.next
+	}
+	l.size--
+	l.modCount++
+}
+
+// equality returns the equality function searching the elements of the list.
+func (l *List[V]) equality() utils.Equal[V] {
+	if l.equal == nil {
+		return utils.InterfaceEqual[V]
+	}
+	return l.equal
+}
+
 // Check that the index is within bounds of the list
 func (l *List[V]) withinRange(index int) bool {
 	return index >= 0 && index < l.size
@@ -0,0 +1,87 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sortedlist
+
+import "github.com/monitor1379/yagods/containers"
+
+var _ containers.EnumerableWithIndex[*List[int], int] = (*List[int])(nil)
+
+// Each calls the given function once for each element, passing that element's index and value.
+func (l *List[V]) Each(f func(index int, value V)) {
+	iterator := l.Iterator()
+	for iterator.Next() {
+		f(iterator.Index(), iterator.Value())
+	}
+}
+
+// Map invokes the given function once for each element and returns a
+// container containing the values returned by the given function, sorted as the list is.
+func (l *List[V]) Map(f func(index int, value V) V) *List[V] {
+	values := make([]V, 0, l.size)
+	iterator := l.Iterator()
+	for iterator.Next() {
+		values = append(values, f(iterator.Index(), iterator.Value()))
+	}
+	newList := l.empty()
+	newList.Add(values...)
+	return newList
+}
+
+// Select returns a new container containing all elements for which the given function returns a true value.
+func (l *List[V]) Select(f func(index int, value V) bool) *List[V] {
+	var values []V
+	iterator := l.Iterator()
+	for iterator.Next() {
+		if f(iterator.Index(), iterator.Value()) {
+			values = append(values, iterator.Value())
+		}
+	}
+	newList := l.empty()
+	newList.rebuild(values) // the selected values are in order already
+	return newList
+}
+
+// Any passes each element of the collection to the given function and
+// returns true if the function ever returns true for any element.
+func (l *List[V]) Any(f func(index int, value V) bool) bool {
+	iterator := l.Iterator()
+	for iterator.Next() {
+		if f(iterator.Index(), iterator.Value()) {
+			return true
+		}
+	}
+	return false
+}
+
+// All passes each element of the collection to the given function and
+// returns true if the function returns true for all elements.
+func (l *List[V]) All(f func(index int, value V) bool) bool {
+	iterator := l.Iterator()
+	for iterator.Next() {
+		if !f(iterator.Index(), iterator.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find passes each element of the container to the given function and returns
+// the first (index,value) for which the function is true or -1,nil otherwise
+// if no element matches the criteria.
+func (l *List[V]) Find(f func(index int, value V) bool) (int, V, bool) {
+	iterator := l.Iterator()
+	for iterator.Next() {
+		if f(iterator.Index(), iterator.Value()) {
+			return iterator.Index(), iterator.Value(), true
+		}
+	}
+	var zeroV V
+	return -1, zeroV, false
+}
+
+// empty returns a new empty list ordering the values as the list does.
+func (l *List[V]) empty() *List[V] {
+	return &List[V]{comparator: l.comparator, load: l.load}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sortedlist
+
+import "github.com/monitor1379/yagods/containers"
+
+var _ containers.Iterator[int] = (*Iterator[int])(nil)
+var _ containers.IteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.ReverseIteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
+
+// Iterator holding the iterator's state
+type Iterator[V any] struct {
+	list     *List[V]
+	index    int
+	chunk    int  // index of the chunk holding the current value
+	position int  // position of the current value in its chunk
+	modCount int  // list's modification count the iterator is synchronized with
+	removed  bool // current element was removed, index points to its predecessor
+}
+
+// Iterator returns a stateful iterator whose values can be fetched by an index.
+func (l *List[V]) Iterator() *Iterator[V] {
+	return &Iterator[V]{list: l, index: -1, modCount: l.modCount}
+}
+
+// IteratorAt returns a stateful iterator whose values can be fetched by an index that is initialised
+// at the first value greater than or equal to the given value (see Seek()).
+func (l *List[V]) IteratorAt(value V) *Iterator[V] {
+	iterator := l.Iterator()
+	iterator.Seek(value)
+	return iterator
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the container.
+// If Next() returns true, then next element's index and value can be retrieved by Index() and Value().
+// If Next() was called for the first time, then it will point the iterator to the first element if it exists.
+// Modifies the state of the iterator.
+func (i *Iterator[V]) Next() bool {
+	i.checkModification()
+	wasWithinRange := !i.removed && i.list.withinRange(i.index)
+	i.removed = false
+	if i.index < i.list.size {
+		i.index++
+	}
+	if wasWithinRange && i.position+1 < len(i.list.chunks[i.chunk]) {
+		// the next value is in the same chunk
+		i.position++
+		return true
+	}
+	return i.locate()
+}
+
+// Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
+// If Prev() returns true, then previous element's index and value can be retrieved by Index() and Value().
+// Modifies the state of the iterator.
+func (i *Iterator[V]) Prev() bool {
+	i.checkModification()
+	wasWithinRange := !i.removed && i.list.withinRange(i.index)
+	if i.removed {
+		i.removed = false
+	} else if i.index >= 0 {
+		i.index--
+	}
+	if wasWithinRange && i.position > 0 {
+		i.position--
+		return true
+	}
+	return i.locate()
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (i *Iterator[V]) Value() V {
+	return i.list.chunks[i.chunk][i.position]
+}
+
+// Index returns the current element's index.
+// Does not modify the state of the iterator.
+func (i *Iterator[V]) Index() int {
+	return i.index
+}
+
+// Begin resets the iterator to its initial state (one-before-first)
+// Call Next() to fetch the first element if any.
+func (i *Iterator[V]) Begin() {
+	i.index = -1
+	i.modCount = i.list.modCount
+	i.removed = false
+}
+
+// End moves the iterator past the last element (one-past-the-end).
+// Call Prev() to fetch the last element if any.
+func (i *Iterator[V]) End() {
+	i.index = i.list.size
+	i.modCount = i.list.modCount
+	i.removed = false
+}
+
+// First moves the iterator to the first element and returns true if there was a first element in the container.
+// If First() returns true, then first element's index and value can be retrieved by Index() and Value().
+// Modifies the state of the iterator.
+func (i *Iterator[V]) First() bool {
+	i.Begin()
+	return i.Next()
+}
+
+// Last moves the iterator to the last element and returns true if there was a last element in the container.
+// If Last() returns true, then last element's index and value can be retrieved by Index() and Value().
+// Modifies the state of the iterator.
+func (i *Iterator[V]) Last() bool {
+	i.End()
+	return i.Prev()
+}
+
+// Seek moves the iterator to the first value greater than or equal to the given value
+// and returns true if there was such a value in the list.
+// If Seek() returns true, then value's index and value can be retrieved by Index() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Modifies the state of the iterator.
+func (i *Iterator[V]) Seek(value V) bool {
+	i.End()
+	i.index = i.list.LowerBound(value)
+	return i.locate()
+}
+
+// SeekPrev moves the iterator to the last value less than or equal to the given value
+// and returns true if there was such a value in the list.
+// If SeekPrev() returns true, then value's index and value can be retrieved by Index() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Modifies the state of the iterator.
+func (i *Iterator[V]) SeekPrev(value V) bool {
+	i.Begin()
+	i.index = i.list.UpperBound(value) - 1
+	return i.locate()
+}
+
+// Err returns containers.ErrConcurrentModification if the list was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (i *Iterator[V]) Err() error {
+	if i.modCount != i.list.modCount && (i.index != -1 || i.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current element from the list and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (i *Iterator[V]) Remove() {
+	i.checkModification()
+	if i.removed || !i.list.withinRange(i.index) {
+		return
+	}
+	i.list.removeAt(i.chunk, i.position)
+	i.modCount = i.list.modCount
+	i.index--
+	i.removed = true
+}
+
+// locate points the iterator to the value at its index and returns true if the index is within the list.
+func (i *Iterator[V]) locate() bool {
+	if !i.list.withinRange(i.index) {
+		return false
+	}
+	i.chunk, i.position = i.list.locate(i.index)
+	return true
+}
+
+// checkModification panics if the list was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the list.
+func (i *Iterator[V]) checkModification() {
+	if err := i.Err(); err != nil {
+		panic(err)
+	}
+	i.modCount = i.list.modCount
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sortedlist
+
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ containers.JSONSerializer = (*List[int])(nil)
+var _ containers.JSONDeserializer = (*List[int])(nil)
+var _ encoding.BinaryMarshaler = (*List[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*List[int])(nil)
+var _ gob.GobEncoder = (*List[int])(nil)
+var _ gob.GobDecoder = (*List[int])(nil)
+var _ json.Marshaler = (*List[int])(nil)
+var _ json.Unmarshaler = (*List[int])(nil)
+var _ containers.JSONWriter = (*List[int])(nil)
+var _ containers.JSONReader = (*List[int])(nil)
+
+// ToJSON outputs the JSON representation of list's elements, see MarshalJSON.
+func (l *List[V]) ToJSON() ([]byte, error) {
+	return l.MarshalJSON()
+}
+
+// FromJSON populates list's elements from the input JSON representation, see UnmarshalJSON.
+func (l *List[V]) FromJSON(data []byte) error {
+	return l.UnmarshalJSON(data)
+}
+
+// MarshalBinary outputs the binary representation of list's elements in order.
+// Elements are encoded by the codec of containers.CodecFor.
+func (l *List[V]) MarshalBinary() ([]byte, error) {
+	return containers.EncodeValues(containers.CodecFor[V](), l.Values())
+}
+
+// UnmarshalBinary populates list's elements from the input binary representation, sorting them.
+// The zero value of a list is ordered by default comparators, see utils.DefaultComparator;
+// returns containers.ErrNoComparator if there are none.
+func (l *List[V]) UnmarshalBinary(data []byte) error {
+	if err := l.initZero(); err != nil {
+		return err
+	}
+	values, err := containers.DecodeValues(containers.CodecFor[V](), data)
+	if err == nil {
+		l.Clear()
+		l.Add(values...)
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the list for encoding/gob, see MarshalBinary.
+func (l *List[V]) GobEncode() ([]byte, error) {
+	return l.MarshalBinary()
+}
+
+// GobDecode populates the list from the binary representation for encoding/gob, see UnmarshalBinary.
+func (l *List[V]) GobDecode(data []byte) error {
+	return l.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the list for encoding/json: an array of its elements in order.
+func (l *List[V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Values())
+}
+
+// UnmarshalJSON populates the list from the JSON representation for encoding/json, see MarshalJSON.
+// The elements need not be in order. Like UnmarshalBinary, it accepts the zero value of a list.
+func (l *List[V]) UnmarshalJSON(data []byte) error {
+	if err := l.initZero(); err != nil {
+		return err
+	}
+	var values []V
+	err := json.Unmarshal(data, &values)
+	if err == nil {
+		l.Clear()
+		l.Add(values...)
+	}
+	return err
+}
+
+// initZero sets the comparator and the load of a list that was not instantiated by a constructor,
+// ordering it by the default comparator of its values.
+func (l *List[V]) initZero() error {
+	if l.comparator == nil {
+		comparator := utils.DefaultComparator[V]()
+		if comparator == nil {
+			return containers.ErrNoComparator
+		}
+		l.comparator, l.load = comparator, DefaultLoad
+	}
+	return nil
+}
+
+// WriteJSON writes the JSON representation of the list to the writer in order, encoding one element at a time.
+func (l *List[V]) WriteJSON(w io.Writer) error {
+	it := l.Iterator()
+	return containers.WriteJSONValues[V](w, it)
+}
+
+// ReadJSON populates the list from the JSON representation read from the reader, sorting the elements
+// once they are all decoded. Like UnmarshalJSON, it accepts the zero value of a list.
+// On error, the list holds the elements read before the error.
+func (l *List[V]) ReadJSON(r io.Reader) error {
+	if err := l.initZero(); err != nil {
+		return err
+	}
+	var values []V
+	err := containers.ReadJSONValues(r, func(value V) {
+		values = append(values, value)
+	})
+	l.Clear()
+	l.Add(values...)
+	return err
+}
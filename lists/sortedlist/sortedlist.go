@@ -0,0 +1,423 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sortedlist implements a list that keeps its values sorted by a comparator.
+//
+// The list may hold equal values, a value being added after the values equal to it.
+// Values are kept in a sequence of sorted chunks of at most twice the load values each, along with the maximum
+// and the index of the first value of every chunk, as in the sortedcontainers library of Python.
+// Adding or removing a value binary searches the maximums for its chunk and the chunk for its position,
+// and shifts the values of that chunk only, so that it remains fast for large lists.
+// A chunk that grows beyond twice the load is split in halves, and one that shrinks below half the load is merged
+// with its neighbour. Get(index) binary searches the indexes of the chunks, in O(log n) time.
+//
+// Structure is not thread safe.
+//
+// Reference: https://grantjenks.com/docs/sortedcontainers/implementation.html
+package sortedlist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ containers.Container[int] = (*List[int])(nil)
+
+// DefaultLoad is the load of lists instantiated without one, see NewWithLoad.
+const DefaultLoad = 512
+
+// List holds the values in sorted chunks
+type List[V any] struct {
+	comparator utils.Comparator[V]
+	load       int
+	chunks     [][]V // sorted chunks, none of them empty
+	maxes      []V   // maximum value of every chunk
+	offsets    []int // index of the first value of every chunk
+	size       int
+	modCount   int // number of structural modifications, checked by iterators
+}
+
+// New instantiates a new list ordering the values by the < and > operators, see utils.OrderedComparator,
+// and adds the passed values, if any, to the list.
+func New[V utils.Ordered](values ...V) *List[V] {
+	return NewWith(utils.OrderedComparator[V], values...)
+}
+
+// NewWith instantiates a new list with the custom comparator and adds the passed values, if any, to the list.
+func NewWith[V any](comparator utils.Comparator[V], values ...V) *List[V] {
+	return NewWithLoad(DefaultLoad, comparator, values...)
+}
+
+// NewWithLoad instantiates a new list with the custom comparator whose chunks hold from half the load
+// to twice the load values, and adds the passed values, if any, to the list.
+// Larger loads make searches and iteration faster and adding or removing values slower.
+// Panics if the load is less than 2.
+func NewWithLoad[V any](load int, comparator utils.Comparator[V], values ...V) *List[V] {
+	if load < 2 {
+		panic(fmt.Sprintf("sortedlist: load %d is less than 2", load))
+	}
+	l := &List[V]{comparator: comparator, load: load}
+	if len(values) > 0 {
+		l.Add(values...)
+	}
+	return l
+}
+
+// Add adds the values to the list, each one after the values equal to it.
+// A batch of values that is at least as large as the list is sorted and merged into it in O(n + k log k) time,
+// k being the number of values; smaller batches are added one by one in O(log n + load) time each.
+func (l *List[V]) Add(values ...V) {
+	if len(values) > 1 && len(values) >= l.size {
+		l.merge(values)
+		return
+	}
+	for _, value := range values {
+		l.add(value)
+	}
+}
+
+func (l *List[V]) add(value V) {
+	l.modCount++
+	if len(l.chunks) == 0 {
+		l.chunks = [][]V{{value}}
+		l.maxes = []V{value}
+		l.offsets = []int{0}
+		l.size = 1
+		return
+	}
+	k := l.chunkAfter(value)
+	if k == len(l.chunks) {
+		// beyond the maximum of the last chunk
+		k--
+		l.chunks[k] = append(l.chunks[k], value)
+		l.maxes[k] = value
+	} else {
+		chunk := l.chunks[k]
+		j := l.upperBound(chunk, value)
+		var zeroV V
+		chunk = append(chunk, zeroV)
+		copy(chunk[j+1:], chunk[j:])
+		chunk[j] = value
+		l.chunks[k] = chunk
+	}
+	for i := k + 1; i < len(l.offsets); i++ {
+		l.offsets[i]++
+	}
+	l.size++
+	if len(l.chunks[k]) > 2*l.load {
+		l.split(k)
+	}
+}
+
+// merge sorts the values and merges them with the values of the list into new chunks.
+func (l *List[V]) merge(values []V) {
+	batch := append([]V(nil), values...)
+	sort.Stable(sorter[V]{values: batch, comparator: l.comparator})
+	merged := make([]V, 0, l.size+len(batch))
+	for _, chunk := range l.chunks {
+		for _, value := range chunk {
+			// the values of the batch that are less go first, the equal ones after the values of the list
+			for len(batch) > 0 && l.comparator(batch[0], value) < 0 {
+				merged = append(merged, batch[0])
+				batch = batch[1:]
+			}
+			merged = append(merged, value)
+		}
+	}
+	l.rebuild(append(merged, batch...))
+}
+
+// rebuild replaces the chunks of the list by chunks of load values holding the sorted values.
+func (l *List[V]) rebuild(values []V) {
+	n := (len(values) + l.load - 1) / l.load
+	l.chunks, l.maxes, l.offsets = make([][]V, 0, n), make([]V, 0, n), make([]int, 0, n)
+	for i := 0; i < len(values); i += l.load {
+		end := i + l.load
+		if end > len(values) {
+			end = len(values)
+		}
+		l.chunks = append(l.chunks, values[i:end:end])
+		l.maxes = append(l.maxes, values[end-1])
+		l.offsets = append(l.offsets, i)
+	}
+	l.size = len(values)
+	l.modCount++
+}
+
+// split divides the chunk at index k in halves.
+func (l *List[V]) split(k int) {
+	chunk := l.chunks[k]
+	half := len(chunk) / 2
+	right := append(make([]V, 0, l.load+1), chunk[half:]...)
+	var zeroV V
+	for i := half; i < len(chunk); i++ {
+		chunk[i] = zeroV
+	}
+	l.chunks[k] = chunk[:half]
+	l.chunks = append(l.chunks, nil)
+	copy(l.chunks[k+2:], l.chunks[k+1:])
+	l.chunks[k+1] = right
+	l.maxes = append(l.maxes, zeroV)
+	copy(l.maxes[k+1:], l.maxes[k:])
+	l.maxes[k] = chunk[half-1]
+	l.offsets = append(l.offsets, 0)
+	copy(l.offsets[k+2:], l.offsets[k+1:])
+	l.offsets[k+1] = l.offsets[k] + half
+}
+
+// removeAt removes the value at position j of the chunk at index k.
+func (l *List[V]) removeAt(k, j int) {
+	chunk := l.chunks[k]
+	copy(chunk[j:], chunk[j+1:])
+	var zeroV V
+	chunk[len(chunk)-1] = zeroV // cleanup reference
+	chunk = chunk[:len(chunk)-1]
+	l.chunks[k] = chunk
+	for i := k + 1; i < len(l.offsets); i++ {
+		l.offsets[i]--
+	}
+	l.size--
+	l.modCount++
+	switch {
+	case len(chunk) == 0:
+		l.removeChunk(k)
+	case len(chunk) < l.load/2 && len(l.chunks) > 1:
+		if k == len(l.chunks)-1 {
+			k--
+		}
+		l.join(k)
+	default:
+		l.maxes[k] = chunk[len(chunk)-1]
+	}
+}
+
+// join appends the chunk at index k+1 to the chunk at index k, splitting the result if it is too large.
+func (l *List[V]) join(k int) {
+	l.chunks[k] = append(l.chunks[k], l.chunks[k+1]...)
+	l.removeChunk(k + 1)
+	l.maxes[k] = l.chunks[k][len(l.chunks[k])-1]
+	if len(l.chunks[k]) > 2*l.load {
+		l.split(k)
+	}
+}
+
+func (l *List[V]) removeChunk(k int) {
+	var zeroV V
+	copy(l.chunks[k:], l.chunks[k+1:])
+	l.chunks[len(l.chunks)-1] = nil
+	l.chunks = l.chunks[:len(l.chunks)-1]
+	copy(l.maxes[k:], l.maxes[k+1:])
+	l.maxes[len(l.maxes)-1] = zeroV
+	l.maxes = l.maxes[:len(l.maxes)-1]
+	copy(l.offsets[k:], l.offsets[k+1:])
+	l.offsets = l.offsets[:len(l.offsets)-1]
+}
+
+// chunkAt returns the index of the first chunk whose maximum is greater than or equal to the value,
+// or the number of chunks if there is none.
+func (l *List[V]) chunkAt(value V) int {
+	return sort.Search(len(l.maxes), func(k int) bool { return l.comparator(l.maxes[k], value) >= 0 })
+}
+
+// chunkAfter returns the index of the first chunk whose maximum is greater than the value,
+// or the number of chunks if there is none.
+func (l *List[V]) chunkAfter(value V) int {
+	return sort.Search(len(l.maxes), func(k int) bool { return l.comparator(l.maxes[k], value) > 0 })
+}
+
+func (l *List[V]) lowerBound(chunk []V, value V) int {
+	return sort.Search(len(chunk), func(j int) bool { return l.comparator(chunk[j], value) >= 0 })
+}
+
+func (l *List[V]) upperBound(chunk []V, value V) int {
+	return sort.Search(len(chunk), func(j int) bool { return l.comparator(chunk[j], value) > 0 })
+}
+
+// locate returns the index of the chunk holding the value at the index and the position of the value in the chunk.
+// The index must be within the range of the list.
+func (l *List[V]) locate(index int) (int, int) {
+	k := sort.Search(len(l.offsets), func(k int) bool { return l.offsets[k] > index }) - 1
+	return k, index - l.offsets[k]
+}
+
+// find returns the chunk and the position in it of the first value that is greater than or equal to the value,
+// and whether that value equals the value.
+func (l *List[V]) find(value V) (int, int, bool) {
+	k := l.chunkAt(value)
+	if k == len(l.chunks) {
+		return k, 0, false
+	}
+	j := l.lowerBound(l.chunks[k], value)
+	return k, j, l.comparator(l.chunks[k][j], value) == 0
+}
+
+// Get returns the value at index in O(log n) time.
+// Second return parameter is true if index is within bounds of the list and list is not empty, otherwise false.
+func (l *List[V]) Get(index int) (V, bool) {
+	if !l.withinRange(index) {
+		var zeroV V
+		return zeroV, false
+	}
+	k, j := l.locate(index)
+	return l.chunks[k][j], true
+}
+
+// Remove removes the value at the given index from the list.
+func (l *List[V]) Remove(index int) {
+	if l.withinRange(index) {
+		l.removeAt(l.locate(index))
+	}
+}
+
+// RemoveValue removes the first value equal to the given value from the list.
+// Returns true if such a value was found, otherwise false.
+func (l *List[V]) RemoveValue(value V) bool {
+	k, j, found := l.find(value)
+	if found {
+		l.removeAt(k, j)
+	}
+	return found
+}
+
+// IndexOf returns the index of the first value equal to the given value, or -1 if there is none.
+func (l *List[V]) IndexOf(value V) int {
+	if k, j, found := l.find(value); found {
+		return l.offsets[k] + j
+	}
+	return -1
+}
+
+// LowerBound returns the index of the first value that is greater than or equal to the given value,
+// or the size of the list if there is none.
+func (l *List[V]) LowerBound(value V) int {
+	k := l.chunkAt(value)
+	if k == len(l.chunks) {
+		return l.size
+	}
+	return l.offsets[k] + l.lowerBound(l.chunks[k], value)
+}
+
+// UpperBound returns the index of the first value that is greater than the given value,
+// or the size of the list if there is none.
+func (l *List[V]) UpperBound(value V) int {
+	k := l.chunkAfter(value)
+	if k == len(l.chunks) {
+		return l.size
+	}
+	return l.offsets[k] + l.upperBound(l.chunks[k], value)
+}
+
+// Contains checks if values (one or more) are present in the list, in O(log n) time each.
+// All values have to be present in the list for the method to return true.
+// Returns true if no arguments are passed at all, i.e. list is always super-set of empty list.
+func (l *List[V]) Contains(values ...V) bool {
+	for _, value := range values {
+		if _, _, found := l.find(value); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Slice returns the values at the indexes within [from, to) in order.
+// The range is clipped to the bounds of the list.
+func (l *List[V]) Slice(from, to int) []V {
+	if from < 0 {
+		from = 0
+	}
+	if to > l.size {
+		to = l.size
+	}
+	if from >= to {
+		return []V{}
+	}
+	values := make([]V, 0, to-from)
+	k, j := l.locate(from)
+	for ; len(values) < to-from; k, j = k+1, 0 {
+		chunk := l.chunks[k][j:]
+		if n := to - from - len(values); len(chunk) > n {
+			chunk = chunk[:n]
+		}
+		values = append(values, chunk...)
+	}
+	return values
+}
+
+// Range returns the values that are greater than or equal to from and less than to in order.
+func (l *List[V]) Range(from, to V) []V {
+	return l.Slice(l.LowerBound(from), l.LowerBound(to))
+}
+
+// Values returns all values in the list in order.
+func (l *List[V]) Values() []V {
+	return l.Slice(0, l.size)
+}
+
+// InterfaceValues returns all values in the list with type interface{}.
+func (l *List[V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, 0, l.size)
+	for _, chunk := range l.chunks {
+		for _, value := range chunk {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Empty returns true if list does not contain any values.
+func (l *List[V]) Empty() bool {
+	return l.size == 0
+}
+
+// Size returns number of values within the list.
+func (l *List[V]) Size() int {
+	return l.size
+}
+
+// Clear removes all values from the list.
+func (l *List[V]) Clear() {
+	l.chunks, l.maxes, l.offsets = nil, nil, nil
+	l.size = 0
+	l.modCount++
+}
+
+// String returns a string representation of container
+func (l *List[V]) String() string {
+	str := "SortedList\n"
+	values := make([]string, 0, l.size)
+	for _, chunk := range l.chunks {
+		for _, value := range chunk {
+			values = append(values, fmt.Sprintf("%v", value))
+		}
+	}
+	str += strings.Join(values, ", ")
+	return str
+}
+
+// Check that the index is within bounds of the list
+func (l *List[V]) withinRange(index int) bool {
+	return index >= 0 && index < l.size
+}
+
+// sorter sorts values by a comparator, see sort.Stable.
+type sorter[V any] struct {
+	values     []V
+	comparator utils.Comparator[V]
+}
+
+func (s sorter[V]) Len() int {
+	return len(s.values)
+}
+
+func (s sorter[V]) Less(i, j int) bool {
+	return s.comparator(s.values[i], s.values[j]) < 0
+}
+
+func (s sorter[V]) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
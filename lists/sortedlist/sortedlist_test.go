@@ -0,0 +1,544 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sortedlist_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/lists/sortedlist"
+	"github.com/monitor1379/yagods/utils"
+)
+
+func TestListNew(t *testing.T) {
+	list1 := sortedlist.New[int]()
+	if actualValue := list1.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	list2 := sortedlist.New(3, 1, 2, 1)
+	if actualValue, expectedValue := fmt.Sprint(list2.Values()), "[1 1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list3 := sortedlist.NewWith(utils.Reverse(utils.StringComparator), "a", "c", "b")
+	if actualValue, expectedValue := fmt.Sprint(list3.Values()), "[c b a]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Got %v expected %v", r, "panic")
+		}
+	}()
+	sortedlist.NewWithLoad(1, utils.NumberComparator[int])
+}
+
+type event struct {
+	time int
+	name string
+}
+
+func byTime(a, b event) int {
+	return utils.NumberComparator(a.time, b.time)
+}
+
+func TestListAddDuplicates(t *testing.T) {
+	list := sortedlist.NewWith(byTime, event{2, "a"}, event{1, "b"}, event{2, "c"})
+	list.Add(event{2, "d"})
+	list.Add(event{2, "e"}, event{0, "f"}, event{2, "g"}, event{3, "h"})
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[{0 f} {1 b} {2 a} {2 c} {2 d} {2 e} {2 g} {3 h}]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := list.IndexOf(event{time: 2}), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := list.RemoveValue(event{time: 2}); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Range(event{time: 2}, event{time: 3})), "[{2 c} {2 d} {2 e} {2 g}]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListSearch(t *testing.T) {
+	list := sortedlist.New(10, 20, 20, 20, 30)
+	tests := [][]interface{}{
+		// value, IndexOf, LowerBound, UpperBound, Contains
+		{5, -1, 0, 0, false},
+		{10, 0, 0, 1, true},
+		{20, 1, 1, 4, true},
+		{25, -1, 4, 4, false},
+		{30, 4, 4, 5, true},
+		{35, -1, 5, 5, false},
+	}
+	for _, test := range tests {
+		value := test[0].(int)
+		actualValue := fmt.Sprint(list.IndexOf(value), list.LowerBound(value), list.UpperBound(value), list.Contains(value))
+		if expectedValue := fmt.Sprint(test[1:]...); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if actualValue := list.Contains(10, 30); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := list.Contains(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := sortedlist.New[int]().IndexOf(1); actualValue != -1 {
+		t.Errorf("Got %v expected %v", actualValue, -1)
+	}
+}
+
+func TestListGetRemove(t *testing.T) {
+	list := sortedlist.New("c", "a", "b")
+	if actualValue, ok := list.Get(1); actualValue != "b" || !ok {
+		t.Errorf("Got %v expected %v", actualValue, "b")
+	}
+	if _, ok := list.Get(3); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+	if _, ok := list.Get(-1); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+	list.Remove(1)
+	list.Remove(5)
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[a c]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := list.RemoveValue("b"); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	list.Remove(0)
+	list.Remove(0)
+	if actualValue := list.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	list.Add("d")
+	if actualValue, expectedValue := list.String(), "SortedList\nd"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	list.Clear()
+	if actualValue, expectedValue := fmt.Sprint(list.Size(), list.Values()), "0 []"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListSliceRange(t *testing.T) {
+	list := sortedlist.NewWithLoad(2, utils.NumberComparator[int])
+	for i := 9; i >= 0; i-- {
+		list.Add(i)
+	}
+	tests := [][]interface{}{
+		{0, 10, "[0 1 2 3 4 5 6 7 8 9]"},
+		{3, 7, "[3 4 5 6]"},
+		{-5, 2, "[0 1]"},
+		{8, 20, "[8 9]"},
+		{5, 5, "[]"},
+		{7, 3, "[]"},
+	}
+	for _, test := range tests {
+		if actualValue, expectedValue := fmt.Sprint(list.Slice(test[0].(int), test[1].(int))), test[2]; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Range(2, 5), list.Range(-1, 1), list.Range(9, 100), list.Range(5, 2)), "[2 3 4] [0] [9] []"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+// checkModel checks that the list holds the values of the sorted model.
+func checkModel(t *testing.T, list *sortedlist.List[int], model []int) {
+	t.Helper()
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), fmt.Sprint(model); actualValue != expectedValue {
+		t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for i, value := range model {
+		if actualValue, ok := list.Get(i); actualValue != value || !ok {
+			t.Fatalf("Got %v expected %v at %v", actualValue, value, i)
+		}
+	}
+	it := list.Iterator()
+	for it.Next() {
+		if actualValue, expectedValue := it.Value(), model[it.Index()]; actualValue != expectedValue {
+			t.Fatalf("Got %v expected %v at %v", actualValue, expectedValue, it.Index())
+		}
+	}
+	for it.Last(); it.Index() >= 0; it.Prev() {
+		if actualValue, expectedValue := it.Value(), model[it.Index()]; actualValue != expectedValue {
+			t.Fatalf("Got %v expected %v at %v", actualValue, expectedValue, it.Index())
+		}
+	}
+}
+
+func TestListChunks(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, load := range []int{2, 3, 8} {
+		list := sortedlist.NewWithLoad(load, utils.NumberComparator[int])
+		var model []int
+		for i := 0; i < 500; i++ {
+			value := random.Intn(50)
+			switch random.Intn(4) {
+			case 0, 1:
+				list.Add(value)
+				model = append(model, value)
+				sort.Ints(model)
+			case 2:
+				if len(model) > 0 {
+					index := random.Intn(len(model))
+					list.Remove(index)
+					model = append(model[:index], model[index+1:]...)
+				}
+			case 3:
+				removed := list.RemoveValue(value)
+				index := sort.SearchInts(model, value)
+				if found := index < len(model) && model[index] == value; found != removed {
+					t.Fatalf("Got %v expected %v", removed, found)
+				}
+				if removed {
+					model = append(model[:index], model[index+1:]...)
+				}
+			}
+			checkModel(t, list, model)
+		}
+		batch := random.Perm(100)
+		list.Add(batch...)
+		model = append(model, batch...)
+		sort.Ints(model)
+		checkModel(t, list, model)
+	}
+}
+
+func TestListEach(t *testing.T) {
+	list := sortedlist.New("c", "a", "b")
+	list.Each(func(index int, value string) {
+		if expectedValue := string(rune('a' + index)); value != expectedValue {
+			t.Errorf("Got %v expected %v", value, expectedValue)
+		}
+	})
+}
+
+func TestListMap(t *testing.T) {
+	list := sortedlist.New(1, 2, 3)
+	mappedList := list.Map(func(index int, value int) int {
+		return -value
+	})
+	if actualValue, expectedValue := fmt.Sprint(mappedList.Values()), "[-3 -2 -1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	mappedList.Add(0)
+	if actualValue, expectedValue := fmt.Sprint(mappedList.Values()), "[-3 -2 -1 0]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListSelect(t *testing.T) {
+	list := sortedlist.New(1, 2, 3, 4)
+	selectedList := list.Select(func(index int, value int) bool {
+		return value%2 == 0
+	})
+	selectedList.Add(3)
+	if actualValue, expectedValue := fmt.Sprint(selectedList.Values()), "[2 3 4]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := list.Select(func(index int, value int) bool { return false }).Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestListAnyAllFind(t *testing.T) {
+	list := sortedlist.New(3, 1, 2)
+	if actualValue := list.Any(func(index int, value int) bool { return value == 2 }); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := list.All(func(index int, value int) bool { return value < 3 }); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if index, value, found := list.Find(func(index int, value int) bool { return value > 1 }); index != 1 || value != 2 || !found {
+		t.Errorf("Got %v %v %v expected %v %v %v", index, value, found, 1, 2, true)
+	}
+	if index, _, found := list.Find(func(index int, value int) bool { return value > 3 }); index != -1 || found {
+		t.Errorf("Got %v %v expected %v %v", index, found, -1, false)
+	}
+}
+
+func TestListIteratorSeek(t *testing.T) {
+	list := sortedlist.NewWithLoad(2, utils.NumberComparator[int], 10, 20, 20, 30, 40)
+	it := list.IteratorAt(20)
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "1 20"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if it.SeekPrev(20); it.Index() != 2 {
+		t.Errorf("Got %v expected %v", it.Index(), 2)
+	}
+	if actualValue := it.Seek(41); actualValue != false || it.Index() != 5 {
+		t.Errorf("Got %v %v expected %v %v", actualValue, it.Index(), false, 5)
+	}
+	if actualValue := it.Prev(); actualValue != true || it.Value() != 40 {
+		t.Errorf("Got %v %v expected %v %v", actualValue, it.Value(), true, 40)
+	}
+	if actualValue := it.SeekPrev(5); actualValue != false || it.Index() != -1 {
+		t.Errorf("Got %v %v expected %v %v", actualValue, it.Index(), false, -1)
+	}
+	if actualValue := it.Next(); actualValue != true || it.Value() != 10 {
+		t.Errorf("Got %v %v expected %v %v", actualValue, it.Value(), true, 10)
+	}
+}
+
+func TestListIteratorRemove(t *testing.T) {
+	list := sortedlist.NewWithLoad(2, utils.NumberComparator[int])
+	for i := 0; i < 20; i++ {
+		list.Add(i)
+	}
+	it := list.Iterator()
+	for it.Next() {
+		if it.Value()%3 != 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(list.Values()), "[0 3 6 9 12 15 18]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it.Last()
+	list.Add(1)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListSerialization(t *testing.T) {
+	list := sortedlist.New("c", "a", "b")
+	data, err := list.ToJSON()
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `["a","b","c"]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restored := sortedlist.New[string]()
+	if err := restored.FromJSON([]byte(`["z","x","y"]`)); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), "[x y z]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	data, err = list.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), "[a b c]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(list); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	var decoded sortedlist.List[string]
+	if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	decoded.Add("b")
+	if actualValue, expectedValue := fmt.Sprint(decoded.Values()), "[a b b c]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListJSONMarshaler(t *testing.T) {
+	value := struct {
+		List *sortedlist.List[int] `json:"list"`
+	}{List: sortedlist.New(2, 1)}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"list":[1,2]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var zero struct {
+		List sortedlist.List[int] `json:"list"`
+	}
+	if err := json.Unmarshal([]byte(`{"list":[3,1,2]}`), &zero); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(zero.List.Values()), "[1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var noComparator sortedlist.List[struct{}]
+	if err := noComparator.UnmarshalJSON([]byte(`[]`)); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestListJSONStream(t *testing.T) {
+	list := sortedlist.New(3, 1, 2)
+	var buffer bytes.Buffer
+	if err := list.WriteJSON(&buffer); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), "[1,2,3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restored := sortedlist.New[int]()
+	if err := restored.ReadJSON(strings.NewReader("[5,4,6]")); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), "[4 5 6]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader("[8,7,")); err == nil {
+		t.Errorf("Got %v expected %v", err, "error")
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), "[7 8]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestListConformance(t *testing.T) {
+	containertest.TestReverseIteratorWithIndex(t, func(values []int) containers.ReverseIteratorWithIndex[int] {
+		return sortedlist.NewWithLoad(2, utils.NumberComparator[int], values...).Iterator()
+	})
+}
+
+// FuzzList checks the list against a sorted slice, decoding operations from the data:
+// the first byte of an operation selects it and the next one is its argument.
+func FuzzList(f *testing.F) {
+	f.Add([]byte{0, 5, 0, 3, 0, 5, 1, 0, 2, 5, 3, 9})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		list := sortedlist.NewWithLoad(2, utils.NumberComparator[int])
+		var model []int
+		for ; len(data) >= 2; data = data[2:] {
+			value := int(data[1] % 32)
+			switch data[0] % 5 {
+			case 0:
+				list.Add(value)
+				index := sort.SearchInts(model, value+1)
+				model = append(model[:index], append([]int{value}, model[index:]...)...)
+			case 1:
+				list.Remove(value)
+				if value < len(model) {
+					model = append(model[:value], model[value+1:]...)
+				}
+			case 2:
+				removed := list.RemoveValue(value)
+				index := sort.SearchInts(model, value)
+				if found := index < len(model) && model[index] == value; found != removed {
+					t.Fatalf("Got %v expected %v", removed, found)
+				}
+				if removed {
+					model = append(model[:index], model[index+1:]...)
+				}
+			case 3:
+				batch := make([]int, len(data)%7)
+				for i := range batch {
+					batch[i] = (value + 5*i) % 32
+				}
+				list.Add(batch...)
+				model = append(model, batch...)
+				sort.Ints(model)
+			case 4:
+				if actualValue, expectedValue := list.LowerBound(value), sort.SearchInts(model, value); actualValue != expectedValue {
+					t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+				}
+				if actualValue, expectedValue := list.UpperBound(value), sort.SearchInts(model, value+1); actualValue != expectedValue {
+					t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+				}
+			}
+			checkModel(t, list, model)
+		}
+	})
+}
+
+func randomValues(size int) []int {
+	random := rand.New(rand.NewSource(int64(size)))
+	values := make([]int, size)
+	for i := range values {
+		values[i] = random.Int()
+	}
+	return values
+}
+
+func benchmarkAdd(b *testing.B, size int) {
+	values := randomValues(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list := sortedlist.New[int]()
+		for _, value := range values {
+			list.Add(value)
+		}
+	}
+}
+
+// benchmarkSliceInsert inserts the values into a sorted slice, for comparison with benchmarkAdd.
+func benchmarkSliceInsert(b *testing.B, size int) {
+	values := randomValues(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var slice []int
+		for _, value := range values {
+			index := sort.SearchInts(slice, value)
+			slice = append(slice, 0)
+			copy(slice[index+1:], slice[index:])
+			slice[index] = value
+		}
+	}
+}
+
+func benchmarkGet(b *testing.B, size int) {
+	list := sortedlist.New(randomValues(size)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			list.Get(n)
+		}
+	}
+}
+
+func benchmarkRemoveValue(b *testing.B, size int) {
+	values := randomValues(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		list := sortedlist.New(values...)
+		b.StartTimer()
+		for _, value := range values {
+			list.RemoveValue(value)
+		}
+	}
+}
+
+func BenchmarkSortedListAdd1000(b *testing.B) {
+	benchmarkAdd(b, 1000)
+}
+
+func BenchmarkSliceInsert1000(b *testing.B) {
+	benchmarkSliceInsert(b, 1000)
+}
+
+func BenchmarkSortedListAdd100000(b *testing.B) {
+	benchmarkAdd(b, 100000)
+}
+
+func BenchmarkSliceInsert100000(b *testing.B) {
+	benchmarkSliceInsert(b, 100000)
+}
+
+func BenchmarkSortedListGet100000(b *testing.B) {
+	benchmarkGet(b, 100000)
+}
+
+func BenchmarkSortedListRemoveValue100000(b *testing.B) {
+	benchmarkRemoveValue(b, 100000)
+}
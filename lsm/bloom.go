@@ -0,0 +1,67 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsm
+
+import "hash/fnv"
+
+// bloomFilter is a Bloom filter over encoded keys. Its last byte holds the number of probes,
+// the other bytes hold the bits.
+//
+// Reference: https://en.wikipedia.org/wiki/Bloom_filter
+type bloomFilter []byte
+
+// newBloomFilter builds a filter of bitsPerKey bits for every key, which gives a false positive rate
+// of about 1% for 10 bits per key.
+func newBloomFilter(keys [][]byte, bitsPerKey int) bloomFilter {
+	// the optimal number of probes is bitsPerKey * ln(2)
+	probes := bitsPerKey * 69 / 100
+	if probes < 1 {
+		probes = 1
+	} else if probes > 30 {
+		probes = 30
+	}
+	bits := len(keys) * bitsPerKey
+	if bits < 64 {
+		bits = 64
+	}
+	filter := make(bloomFilter, (bits+7)/8+1)
+	bits = (len(filter) - 1) * 8
+	filter[len(filter)-1] = byte(probes)
+	for _, key := range keys {
+		hash, delta := bloomHash(key)
+		for i := 0; i < probes; i++ {
+			position := hash % uint32(bits)
+			filter[position/8] |= 1 << (position % 8)
+			hash += delta
+		}
+	}
+	return filter
+}
+
+// mayContain returns false if the key is definitely not in the filter.
+func (filter bloomFilter) mayContain(key []byte) bool {
+	if len(filter) < 2 {
+		return true
+	}
+	bits := uint32(len(filter)-1) * 8
+	probes := int(filter[len(filter)-1])
+	hash, delta := bloomHash(key)
+	for i := 0; i < probes; i++ {
+		position := hash % bits
+		if filter[position/8]&(1<<(position%8)) == 0 {
+			return false
+		}
+		hash += delta
+	}
+	return true
+}
+
+// bloomHash returns the two hashes combined by double hashing.
+func bloomHash(key []byte) (uint32, uint32) {
+	hasher := fnv.New64a()
+	hasher.Write(key)
+	sum := hasher.Sum64()
+	return uint32(sum), uint32(sum>>32) | 1
+}
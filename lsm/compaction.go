@@ -0,0 +1,372 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/monitor1379/yagods/maps/treemap"
+)
+
+const (
+	manifestName    = "MANIFEST"
+	tablePattern    = "%06d.sst"
+	tableSuffix     = ".sst"
+	temporarySuffix = ".tmp"
+)
+
+// tableFile is an open table. It is referenced by the level that holds it and by every reader using it,
+// and closed once the last reference is released.
+type tableFile[K comparable, V any] struct {
+	number uint64
+	size   int64
+	file   *os.File
+	table  *Table[K, V]
+	refs   int
+}
+
+// manifest lists the tables of every level by number
+type manifest struct {
+	Next   uint64     `json:"next"`
+	Levels [][]uint64 `json:"levels"`
+}
+
+// background flushes full memtables and compacts levels until the store is closed.
+func (store *Store[K, V]) background() {
+	defer close(store.done)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for {
+		var err error
+		switch {
+		case store.err != nil:
+			if store.closing {
+				return
+			}
+			store.cond.Wait()
+			continue
+		case store.immutable != nil:
+			err = store.flush()
+		case store.closing:
+			store.compactAll = false
+			return
+		case store.compactAll:
+			if deepest := len(store.levels) - 1; deepest >= 0 {
+				if deepest == 0 {
+					deepest = 1
+				}
+				err = store.compact(0, deepest)
+			}
+			store.compactAll = false
+		default:
+			level := store.pickLevel()
+			if level < 0 {
+				store.cond.Wait()
+				continue
+			}
+			err = store.compact(level, level+1)
+		}
+		if err != nil {
+			store.err = err
+		}
+		store.cond.Broadcast()
+	}
+}
+
+// pickLevel returns the level that is due to be merged into the next level, or -1 if none is.
+func (store *Store[K, V]) pickLevel() int {
+	if len(store.levels) > 0 && len(store.levels[0]) >= store.options.Level0Tables {
+		return 0
+	}
+	limit := int64(store.options.LevelSize)
+	for level := 1; level < len(store.levels); level++ {
+		var size int64
+		for _, table := range store.levels[level] {
+			size += table.size
+		}
+		if size > limit {
+			return level
+		}
+		limit *= int64(store.options.LevelRatio)
+	}
+	return -1
+}
+
+// flush writes the immutable memtable into a new table in level 0. The lock is released while writing.
+func (store *Store[K, V]) flush() error {
+	memtable, number := store.immutable, store.next
+	store.next++
+	store.mu.Unlock()
+	table, err := store.writeTable(number, func(writer *TableWriter[K, V]) error {
+		return writeMemtable(writer, memtable)
+	})
+	store.mu.Lock()
+	if err != nil {
+		return err
+	}
+	levels := store.copyLevels(1)
+	levels[0] = append([]*tableFile[K, V]{table}, levels[0]...)
+	if err := store.install(levels); err != nil {
+		store.releaseLocked([]*tableFile[K, V]{table})
+		return err
+	}
+	store.immutable = nil
+	return nil
+}
+
+// compact merges all tables of the levels from the first to the last level into a single table in the last level.
+// Tombstones are dropped if no deeper level holds a table. The lock is released while merging.
+func (store *Store[K, V]) compact(first int, last int) error {
+	levels := store.copyLevels(last + 1)
+	var inputs []*tableFile[K, V]
+	for level := first; level <= last; level++ {
+		inputs = append(inputs, levels[level]...)
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+	dropTombstones := true
+	for _, level := range levels[last+1:] {
+		dropTombstones = dropTombstones && len(level) == 0
+	}
+	for _, table := range inputs {
+		table.refs++
+	}
+	defer store.releaseLocked(inputs)
+	merged := len(levels[first])
+	number := store.next
+	store.next++
+	store.mu.Unlock()
+	sources := make([]source[K, V], len(inputs))
+	for i, input := range inputs {
+		sources[i] = input.table.Iterator()
+	}
+	table, err := store.writeTable(number, func(writer *TableWriter[K, V]) error {
+		merge := newMergeIterator(store.comparator, sources)
+		for merge.Next() {
+			var err error
+			if !merge.Deleted() {
+				err = writer.Add(merge.Key(), merge.Value())
+			} else if !dropTombstones {
+				err = writer.AddTombstone(merge.Key())
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return merge.Err()
+	})
+	store.mu.Lock()
+	if err != nil {
+		return err
+	}
+
+	levels = store.copyLevels(last + 1)
+	levels[first] = levels[first][:len(levels[first])-merged]
+	for level := first + 1; level <= last; level++ {
+		levels[level] = nil
+	}
+	if table != nil {
+		levels[last] = []*tableFile[K, V]{table}
+	}
+	if err := store.install(levels); err != nil {
+		if table != nil {
+			store.releaseLocked([]*tableFile[K, V]{table})
+		}
+		return err
+	}
+	return nil
+}
+
+// install records the levels in the manifest and makes them current, releasing the tables no longer in use.
+func (store *Store[K, V]) install(levels [][]*tableFile[K, V]) error {
+	current := manifest{Next: store.next, Levels: make([][]uint64, len(levels))}
+	live := make(map[*tableFile[K, V]]bool)
+	for i, level := range levels {
+		current.Levels[i] = []uint64{}
+		for _, table := range level {
+			current.Levels[i] = append(current.Levels[i], table.number)
+			live[table] = true
+		}
+	}
+	if err := store.writeManifest(current); err != nil {
+		return err
+	}
+	var obsolete []*tableFile[K, V]
+	for _, level := range store.levels {
+		for _, table := range level {
+			if !live[table] {
+				obsolete = append(obsolete, table)
+			}
+		}
+	}
+	store.levels = levels
+	store.releaseLocked(obsolete)
+	return nil
+}
+
+// copyLevels returns a copy of the levels with at least the given number of levels.
+func (store *Store[K, V]) copyLevels(count int) [][]*tableFile[K, V] {
+	if count < len(store.levels) {
+		count = len(store.levels)
+	}
+	levels := make([][]*tableFile[K, V], count)
+	for i, level := range store.levels {
+		levels[i] = append([]*tableFile[K, V](nil), level...)
+	}
+	return levels
+}
+
+// writeTable writes a new table with the given number and opens it. Returns a nil table if nothing was written.
+func (store *Store[K, V]) writeTable(number uint64, write func(writer *TableWriter[K, V]) error) (*tableFile[K, V], error) {
+	path := filepath.Join(store.dir, fmt.Sprintf(tablePattern, number))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*tableFile[K, V], error) {
+		file.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	buffered := bufio.NewWriter(file)
+	writer := NewTableWriter(buffered, store.comparator, store.keyCodec, store.valueCodec, store.options.BlockSize, store.options.BloomBitsPerKey)
+	if err := write(writer); err != nil {
+		return fail(err)
+	}
+	if writer.Entries() == 0 {
+		return fail(nil)
+	}
+	if err := writer.Finish(); err != nil {
+		return fail(err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fail(err)
+	}
+	if err := file.Sync(); err != nil {
+		return fail(err)
+	}
+	return store.openTable(number, file)
+}
+
+// openTable opens the table held by the file, closing the file on error.
+func (store *Store[K, V]) openTable(number uint64, file *os.File) (*tableFile[K, V], error) {
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	table, err := OpenTable(file, info.Size(), store.comparator, store.keyCodec, store.valueCodec)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("lsm: table %s: %w", file.Name(), err)
+	}
+	return &tableFile[K, V]{number: number, size: info.Size(), file: file, table: table, refs: 1}, nil
+}
+
+// writeManifest atomically replaces the manifest.
+func (store *Store[K, V]) writeManifest(current manifest) error {
+	data, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(store.dir, manifestName)
+	file, err := os.Create(path + temporarySuffix)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(path+temporarySuffix, path)
+	}
+	if err == nil {
+		err = syncDir(store.dir)
+	}
+	return err
+}
+
+// load opens the tables listed in the manifest and removes all other table files,
+// which were left behind by a crash during a flush or a compaction.
+func (store *Store[K, V]) load() error {
+	var current manifest
+	data, err := os.ReadFile(filepath.Join(store.dir, manifestName))
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, &current); err != nil {
+			return fmt.Errorf("lsm: corrupt manifest: %w", err)
+		}
+	}
+	store.next = current.Next
+	live := make(map[string]bool)
+	for _, numbers := range current.Levels {
+		var level []*tableFile[K, V]
+		for _, number := range numbers {
+			file, err := os.Open(filepath.Join(store.dir, fmt.Sprintf(tablePattern, number)))
+			if err != nil {
+				return err
+			}
+			table, err := store.openTable(number, file)
+			if err != nil {
+				return err
+			}
+			level = append(level, table)
+			live[filepath.Base(file.Name())] = true
+		}
+		store.levels = append(store.levels, level)
+	}
+	entries, err := os.ReadDir(store.dir)
+	if err != nil {
+		return err
+	}
+	for _, dirEntry := range entries {
+		name := dirEntry.Name()
+		if (strings.HasSuffix(name, tableSuffix) && !live[name]) || name == manifestName+temporarySuffix {
+			if err := os.Remove(filepath.Join(store.dir, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeMemtable adds all entries of the memtable to the table.
+func writeMemtable[K comparable, V any](writer *TableWriter[K, V], memtable *treemap.Map[K, entry[V]]) error {
+	iterator := memtable.Iterator()
+	for iterator.Next() {
+		var err error
+		if entry := iterator.Value(); entry.deleted {
+			err = writer.AddTombstone(iterator.Key())
+		} else {
+			err = writer.Add(iterator.Key(), entry.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsm
+
+import "github.com/monitor1379/yagods/maps/treemap"
+
+// Iterator iterates over a snapshot of the store in ascending key order.
+// Writes to the store after the iterator was created are not visible to it.
+type Iterator[K comparable, V any] struct {
+	store  *Store[K, V]
+	merge  *mergeIterator[K, V]
+	tables []*tableFile[K, V] // tables kept open for the iterator
+	closed bool
+}
+
+// Iterator returns an iterator over the store, which copies the memtable and keeps the current tables open.
+// The iterator should be closed once it is no longer used.
+func (store *Store[K, V]) Iterator() (*Iterator[K, V], error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.check(); err != nil {
+		return nil, err
+	}
+	var sources []source[K, V]
+	for _, memtable := range []*treemap.Map[K, entry[V]]{store.memtable, store.immutable} {
+		if memtable != nil {
+			sources = append(sources, &memtableSource[K, V]{comparator: store.comparator, keys: memtable.Keys(), entries: memtable.Values(), position: -1})
+		}
+	}
+	tables := store.acquire()
+	for _, table := range tables {
+		sources = append(sources, table.table.Iterator())
+	}
+	return &Iterator[K, V]{store: store, merge: newMergeIterator(store.comparator, sources), tables: tables}, nil
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the store.
+// If Next() returns true, then next element's key and value can be retrieved by Key() and Value().
+// Returns false at the end of the store or if a table could not be read, see Err().
+func (iterator *Iterator[K, V]) Next() bool {
+	for iterator.merge.Next() {
+		if !iterator.merge.Deleted() {
+			return true
+		}
+	}
+	return false
+}
+
+// Seek moves the iterator to the first element whose key is greater than or equal to the given key
+// and returns true if there was such an element.
+// Next() continues after that element.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	iterator.merge.Seek(key)
+	return iterator.Next()
+}
+
+// Key returns the current element's key.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Key() K {
+	return iterator.merge.Key()
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Value() V {
+	return iterator.merge.Value()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (iterator *Iterator[K, V]) Err() error {
+	return iterator.merge.Err()
+}
+
+// Close releases the tables of the iterator, which are removed if a compaction replaced them.
+func (iterator *Iterator[K, V]) Close() error {
+	if iterator.closed {
+		return ErrClosed
+	}
+	iterator.closed = true
+	iterator.store.release(iterator.tables)
+	return nil
+}
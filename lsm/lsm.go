@@ -0,0 +1,324 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lsm implements a key-value store as a log-structured merge-tree.
+//
+// Writes go to an in-memory treemap, the memtable. A full memtable is flushed in the background into an
+// immutable sorted string table (SSTable) on disk, which holds data blocks, a block index and an optional
+// Bloom filter. Tables are organized in levels: level 0 holds the flushed tables, which may overlap, and every
+// deeper level holds a single table that is a few times larger than the one above it. Tables are merged into
+// the next level by a background compaction, which drops overwritten values and, at the deepest level, the
+// tombstones of removed keys.
+//
+// Reads look into the memtable first and then into the tables from newest to oldest. Iterators merge all of
+// them with a k-way merge over a binary heap.
+//
+// The set of live tables is recorded in a manifest that is replaced atomically, so that a crash never leaves
+// the store inconsistent. There is no write-ahead log: writes that were not flushed yet are lost on a crash.
+//
+// Keys and values are converted to bytes by codecs (see containers.Codec).
+//
+// Structure is thread safe.
+//
+// Reference: https://en.wikipedia.org/wiki/Log-structured_merge-tree
+package lsm
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/maps/treemap"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// Options configures a store. Zero fields take their default.
+type Options struct {
+	MemtableSize    int // bytes of encoded keys and values held in the memtable before it is flushed, 4 MiB by default
+	BlockSize       int // bytes of a data block of a table, 4 KiB by default
+	BloomBitsPerKey int // bits per key of the Bloom filter of a table, 10 by default, negative disables filters
+	Level0Tables    int // number of tables in level 0 that triggers a compaction into level 1, 4 by default
+	LevelSize       int // bytes of level 1 that trigger a compaction into level 2, 10 MiB by default
+	LevelRatio      int // factor by which the size of each level exceeds the size of the level above, 10 by default
+}
+
+// ErrClosed is returned when using a store after it was closed.
+var ErrClosed = errors.New("lsm: store is closed")
+
+// entry is a value or a tombstone in the memtable
+type entry[V any] struct {
+	value   V
+	deleted bool
+}
+
+// Store holds the most recent writes in a memtable and all others in tables in a directory
+type Store[K comparable, V any] struct {
+	dir        string
+	comparator utils.Comparator[K]
+	keyCodec   containers.Codec[K]
+	valueCodec containers.Codec[V]
+	options    Options
+
+	mu           sync.Mutex
+	cond         *sync.Cond // signals every change of the state below
+	memtable     *treemap.Map[K, entry[V]]
+	memtableSize int
+	immutable    *treemap.Map[K, entry[V]] // full memtable being flushed, nil if none
+	levels       [][]*tableFile[K, V]      // level 0 from newest to oldest, one table in every other level
+	next         uint64                    // number of the next table file
+	compactAll   bool                      // a full compaction was requested
+	err          error                     // error of the background work, returned by all later calls
+	closing      bool
+	done         chan struct{} // closed when the background work stops
+}
+
+// Open opens the store in the directory, creating the directory if it does not exist.
+// Keys are ordered by the comparator.
+func Open[K comparable, V any](dir string, comparator utils.Comparator[K], keyCodec containers.Codec[K], valueCodec containers.Codec[V], options Options) (*Store[K, V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	store := &Store[K, V]{
+		dir:        dir,
+		comparator: comparator,
+		keyCodec:   keyCodec,
+		valueCodec: valueCodec,
+		options:    options.withDefaults(),
+		memtable:   treemap.NewWith[K, entry[V]](comparator),
+		done:       make(chan struct{}),
+	}
+	store.cond = sync.NewCond(&store.mu)
+	if err := store.load(); err != nil {
+		for _, level := range store.levels {
+			for _, table := range level {
+				table.file.Close()
+			}
+		}
+		return nil, err
+	}
+	go store.background()
+	return store, nil
+}
+
+// Put inserts the key-value pair into the store.
+// Returns the error of a failed background flush or compaction, if any.
+func (store *Store[K, V]) Put(key K, value V) error {
+	encodedKey, err := store.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	encodedValue, err := store.valueCodec.Encode(value)
+	if err != nil {
+		return err
+	}
+	return store.write(key, entry[V]{value: value}, len(encodedKey)+len(encodedValue))
+}
+
+// Remove removes the key from the store by writing a tombstone for it.
+// Returns the error of a failed background flush or compaction, if any.
+func (store *Store[K, V]) Remove(key K) error {
+	encodedKey, err := store.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	var zero V
+	return store.write(key, entry[V]{value: zero, deleted: true}, len(encodedKey))
+}
+
+// Get searches the key in the store and returns its value.
+// Second return parameter is true if key was found, otherwise false.
+func (store *Store[K, V]) Get(key K) (value V, found bool, err error) {
+	store.mu.Lock()
+	if err := store.check(); err != nil {
+		store.mu.Unlock()
+		return value, false, err
+	}
+	for _, memtable := range []*treemap.Map[K, entry[V]]{store.memtable, store.immutable} {
+		if memtable == nil {
+			continue
+		}
+		if entry, ok := memtable.Get(key); ok {
+			store.mu.Unlock()
+			return entry.value, !entry.deleted, nil
+		}
+	}
+	tables := store.acquire()
+	store.mu.Unlock()
+	defer store.release(tables)
+
+	for _, table := range tables {
+		value, deleted, found, err := table.table.Get(key)
+		if err != nil || found {
+			return value, found && !deleted, err
+		}
+	}
+	return value, false, nil
+}
+
+// Flush writes the memtable into a table and waits until it is written.
+func (store *Store[K, V]) Flush() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.check(); err != nil {
+		return err
+	}
+	store.rotate()
+	for store.immutable != nil && store.err == nil {
+		store.cond.Wait()
+	}
+	return store.err
+}
+
+// Compact flushes the memtable and merges all tables into a single table at the deepest level,
+// which drops all overwritten values and tombstones. Waits until the compaction is done.
+func (store *Store[K, V]) Compact() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.check(); err != nil {
+		return err
+	}
+	store.rotate()
+	store.compactAll = true
+	store.cond.Broadcast()
+	for store.compactAll && store.err == nil {
+		store.cond.Wait()
+	}
+	return store.err
+}
+
+// Levels returns the number of tables in every level, starting with level 0.
+func (store *Store[K, V]) Levels() []int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	levels := make([]int, len(store.levels))
+	for i, level := range store.levels {
+		levels[i] = len(level)
+	}
+	return levels
+}
+
+// Close flushes the memtable, waits for the background work to stop and closes all tables.
+// Iterators that are still open keep their tables open until they are closed.
+func (store *Store[K, V]) Close() error {
+	store.mu.Lock()
+	if store.closing {
+		store.mu.Unlock()
+		return ErrClosed
+	}
+	if store.err == nil {
+		store.rotate()
+	}
+	store.closing = true
+	store.cond.Broadcast()
+	store.mu.Unlock()
+	<-store.done
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, level := range store.levels {
+		store.releaseLocked(level)
+	}
+	return store.err
+}
+
+// String returns a string representation of container
+func (store *Store[K, V]) String() string {
+	return "LSM"
+}
+
+// write inserts the entry into the memtable and hands the memtable to the background work once it is full.
+func (store *Store[K, V]) write(key K, entry entry[V], size int) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.check(); err != nil {
+		return err
+	}
+	store.memtable.Put(key, entry)
+	store.memtableSize += size
+	if store.memtableSize >= store.options.MemtableSize {
+		store.rotate()
+	}
+	return store.err
+}
+
+// check returns ErrClosed or the error of the background work.
+func (store *Store[K, V]) check() error {
+	if store.closing {
+		return ErrClosed
+	}
+	return store.err
+}
+
+// rotate turns a non-empty memtable into the immutable memtable, first waiting for the previous one to be flushed.
+func (store *Store[K, V]) rotate() {
+	if store.memtable.Empty() {
+		return
+	}
+	for store.immutable != nil && store.err == nil {
+		store.cond.Wait()
+	}
+	if store.err != nil {
+		return
+	}
+	store.immutable = store.memtable
+	store.memtable = treemap.NewWith[K, entry[V]](store.comparator)
+	store.memtableSize = 0
+	store.cond.Broadcast()
+}
+
+// acquire returns all tables from newest to oldest and keeps them open until they are released.
+func (store *Store[K, V]) acquire() []*tableFile[K, V] {
+	var tables []*tableFile[K, V]
+	for _, level := range store.levels {
+		for _, table := range level {
+			table.refs++
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// release drops references to the tables. A table that is no longer referenced is closed,
+// and its file is removed unless the store is closing.
+func (store *Store[K, V]) release(tables []*tableFile[K, V]) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.releaseLocked(tables)
+}
+
+// releaseLocked is release for callers that hold the lock.
+func (store *Store[K, V]) releaseLocked(tables []*tableFile[K, V]) {
+	for _, table := range tables {
+		table.refs--
+		if table.refs == 0 {
+			table.file.Close()
+			if !store.closing {
+				os.Remove(table.file.Name())
+			}
+		}
+	}
+}
+
+func (options Options) withDefaults() Options {
+	if options.MemtableSize <= 0 {
+		options.MemtableSize = 4 << 20
+	}
+	if options.BlockSize <= 0 {
+		options.BlockSize = 4 << 10
+	}
+	if options.BloomBitsPerKey == 0 {
+		options.BloomBitsPerKey = 10
+	}
+	if options.Level0Tables <= 0 {
+		options.Level0Tables = 4
+	}
+	if options.LevelSize <= 0 {
+		options.LevelSize = 10 << 20
+	}
+	if options.LevelRatio <= 1 {
+		options.LevelRatio = 10
+	}
+	return options
+}
@@ -0,0 +1,424 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsm_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/lsm"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// small memtables and levels so that tests flush and compact often
+var smallOptions = lsm.Options{MemtableSize: 512, BlockSize: 128, Level0Tables: 3, LevelSize: 4096, LevelRatio: 2}
+
+func open(t *testing.T, dir string, options lsm.Options) *lsm.Store[int, string] {
+	t.Helper()
+	store, err := lsm.Open[int, string](dir, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), options)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	return store
+}
+
+// assertContents checks the store against the model through Get and an iterator.
+func assertContents(t *testing.T, store *lsm.Store[int, string], model map[int]string, keys int) {
+	t.Helper()
+	for key := 0; key < keys; key++ {
+		value, found, err := store.Get(key)
+		if err != nil {
+			t.Fatalf("Got error %v", err)
+		}
+		expectedValue, expectedFound := model[key]
+		if found != expectedFound || value != expectedValue {
+			t.Fatalf("Got %v, %v expected %v, %v for key %v", value, found, expectedValue, expectedFound, key)
+		}
+	}
+	var expectedKeys []int
+	for key := range model {
+		expectedKeys = append(expectedKeys, key)
+	}
+	sort.Ints(expectedKeys)
+	it, err := store.Iterator()
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	defer it.Close()
+	var actualKeys []int
+	for it.Next() {
+		if actualValue, expectedValue := it.Value(), model[it.Key()]; actualValue != expectedValue {
+			t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+		}
+		actualKeys = append(actualKeys, it.Key())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(actualKeys), fmt.Sprint(expectedKeys); actualValue != expectedValue {
+		t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func tableFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.sst"))
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	return files
+}
+
+func TestStorePutGetRemove(t *testing.T) {
+	store := open(t, t.TempDir(), lsm.Options{})
+	defer store.Close()
+	store.Put(5, "e")
+	store.Put(6, "f")
+	store.Put(7, "g")
+	store.Put(3, "c")
+	store.Put(4, "d")
+	store.Put(1, "x")
+	store.Put(2, "b")
+	store.Put(1, "a") //overwrite
+	store.Remove(6)
+
+	tests := [][]interface{}{
+		{1, "a", true},
+		{2, "b", true},
+		{3, "c", true},
+		{4, "d", true},
+		{5, "e", true},
+		{6, "", false},
+		{7, "g", true},
+		{8, "", false},
+	}
+	for _, test := range tests {
+		value, found, err := store.Get(test[0].(int))
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+		if value != test[1] || found != test[2] {
+			t.Errorf("Got %v,%v expected %v,%v", value, found, test[1], test[2])
+		}
+	}
+
+	// the same answers from a table
+	if err := store.Flush(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(store.Levels()), "[1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for _, test := range tests {
+		value, found, err := store.Get(test[0].(int))
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+		if value != test[1] || found != test[2] {
+			t.Errorf("Got %v,%v expected %v,%v", value, found, test[1], test[2])
+		}
+	}
+
+	// a tombstone in the memtable hides the value in the table
+	store.Remove(1)
+	if _, found, _ := store.Get(1); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+}
+
+func TestStoreRandom(t *testing.T) {
+	dir := t.TempDir()
+	store := open(t, dir, smallOptions)
+	model := make(map[int]string)
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		key := random.Intn(1000)
+		if random.Intn(4) == 0 {
+			if err := store.Remove(key); err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			delete(model, key)
+		} else {
+			value := fmt.Sprint(i)
+			if err := store.Put(key, value); err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			model[key] = value
+		}
+		if i%1000 == 0 {
+			assertContents(t, store, model, 1000)
+		}
+	}
+	assertContents(t, store, model, 1000)
+	if levels := store.Levels(); len(levels) < 2 {
+		t.Errorf("Got %v expected compacted levels", levels)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if _, _, err := store.Get(1); !errors.Is(err, lsm.ErrClosed) {
+		t.Errorf("Got %v expected %v", err, lsm.ErrClosed)
+	}
+	if err := store.Put(1, "a"); !errors.Is(err, lsm.ErrClosed) {
+		t.Errorf("Got %v expected %v", err, lsm.ErrClosed)
+	}
+
+	store = open(t, dir, smallOptions)
+	defer store.Close()
+	assertContents(t, store, model, 1000)
+}
+
+func TestStoreCompact(t *testing.T) {
+	dir := t.TempDir()
+	// level 1 never outgrows its size, so that no background compaction follows the full one
+	options := smallOptions
+	options.LevelSize = 1 << 20
+	store := open(t, dir, options)
+	defer store.Close()
+	for key := 0; key < 1000; key++ {
+		store.Put(key, fmt.Sprint(key))
+	}
+	for key := 0; key < 1000; key += 2 {
+		store.Remove(key)
+	}
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	levels := store.Levels()
+	tables := 0
+	for _, count := range levels {
+		tables += count
+	}
+	if actualValue, expectedValue := tables, 1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := levels[len(levels)-1], 1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	// obsolete tables are removed
+	if actualValue, expectedValue := len(tableFiles(t, dir)), 1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	model := make(map[int]string)
+	for key := 1; key < 1000; key += 2 {
+		model[key] = fmt.Sprint(key)
+	}
+	assertContents(t, store, model, 1000)
+
+	// removing everything leaves no table behind
+	for key := 1; key < 1000; key += 2 {
+		store.Remove(key)
+	}
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := len(tableFiles(t, dir)), 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	assertContents(t, store, map[int]string{}, 1000)
+}
+
+func TestStoreIteratorSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	store := open(t, dir, smallOptions)
+	defer store.Close()
+	for key := 0; key < 100; key++ {
+		store.Put(key, fmt.Sprint(key))
+	}
+	store.Compact()
+	store.Put(100, "100")
+
+	it, err := store.Iterator()
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	// neither later writes nor the compaction that replaces the tables affect the iterator
+	store.Put(101, "101")
+	store.Remove(50)
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	count := 0
+	for it.Next() {
+		if actualValue, expectedValue := it.Key(), count; actualValue != expectedValue {
+			t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+		}
+		count++
+	}
+	if actualValue, expectedValue := count, 101; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := len(tableFiles(t, dir)), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := it.Close(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if err := it.Close(); !errors.Is(err, lsm.ErrClosed) {
+		t.Errorf("Got %v expected %v", err, lsm.ErrClosed)
+	}
+	// the tables of the iterator are removed once it is closed
+	if actualValue, expectedValue := len(tableFiles(t, dir)), 1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestStoreIteratorSeek(t *testing.T) {
+	store := open(t, t.TempDir(), smallOptions)
+	defer store.Close()
+	for key := 0; key < 300; key += 3 {
+		store.Put(key, fmt.Sprint(key))
+	}
+	store.Flush()
+	store.Remove(150)
+	store.Put(151, "151")
+
+	it, err := store.Iterator()
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	defer it.Close()
+	if !it.Seek(149) || it.Key() != 151 || it.Value() != "151" {
+		t.Errorf("Got %v expected %v", it.Key(), 151)
+	}
+	if !it.Next() || it.Key() != 153 {
+		t.Errorf("Got %v expected %v", it.Key(), 153)
+	}
+	if !it.Seek(0) || it.Key() != 0 {
+		t.Errorf("Got %v expected %v", it.Key(), 0)
+	}
+	if it.Seek(298) {
+		t.Errorf("Got %v expected %v", true, false)
+	}
+}
+
+func TestStoreOpenRemovesStrayTables(t *testing.T) {
+	dir := t.TempDir()
+	store := open(t, dir, smallOptions)
+	store.Put(1, "a")
+	store.Close()
+
+	// left behind by a flush that crashed before the manifest was written
+	if err := os.WriteFile(filepath.Join(dir, "999999.sst"), []byte("partial"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store = open(t, dir, smallOptions)
+	defer store.Close()
+	if actualValue, expectedValue := len(tableFiles(t, dir)), 1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found, err := store.Get(1); value != "a" || !found || err != nil {
+		t.Errorf("Got %v,%v,%v expected %v,%v", value, found, err, "a", true)
+	}
+}
+
+func TestStoreCorruptTable(t *testing.T) {
+	dir := t.TempDir()
+	store := open(t, dir, smallOptions)
+	store.Put(1, "a")
+	store.Close()
+
+	files := tableFiles(t, dir)
+	if err := os.Truncate(files[0], 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lsm.Open[int, string](dir, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), smallOptions); !errors.Is(err, lsm.ErrCorruptTable) {
+		t.Errorf("Got %v expected %v", err, lsm.ErrCorruptTable)
+	}
+}
+
+func TestStoreConcurrent(t *testing.T) {
+	store := open(t, t.TempDir(), smallOptions)
+	defer store.Close()
+	var wg sync.WaitGroup
+	for writer := 0; writer < 4; writer++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := 4*i + writer
+				if err := store.Put(key, fmt.Sprint(key)); err != nil {
+					t.Errorf("Got error %v", err)
+					return
+				}
+				if value, found, err := store.Get(key); value != fmt.Sprint(key) || !found || err != nil {
+					t.Errorf("Got %v,%v,%v expected %v,%v", value, found, err, key, true)
+					return
+				}
+			}
+		}(writer)
+	}
+	wg.Wait()
+	model := make(map[int]string)
+	for key := 0; key < 2000; key++ {
+		model[key] = fmt.Sprint(key)
+	}
+	assertContents(t, store, model, 2000)
+}
+
+// FuzzStore runs random changes, flushes, compactions and reopenings against a built-in map, three bytes per operation.
+func FuzzStore(f *testing.F) {
+	f.Add([]byte{0, 1, 1, 0, 2, 2, 3, 0, 0, 2, 1, 0, 0, 3, 3, 4, 0, 0, 1, 1, 4, 5, 0, 0, 2, 2, 0, 3, 0, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		dir := t.TempDir()
+		options := lsm.Options{MemtableSize: 64, BlockSize: 32, Level0Tables: 2, LevelSize: 256, LevelRatio: 2}
+		store := open(t, dir, options)
+		defer func() { store.Close() }()
+		model := make(map[int]string)
+		for i := 0; i+2 < len(data) && i < 3*64; i += 3 {
+			key, value := int(data[i+1]%16), fmt.Sprint(data[i+2])
+			var err error
+			switch data[i] % 6 {
+			case 0, 1:
+				err = store.Put(key, value)
+				model[key] = value
+			case 2:
+				err = store.Remove(key)
+				delete(model, key)
+			case 3:
+				err = store.Flush()
+			case 4:
+				err = store.Compact()
+			case 5:
+				err = store.Close()
+				store = open(t, dir, options)
+			}
+			if err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			assertContents(t, store, model, 16)
+		}
+	})
+}
+
+func BenchmarkStorePut(b *testing.B) {
+	store, _ := lsm.Open[int, string](b.TempDir(), utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), lsm.Options{MemtableSize: 1 << 20})
+	defer store.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		store.Put(i, "value")
+	}
+}
+
+func BenchmarkStoreGet(b *testing.B) {
+	store, _ := lsm.Open[int, string](b.TempDir(), utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), lsm.Options{MemtableSize: 1 << 20})
+	defer store.Close()
+	for i := 0; i < 100000; i++ {
+		store.Put(i, "value")
+	}
+	store.Compact()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		store.Get(i % 100000)
+	}
+}
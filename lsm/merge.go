@@ -0,0 +1,153 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsm
+
+import (
+	"sort"
+
+	"github.com/monitor1379/yagods/trees/binaryheap"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// source is a sorted run of entries, either a table or a snapshot of a memtable.
+type source[K comparable, V any] interface {
+	Next() bool
+	Seek(key K) bool
+	Key() K
+	Value() V
+	Deleted() bool
+	Err() error
+}
+
+// cursor is a source in the merge heap. Sources with a lower rank are newer and win on equal keys.
+type cursor[K comparable, V any] struct {
+	source source[K, V]
+	rank   int
+}
+
+// mergeIterator merges sorted sources into a single run in which every key appears once with its newest entry.
+// The sources are kept in a binary heap ordered by their current key, so that each step takes O(log k) time
+// for k sources.
+type mergeIterator[K comparable, V any] struct {
+	cursors    []*cursor[K, V]
+	heap       *binaryheap.Heap[*cursor[K, V]]
+	comparator utils.Comparator[K]
+	current    *cursor[K, V] // cursor of the current entry, nil before the first entry
+	err        error
+}
+
+// newMergeIterator merges the sources, ordered from newest to oldest.
+func newMergeIterator[K comparable, V any](comparator utils.Comparator[K], sources []source[K, V]) *mergeIterator[K, V] {
+	iterator := &mergeIterator[K, V]{comparator: comparator}
+	iterator.heap = binaryheap.NewWith(func(a, b *cursor[K, V]) int {
+		if order := comparator(a.source.Key(), b.source.Key()); order != 0 {
+			return order
+		}
+		return utils.NumberComparator(a.rank, b.rank)
+	})
+	for rank, source := range sources {
+		iterator.cursors = append(iterator.cursors, &cursor[K, V]{source: source, rank: rank})
+	}
+	for _, cursor := range iterator.cursors {
+		iterator.advance(cursor, cursor.source.Next())
+	}
+	return iterator
+}
+
+// Next moves the iterator to the next key and returns true if there was a next key.
+func (iterator *mergeIterator[K, V]) Next() bool {
+	if iterator.current != nil {
+		key := iterator.current.source.Key()
+		// skip the older entries of the current key
+		for {
+			cursor, ok := iterator.heap.Peek()
+			if !ok || iterator.comparator(cursor.source.Key(), key) != 0 {
+				break
+			}
+			iterator.heap.Pop()
+			iterator.advance(cursor, cursor.source.Next())
+		}
+	}
+	if iterator.err != nil {
+		iterator.current = nil
+		return false
+	}
+	// the current cursor stays in the heap until the iterator moves on
+	iterator.current, _ = iterator.heap.Peek()
+	return iterator.current != nil
+}
+
+// Seek positions all sources at the first key greater than or equal to the given key.
+// Next() then moves to that key.
+func (iterator *mergeIterator[K, V]) Seek(key K) {
+	iterator.heap.Clear()
+	iterator.current = nil
+	for _, cursor := range iterator.cursors {
+		iterator.advance(cursor, cursor.source.Seek(key))
+	}
+}
+
+// Key returns the current key.
+func (iterator *mergeIterator[K, V]) Key() K {
+	return iterator.current.source.Key()
+}
+
+// Value returns the newest value of the current key.
+func (iterator *mergeIterator[K, V]) Value() V {
+	return iterator.current.source.Value()
+}
+
+// Deleted returns true if the newest entry of the current key is a tombstone.
+func (iterator *mergeIterator[K, V]) Deleted() bool {
+	return iterator.current.source.Deleted()
+}
+
+// Err returns the first error of any source.
+func (iterator *mergeIterator[K, V]) Err() error {
+	return iterator.err
+}
+
+// advance pushes the cursor back into the heap if its source moved to an entry.
+func (iterator *mergeIterator[K, V]) advance(cursor *cursor[K, V], ok bool) {
+	if ok {
+		iterator.heap.Push(cursor)
+	} else if err := cursor.source.Err(); err != nil && iterator.err == nil {
+		iterator.err = err
+	}
+}
+
+// memtableSource iterates over a copy of the memtable, so that later writes do not affect it.
+type memtableSource[K comparable, V any] struct {
+	comparator utils.Comparator[K]
+	keys       []K
+	entries    []entry[V]
+	position   int
+}
+
+func (source *memtableSource[K, V]) Next() bool {
+	source.position++
+	return source.position < len(source.keys)
+}
+
+func (source *memtableSource[K, V]) Seek(key K) bool {
+	source.position = sort.Search(len(source.keys), func(i int) bool { return source.comparator(source.keys[i], key) >= 0 })
+	return source.position < len(source.keys)
+}
+
+func (source *memtableSource[K, V]) Key() K {
+	return source.keys[source.position]
+}
+
+func (source *memtableSource[K, V]) Value() V {
+	return source.entries[source.position].value
+}
+
+func (source *memtableSource[K, V]) Deleted() bool {
+	return source.entries[source.position].deleted
+}
+
+func (source *memtableSource[K, V]) Err() error {
+	return nil
+}
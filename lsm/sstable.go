@@ -0,0 +1,431 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// Sorted string table layout
+//
+//	data block * n | index block | bloom block | footer
+//
+// A data block holds entries in ascending key order, followed by the checksum of the block:
+//
+//	entry: flags byte | key length uvarint | key | value length uvarint | value
+//
+// The index block holds the last key, the offset and the length of every data block, followed by its checksum.
+// The bloom block holds the Bloom filter over all keys followed by its checksum, and is empty if filters are disabled.
+// The footer has a fixed size:
+//
+//	index offset uint64 | index length uint64 | bloom offset uint64 | bloom length uint64 | entries uint64 | magic uint64
+
+const (
+	footerSize   = 6 * 8
+	tableMagic   = 0x5941474f44535354 // "YAGODSST"
+	tombstone    = 1
+	checksumSize = 4
+)
+
+// ErrCorruptTable is returned when a table is truncated or one of its blocks fails its checksum.
+var ErrCorruptTable = errors.New("lsm: corrupt table")
+
+// TableWriter writes entries in ascending key order into a sorted string table.
+type TableWriter[K comparable, V any] struct {
+	writer          io.Writer
+	comparator      utils.Comparator[K]
+	keyCodec        containers.Codec[K]
+	valueCodec      containers.Codec[V]
+	blockSize       int
+	bloomBitsPerKey int
+
+	block    []byte   // current data block
+	index    []byte   // index block written so far
+	keys     [][]byte // encoded keys for the Bloom filter
+	lastKey  K
+	last     []byte // encoded last key
+	entries  int
+	offset   uint64
+	finished bool
+}
+
+// NewTableWriter instantiates a writer of a table into the writer, which is not closed by the table writer.
+// Data blocks are cut once they exceed blockSize bytes. A Bloom filter of bloomBitsPerKey bits for every key
+// is written if bloomBitsPerKey is positive.
+func NewTableWriter[K comparable, V any](writer io.Writer, comparator utils.Comparator[K], keyCodec containers.Codec[K], valueCodec containers.Codec[V], blockSize int, bloomBitsPerKey int) *TableWriter[K, V] {
+	return &TableWriter[K, V]{
+		writer:          writer,
+		comparator:      comparator,
+		keyCodec:        keyCodec,
+		valueCodec:      valueCodec,
+		blockSize:       blockSize,
+		bloomBitsPerKey: bloomBitsPerKey,
+	}
+}
+
+// Add appends a key-value pair to the table.
+// Returns an error wrapping containers.ErrNotSorted if the key is not greater than the previous key.
+func (writer *TableWriter[K, V]) Add(key K, value V) error {
+	encodedValue, err := writer.valueCodec.Encode(value)
+	if err != nil {
+		return err
+	}
+	return writer.add(key, encodedValue, 0)
+}
+
+// AddTombstone appends a marker that the key was removed, which hides the key in older tables.
+// Returns an error wrapping containers.ErrNotSorted if the key is not greater than the previous key.
+func (writer *TableWriter[K, V]) AddTombstone(key K) error {
+	return writer.add(key, nil, tombstone)
+}
+
+// Entries returns the number of entries added so far.
+func (writer *TableWriter[K, V]) Entries() int {
+	return writer.entries
+}
+
+// Finish writes the last data block, the index, the Bloom filter and the footer.
+func (writer *TableWriter[K, V]) Finish() error {
+	if writer.finished {
+		return errors.New("lsm: table already finished")
+	}
+	writer.finished = true
+	if err := writer.flushBlock(); err != nil {
+		return err
+	}
+	indexOffset, indexLength := writer.offset, uint64(len(writer.index)+checksumSize)
+	if err := writer.write(withChecksum(writer.index)); err != nil {
+		return err
+	}
+	bloomOffset, bloomLength := writer.offset, uint64(0)
+	if writer.bloomBitsPerKey > 0 {
+		filter := newBloomFilter(writer.keys, writer.bloomBitsPerKey)
+		bloomLength = uint64(len(filter) + checksumSize)
+		if err := writer.write(withChecksum(filter)); err != nil {
+			return err
+		}
+	}
+	footer := make([]byte, footerSize)
+	for i, field := range []uint64{indexOffset, indexLength, bloomOffset, bloomLength, uint64(writer.entries), tableMagic} {
+		binary.BigEndian.PutUint64(footer[i*8:], field)
+	}
+	return writer.write(footer)
+}
+
+func (writer *TableWriter[K, V]) add(key K, value []byte, flags byte) error {
+	if writer.finished {
+		return errors.New("lsm: table already finished")
+	}
+	if writer.entries > 0 && writer.comparator(key, writer.lastKey) <= 0 {
+		return fmt.Errorf("lsm: key at index %d: %w", writer.entries, containers.ErrNotSorted)
+	}
+	encodedKey, err := writer.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	writer.block = append(writer.block, flags)
+	writer.block = appendBytes(writer.block, encodedKey)
+	writer.block = appendBytes(writer.block, value)
+	writer.lastKey, writer.last = key, encodedKey
+	writer.entries++
+	if writer.bloomBitsPerKey > 0 {
+		writer.keys = append(writer.keys, encodedKey)
+	}
+	if len(writer.block) >= writer.blockSize {
+		return writer.flushBlock()
+	}
+	return nil
+}
+
+func (writer *TableWriter[K, V]) flushBlock() error {
+	if len(writer.block) == 0 {
+		return nil
+	}
+	offset := writer.offset
+	if err := writer.write(withChecksum(writer.block)); err != nil {
+		return err
+	}
+	writer.index = appendBytes(writer.index, writer.last)
+	writer.index = appendUvarint(writer.index, offset)
+	writer.index = appendUvarint(writer.index, writer.offset-offset)
+	writer.block = writer.block[:0]
+	return nil
+}
+
+func (writer *TableWriter[K, V]) write(data []byte) error {
+	n, err := writer.writer.Write(data)
+	writer.offset += uint64(n)
+	return err
+}
+
+// Table reads a sorted string table. The index and the Bloom filter are kept in memory, data blocks are read on demand.
+type Table[K comparable, V any] struct {
+	reader     io.ReaderAt
+	comparator utils.Comparator[K]
+	keyCodec   containers.Codec[K]
+	valueCodec containers.Codec[V]
+	blocks     []blockHandle[K]
+	bloom      bloomFilter // nil if the table has no Bloom filter
+	entries    int
+}
+
+type blockHandle[K comparable] struct {
+	lastKey K
+	offset  uint64
+	length  uint64
+}
+
+// block holds the decoded entries of a data block
+type block[K comparable, V any] struct {
+	keys    []K
+	values  []V
+	deleted []bool
+}
+
+// OpenTable opens the table of the given size in bytes held by the reader.
+func OpenTable[K comparable, V any](reader io.ReaderAt, size int64, comparator utils.Comparator[K], keyCodec containers.Codec[K], valueCodec containers.Codec[V]) (*Table[K, V], error) {
+	if size < footerSize {
+		return nil, ErrCorruptTable
+	}
+	footer := make([]byte, footerSize)
+	if _, err := reader.ReadAt(footer, size-footerSize); err != nil {
+		return nil, err
+	}
+	var fields [6]uint64
+	for i := range fields {
+		fields[i] = binary.BigEndian.Uint64(footer[i*8:])
+	}
+	indexOffset, indexLength, bloomOffset, bloomLength, entries, magic := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+	if magic != tableMagic || indexOffset+indexLength > uint64(size) || bloomOffset+bloomLength > uint64(size) {
+		return nil, ErrCorruptTable
+	}
+	table := &Table[K, V]{reader: reader, comparator: comparator, keyCodec: keyCodec, valueCodec: valueCodec, entries: int(entries)}
+
+	index, err := table.read(indexOffset, indexLength)
+	if err != nil {
+		return nil, err
+	}
+	for len(index) > 0 {
+		var key []byte
+		var handle blockHandle[K]
+		if key, index, err = readBytes(index); err != nil {
+			return nil, err
+		}
+		if handle.offset, index, err = readUvarint(index); err != nil {
+			return nil, err
+		}
+		if handle.length, index, err = readUvarint(index); err != nil {
+			return nil, err
+		}
+		if handle.lastKey, err = keyCodec.Decode(key); err != nil {
+			return nil, err
+		}
+		table.blocks = append(table.blocks, handle)
+	}
+	if bloomLength > 0 {
+		if table.bloom, err = table.read(bloomOffset, bloomLength); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+// Entries returns the number of entries in the table, including tombstones.
+func (table *Table[K, V]) Entries() int {
+	return table.entries
+}
+
+// Get searches the key in the table and returns its value.
+// Second return parameter is true if the table holds a tombstone for the key, third is true if the key was found.
+func (table *Table[K, V]) Get(key K) (value V, deleted bool, found bool, err error) {
+	if table.bloom != nil {
+		encodedKey, err := table.keyCodec.Encode(key)
+		if err != nil {
+			return value, false, false, err
+		}
+		if !table.bloom.mayContain(encodedKey) {
+			return value, false, false, nil
+		}
+	}
+	index := table.search(key)
+	if index == len(table.blocks) {
+		return value, false, false, nil
+	}
+	block, err := table.block(index)
+	if err != nil {
+		return value, false, false, err
+	}
+	position := sort.Search(len(block.keys), func(i int) bool { return table.comparator(block.keys[i], key) >= 0 })
+	if position < len(block.keys) && table.comparator(block.keys[position], key) == 0 {
+		return block.values[position], block.deleted[position], true, nil
+	}
+	return value, false, false, nil
+}
+
+// Iterator returns an iterator over all entries of the table, including tombstones.
+func (table *Table[K, V]) Iterator() *TableIterator[K, V] {
+	return &TableIterator[K, V]{table: table, index: -1}
+}
+
+// search returns the index of the first block whose last key is greater than or equal to the key.
+func (table *Table[K, V]) search(key K) int {
+	return sort.Search(len(table.blocks), func(i int) bool { return table.comparator(table.blocks[i].lastKey, key) >= 0 })
+}
+
+// read reads a block and verifies its checksum, returning the block without checksum.
+func (table *Table[K, V]) read(offset uint64, length uint64) ([]byte, error) {
+	if length < checksumSize {
+		return nil, ErrCorruptTable
+	}
+	data := make([]byte, length)
+	if _, err := table.reader.ReadAt(data, int64(offset)); err != nil {
+		if err == io.EOF {
+			return nil, ErrCorruptTable
+		}
+		return nil, err
+	}
+	data, checksum := data[:length-checksumSize], data[length-checksumSize:]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(checksum) {
+		return nil, ErrCorruptTable
+	}
+	return data, nil
+}
+
+// block reads and decodes the data block at the index.
+func (table *Table[K, V]) block(index int) (*block[K, V], error) {
+	handle := table.blocks[index]
+	data, err := table.read(handle.offset, handle.length)
+	if err != nil {
+		return nil, err
+	}
+	block := &block[K, V]{}
+	for len(data) > 0 {
+		flags := data[0]
+		var key, value []byte
+		if key, data, err = readBytes(data[1:]); err != nil {
+			return nil, err
+		}
+		if value, data, err = readBytes(data); err != nil {
+			return nil, err
+		}
+		decodedKey, err := table.keyCodec.Decode(key)
+		if err != nil {
+			return nil, err
+		}
+		var decodedValue V
+		if flags&tombstone == 0 {
+			if decodedValue, err = table.valueCodec.Decode(value); err != nil {
+				return nil, err
+			}
+		}
+		block.keys = append(block.keys, decodedKey)
+		block.values = append(block.values, decodedValue)
+		block.deleted = append(block.deleted, flags&tombstone != 0)
+	}
+	return block, nil
+}
+
+// TableIterator iterates over the entries of a table in ascending key order, reading one data block at a time.
+type TableIterator[K comparable, V any] struct {
+	table    *Table[K, V]
+	index    int // index of the current block, -1 before the first block
+	block    *block[K, V]
+	position int // position of the current entry within the block
+	err      error
+}
+
+// Next moves the iterator to the next entry and returns true if there was a next entry.
+// Returns false if a block could not be read, see Err().
+func (iterator *TableIterator[K, V]) Next() bool {
+	if iterator.block != nil && iterator.position+1 < len(iterator.block.keys) {
+		iterator.position++
+		return true
+	}
+	return iterator.load(iterator.index+1, 0)
+}
+
+// Seek moves the iterator to the first entry whose key is greater than or equal to the given key
+// and returns true if there was such an entry.
+// Next() continues after that entry.
+func (iterator *TableIterator[K, V]) Seek(key K) bool {
+	iterator.err = nil
+	index := iterator.table.search(key)
+	if !iterator.load(index, 0) {
+		return false
+	}
+	keys := iterator.block.keys
+	iterator.position = sort.Search(len(keys), func(i int) bool { return iterator.table.comparator(keys[i], key) >= 0 })
+	return true
+}
+
+// Key returns the current entry's key.
+func (iterator *TableIterator[K, V]) Key() K {
+	return iterator.block.keys[iterator.position]
+}
+
+// Value returns the current entry's value, the zero value for a tombstone.
+func (iterator *TableIterator[K, V]) Value() V {
+	return iterator.block.values[iterator.position]
+}
+
+// Deleted returns true if the current entry is a tombstone.
+func (iterator *TableIterator[K, V]) Deleted() bool {
+	return iterator.block.deleted[iterator.position]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (iterator *TableIterator[K, V]) Err() error {
+	return iterator.err
+}
+
+// load positions the iterator at the entry of the block, returning false past the last block or on error.
+func (iterator *TableIterator[K, V]) load(index int, position int) bool {
+	iterator.index, iterator.block, iterator.position = index, nil, position
+	if iterator.err != nil || index >= len(iterator.table.blocks) {
+		iterator.index = len(iterator.table.blocks)
+		return false
+	}
+	iterator.block, iterator.err = iterator.table.block(index)
+	return iterator.err == nil && position < len(iterator.block.keys)
+}
+
+func withChecksum(data []byte) []byte {
+	var checksum [checksumSize]byte
+	binary.BigEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(data))
+	return append(append(make([]byte, 0, len(data)+checksumSize), data...), checksum[:]...)
+}
+
+func appendUvarint(buffer []byte, n uint64) []byte {
+	var encoded [binary.MaxVarintLen64]byte
+	return append(buffer, encoded[:binary.PutUvarint(encoded[:], n)]...)
+}
+
+func appendBytes(buffer []byte, data []byte) []byte {
+	return append(appendUvarint(buffer, uint64(len(data))), data...)
+}
+
+func readUvarint(data []byte) (uint64, []byte, error) {
+	n, size := binary.Uvarint(data)
+	if size <= 0 {
+		return 0, nil, ErrCorruptTable
+	}
+	return n, data[size:], nil
+}
+
+func readBytes(data []byte) ([]byte, []byte, error) {
+	length, data, err := readUvarint(data)
+	if err != nil || length > uint64(len(data)) {
+		return nil, nil, ErrCorruptTable
+	}
+	return data[:length], data[length:], nil
+}
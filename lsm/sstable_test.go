@@ -0,0 +1,176 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsm_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/lsm"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// writeTable writes the keys 0, 2, 4, ... below 2*n with tombstones for multiples of 10
+func writeTable(t *testing.T, n int, blockSize int, bloomBitsPerKey int) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := lsm.NewTableWriter[int, string](&buffer, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), blockSize, bloomBitsPerKey)
+	for key := 0; key < 2*n; key += 2 {
+		var err error
+		if key%10 == 0 {
+			err = writer.AddTombstone(key)
+		} else {
+			err = writer.Add(key, fmt.Sprint(key))
+		}
+		if err != nil {
+			t.Fatalf("Got error %v", err)
+		}
+	}
+	if err := writer.Finish(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func openTable(t *testing.T, data []byte) *lsm.Table[int, string] {
+	t.Helper()
+	table, err := lsm.OpenTable[int, string](bytes.NewReader(data), int64(len(data)), utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string]())
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	return table
+}
+
+func TestTableGet(t *testing.T) {
+	for _, bloomBitsPerKey := range []int{0, 10} {
+		table := openTable(t, writeTable(t, 1000, 128, bloomBitsPerKey))
+		if actualValue, expectedValue := table.Entries(), 1000; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		for key := -1; key <= 2000; key++ {
+			value, deleted, found, err := table.Get(key)
+			if err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			if actualValue, expectedValue := found, key >= 0 && key < 2000 && key%2 == 0; actualValue != expectedValue {
+				t.Fatalf("Got %v expected %v for key %v", actualValue, expectedValue, key)
+			}
+			if actualValue, expectedValue := deleted, found && key%10 == 0; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v for key %v", actualValue, expectedValue, key)
+			}
+			if found && !deleted && value != fmt.Sprint(key) {
+				t.Errorf("Got %v expected %v", value, key)
+			}
+		}
+	}
+}
+
+func TestTableIterator(t *testing.T) {
+	table := openTable(t, writeTable(t, 500, 64, 10))
+	it := table.Iterator()
+	count := 0
+	for it.Next() {
+		if actualValue, expectedValue := it.Key(), 2*count; actualValue != expectedValue {
+			t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := it.Deleted(), count%5 == 0; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		count++
+	}
+	if actualValue, expectedValue := count, 500; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if it.Next() {
+		t.Errorf("Got %v expected %v", true, false)
+	}
+
+	if !it.Seek(501) || it.Key() != 502 || it.Value() != "502" {
+		t.Errorf("Got %v expected %v", it.Key(), 502)
+	}
+	if !it.Next() || it.Key() != 504 {
+		t.Errorf("Got %v expected %v", it.Key(), 504)
+	}
+	if !it.Seek(-5) || it.Key() != 0 {
+		t.Errorf("Got %v expected %v", it.Key(), 0)
+	}
+	if it.Seek(999) {
+		t.Errorf("Got %v expected %v", true, false)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+}
+
+func TestTableWriterNotSorted(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := lsm.NewTableWriter[int, string](&buffer, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), 4096, 10)
+	if err := writer.Add(2, "b"); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if err := writer.Add(2, "b"); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+	if err := writer.AddTombstone(1); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+}
+
+func TestTableEmpty(t *testing.T) {
+	table := openTable(t, writeTable(t, 0, 4096, 10))
+	if _, _, found, err := table.Get(1); found || err != nil {
+		t.Errorf("Got %v, %v expected %v", found, err, false)
+	}
+	if table.Iterator().Next() {
+		t.Errorf("Got %v expected %v", true, false)
+	}
+}
+
+func TestTableCorrupt(t *testing.T) {
+	data := writeTable(t, 100, 128, 10)
+	if _, err := lsm.OpenTable[int, string](bytes.NewReader(data[:len(data)-1]), int64(len(data)-1), utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string]()); !errors.Is(err, lsm.ErrCorruptTable) {
+		t.Errorf("Got %v expected %v", err, lsm.ErrCorruptTable)
+	}
+
+	data[10] ^= 0xff // inside the first data block
+	table := openTable(t, data)
+	if _, _, _, err := table.Get(2); !errors.Is(err, lsm.ErrCorruptTable) {
+		t.Errorf("Got %v expected %v", err, lsm.ErrCorruptTable)
+	}
+	it := table.Iterator()
+	if it.Next() {
+		t.Errorf("Got %v expected %v", true, false)
+	}
+	if err := it.Err(); !errors.Is(err, lsm.ErrCorruptTable) {
+		t.Errorf("Got %v expected %v", err, lsm.ErrCorruptTable)
+	}
+}
+
+func benchmarkTableGet(b *testing.B, bloomBitsPerKey int) {
+	var buffer bytes.Buffer
+	writer := lsm.NewTableWriter[int, string](&buffer, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), 4096, bloomBitsPerKey)
+	for key := 0; key < 100000; key += 2 {
+		writer.Add(key, "value")
+	}
+	writer.Finish()
+	data := buffer.Bytes()
+	table, _ := lsm.OpenTable[int, string](bytes.NewReader(data), int64(len(data)), utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string]())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// odd keys are missing
+		table.Get(2*(i%50000) + 1)
+	}
+}
+
+func BenchmarkTableGetMissingWithoutBloomFilter(b *testing.B) {
+	benchmarkTableGet(b, 0)
+}
+
+func BenchmarkTableGetMissingWithBloomFilter(b *testing.B) {
+	benchmarkTableGet(b, 10)
+}
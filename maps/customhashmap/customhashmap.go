@@ -0,0 +1,166 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package customhashmap implements a map backed by a hash table that hashes and compares its keys by a utils.Hasher.
+//
+// Unlike hashmap, keys need not be comparable with ==: byte slices or other slices can be keys,
+// and keys can be equal other than by ==, e.g. strings regardless of their case.
+// Keys are not copied, so a key such as a byte slice must not be modified while it is in the map.
+//
+// Elements are unordered in the map.
+//
+// Structure is not thread safe.
+//
+// Reference: http://en.wikipedia.org/wiki/Associative_array
+package customhashmap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/monitor1379/yagods/maps"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ maps.Map[string, int] = (*Map[string, int])(nil)
+var _ maps.Map[[]byte, int] = (*Map[[]byte, int])(nil)
+
+// Map holds the elements in buckets of entries whose keys have the same hash.
+type Map[K any, V any] struct {
+	hasher  utils.Hasher[K]
+	buckets map[uint64][]entry[K, V]
+	size    int
+}
+
+type entry[K any, V any] struct {
+	key   K
+	value V
+}
+
+// New instantiates a hash map hashing and comparing its keys by the hasher.
+func New[K any, V any](hasher utils.Hasher[K]) *Map[K, V] {
+	return &Map[K, V]{hasher: hasher, buckets: make(map[uint64][]entry[K, V])}
+}
+
+// Hasher returns the hasher of the keys.
+func (m *Map[K, V]) Hasher() utils.Hasher[K] {
+	return m.hasher
+}
+
+// find returns the hash of the key and the index of its entry in its bucket, or -1 if the key is not found.
+func (m *Map[K, V]) find(key K) (uint64, int) {
+	hash := m.hasher.Hash(key)
+	for i, e := range m.buckets[hash] {
+		if m.hasher.Equal(e.key, key) {
+			return hash, i
+		}
+	}
+	return hash, -1
+}
+
+// Put inserts element into the map.
+// If the key is already present, its value is replaced and the key is kept as it was first put.
+func (m *Map[K, V]) Put(key K, value V) {
+	hash, i := m.find(key)
+	if i >= 0 {
+		m.buckets[hash][i].value = value
+		return
+	}
+	m.buckets[hash] = append(m.buckets[hash], entry[K, V]{key: key, value: value})
+	m.size++
+}
+
+// Get searches the element in the map by key and returns its value or the zero value if key is not found in map.
+// Second return parameter is true if key was found, otherwise false.
+func (m *Map[K, V]) Get(key K) (value V, found bool) {
+	if hash, i := m.find(key); i >= 0 {
+		return m.buckets[hash][i].value, true
+	}
+	return value, false
+}
+
+// Remove removes the element from the map by key.
+func (m *Map[K, V]) Remove(key K) {
+	hash, i := m.find(key)
+	if i < 0 {
+		return
+	}
+	bucket := m.buckets[hash]
+	if len(bucket) == 1 {
+		delete(m.buckets, hash)
+	} else {
+		last := len(bucket) - 1
+		bucket[i] = bucket[last]
+		bucket[last] = entry[K, V]{} // let the key and value be collected
+		m.buckets[hash] = bucket[:last]
+	}
+	m.size--
+}
+
+// Empty returns true if map does not contain any elements
+func (m *Map[K, V]) Empty() bool {
+	return m.Size() == 0
+}
+
+// Size returns number of elements in the map.
+func (m *Map[K, V]) Size() int {
+	return m.size
+}
+
+// Keys returns all keys (random order).
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.size)
+	for _, bucket := range m.buckets {
+		for _, e := range bucket {
+			keys = append(keys, e.key)
+		}
+	}
+	return keys
+}
+
+// Values returns all values (random order).
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, m.size)
+	for _, bucket := range m.buckets {
+		for _, e := range bucket {
+			values = append(values, e.value)
+		}
+	}
+	return values
+}
+
+// InterfaceValues returns all elements in the map as type interface{}.
+func (m *Map[K, V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, 0, m.size)
+	for _, value := range m.Values() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Clear removes all elements from the map.
+func (m *Map[K, V]) Clear() {
+	m.buckets = make(map[uint64][]entry[K, V])
+	m.size = 0
+}
+
+// each calls the function once for every element, in random order.
+func (m *Map[K, V]) each(f func(key K, value V)) {
+	for _, bucket := range m.buckets {
+		for _, e := range bucket {
+			f(e.key, e.value)
+		}
+	}
+}
+
+// String returns a string representation of container
+func (m *Map[K, V]) String() string {
+	str := "CustomHashMap\n"
+	entries := []string{}
+	m.each(func(key K, value V) {
+		entries = append(entries, fmt.Sprintf("%v:%v", key, value))
+	})
+	str += "map[" + strings.Join(entries, " ") + "]"
+	return str
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package customhashmap_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
+	"github.com/monitor1379/yagods/maps/customhashmap"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// sortedStrings returns the values formatted by fmt.Sprint in ascending order.
+func sortedStrings[T any](values []T) []string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = fmt.Sprint(value)
+	}
+	sort.Strings(strs)
+	return strs
+}
+
+func TestMapPut(t *testing.T) {
+	m := customhashmap.New[int, string](utils.NewComparableHasher[int]())
+	m.Put(5, "e")
+	m.Put(6, "f")
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(4, "d")
+	m.Put(1, "x")
+	m.Put(2, "b")
+	m.Put(1, "a") //overwrite
+
+	if actualValue := m.Size(); actualValue != 7 {
+		t.Errorf("Got %v expected %v", actualValue, 7)
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Keys())), "[1 2 3 4 5 6 7]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Values())), "[a b c d e f g]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// key,expectedValue,expectedFound
+	tests1 := [][]interface{}{
+		{1, "a", true},
+		{2, "b", true},
+		{3, "c", true},
+		{4, "d", true},
+		{5, "e", true},
+		{6, "f", true},
+		{7, "g", true},
+		{8, "", false},
+	}
+
+	for _, test := range tests1 {
+		// retrievals
+		actualValue, actualFound := m.Get(test[0].(int))
+		if actualValue != test[1] || actualFound != test[2] {
+			t.Errorf("Got %v expected %v", actualValue, test[1])
+		}
+	}
+}
+
+func TestMapRemove(t *testing.T) {
+	m := customhashmap.New[int, string](utils.NewComparableHasher[int]())
+	m.Put(5, "e")
+	m.Put(6, "f")
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(4, "d")
+	m.Put(1, "x")
+	m.Put(2, "b")
+	m.Put(1, "a") //overwrite
+
+	m.Remove(5)
+	m.Remove(6)
+	m.Remove(7)
+	m.Remove(8)
+	m.Remove(5)
+
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Keys())), "[1 2 3 4]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := m.Size(); actualValue != 4 {
+		t.Errorf("Got %v expected %v", actualValue, 4)
+	}
+
+	m.Remove(1)
+	m.Remove(4)
+	m.Remove(2)
+	m.Remove(3)
+	m.Remove(2)
+	m.Remove(2)
+
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := m.Size(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+	if actualValue := m.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestMapBytesKeys(t *testing.T) {
+	m := customhashmap.New[[]byte, int](utils.NewBytesHasher())
+	m.Put([]byte("a"), 1)
+	m.Put([]byte("b"), 2)
+	m.Put(nil, 0)
+	m.Put([]byte{}, 3) // same key as nil
+
+	if actualValue := m.Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Keys())), "[[97] [98] []]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found := m.Get(nil); value != 3 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 3, true)
+	}
+	if value, found := m.Get([]byte("a")); value != 1 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 1, true)
+	}
+	m.Remove([]byte("b"))
+	if value, found := m.Get([]byte("b")); found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 0, false)
+	}
+	if actualValue := m.Size(); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+}
+
+func TestMapCaseInsensitiveKeys(t *testing.T) {
+	m := customhashmap.New[string, int](utils.NewCaseInsensitiveStringHasher())
+	m.Put("Content-Type", 1)
+	m.Put("content-type", 2)
+	m.Put("ACCEPT", 3)
+
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Keys())), "[ACCEPT Content-Type]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found := m.Get("CONTENT-TYPE"); value != 2 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 2, true)
+	}
+	m.Remove("accept")
+	if actualValue := m.Size(); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+}
+
+func TestMapCollisions(t *testing.T) {
+	// every key has the same hash
+	hasher := utils.NewHasher(func(key int) uint64 { return 0 }, utils.ComparableEqual[int])
+	m := customhashmap.New[int, string](hasher)
+	for i := 0; i < 10; i++ {
+		m.Put(i, fmt.Sprint(i))
+	}
+	for i := 0; i < 10; i += 2 {
+		m.Remove(i)
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Values())), "[1 3 5 7 9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for i := 0; i < 10; i++ {
+		if value, found := m.Get(i); found != (i%2 == 1) || found && value != fmt.Sprint(i) {
+			t.Errorf("Got %v,%v for key %v", value, found, i)
+		}
+	}
+}
+
+func TestMapString(t *testing.T) {
+	m := customhashmap.New[string, int](utils.NewStringHasher())
+	m.Put("a", 1)
+	if actualValue, expectedValue := m.String(), "CustomHashMap\nmap[a:1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapSerialization(t *testing.T) {
+	m := customhashmap.New[string, float64](utils.NewStringHasher())
+	m.Put("a", 1.0)
+	m.Put("b", 2.0)
+	m.Put("c", 3.0)
+
+	var err error
+	assert := func() {
+		if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Keys())), "[a b c]"; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(sortedStrings(m.Values())), "[1 2 3]"; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := m.Size(), 3; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+
+	assert()
+
+	json, err := m.ToJSON()
+	assert()
+
+	err = m.FromJSON(json)
+	assert()
+}
+
+func TestMapBinarySerialization(t *testing.T) {
+	m := customhashmap.New[[]byte, string](utils.NewBytesHasher())
+	m.Put([]byte("ab"), "a")
+	m.Put([]byte("cd"), "b")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := customhashmap.New[[]byte, string](utils.NewBytesHasher())
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(restored.Values())), "[a b]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found := restored.Get([]byte("cd")); value != "b" || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "b", true)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = customhashmap.New[[]byte, string](utils.NewBytesHasher())
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if value, found := restored.Get([]byte("cd")); value != "b" || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "b", true)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	var zero customhashmap.Map[[]byte, string]
+	if err := zero.UnmarshalBinary(data); !errors.Is(err, containers.ErrNoHasher) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoHasher)
+	}
+	if _, err := m.MarshalJSON(); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := customhashmap.New[string, int](utils.NewCaseInsensitiveStringHasher())
+	m.Put("A", 1)
+
+	data, err := json.Marshal(struct {
+		Map *customhashmap.Map[string, int]
+	}{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"A":1}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restored := struct {
+		Map *customhashmap.Map[string, int]
+	}{customhashmap.New[string, int](utils.NewCaseInsensitiveStringHasher())}
+	if err := json.Unmarshal([]byte(`{"Map":{"A":1,"a":2,"b":3}}`), &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if value, found := restored.Map.Get("B"); value != 3 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 3, true)
+	}
+	if actualValue, expectedValue := restored.Map.Size(), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	var zero struct {
+		Map customhashmap.Map[string, int]
+	}
+	if err := json.Unmarshal(data, &zero); !errors.Is(err, containers.ErrNoHasher) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoHasher)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := customhashmap.New[int, string](utils.NewComparableHasher[int]())
+	m.Put(2, "b")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"2":"b"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restored := customhashmap.New[int, string](utils.NewComparableHasher[int]())
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Size()), fmt.Sprint(m.Size()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestMapConformance(t *testing.T) {
+	containertest.TestMap(t, func() maps.Map[int, string] {
+		return customhashmap.New[int, string](utils.NewComparableHasher[int]())
+	})
+}
+
+func FuzzMap(f *testing.F) {
+	// few distinct hashes, so that keys collide
+	hasher := utils.NewHasher(func(key int) uint64 { return uint64(key % 3) }, utils.ComparableEqual[int])
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return customhashmap.New[int, string](hasher) }, containertest.Unordered)
+}
+
+func benchmarkGet(b *testing.B, m *customhashmap.Map[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			m.Get(n)
+		}
+	}
+}
+
+func benchmarkPut(b *testing.B, m *customhashmap.Map[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			m.Put(n, struct{}{})
+		}
+	}
+}
+
+func BenchmarkCustomHashMapGet10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	m := customhashmap.New[int, struct{}](utils.NewComparableHasher[int]())
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkGet(b, m, size)
+}
+
+func BenchmarkCustomHashMapPut10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	m := customhashmap.New[int, struct{}](utils.NewComparableHasher[int]())
+	b.StartTimer()
+	benchmarkPut(b, m, size)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package customhashmap
+
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+var _ containers.JSONSerializer = (*Map[string, int])(nil)
+var _ containers.JSONDeserializer = (*Map[string, int])(nil)
+var _ encoding.BinaryMarshaler = (*Map[string, int])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[string, int])(nil)
+var _ gob.GobEncoder = (*Map[string, int])(nil)
+var _ gob.GobDecoder = (*Map[string, int])(nil)
+var _ json.Marshaler = (*Map[string, int])(nil)
+var _ json.Unmarshaler = (*Map[string, int])(nil)
+var _ containers.JSONWriter = (*Map[string, int])(nil)
+var _ containers.JSONReader = (*Map[string, int])(nil)
+
+// ToJSON outputs the JSON representation of the map, see MarshalJSON.
+func (m *Map[K, V]) ToJSON() ([]byte, error) {
+	return m.MarshalJSON()
+}
+
+// FromJSON populates the map from the input JSON representation, see UnmarshalJSON.
+func (m *Map[K, V]) FromJSON(data []byte) error {
+	return m.UnmarshalJSON(data)
+}
+
+// entries returns the keys and the values of the map, keys[i] holding values[i].
+func (m *Map[K, V]) entries() ([]K, []V) {
+	keys, values := make([]K, 0, m.size), make([]V, 0, m.size)
+	m.each(func(key K, value V) {
+		keys, values = append(keys, key), append(values, value)
+	})
+	return keys, values
+}
+
+// MarshalBinary outputs the binary representation of the map.
+// Keys and values are encoded by the codecs of containers.CodecFor, so that keys of any type round-trip.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	keys, values := m.entries()
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), keys, values)
+}
+
+// UnmarshalBinary populates the map from the input binary representation.
+// The map must have been instantiated by New, otherwise containers.ErrNoHasher is returned.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	if m.hasher == nil {
+		return containers.ErrNoHasher
+	}
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object in no particular order,
+// whose keys are converted as encoding/json converts the keys of a Go map (see containers.MarshalJSONEntries).
+// Keys that are not strings, integers or encoding.TextMarshaler, such as byte slices, cannot be converted to JSON.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	keys, values := m.entries()
+	return containers.MarshalJSONEntries(keys, values)
+}
+
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, see MarshalJSON.
+// The map must have been instantiated by New, otherwise containers.ErrNoHasher is returned.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	if m.hasher == nil {
+		return containers.ErrNoHasher
+	}
+	keys, values, err := containers.UnmarshalJSONEntries[K, V](data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// WriteJSON writes the JSON representation of the map to the writer, see MarshalJSON.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	data, err := m.MarshalJSON()
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	return err
+}
+
+// ReadJSON populates the map from the JSON representation read from the reader, putting every entry as it is decoded.
+// On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	if m.hasher == nil {
+		return containers.ErrNoHasher
+	}
+	m.Clear()
+	return containers.ReadJSONEntries(r, func(key K, value V) {
+		m.Put(key, value)
+	})
+}
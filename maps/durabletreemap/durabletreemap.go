@@ -0,0 +1,539 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package durabletreemap implements a tree map that survives restarts by logging its changes to disk.
+//
+// Every Put, Remove and Clear is appended to a write-ahead log before it is applied to the in-memory treemap.
+// Opening the map loads the last snapshot and replays the log written since. Compaction writes the whole map
+// into a new snapshot and starts an empty log, the snapshot replacing the old one atomically by renaming it.
+//
+// Records are checksummed. A record torn by a crash ends the log, which is truncated before it when the map
+// is opened. How often the log is synced to stable storage is configured by the SyncPolicy.
+//
+// Keys and values are converted to bytes by codecs (see containers.Codec).
+//
+// Structure is not thread safe.
+//
+// Reference: https://en.wikipedia.org/wiki/Write-ahead_logging
+package durabletreemap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/maps/treemap"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// SyncPolicy determines when the log is synced to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways syncs the log after every change, so that no acknowledged change is lost.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval syncs the log on a change if the last sync is older than Options.SyncInterval,
+	// so that at most the changes of that interval are lost if the operating system crashes.
+	SyncInterval
+	// SyncNever leaves syncing to the operating system and to explicit calls of Sync and Close.
+	// Changes survive a crash of the process, but not of the operating system.
+	SyncNever
+)
+
+// Options configures a durable map. The zero value syncs every change and never compacts automatically.
+type Options struct {
+	Sync         SyncPolicy    // when to sync the log
+	SyncInterval time.Duration // maximum time between syncs for SyncInterval
+	// CompactionThreshold is the number of log records after which the map is compacted automatically,
+	// provided the log holds more than twice as many records as the map has entries. Zero disables it.
+	// The change that triggers a compaction succeeds even if the compaction fails, see Map.Err.
+	CompactionThreshold int
+}
+
+// ErrCorruptSnapshot is returned when opening a map whose snapshot is incomplete or fails its checksums.
+var ErrCorruptSnapshot = errors.New("durabletreemap: corrupt snapshot")
+
+// ErrClosed is returned when changing a map after it was closed.
+var ErrClosed = errors.New("durabletreemap: map is closed")
+
+const (
+	snapshotName = "snapshot"
+	logPattern   = "wal-%016x.log"
+)
+
+var snapshotMagic = [8]byte{'Y', 'A', 'G', 'O', 'D', 'S', 'S', 'N'}
+
+// snapshotHeaderSize is the size of the snapshot header, followed by one record per entry:
+//
+//	magic [8]byte | generation uint64 | count uint64 | crc32 uint32 of the preceding fields
+const snapshotHeaderSize = 8 + 8 + 8 + 4
+
+// Map holds the elements in a treemap and logs all changes to a directory
+type Map[K comparable, V any] struct {
+	tree       *treemap.Map[K, V]
+	comparator utils.Comparator[K]
+	dir        string
+	keyCodec   containers.Codec[K]
+	valueCodec containers.Codec[V]
+	options    Options
+	log        *os.File  // current log, nil once the map is closed
+	generation uint64    // generation of the current log, the snapshot holds all earlier generations
+	records    int       // number of records in the current log
+	logSize    int64     // number of bytes of the current log
+	lastSync   time.Time // time of the last sync of the log
+	failed     error     // error that left the log in an unknown state, returned by all later changes
+	compactErr error     // error of the last automatic compaction, see Err
+	buffer     []byte
+}
+
+// Open opens the map stored in the directory, creating the directory if it does not exist.
+// The snapshot is loaded and the log is replayed into a treemap using the comparator.
+func Open[K comparable, V any](dir string, comparator utils.Comparator[K], keyCodec containers.Codec[K], valueCodec containers.Codec[V], options Options) (*Map[K, V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	m := &Map[K, V]{
+		tree:       treemap.NewWith[K, V](comparator),
+		comparator: comparator,
+		dir:        dir,
+		keyCodec:   keyCodec,
+		valueCodec: valueCodec,
+		options:    options,
+		lastSync:   time.Now(),
+	}
+	if err := m.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	generations, err := m.logGenerations()
+	if err != nil {
+		return nil, err
+	}
+	for _, generation := range generations {
+		if generation < m.generation {
+			// left behind by a compaction that crashed after replacing the snapshot
+			if err := os.Remove(m.logPath(generation)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := m.replay(generation); err != nil {
+			return nil, err
+		}
+		m.generation = generation
+	}
+	if m.log, err = os.OpenFile(m.logPath(m.generation), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644); err != nil {
+		return nil, err
+	}
+	info, err := m.log.Stat()
+	if err != nil {
+		m.log.Close()
+		return nil, err
+	}
+	m.logSize = info.Size()
+	return m, syncDir(dir)
+}
+
+// Put inserts element into the map after logging it.
+// The change is not applied if it cannot be logged, whose error is returned.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Put(key K, value V) error {
+	encodedKey, err := m.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	encodedValue, err := m.valueCodec.Encode(value)
+	if err != nil {
+		return err
+	}
+	if err := m.append(opPut, encodedKey, encodedValue); err != nil {
+		return err
+	}
+	m.tree.Put(key, value)
+	m.maybeCompact()
+	return nil
+}
+
+// Get searches the element in the map by key and returns its value or nil if key is not found in tree.
+// Second return parameter is true if key was found, otherwise false.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Get(key K) (value V, found bool) {
+	return m.tree.Get(key)
+}
+
+// Remove removes the element from the map by key after logging it.
+// Removing a key that is not in the map is not logged.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Remove(key K) error {
+	if _, found := m.tree.Get(key); !found {
+		return nil
+	}
+	encodedKey, err := m.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	if err := m.append(opRemove, encodedKey, nil); err != nil {
+		return err
+	}
+	m.tree.Remove(key)
+	m.maybeCompact()
+	return nil
+}
+
+// Clear removes all elements from the map after logging it.
+func (m *Map[K, V]) Clear() error {
+	if err := m.append(opClear, nil, nil); err != nil {
+		return err
+	}
+	m.tree.Clear()
+	m.maybeCompact()
+	return nil
+}
+
+// Empty returns true if map does not contain any elements
+func (m *Map[K, V]) Empty() bool {
+	return m.tree.Empty()
+}
+
+// Size returns number of elements in the map.
+func (m *Map[K, V]) Size() int {
+	return m.tree.Size()
+}
+
+// Keys returns all keys in-order
+func (m *Map[K, V]) Keys() []K {
+	return m.tree.Keys()
+}
+
+// Values returns all values in-order based on the key.
+func (m *Map[K, V]) Values() []V {
+	return m.tree.Values()
+}
+
+// Min returns the minimum key and its value from the tree map.
+// Returns nil, nil if map is empty.
+func (m *Map[K, V]) Min() (key K, value V) {
+	return m.tree.Min()
+}
+
+// Max returns the maximum key and its value from the tree map.
+// Returns nil, nil if map is empty.
+func (m *Map[K, V]) Max() (key K, value V) {
+	return m.tree.Max()
+}
+
+// Floor finds the floor key-value pair for the input key (see treemap.Map.Floor).
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+	return m.tree.Floor(key)
+}
+
+// Ceiling finds the ceiling key-value pair for the input key (see treemap.Map.Ceiling).
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+	return m.tree.Ceiling(key)
+}
+
+// Each calls the given function once for each element, passing that element's key and value.
+func (m *Map[K, V]) Each(f func(key K, value V)) {
+	m.tree.Each(f)
+}
+
+// Compact writes all elements into a new snapshot, which atomically replaces the old one, and starts an empty log.
+func (m *Map[K, V]) Compact() error {
+	if m.log == nil {
+		return ErrClosed
+	}
+	if m.failed != nil {
+		return m.failed
+	}
+	generation := m.generation + 1
+	if err := m.writeSnapshot(generation); err != nil {
+		return err
+	}
+	// from now on, opening the map discards the current log, so changes must go to the next one
+	log, err := os.OpenFile(m.logPath(generation), os.O_WRONLY|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0o644)
+	if err != nil {
+		m.failed = err
+		return err
+	}
+	previous, previousGeneration := m.log, m.generation
+	m.log, m.generation, m.records, m.logSize = log, generation, 0, 0
+	m.lastSync = time.Now()
+	err = syncDir(m.dir)
+	if closeErr := previous.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// the previous log is kept, in case the new snapshot does not survive a crash
+		return err
+	}
+	if err := os.Remove(m.logPath(previousGeneration)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Err returns the error of the last automatic compaction (see Options.CompactionThreshold), or nil if it succeeded.
+// A failed compaction does not undo the change that triggered it, which is logged, and is retried on later changes.
+// Once a failure leaves the log in an unknown state, Err returns that error, which all later changes return as well.
+func (m *Map[K, V]) Err() error {
+	if m.failed != nil {
+		return m.failed
+	}
+	return m.compactErr
+}
+
+// Sync syncs the log to stable storage.
+func (m *Map[K, V]) Sync() error {
+	if m.log == nil {
+		return ErrClosed
+	}
+	m.lastSync = time.Now()
+	return m.log.Sync()
+}
+
+// Close syncs and closes the log. The elements stay readable, but further changes return ErrClosed.
+func (m *Map[K, V]) Close() error {
+	if m.log == nil {
+		return ErrClosed
+	}
+	err := m.log.Sync()
+	if closeErr := m.log.Close(); err == nil {
+		err = closeErr
+	}
+	m.log = nil
+	return err
+}
+
+// String returns a string representation of container
+func (m *Map[K, V]) String() string {
+	return strings.Replace(m.tree.String(), "TreeMap", "DurableTreeMap", 1)
+}
+
+// append logs a change and syncs the log according to the policy.
+func (m *Map[K, V]) append(op operation, key []byte, value []byte) error {
+	if m.log == nil {
+		return ErrClosed
+	}
+	if m.failed != nil {
+		return m.failed
+	}
+	m.buffer = appendRecord(m.buffer[:0], op, key, value)
+	_, err := m.log.Write(m.buffer)
+	if err == nil {
+		switch m.options.Sync {
+		case SyncAlways:
+			err = m.Sync()
+		case SyncInterval:
+			if time.Since(m.lastSync) >= m.options.SyncInterval {
+				err = m.Sync()
+			}
+		}
+	}
+	if err != nil {
+		// drop the record, possibly written in part, as the change is not applied
+		if truncateErr := m.log.Truncate(m.logSize); truncateErr != nil {
+			m.failed = fmt.Errorf("durabletreemap: log not truncated after %v: %w", err, truncateErr)
+		}
+		return err
+	}
+	m.logSize += int64(len(m.buffer))
+	m.records++
+	return nil
+}
+
+// maybeCompact compacts the map once the log reaches the compaction threshold, keeping the error for Err.
+func (m *Map[K, V]) maybeCompact() {
+	if threshold := m.options.CompactionThreshold; threshold > 0 && m.records >= threshold && m.records > 2*m.tree.Size() {
+		m.compactErr = m.Compact()
+	}
+}
+
+// apply applies a logged change to the treemap.
+func (m *Map[K, V]) apply(op operation, encodedKey []byte, encodedValue []byte) error {
+	if op == opClear {
+		m.tree.Clear()
+		return nil
+	}
+	key, err := m.keyCodec.Decode(encodedKey)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case opPut:
+		value, err := m.valueCodec.Decode(encodedValue)
+		if err != nil {
+			return err
+		}
+		m.tree.Put(key, value)
+	case opRemove:
+		m.tree.Remove(key)
+	default:
+		return fmt.Errorf("durabletreemap: unknown operation %d", op)
+	}
+	return nil
+}
+
+func (m *Map[K, V]) logPath(generation uint64) string {
+	return filepath.Join(m.dir, fmt.Sprintf(logPattern, generation))
+}
+
+// logGenerations returns the generations of all logs in the directory in ascending order.
+func (m *Map[K, V]) logGenerations() ([]uint64, error) {
+	paths, err := filepath.Glob(filepath.Join(m.dir, "wal-*.log"))
+	if err != nil {
+		return nil, err
+	}
+	var generations []uint64
+	for _, path := range paths {
+		var generation uint64
+		if _, err := fmt.Sscanf(filepath.Base(path), logPattern, &generation); err == nil {
+			generations = append(generations, generation)
+		}
+	}
+	sort.Slice(generations, func(i, j int) bool { return generations[i] < generations[j] })
+	return generations, nil
+}
+
+// replay applies all records of the log and truncates the log after the last intact record.
+func (m *Map[K, V]) replay(generation uint64) error {
+	file, err := os.OpenFile(m.logPath(generation), os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	offset := int64(0)
+	m.records = 0
+	for {
+		op, key, value, size, err := readRecord(reader, &m.buffer)
+		if err == io.EOF {
+			return nil
+		}
+		if err == errTornRecord {
+			// the crash happened while appending, drop the partial record
+			if err := file.Truncate(offset); err != nil {
+				return err
+			}
+			return file.Sync()
+		}
+		if err := m.apply(op, key, value); err != nil {
+			return err
+		}
+		offset += int64(size)
+		m.records++
+	}
+}
+
+// writeSnapshot writes all elements into a temporary file and renames it to the snapshot once it is synced.
+//
+// Snapshot layout: magic [8]byte | generation uint64 | count uint64, followed by count put records.
+func (m *Map[K, V]) writeSnapshot(generation uint64) error {
+	path := filepath.Join(m.dir, snapshotName)
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	header := make([]byte, snapshotHeaderSize)
+	copy(header, snapshotMagic[:])
+	binary.BigEndian.PutUint64(header[8:], generation)
+	binary.BigEndian.PutUint64(header[16:], uint64(m.tree.Size()))
+	binary.BigEndian.PutUint32(header[24:], crc32.ChecksumIEEE(header[:24]))
+	writer.Write(header)
+	it := m.tree.Iterator()
+	for it.Next() && err == nil {
+		var key, value []byte
+		if key, err = m.keyCodec.Encode(it.Key()); err != nil {
+			break
+		}
+		if value, err = m.valueCodec.Encode(it.Value()); err != nil {
+			break
+		}
+		m.buffer = appendRecord(m.buffer[:0], opPut, key, value)
+		_, err = writer.Write(m.buffer)
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+	}
+	return err
+}
+
+// loadSnapshot loads the elements of the snapshot, if there is one, and the generation of the log that follows it.
+func (m *Map[K, V]) loadSnapshot() error {
+	file, err := os.Open(filepath.Join(m.dir, snapshotName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(file)
+	header := make([]byte, snapshotHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil || string(header[:8]) != string(snapshotMagic[:]) ||
+		crc32.ChecksumIEEE(header[:24]) != binary.BigEndian.Uint32(header[24:]) {
+		return ErrCorruptSnapshot
+	}
+	m.generation = binary.BigEndian.Uint64(header[8:])
+	count := binary.BigEndian.Uint64(header[16:])
+	// every record takes at least its header and two payload bytes
+	if count > uint64(info.Size()-snapshotHeaderSize)/(recordHeaderSize+2) {
+		return ErrCorruptSnapshot
+	}
+	keys, values := make([]K, 0, count), make([]V, 0, count)
+	for i := uint64(0); i < count; i++ {
+		op, encodedKey, encodedValue, _, err := readRecord(reader, &m.buffer)
+		if err != nil || op != opPut {
+			return ErrCorruptSnapshot
+		}
+		key, err := m.keyCodec.Decode(encodedKey)
+		if err != nil {
+			return err
+		}
+		value, err := m.valueCodec.Decode(encodedValue)
+		if err != nil {
+			return err
+		}
+		keys, values = append(keys, key), append(values, value)
+	}
+	// the snapshot was written in order, so the tree is built in linear time
+	if m.tree, err = treemap.FromSorted(m.comparator, keys, values); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptSnapshot, err)
+	}
+	return nil
+}
+
+// syncDir syncs the directory, making created, renamed and removed files durable.
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
@@ -0,0 +1,408 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package durabletreemap_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps/durabletreemap"
+	"github.com/monitor1379/yagods/maps/treemap"
+	"github.com/monitor1379/yagods/utils"
+)
+
+func open(t *testing.T, dir string, options durabletreemap.Options) *durabletreemap.Map[int, string] {
+	t.Helper()
+	m, err := durabletreemap.Open[int, string](dir, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), options)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	return m
+}
+
+func files(t *testing.T, dir string) string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return fmt.Sprint(names)
+}
+
+func TestDurableTreeMapReopen(t *testing.T) {
+	dir := t.TempDir()
+	m := open(t, dir, durabletreemap.Options{})
+	m.Put(5, "e")
+	m.Put(6, "f")
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(4, "d")
+	m.Put(1, "x")
+	m.Put(2, "b")
+	m.Put(1, "a") //overwrite
+	m.Remove(6)
+	m.Remove(8) // not logged
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 2 3 4 5 7]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if err := m.Put(8, "h"); !errors.Is(err, durabletreemap.ErrClosed) {
+		t.Errorf("Got %v expected %v", err, durabletreemap.ErrClosed)
+	}
+
+	m = open(t, dir, durabletreemap.Options{})
+	defer m.Close()
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 2 3 4 5 7]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), "[a b c d e g]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, value := m.Min(); key != 1 || value != "a" {
+		t.Errorf("Got %v,%v expected %v,%v", key, value, 1, "a")
+	}
+	if key, _, found := m.Floor(6); key != 5 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", key, found, 5, true)
+	}
+	if actualValue, expectedValue := m.String(), "DurableTreeMap\nmap[1:a 2:b 3:c 4:d 5:e 7:g]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	m.Clear()
+	m.Put(9, "i")
+	m.Close()
+	m = open(t, dir, durabletreemap.Options{Sync: durabletreemap.SyncNever})
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestDurableTreeMapTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	m := open(t, dir, durabletreemap.Options{Sync: durabletreemap.SyncInterval, SyncInterval: time.Hour})
+	m.Put(1, "a")
+	m.Put(2, "b")
+	m.Close()
+
+	logs, _ := filepath.Glob(filepath.Join(dir, "wal-*.log"))
+	data, _ := os.ReadFile(logs[0])
+	intact := len(data)
+
+	// a crash while appending the third record
+	m = open(t, dir, durabletreemap.Options{})
+	m.Put(3, "c")
+	m.Close()
+	data, _ = os.ReadFile(logs[0])
+	os.WriteFile(logs[0], data[:len(data)-3], 0o644)
+
+	m = open(t, dir, durabletreemap.Options{})
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 2]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if info, _ := os.Stat(logs[0]); info.Size() != int64(intact) {
+		t.Errorf("Got %v expected %v", info.Size(), intact)
+	}
+	m.Put(4, "d")
+	m.Close()
+
+	// a record whose checksum does not match
+	data, _ = os.ReadFile(logs[0])
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(logs[0], data, 0o644)
+
+	m = open(t, dir, durabletreemap.Options{})
+	defer m.Close()
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 2]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestDurableTreeMapCompact(t *testing.T) {
+	dir := t.TempDir()
+	m := open(t, dir, durabletreemap.Options{Sync: durabletreemap.SyncNever})
+	for i := 0; i < 100; i++ {
+		m.Put(i%10, fmt.Sprint(i))
+	}
+	m.Remove(0)
+	if err := m.Compact(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := files(t, dir), "[snapshot wal-0000000000000001.log]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.Put(10, "100")
+	m.Close()
+
+	m = open(t, dir, durabletreemap.Options{})
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 2 3 4 5 6 7 8 9 10]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), "[91 92 93 94 95 96 97 98 99 100]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// a compaction that crashed after replacing the snapshot leaves the previous log behind
+	stale, _ := os.ReadFile(filepath.Join(dir, "wal-0000000000000001.log"))
+	m.Compact()
+	m.Close()
+	os.WriteFile(filepath.Join(dir, "wal-0000000000000001.log"), stale, 0o644)
+
+	m = open(t, dir, durabletreemap.Options{})
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 2 3 4 5 6 7 8 9 10]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := files(t, dir), "[snapshot wal-0000000000000002.log]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.Close()
+
+	// a snapshot is never torn, as it is renamed into place, so any damage is reported
+	data, _ := os.ReadFile(filepath.Join(dir, "snapshot"))
+	os.WriteFile(filepath.Join(dir, "snapshot"), data[:len(data)-1], 0o644)
+	if _, err := durabletreemap.Open[int, string](dir, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), durabletreemap.Options{}); !errors.Is(err, durabletreemap.ErrCorruptSnapshot) {
+		t.Errorf("Got %v expected %v", err, durabletreemap.ErrCorruptSnapshot)
+	}
+}
+
+func TestDurableTreeMapCorruptSnapshotHeader(t *testing.T) {
+	dir := t.TempDir()
+	m := open(t, dir, durabletreemap.Options{})
+	for i := 0; i < 10; i++ {
+		m.Put(i, fmt.Sprint(i))
+	}
+	if err := m.Compact(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	m.Close()
+	data, _ := os.ReadFile(filepath.Join(dir, "snapshot"))
+
+	// a corrupt count fails the header checksum
+	corrupt := append([]byte(nil), data...)
+	binary.BigEndian.PutUint64(corrupt[16:], 1<<62)
+	os.WriteFile(filepath.Join(dir, "snapshot"), corrupt, 0o644)
+	if _, err := durabletreemap.Open[int, string](dir, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), durabletreemap.Options{}); !errors.Is(err, durabletreemap.ErrCorruptSnapshot) {
+		t.Errorf("Got %v expected %v", err, durabletreemap.ErrCorruptSnapshot)
+	}
+
+	// a count that cannot fit in the file is rejected even with a matching checksum
+	binary.BigEndian.PutUint32(corrupt[24:], crc32.ChecksumIEEE(corrupt[:24]))
+	os.WriteFile(filepath.Join(dir, "snapshot"), corrupt, 0o644)
+	if _, err := durabletreemap.Open[int, string](dir, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string](), durabletreemap.Options{}); !errors.Is(err, durabletreemap.ErrCorruptSnapshot) {
+		t.Errorf("Got %v expected %v", err, durabletreemap.ErrCorruptSnapshot)
+	}
+
+	os.WriteFile(filepath.Join(dir, "snapshot"), data, 0o644)
+	m = open(t, dir, durabletreemap.Options{})
+	if actualValue, expectedValue := m.Size(), 10; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.Close()
+}
+
+func TestDurableTreeMapCompactionThreshold(t *testing.T) {
+	dir := t.TempDir()
+	m := open(t, dir, durabletreemap.Options{Sync: durabletreemap.SyncNever, CompactionThreshold: 50})
+	defer m.Close()
+	for i := 0; i < 120; i++ {
+		m.Put(i%5, fmt.Sprint(i))
+	}
+	if actualValue, expectedValue := files(t, dir), "[snapshot wal-0000000000000002.log]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), "[115 116 117 118 119]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestDurableTreeMapCompactionFailure(t *testing.T) {
+	dir := t.TempDir()
+	m := open(t, dir, durabletreemap.Options{Sync: durabletreemap.SyncNever, CompactionThreshold: 10})
+	defer m.Close()
+	// the snapshot cannot be written while a directory takes the place of its temporary file
+	if err := os.Mkdir(filepath.Join(dir, "snapshot.tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := m.Put(i%2, fmt.Sprint(i)); err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+	if m.Err() == nil {
+		t.Errorf("Got %v expected an error", m.Err())
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), "[18 19]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	os.Remove(filepath.Join(dir, "snapshot.tmp"))
+	if err := m.Remove(0); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if err := m.Err(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := files(t, dir), "[snapshot wal-0000000000000001.log]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestDurableTreeMapBinarySerialization(t *testing.T) {
+	dir := t.TempDir()
+	m := open(t, dir, durabletreemap.Options{})
+	for i := 1; i <= 5; i++ {
+		m.Put(i, strconv.Itoa(i))
+	}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	m.Close()
+
+	// the format is the one of treemap
+	tree := treemap.NewWithIntComparator[string]()
+	if err := tree.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[1 2 3 4 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	other := t.TempDir()
+	restored := open(t, other, durabletreemap.Options{})
+	restored.Put(100, "100")
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored.Close()
+	restored = open(t, other, durabletreemap.Options{})
+	defer restored.Close()
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), "[1 2 3 4 5] [1 2 3 4 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := m.UnmarshalBinary(data); !errors.Is(err, durabletreemap.ErrClosed) {
+		t.Errorf("Got %v expected %v", err, durabletreemap.ErrClosed)
+	}
+}
+
+func TestDurableTreeMapIterator(t *testing.T) {
+	dir := t.TempDir()
+	m := open(t, dir, durabletreemap.Options{})
+	for i := 1; i <= 10; i++ {
+		m.Put(i, fmt.Sprint(i))
+	}
+	it := m.Iterator()
+	it.Remove() // not positioned, does nothing
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+			it.Remove() // already removed, does nothing
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	for found := it.SeekPrev(5); found; found = it.Prev() {
+		it.Remove()
+	}
+	m.Close()
+
+	m = open(t, dir, durabletreemap.Options{})
+	defer m.Close()
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[7 9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if it = m.IteratorAt(8); it.Key() != 9 {
+		t.Errorf("Got %v expected %v", it.Key(), 9)
+	}
+}
+
+func TestDurableTreeMapConformance(t *testing.T) {
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		m := open(t, t.TempDir(), durabletreemap.Options{})
+		t.Cleanup(func() { m.Close() })
+		for _, key := range keys {
+			m.Put(key, strconv.Itoa(key))
+		}
+		it := m.Iterator()
+		return &it
+	})
+}
+
+// FuzzDurableTreeMap runs random changes, compactions and reopenings against a built-in map, three bytes per operation.
+func FuzzDurableTreeMap(f *testing.F) {
+	f.Add([]byte{0, 1, 1, 0, 2, 2, 5, 0, 0, 1, 1, 3, 2, 1, 0, 4, 0, 0, 5, 0, 0, 3, 0, 0, 0, 3, 3, 5, 0, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		dir := t.TempDir()
+		options := durabletreemap.Options{Sync: durabletreemap.SyncNever, CompactionThreshold: 8}
+		m := open(t, dir, options)
+		defer func() { m.Close() }()
+		expected := map[int]string{}
+		for i := 0; i+2 < len(data) && i < 3*64; i += 3 {
+			key, value := int(data[i+1]%16), fmt.Sprint(data[i+2])
+			var err error
+			switch data[i] % 6 {
+			case 0, 1:
+				err = m.Put(key, value)
+				expected[key] = value
+			case 2:
+				err = m.Remove(key)
+				delete(expected, key)
+			case 3:
+				err = m.Clear()
+				expected = map[int]string{}
+			case 4:
+				err = m.Compact()
+			case 5:
+				err = m.Close()
+				m = open(t, dir, options)
+			}
+			if err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			assertEqual(t, m, expected)
+		}
+	})
+}
+
+func assertEqual(t *testing.T, m *durabletreemap.Map[int, string], expected map[int]string) {
+	t.Helper()
+	expectedKeys := make([]int, 0, len(expected))
+	for key := range expected {
+		expectedKeys = append(expectedKeys, key)
+	}
+	sort.Ints(expectedKeys)
+	expectedValues := make([]string, len(expectedKeys))
+	for i, key := range expectedKeys {
+		expectedValues[i] = expected[key]
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), fmt.Sprint(expectedKeys); actualValue != expectedValue {
+		t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), fmt.Sprint(expectedValues); actualValue != expectedValue {
+		t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for key := 0; key < 16; key++ {
+		value, found := m.Get(key)
+		if expectedValue, expectedFound := expected[key]; value != expectedValue || found != expectedFound {
+			t.Fatalf("Got %v,%v expected %v,%v for key %v", value, found, expectedValue, expectedFound, key)
+		}
+	}
+}
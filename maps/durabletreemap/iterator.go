@@ -0,0 +1,143 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package durabletreemap
+
+import (
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/maps/treemap"
+)
+
+var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
+
+// Iterator holding the iterator's state
+type Iterator[K comparable, V any] struct {
+	m        *Map[K, V]
+	iterator treemap.Iterator[K, V]
+	valid    bool  // iterator is positioned on an element
+	removed  bool  // current element was removed through the iterator
+	err      error // error logging a removal
+}
+
+// Iterator returns a stateful iterator whose elements are key/value pairs.
+func (m *Map[K, V]) Iterator() Iterator[K, V] {
+	return Iterator[K, V]{m: m, iterator: m.tree.Iterator()}
+}
+
+// IteratorAt returns a stateful iterator whose elements are key/value pairs that is initialised
+// at the first element whose key is greater than or equal to the given key (see Seek()).
+func (m *Map[K, V]) IteratorAt(key K) Iterator[K, V] {
+	iterator := m.Iterator()
+	iterator.Seek(key)
+	return iterator
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the container.
+// If Next() returns true, then next element's key and value can be retrieved by Key() and Value().
+// If Next() was called for the first time, then it will point the iterator to the first element if it exists.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Next() bool {
+	return iterator.moved(iterator.iterator.Next())
+}
+
+// Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
+// If Prev() returns true, then previous element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Prev() bool {
+	return iterator.moved(iterator.iterator.Prev())
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Value() V {
+	return iterator.iterator.Value()
+}
+
+// Key returns the current element's key.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Key() K {
+	return iterator.iterator.Key()
+}
+
+// Begin resets the iterator to its initial state (one-before-first)
+// Call Next() to fetch the first element if any.
+func (iterator *Iterator[K, V]) Begin() {
+	iterator.iterator.Begin()
+	iterator.moved(false)
+}
+
+// End moves the iterator past the last element (one-past-the-end).
+// Call Prev() to fetch the last element if any.
+func (iterator *Iterator[K, V]) End() {
+	iterator.iterator.End()
+	iterator.moved(false)
+}
+
+// First moves the iterator to the first element and returns true if there was a first element in the container.
+// If First() returns true, then first element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator
+func (iterator *Iterator[K, V]) First() bool {
+	return iterator.moved(iterator.iterator.First())
+}
+
+// Last moves the iterator to the last element and returns true if there was a last element in the container.
+// If Last() returns true, then last element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Last() bool {
+	return iterator.moved(iterator.iterator.Last())
+}
+
+// Seek moves the iterator to the first element whose key is greater than or equal to the given key
+// and returns true if there was such an element in the container (see treemap.Iterator.Seek).
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	return iterator.moved(iterator.iterator.Seek(key))
+}
+
+// SeekPrev moves the iterator to the last element whose key is less than or equal to the given key
+// and returns true if there was such an element in the container (see treemap.Iterator.SeekPrev).
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) SeekPrev(key K) bool {
+	return iterator.moved(iterator.iterator.SeekPrev(key))
+}
+
+// Err returns the error of logging (or compacting after) the last removal through the iterator,
+// or containers.ErrConcurrentModification if the map was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	if iterator.err != nil {
+		return iterator.err
+	}
+	return iterator.iterator.Err()
+}
+
+// Remove logs the removal of the current element and removes it from the map, keeping the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// If logging fails, the element is kept and the error is reported by Err().
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[K, V]) Remove() {
+	if !iterator.valid || iterator.removed {
+		return
+	}
+	encodedKey, err := iterator.m.keyCodec.Encode(iterator.Key())
+	if err == nil {
+		err = iterator.m.append(opRemove, encodedKey, nil)
+	}
+	if err != nil {
+		iterator.err = err
+		return
+	}
+	iterator.iterator.Remove()
+	iterator.removed = true
+	iterator.m.maybeCompact()
+}
+
+func (iterator *Iterator[K, V]) moved(valid bool) bool {
+	iterator.valid = valid
+	iterator.removed = false
+	iterator.err = nil
+	return valid
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package durabletreemap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// Record layout, shared by the log and the snapshot:
+//
+//	crc32 uint32 | payload length uint32 | payload
+//	payload: operation byte | key length uvarint | key | value
+//
+// The checksum covers the payload, so that a record torn by a crash is detected when the log is replayed.
+
+type operation byte
+
+const (
+	opPut operation = iota + 1
+	opRemove
+	opClear
+)
+
+const recordHeaderSize = 4 + 4
+
+// maxRecordSize guards against allocating huge buffers for a corrupt length field
+const maxRecordSize = 1 << 30
+
+var errTornRecord = errors.New("durabletreemap: torn record")
+
+// appendRecord appends the encoded record to the buffer.
+func appendRecord(buffer []byte, op operation, key []byte, value []byte) []byte {
+	start := len(buffer)
+	buffer = append(buffer, make([]byte, recordHeaderSize)...)
+	buffer = append(buffer, byte(op))
+	var length [binary.MaxVarintLen64]byte
+	buffer = append(buffer, length[:binary.PutUvarint(length[:], uint64(len(key)))]...)
+	buffer = append(buffer, key...)
+	buffer = append(buffer, value...)
+	payload := buffer[start+recordHeaderSize:]
+	binary.BigEndian.PutUint32(buffer[start:], crc32.ChecksumIEEE(payload))
+	binary.BigEndian.PutUint32(buffer[start+4:], uint32(len(payload)))
+	return buffer
+}
+
+// readRecord reads the next record into the buffer, which is grown as needed, returning io.EOF at the clean
+// end of the input and errTornRecord if the record is incomplete or fails its checksum.
+func readRecord(reader *bufio.Reader, buffer *[]byte) (op operation, key []byte, value []byte, size int, err error) {
+	var header [recordHeaderSize]byte
+	if n, err := io.ReadFull(reader, header[:]); err != nil {
+		if err == io.EOF && n == 0 {
+			return 0, nil, nil, 0, io.EOF
+		}
+		return 0, nil, nil, 0, errTornRecord
+	}
+	length := binary.BigEndian.Uint32(header[4:])
+	if length < 2 || length > maxRecordSize {
+		return 0, nil, nil, 0, errTornRecord
+	}
+	if cap(*buffer) < int(length) {
+		*buffer = make([]byte, length)
+	}
+	payload := (*buffer)[:length]
+	if _, err := io.ReadFull(reader, payload); err != nil || crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[:]) {
+		return 0, nil, nil, 0, errTornRecord
+	}
+	keyLength, n := binary.Uvarint(payload[1:])
+	if n <= 0 || keyLength > uint64(len(payload)-1-n) {
+		return 0, nil, nil, 0, errTornRecord
+	}
+	key = payload[1+n : 1+n+int(keyLength)]
+	value = payload[1+n+int(keyLength):]
+	return operation(payload[0]), key, value, recordHeaderSize + int(length), nil
+}
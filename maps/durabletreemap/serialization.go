@@ -0,0 +1,62 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package durabletreemap
+
+import (
+	"encoding"
+	"encoding/gob"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+var _ encoding.BinaryMarshaler = (*Map[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[int, string])(nil)
+var _ gob.GobEncoder = (*Map[int, string])(nil)
+var _ gob.GobDecoder = (*Map[int, string])(nil)
+
+// MarshalBinary outputs the binary representation of the map in key order.
+// Keys and values are encoded by the codecs of containers.CodecFor, like those of a treemap,
+// rather than by the codecs of the log.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	var keys []K
+	var values []V
+	if m.tree != nil {
+		keys, values = m.tree.Keys(), m.tree.Values()
+	}
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), keys, values)
+}
+
+// UnmarshalBinary replaces the elements of the map by the elements of the input binary representation,
+// logging a clear followed by a put for every element.
+// As the map needs a directory, its zero value cannot be unmarshaled and returns ErrClosed, like a closed map.
+// On error, the map holds the elements put before the error.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	if m.log == nil {
+		return ErrClosed
+	}
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err != nil {
+		return err
+	}
+	if err := m.Clear(); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if err := m.Put(key, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
@@ -0,0 +1,425 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package flatmap implements a map backed by two parallel slices holding the keys in sorted order and their values.
+//
+// Elements are ordered by key in the map.
+//
+// Keys are looked up by binary search, in O(log n) time like treemap, but the search reads a single contiguous slice
+// instead of chasing pointers from node to node, and the map allocates no node per element. Putting a new key
+// or removing one shifts the elements that follow it, in O(n) time, which is cheap for maps of up to a few thousand
+// elements; PutAll inserts a batch of elements with a single merge. Iterating, Min, Max and finding an element
+// by its position are O(1).
+//
+// Structure is not thread safe.
+//
+// Reference: https://en.wikipedia.org/wiki/Associative_array
+package flatmap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/maps"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ maps.Map[int, string] = (*Map[int, string])(nil)
+
+// Map holds the keys in a sorted slice and their values in a parallel slice.
+type Map[K any, V any] struct {
+	comparator utils.Comparator[K]
+	search     func(keys []K, key K) (int, bool) // search comparing ordered keys directly, set by New
+	keys       []K
+	values     []V
+	modCount   int // number of structural modifications, checked by iterators
+}
+
+// New instantiates a flat map ordering the keys by the < and > operators, see utils.OrderedComparator.
+// Looking keys up compares them with the operators instead of calling the comparator.
+func New[K utils.Ordered, V any]() *Map[K, V] {
+	return &Map[K, V]{comparator: utils.OrderedComparator[K], search: searchOrdered[K]}
+}
+
+// NewWith instantiates a flat map with the custom comparator.
+func NewWith[K any, V any](comparator utils.Comparator[K]) *Map[K, V] {
+	return &Map[K, V]{comparator: comparator}
+}
+
+// NewWithIntComparator instantiates a flat map with the IntComparator, i.e. keys are of type int.
+func NewWithIntComparator[V any]() *Map[int, V] {
+	return &Map[int, V]{comparator: utils.NumberComparator[int]}
+}
+
+// NewWithStringComparator instantiates a flat map with the StringComparator, i.e. keys are of type string.
+func NewWithStringComparator[V any]() *Map[string, V] {
+	return &Map[string, V]{comparator: utils.StringComparator}
+}
+
+// FromSorted instantiates a flat map with the custom comparator holding copies of the keys and their values in O(n) time.
+// Returns an error wrapping containers.ErrNotSorted if keys are not in strictly ascending order,
+// or an error if there are not as many values as keys.
+func FromSorted[K any, V any](comparator utils.Comparator[K], keys []K, values []V) (*Map[K, V], error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("flatmap: got %d keys but %d values", len(keys), len(values))
+	}
+	for i := 1; i < len(keys); i++ {
+		if comparator(keys[i-1], keys[i]) >= 0 {
+			return nil, fmt.Errorf("flatmap: key at index %d: %w", i, containers.ErrNotSorted)
+		}
+	}
+	m := NewWith[K, V](comparator)
+	m.keys = append([]K(nil), keys...)
+	m.values = append([]V(nil), values...)
+	return m, nil
+}
+
+// BulkLoad instantiates a flat map with the custom comparator holding the remaining elements
+// of the iterator in O(n) time. See FromSorted.
+func BulkLoad[K any, V any](comparator utils.Comparator[K], iterator containers.IteratorWithKey[K, V]) (*Map[K, V], error) {
+	var (
+		keys   []K
+		values []V
+	)
+	for iterator.Next() {
+		keys = append(keys, iterator.Key())
+		values = append(values, iterator.Value())
+	}
+	return FromSorted(comparator, keys, values)
+}
+
+// find returns the index of the first key that is greater than or equal to the given key
+// and whether that key equals the given key.
+func (m *Map[K, V]) find(key K) (int, bool) {
+	if m.search != nil {
+		return m.search(m.keys, key)
+	}
+	i, n := 0, len(m.keys)
+	for n > 1 {
+		half := n / 2
+		if m.comparator(m.keys[i+half-1], key) < 0 {
+			i += half
+		}
+		n -= half
+	}
+	if n == 1 && m.comparator(m.keys[i], key) < 0 {
+		i++
+	}
+	return i, i < len(m.keys) && m.comparator(m.keys[i], key) == 0
+}
+
+// searchOrdered is the search of maps instantiated by New, comparing the keys as utils.OrderedComparator does.
+// Halving the range whatever the outcome of a comparison lets the compiler select the next range without a branch.
+func searchOrdered[K utils.Ordered](keys []K, key K) (int, bool) {
+	i, n := 0, len(keys)
+	for n > 1 {
+		half := n / 2
+		if keys[i+half-1] < key {
+			i += half
+		}
+		n -= half
+	}
+	if n == 1 && keys[i] < key {
+		i++
+	}
+	return i, i < len(keys) && !(key < keys[i])
+}
+
+// Put inserts key-value pair into the map.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Put(key K, value V) {
+	i, found := m.find(key)
+	if found {
+		m.keys[i] = key
+		m.values[i] = value
+		return
+	}
+	var (
+		zeroK K
+		zeroV V
+	)
+	m.keys = append(m.keys, zeroK)
+	m.values = append(m.values, zeroV)
+	copy(m.keys[i+1:], m.keys[i:])
+	copy(m.values[i+1:], m.values[i:])
+	m.keys[i] = key
+	m.values[i] = value
+	m.modCount++
+}
+
+// PutAll inserts the key-value pairs into the map, keys[i] holding values[i], as if they were put one after another.
+// The pairs are sorted and then merged into the map in a single pass, in O(k log k + n) time,
+// k being the number of pairs, instead of the O(k n) time of putting them one by one.
+// Keys should adhere to the comparator's type assertion, otherwise method panics.
+// Panics if there are not as many values as keys.
+func (m *Map[K, V]) PutAll(keys []K, values []V) {
+	if len(keys) != len(values) {
+		panic(fmt.Sprintf("flatmap: got %d keys but %d values", len(keys), len(values)))
+	}
+	if len(keys) <= 1 {
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+		return
+	}
+	batch := m.sortBatch(keys)
+	added := 0
+	for _, i := range batch {
+		if _, found := m.find(keys[i]); !found {
+			added++
+		}
+	}
+	if added > 0 {
+		m.grow(added)
+		m.modCount++
+	}
+	// merge from the back, so that every element is moved once and the elements of the map not yet merged
+	// are never overwritten; equal keys merge into one slot, the batch replacing the element of the map
+	i, w := len(m.keys)-added-1, len(m.keys)-1
+	for j := len(batch) - 1; j >= 0; w-- {
+		key, value := keys[batch[j]], values[batch[j]]
+		compare := -1
+		if i >= 0 {
+			compare = m.comparator(m.keys[i], key)
+		}
+		if compare > 0 {
+			m.keys[w], m.values[w] = m.keys[i], m.values[i]
+			i--
+			continue
+		}
+		if compare == 0 {
+			i--
+		}
+		m.keys[w], m.values[w] = key, value
+		j--
+	}
+}
+
+// sortBatch returns the indexes of the keys in ascending order of the keys, keeping only the last index of equal keys.
+func (m *Map[K, V]) sortBatch(keys []K) []int {
+	batch := batchSorter[K]{comparator: m.comparator, keys: keys, indexes: make([]int, len(keys))}
+	for i := range batch.indexes {
+		batch.indexes[i] = i
+	}
+	sort.Sort(batch)
+	unique := batch.indexes[:1]
+	for _, i := range batch.indexes[1:] {
+		if m.comparator(keys[unique[len(unique)-1]], keys[i]) == 0 {
+			unique[len(unique)-1] = i
+		} else {
+			unique = append(unique, i)
+		}
+	}
+	return unique
+}
+
+// batchSorter sorts the indexes of keys by key, and the indexes of equal keys in ascending order.
+type batchSorter[K any] struct {
+	comparator utils.Comparator[K]
+	keys       []K
+	indexes    []int
+}
+
+func (s batchSorter[K]) Len() int {
+	return len(s.indexes)
+}
+
+func (s batchSorter[K]) Less(a, b int) bool {
+	i, j := s.indexes[a], s.indexes[b]
+	compare := s.comparator(s.keys[i], s.keys[j])
+	return compare < 0 || compare == 0 && i < j
+}
+
+func (s batchSorter[K]) Swap(a, b int) {
+	s.indexes[a], s.indexes[b] = s.indexes[b], s.indexes[a]
+}
+
+// grow appends n zero elements to the map.
+func (m *Map[K, V]) grow(n int) {
+	m.keys = append(m.keys, make([]K, n)...)
+	m.values = append(m.values, make([]V, n)...)
+}
+
+// Get searches the element in the map by key and returns its value or nil if key is not found in map.
+// Second return parameter is true if key was found, otherwise false.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Get(key K) (value V, found bool) {
+	if i, found := m.find(key); found {
+		return m.values[i], true
+	}
+	return value, false
+}
+
+// Remove removes the element from the map by key.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Remove(key K) {
+	if i, found := m.find(key); found {
+		m.removeAt(i, i+1)
+	}
+}
+
+// removeAt removes the elements at the indexes within [from, to).
+func (m *Map[K, V]) removeAt(from, to int) {
+	if from == to {
+		return
+	}
+	n := copy(m.keys[from:], m.keys[to:])
+	copy(m.values[from:], m.values[to:])
+	size := from + n
+	var (
+		zeroK K
+		zeroV V
+	)
+	for i := size; i < len(m.keys); i++ {
+		m.keys[i], m.values[i] = zeroK, zeroV // let the keys and values be collected
+	}
+	m.keys, m.values = m.keys[:size], m.values[:size]
+	m.modCount++
+}
+
+// Empty returns true if map does not contain any elements
+func (m *Map[K, V]) Empty() bool {
+	return m.Size() == 0
+}
+
+// Size returns number of elements in the map.
+func (m *Map[K, V]) Size() int {
+	return len(m.keys)
+}
+
+// Keys returns all keys in-order
+func (m *Map[K, V]) Keys() []K {
+	return append(make([]K, 0, len(m.keys)), m.keys...)
+}
+
+// Values returns all values in-order based on the key.
+func (m *Map[K, V]) Values() []V {
+	return append(make([]V, 0, len(m.values)), m.values...)
+}
+
+// InterfaceValues returns all elements in the map as type interface{}.
+func (m *Map[K, V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, len(m.values))
+	for i, value := range m.values {
+		values[i] = value
+	}
+	return values
+}
+
+// Clear removes all elements from the map.
+func (m *Map[K, V]) Clear() {
+	m.keys, m.values = nil, nil
+	m.modCount++
+}
+
+// Min returns the minimum key and its value from the map.
+// Returns nil, nil if map is empty.
+func (m *Map[K, V]) Min() (key K, value V) {
+	if len(m.keys) > 0 {
+		return m.keys[0], m.values[0]
+	}
+	return key, value
+}
+
+// Max returns the maximum key and its value from the map.
+// Returns nil, nil if map is empty.
+func (m *Map[K, V]) Max() (key K, value V) {
+	if last := len(m.keys) - 1; last >= 0 {
+		return m.keys[last], m.values[last]
+	}
+	return key, value
+}
+
+// Floor finds the floor key-value pair for the input key.
+// In case that no floor is found, then both returned values will be nil.
+// It's generally enough to check the first value (key) for nil, which determines if floor was found.
+//
+// Floor key is defined as the largest key that is smaller than or equal to the given key.
+// A floor key may not be found, either because the map is empty, or because
+// all keys in the map are larger than the given key.
+//
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+	i, found := m.find(key)
+	if !found {
+		i--
+	}
+	if i >= 0 {
+		return m.keys[i], m.values[i], true
+	}
+	var (
+		zeroK K
+		zeroV V
+	)
+	return zeroK, zeroV, false
+}
+
+// Ceiling finds the ceiling key-value pair for the input key.
+// In case that no ceiling is found, then both returned values will be nil.
+// It's generally enough to check the first value (key) for nil, which determines if ceiling was found.
+//
+// Ceiling key is defined as the smallest key that is larger than or equal to the given key.
+// A ceiling key may not be found, either because the map is empty, or because
+// all keys in the map are smaller than the given key.
+//
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+	if i, _ := m.find(key); i < len(m.keys) {
+		return m.keys[i], m.values[i], true
+	}
+	var (
+		zeroK K
+		zeroV V
+	)
+	return zeroK, zeroV, false
+}
+
+// RemoveRange removes all elements whose keys are within [from, to) from the map.
+// Runs in O(log n + k) time, k being the number of elements following the removed ones.
+// Keys should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) RemoveRange(from K, to K) {
+	i, _ := m.find(from)
+	j, _ := m.find(to)
+	if i < j {
+		m.removeAt(i, j)
+	}
+}
+
+// Split moves all elements whose keys are less than the given key into the left map and
+// all other elements into the right map. The map itself is left empty.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Split(key K) (left *Map[K, V], right *Map[K, V]) {
+	i, _ := m.find(key)
+	left, right = m.empty(), m.empty()
+	left.keys, left.values = m.keys[:i:i], m.values[:i:i]
+	right.keys, right.values = m.keys[i:], m.values[i:]
+	m.Clear()
+	return left, right
+}
+
+// Join moves all elements of the left and the right map into a new map using left's comparator
+// and leaves both maps empty. All keys of the left map should be less than all keys of the right map,
+// otherwise method panics.
+func Join[K any, V any](left *Map[K, V], right *Map[K, V]) *Map[K, V] {
+	if len(left.keys) > 0 && len(right.keys) > 0 && left.comparator(left.keys[len(left.keys)-1], right.keys[0]) >= 0 {
+		panic("Invalid join, all keys of left should be less than all keys of right")
+	}
+	m := left.empty()
+	m.keys = append(left.keys, right.keys...)
+	m.values = append(left.values, right.values...)
+	left.Clear()
+	right.Clear()
+	return m
+}
+
+// String returns a string representation of container
+func (m *Map[K, V]) String() string {
+	str := "FlatMap\nmap["
+	for i, key := range m.keys {
+		str += fmt.Sprintf("%v:%v ", key, m.values[i])
+	}
+	return strings.TrimRight(str, " ") + "]"
+}
@@ -0,0 +1,1253 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatmap_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
+	"github.com/monitor1379/yagods/maps/flatmap"
+	"github.com/monitor1379/yagods/maps/treemap"
+	"github.com/monitor1379/yagods/utils"
+)
+
+func TestMapPut(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(5, "e")
+	m.Put(6, "f")
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(4, "d")
+	m.Put(1, "x")
+	m.Put(2, "b")
+	m.Put(1, "a") //overwrite
+
+	if actualValue := m.Size(); actualValue != 7 {
+		t.Errorf("Got %v expected %v", actualValue, 7)
+	}
+	if actualValue, expectedValue := m.Keys(), []int{1, 2, 3, 4, 5, 6, 7}; !sameElements(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := m.Values(), []string{"a", "b", "c", "d", "e", "f", "g"}; !sameElements(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// key,expectedValue,expectedFound
+	tests1 := [][]interface{}{
+		{1, "a", true},
+		{2, "b", true},
+		{3, "c", true},
+		{4, "d", true},
+		{5, "e", true},
+		{6, "f", true},
+		{7, "g", true},
+		{8, "", false},
+	}
+
+	for _, test := range tests1 {
+		// retrievals
+		actualValue, actualFound := m.Get(test[0].(int))
+		if actualValue != test[1] || actualFound != test[2] {
+			t.Errorf("Got %v expected %v", actualValue, test[1])
+		}
+	}
+}
+
+func TestMapRemove(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(5, "e")
+	m.Put(6, "f")
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(4, "d")
+	m.Put(1, "x")
+	m.Put(2, "b")
+	m.Put(1, "a") //overwrite
+
+	m.Remove(5)
+	m.Remove(6)
+	m.Remove(7)
+	m.Remove(8)
+	m.Remove(5)
+
+	if actualValue, expectedValue := m.Keys(), []int{1, 2, 3, 4}; !sameElements(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := m.Values(), []string{"a", "b", "c", "d"}; !sameElements(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := m.Size(); actualValue != 4 {
+		t.Errorf("Got %v expected %v", actualValue, 4)
+	}
+
+	tests2 := [][]interface{}{
+		{1, "a", true},
+		{2, "b", true},
+		{3, "c", true},
+		{4, "d", true},
+		{5, "", false},
+		{6, "", false},
+		{7, "", false},
+		{8, "", false},
+	}
+
+	for _, test := range tests2 {
+		actualValue, actualFound := m.Get(test[0].(int))
+		if actualValue != test[1] || actualFound != test[2] {
+			t.Errorf("Got %v expected %v", actualValue, test[1])
+		}
+	}
+
+	m.Remove(1)
+	m.Remove(4)
+	m.Remove(2)
+	m.Remove(3)
+	m.Remove(2)
+	m.Remove(2)
+
+	if actualValue, expectedValue := fmt.Sprintf("%v", m.Keys()), "[]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprintf("%s", m.Values()), "[]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := m.Size(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+	if actualValue := m.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestMapFloor(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(1, "a")
+
+	// key,expectedKey,expectedValue,expectedFound
+	tests1 := [][]interface{}{
+		{-1, 0, "", false},
+		{0, 0, "", false},
+		{1, 1, "a", true},
+		{2, 1, "a", true},
+		{3, 3, "c", true},
+		{4, 3, "c", true},
+		{7, 7, "g", true},
+		{8, 7, "g", true},
+	}
+
+	for _, test := range tests1 {
+		// retrievals
+		actualKey, actualValue, actualFound := m.Floor(test[0].(int))
+		if actualKey != test[1] || actualValue != test[2] || actualFound != test[3] {
+			t.Errorf("Got %v, %v, %v, expected %v, %v, %v", actualKey, actualValue, actualFound, test[1], test[2], test[3])
+		}
+	}
+}
+
+func TestMapCeiling(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(1, "a")
+
+	// key,expectedKey,expectedValue,expectedFound
+	tests1 := [][]interface{}{
+		{-1, 1, "a", true},
+		{0, 1, "a", true},
+		{1, 1, "a", true},
+		{2, 3, "c", true},
+		{3, 3, "c", true},
+		{4, 7, "g", true},
+		{7, 7, "g", true},
+		{8, 0, "", false},
+	}
+
+	for _, test := range tests1 {
+		// retrievals
+		actualKey, actualValue, actualFound := m.Ceiling(test[0].(int))
+		if actualKey != test[1] || actualValue != test[2] || actualFound != test[3] {
+			t.Errorf("Got %v, %v, %v, expected %v, %v, %v", actualKey, actualValue, actualFound, test[1], test[2], test[3])
+		}
+	}
+}
+
+func sameElements[T comparable](a []T, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, av := range a {
+		found := false
+		for _, bv := range b {
+			if av == bv {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMapEach(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	count := 0
+	m.Each(func(key string, value int) {
+		count++
+		if actualValue, expectedValue := count, value; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		switch value {
+		case 1:
+			if actualValue, expectedValue := key, "a"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case 2:
+			if actualValue, expectedValue := key, "b"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case 3:
+			if actualValue, expectedValue := key, "c"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		default:
+			t.Errorf("Too many")
+		}
+	})
+}
+
+func TestMapMap(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	mappedMap := m.Map(func(key1 string, value1 int) (key2 string, value2 int) {
+		return key1, value1 * value1
+	})
+	if actualValue, _ := mappedMap.Get("a"); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, "mapped: a")
+	}
+	if actualValue, _ := mappedMap.Get("b"); actualValue != 4 {
+		t.Errorf("Got %v expected %v", actualValue, "mapped: b")
+	}
+	if actualValue, _ := mappedMap.Get("c"); actualValue != 9 {
+		t.Errorf("Got %v expected %v", actualValue, "mapped: c")
+	}
+	if mappedMap.Size() != 3 {
+		t.Errorf("Got %v expected %v", mappedMap.Size(), 3)
+	}
+}
+
+func TestMapSelect(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	selectedMap := m.Select(func(key string, value int) bool {
+		return key >= "a" && key <= "b"
+	})
+	if actualValue, _ := selectedMap.Get("a"); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, "value: a")
+	}
+	if actualValue, _ := selectedMap.Get("b"); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, "value: b")
+	}
+	if selectedMap.Size() != 2 {
+		t.Errorf("Got %v expected %v", selectedMap.Size(), 2)
+	}
+}
+
+func TestMapAny(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	any := m.Any(func(key string, value int) bool {
+		return value == 3
+	})
+	if any != true {
+		t.Errorf("Got %v expected %v", any, true)
+	}
+	any = m.Any(func(key string, value int) bool {
+		return value == 4
+	})
+	if any != false {
+		t.Errorf("Got %v expected %v", any, false)
+	}
+}
+
+func TestMapAll(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	all := m.All(func(key string, value int) bool {
+		return key >= "a" && key <= "c"
+	})
+	if all != true {
+		t.Errorf("Got %v expected %v", all, true)
+	}
+	all = m.All(func(key string, value int) bool {
+		return key >= "a" && key <= "b"
+	})
+	if all != false {
+		t.Errorf("Got %v expected %v", all, false)
+	}
+}
+
+func TestMapFind(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	foundKey, foundValue, found := m.Find(func(key string, value int) bool {
+		return key == "c"
+	})
+	if foundKey != "c" || foundValue != 3 || !found {
+		t.Errorf("Got %v -> %v expected %v -> %v", foundKey, foundValue, "c", 3)
+	}
+	foundKey, foundValue, found = m.Find(func(key string, value int) bool {
+		return key == "x"
+	})
+	if foundKey != "" || foundValue != 0 || found {
+		t.Errorf("Got %v at %v expected %v at %v", foundValue, foundKey, nil, nil)
+	}
+}
+
+func TestMapChaining(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	chainedMap := m.Select(func(key string, value int) bool {
+		return value > 1
+	}).Map(func(key string, value int) (string, int) {
+		return key + key, value * value
+	})
+	if actualValue := chainedMap.Size(); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+	if actualValue, found := chainedMap.Get("aa"); actualValue != 0 || found {
+		t.Errorf("Got %v expected %v", actualValue, nil)
+	}
+	if actualValue, found := chainedMap.Get("bb"); actualValue != 4 || !found {
+		t.Errorf("Got %v expected %v", actualValue, 4)
+	}
+	if actualValue, found := chainedMap.Get("cc"); actualValue != 9 || !found {
+		t.Errorf("Got %v expected %v", actualValue, 9)
+	}
+}
+
+func TestMapIteratorNextOnEmpty(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	it := m.Iterator()
+	it = m.Iterator()
+	for it.Next() {
+		t.Errorf("Shouldn't iterate on empty map")
+	}
+}
+
+func TestMapIteratorPrevOnEmpty(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	it := m.Iterator()
+	it = m.Iterator()
+	for it.Prev() {
+		t.Errorf("Shouldn't iterate on empty map")
+	}
+}
+
+func TestMapIteratorNext(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+
+	it := m.Iterator()
+	count := 0
+	for it.Next() {
+		count++
+		key := it.Key()
+		value := it.Value()
+		switch key {
+		case "a":
+			if actualValue, expectedValue := value, 1; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case "b":
+			if actualValue, expectedValue := value, 2; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case "c":
+			if actualValue, expectedValue := value, 3; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		default:
+			t.Errorf("Too many")
+		}
+		if actualValue, expectedValue := value, count; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if actualValue, expectedValue := count, 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapIteratorPrev(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+
+	it := m.Iterator()
+	for it.Next() {
+	}
+	countDown := m.Size()
+	for it.Prev() {
+		key := it.Key()
+		value := it.Value()
+		switch key {
+		case "a":
+			if actualValue, expectedValue := value, 1; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case "b":
+			if actualValue, expectedValue := value, 2; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case "c":
+			if actualValue, expectedValue := value, 3; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		default:
+			t.Errorf("Too many")
+		}
+		if actualValue, expectedValue := value, countDown; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		countDown--
+	}
+	if actualValue, expectedValue := countDown, 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapIteratorBegin(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	it := m.Iterator()
+	it.Begin()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+	for it.Next() {
+	}
+	it.Begin()
+	it.Next()
+	if key, value := it.Key(), it.Value(); key != 1 || value != "a" {
+		t.Errorf("Got %v,%v expected %v,%v", key, value, 1, "a")
+	}
+}
+
+func TestMapIteratorEnd(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	it := m.Iterator()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+	it.End()
+	it.Prev()
+	if key, value := it.Key(), it.Value(); key != 3 || value != "c" {
+		t.Errorf("Got %v,%v expected %v,%v", key, value, 3, "c")
+	}
+}
+
+func TestMapIteratorFirst(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+	it := m.Iterator()
+	if actualValue, expectedValue := it.First(), true; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, value := it.Key(), it.Value(); key != 1 || value != "a" {
+		t.Errorf("Got %v,%v expected %v,%v", key, value, 1, "a")
+	}
+}
+
+func TestMapIteratorLast(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+	it := m.Iterator()
+	if actualValue, expectedValue := it.Last(), true; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, value := it.Key(), it.Value(); key != 3 || value != "c" {
+		t.Errorf("Got %v,%v expected %v,%v", key, value, 3, "c")
+	}
+}
+
+func TestMapIteratorRemove(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	for i := 1; i <= 6; i++ {
+		m.Put(i, fmt.Sprint(i))
+	}
+	it := m.Iterator()
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, found := m.Get(2); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	it.Last()
+	m.Put(7, "7")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapIteratorSeek(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	m.Put("b", 1)
+	m.Put("d", 2)
+	m.Put("f", 3)
+	it := m.IteratorAt("c")
+	var keys []string
+	for ok := true; ok; ok = it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if actualValue, expectedValue := fmt.Sprint(keys), "[d f]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := it.SeekPrev("e"); actualValue != true || it.Key() != "d" {
+		t.Errorf("Got %v expected %v", it.Key(), "d")
+	}
+	if actualValue := it.SeekPrev("a"); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue := it.Seek("g"); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+}
+
+func TestMapRemoveRangeSplitJoin(t *testing.T) {
+	m := flatmap.NewWithStringComparator[int]()
+	for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		m.Put(key, i)
+	}
+	m.RemoveRange("b", "d")
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[a d e f]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	left, right := m.Split("e")
+	if actualValue, expectedValue := fmt.Sprint(left.Keys(), right.Keys(), m.Size()), "[a d] [e f] 0"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m = flatmap.Join(left, right)
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[a d e f] [0 3 4 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapFromSorted(t *testing.T) {
+	m, err := flatmap.FromSorted(utils.StringComparator, []string{"a", "b", "c"}, []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[a b c] [1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it := m.Iterator()
+	copied, err := flatmap.BulkLoad[string, int](utils.StringComparator, &it)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(copied.Keys(), copied.Values()), "[a b c] [1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, err := flatmap.FromSorted(utils.StringComparator, []string{"b", "a"}, []int{1, 2}); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+}
+
+func TestMapPutAll(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.PutAll([]int{5, 1, 3}, []string{"e", "a", "c"})
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[1 3 5] [a c e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.PutAll([]int{6, 3, 0, 4, 3, 7}, []string{"f", "x", "z", "d", "C", "g"})
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[0 1 3 4 5 6 7] [z a C d e f g]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.PutAll([]int{1, 7}, []string{"A", "G"})
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[0 1 3 4 5 6 7] [z A C d e f G]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.PutAll([]int{2}, []string{"b"})
+	m.PutAll(nil, nil)
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[0 1 2 3 4 5 6 7] [z A b C d e f G]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Got %v expected %v", r, "panic")
+		}
+	}()
+	m.PutAll([]int{1, 2}, []string{"a"})
+}
+
+func TestMapPutAllAsPut(t *testing.T) {
+	keys, values := make([]int, 1000), make([]string, 1000)
+	for i := range keys {
+		keys[i] = (i * 7919) % 503
+		values[i] = strconv.Itoa(i)
+	}
+	expected := flatmap.NewWithIntComparator[string]()
+	actual := flatmap.NewWithIntComparator[string]()
+	for i := 0; i < len(keys); i += 100 {
+		for j := i; j < i+100; j++ {
+			expected.Put(keys[j], values[j])
+		}
+		actual.PutAll(keys[i:i+100], values[i:i+100])
+		if actualValue, expectedValue := actual.String(), expected.String(); actualValue != expectedValue {
+			t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+}
+
+func TestMapIteratorIndex(t *testing.T) {
+	m := flatmap.New[string, int]()
+	m.PutAll([]string{"c", "a", "b"}, []int{3, 1, 2})
+	it := m.Iterator()
+	for it.Next() {
+		if actualValue, expectedValue := it.Index(), it.Value()-1; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if it.Seek("b"); it.Index() != 1 {
+		t.Errorf("Got %v expected %v", it.Index(), 1)
+	}
+	if it.SeekPrev("bb"); it.Index() != 1 {
+		t.Errorf("Got %v expected %v", it.Index(), 1)
+	}
+}
+
+func TestMapFromSortedCopies(t *testing.T) {
+	keys, values := []int{1, 2}, []string{"a", "b"}
+	m, err := flatmap.FromSorted(utils.NumberComparator[int], keys, values)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	keys[0], values[0] = 3, "c"
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[1 2] [a b]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, err := flatmap.FromSorted(utils.NumberComparator[int], keys, values[:1]); err == nil {
+		t.Errorf("Got %v expected %v", err, "error")
+	}
+}
+
+func TestMapZeroValue(t *testing.T) {
+	var m flatmap.Map[string, int]
+	if err := json.Unmarshal([]byte(`{"b":2,"a":1}`), &m); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	m.Put("c", 3)
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[a b c] [1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var noComparator flatmap.Map[struct{}, int]
+	if err := noComparator.UnmarshalJSON([]byte(`{}`)); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapSerialization(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		original := flatmap.NewWithStringComparator[string]()
+		original.Put("d", "4")
+		original.Put("e", "5")
+		original.Put("c", "3")
+		original.Put("b", "2")
+		original.Put("a", "1")
+
+		assertSerialization(original, "A", t)
+
+		serialized, err := original.ToJSON()
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+		assertSerialization(original, "B", t)
+
+		deserialized := flatmap.NewWithStringComparator[string]()
+		err = deserialized.FromJSON(serialized)
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+		assertSerialization(deserialized, "C", t)
+	}
+}
+
+func TestMapBinarySerialization(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := flatmap.NewWithIntComparator[string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = flatmap.NewWithIntComparator[string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	zero := &flatmap.Map[int, string]{}
+	if err := zero.UnmarshalBinary(data); err != nil || zero.Size() != restored.Size() {
+		t.Errorf("Got %v, %v expected %v", zero.Size(), err, restored.Size())
+	}
+	if err := (&flatmap.Map[[2]int, string]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(10, "c")
+
+	data, err := json.Marshal(struct{ Map *flatmap.Map[int, string] }{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"1":"a","2":"b","10":"c"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Map flatmap.Map[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Map.Keys(), restored.Map.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&flatmap.Map[[2]int, string]{}).UnmarshalJSON([]byte("{}")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := flatmap.NewWithIntComparator[string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(10, "c")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":"a","2":"b","10":"c"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored flatmap.Map[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestMapJSONMarshalerTextKeys(t *testing.T) {
+	day := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	m := flatmap.NewWith[time.Time, int](utils.TimeComparator)
+	m.Put(day.AddDate(0, 0, 1), 2)
+	m.Put(day, 1)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"2022-03-01T00:00:00Z":1,"2022-03-02T00:00:00Z":2}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored flatmap.Map[time.Time, int]
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, found := restored.Get(day); actualValue != 1 || !found {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	if actualValue, expectedValue := restored.Size(), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapNew(t *testing.T) {
+	m := flatmap.New[time.Duration, string]()
+	m.Put(time.Hour, "hour")
+	m.Put(time.Second, "second")
+	m.Put(time.Minute, "minute")
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1s 1m0s 1h0m0s]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, value, found := m.Floor(30 * time.Minute); key != time.Minute || value != "minute" || !found {
+		t.Errorf("Got %v %v %v expected %v %v %v", key, value, found, time.Minute, "minute", true)
+	}
+	selected := m.Select(func(key time.Duration, value string) bool { return key >= time.Minute })
+	selected.Put(time.Millisecond, "millisecond")
+	if actualValue, expectedValue := fmt.Sprint(selected.Keys()), "[1ms 1m0s 1h0m0s]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := m.Size(), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return flatmap.New[int, string]() })
+}
+
+func TestMapConformance(t *testing.T) {
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return flatmap.NewWithIntComparator[string]() })
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		m := flatmap.NewWithIntComparator[string]()
+		for _, key := range keys {
+			m.Put(key, strconv.Itoa(key))
+		}
+		it := m.Iterator()
+		return &it
+	})
+}
+
+func FuzzMap(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return flatmap.NewWithIntComparator[string]() }, containertest.SortedOrder)
+}
+
+func FuzzMapNew(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return flatmap.New[int, string]() }, containertest.SortedOrder)
+}
+
+// noinspection GoBoolExpressions
+func assertSerialization(m *flatmap.Map[string, string], txt string, t *testing.T) {
+	if actualValue := m.Keys(); false ||
+		actualValue[0] != "a" ||
+		actualValue[1] != "b" ||
+		actualValue[2] != "c" ||
+		actualValue[3] != "d" ||
+		actualValue[4] != "e" {
+		t.Errorf("[%s] Got %v expected %v", txt, actualValue, "[a,b,c,d,e]")
+	}
+	if actualValue := m.Values(); false ||
+		actualValue[0] != "1" ||
+		actualValue[1] != "2" ||
+		actualValue[2] != "3" ||
+		actualValue[3] != "4" ||
+		actualValue[4] != "5" {
+		t.Errorf("[%s] Got %v expected %v", txt, actualValue, "[1,2,3,4,5]")
+	}
+	if actualValue, expectedValue := m.Size(), 5; actualValue != expectedValue {
+		t.Errorf("[%s] Got %v expected %v", txt, actualValue, expectedValue)
+	}
+}
+
+func benchmarkGet(b *testing.B, m *flatmap.Map[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			m.Get(n)
+		}
+	}
+}
+
+func benchmarkPut(b *testing.B, m *flatmap.Map[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			m.Put(n, struct{}{})
+		}
+	}
+}
+
+func benchmarkRemove(b *testing.B, m *flatmap.Map[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			m.Remove(n)
+		}
+	}
+}
+
+func BenchmarkFlatMapGet100(b *testing.B) {
+	b.StopTimer()
+	size := 100
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkGet(b, m, size)
+}
+
+func BenchmarkFlatMapGet1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkGet(b, m, size)
+}
+
+func BenchmarkFlatMapGet10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkGet(b, m, size)
+}
+
+func BenchmarkFlatMapGet100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkGet(b, m, size)
+}
+
+func BenchmarkFlatMapPut100(b *testing.B) {
+	b.StopTimer()
+	size := 100
+	m := flatmap.NewWithIntComparator[struct{}]()
+	b.StartTimer()
+	benchmarkPut(b, m, size)
+}
+
+func BenchmarkFlatMapPut1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkPut(b, m, size)
+}
+
+func BenchmarkFlatMapPut10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkPut(b, m, size)
+}
+
+func BenchmarkFlatMapPut100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkPut(b, m, size)
+}
+
+func BenchmarkFlatMapRemove100(b *testing.B) {
+	b.StopTimer()
+	size := 100
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkRemove(b, m, size)
+}
+
+func BenchmarkFlatMapRemove1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkRemove(b, m, size)
+}
+
+func BenchmarkFlatMapRemove10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkRemove(b, m, size)
+}
+
+func BenchmarkFlatMapRemove100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	m := flatmap.NewWithIntComparator[struct{}]()
+	for n := 0; n < size; n++ {
+		m.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkRemove(b, m, size)
+}
+
+func benchmarkSerializationMap(size int) *flatmap.Map[int, string] {
+	m := flatmap.NewWithIntComparator[string]()
+	for n := 0; n < size; n++ {
+		m.Put(n, fmt.Sprint(n))
+	}
+	return m
+}
+
+func BenchmarkFlatMapToJSON10000(b *testing.B) {
+	m := benchmarkSerializationMap(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := m.ToJSON()
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkFlatMapMarshalBinary10000(b *testing.B) {
+	m := benchmarkSerializationMap(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := m.MarshalBinary()
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkFlatMapFromJSON10000(b *testing.B) {
+	data, _ := benchmarkSerializationMap(10000).ToJSON()
+	m := flatmap.NewWithIntComparator[string]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.FromJSON(data)
+	}
+}
+
+func BenchmarkFlatMapUnmarshalBinary10000(b *testing.B) {
+	data, _ := benchmarkSerializationMap(10000).MarshalBinary()
+	m := flatmap.NewWithIntComparator[string]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.UnmarshalBinary(data)
+	}
+}
+
+// The benchmarks below compare the map with treemap, both instantiated by New, looking the keys up
+// in a shuffled order so that consecutive searches do not follow the same path.
+
+// shuffledKeys returns the integers in [0, size) in a random but fixed order.
+func shuffledKeys(size int) []int {
+	return rand.New(rand.NewSource(int64(size))).Perm(size)
+}
+
+func benchmarkFlatMapGetShuffled(b *testing.B, size int) {
+	keys := shuffledKeys(size)
+	m := flatmap.New[int, int]()
+	for _, key := range keys {
+		m.Put(key, key)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, key := range keys {
+			m.Get(key)
+		}
+	}
+}
+
+func benchmarkTreeMapGetShuffled(b *testing.B, size int) {
+	keys := shuffledKeys(size)
+	m := treemap.New[int, int]()
+	for _, key := range keys {
+		m.Put(key, key)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, key := range keys {
+			m.Get(key)
+		}
+	}
+}
+
+func benchmarkFlatMapIterate(b *testing.B, size int) {
+	m := flatmap.New[int, int]()
+	for _, key := range shuffledKeys(size) {
+		m.Put(key, key)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		it := m.Iterator()
+		for it.Next() {
+			_ = it.Value()
+		}
+	}
+}
+
+func benchmarkTreeMapIterate(b *testing.B, size int) {
+	m := treemap.New[int, int]()
+	for _, key := range shuffledKeys(size) {
+		m.Put(key, key)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		it := m.Iterator()
+		for it.Next() {
+			_ = it.Value()
+		}
+	}
+}
+
+func benchmarkFlatMapPutShuffled(b *testing.B, size int) {
+	keys := shuffledKeys(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := flatmap.New[int, int]()
+		for _, key := range keys {
+			m.Put(key, key)
+		}
+	}
+}
+
+func benchmarkFlatMapPutAllShuffled(b *testing.B, size int) {
+	keys := shuffledKeys(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := flatmap.New[int, int]()
+		m.PutAll(keys, keys)
+	}
+}
+
+func benchmarkTreeMapPutShuffled(b *testing.B, size int) {
+	keys := shuffledKeys(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := treemap.New[int, int]()
+		for _, key := range keys {
+			m.Put(key, key)
+		}
+	}
+}
+
+func BenchmarkFlatMapGetShuffled100(b *testing.B) {
+	benchmarkFlatMapGetShuffled(b, 100)
+}
+
+func BenchmarkTreeMapGetShuffled100(b *testing.B) {
+	benchmarkTreeMapGetShuffled(b, 100)
+}
+
+func BenchmarkFlatMapGetShuffled1000(b *testing.B) {
+	benchmarkFlatMapGetShuffled(b, 1000)
+}
+
+func BenchmarkTreeMapGetShuffled1000(b *testing.B) {
+	benchmarkTreeMapGetShuffled(b, 1000)
+}
+
+func BenchmarkFlatMapGetShuffled100000(b *testing.B) {
+	benchmarkFlatMapGetShuffled(b, 100000)
+}
+
+func BenchmarkTreeMapGetShuffled100000(b *testing.B) {
+	benchmarkTreeMapGetShuffled(b, 100000)
+}
+
+func BenchmarkFlatMapIterate1000(b *testing.B) {
+	benchmarkFlatMapIterate(b, 1000)
+}
+
+func BenchmarkTreeMapIterate1000(b *testing.B) {
+	benchmarkTreeMapIterate(b, 1000)
+}
+
+func BenchmarkFlatMapPutShuffled100(b *testing.B) {
+	benchmarkFlatMapPutShuffled(b, 100)
+}
+
+func BenchmarkTreeMapPutShuffled100(b *testing.B) {
+	benchmarkTreeMapPutShuffled(b, 100)
+}
+
+func BenchmarkFlatMapPutShuffled1000(b *testing.B) {
+	benchmarkFlatMapPutShuffled(b, 1000)
+}
+
+func BenchmarkTreeMapPutShuffled1000(b *testing.B) {
+	benchmarkTreeMapPutShuffled(b, 1000)
+}
+
+func BenchmarkFlatMapPutAllShuffled100000(b *testing.B) {
+	benchmarkFlatMapPutAllShuffled(b, 100000)
+}
+
+func BenchmarkTreeMapPutShuffled100000(b *testing.B) {
+	benchmarkTreeMapPutShuffled(b, 100000)
+}
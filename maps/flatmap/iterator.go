@@ -0,0 +1,175 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatmap
+
+import "github.com/monitor1379/yagods/containers"
+
+var _ containers.IteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
+
+// Iterator holding the iterator's state
+type Iterator[K any, V any] struct {
+	m        *Map[K, V]
+	index    int
+	modCount int  // map's modification count the iterator is synchronized with
+	removed  bool // current element was removed, index points to its predecessor
+}
+
+// Iterator returns a stateful iterator whose elements are key/value pairs.
+func (m *Map[K, V]) Iterator() Iterator[K, V] {
+	return Iterator[K, V]{m: m, index: -1, modCount: m.modCount}
+}
+
+// IteratorAt returns a stateful iterator whose elements are key/value pairs that is initialised
+// at the first element whose key is greater than or equal to the given key (see Seek()).
+func (m *Map[K, V]) IteratorAt(key K) Iterator[K, V] {
+	iterator := m.Iterator()
+	iterator.Seek(key)
+	return iterator
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the container.
+// If Next() returns true, then next element's key and value can be retrieved by Key() and Value().
+// If Next() was called for the first time, then it will point the iterator to the first element if it exists.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
+	if iterator.index < iterator.m.Size() {
+		iterator.index++
+	}
+	return iterator.withinRange()
+}
+
+// Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
+// If Prev() returns true, then previous element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Prev() bool {
+	iterator.checkModification()
+	if iterator.removed {
+		iterator.removed = false
+		return iterator.withinRange()
+	}
+	if iterator.index >= 0 {
+		iterator.index--
+	}
+	return iterator.withinRange()
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Value() V {
+	return iterator.m.values[iterator.index]
+}
+
+// Key returns the current element's key.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Key() K {
+	return iterator.m.keys[iterator.index]
+}
+
+// Index returns the position of the current element in the map, i.e. the number of keys less than its key.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Index() int {
+	return iterator.index
+}
+
+// Begin resets the iterator to its initial state (one-before-first)
+// Call Next() to fetch the first element if any.
+func (iterator *Iterator[K, V]) Begin() {
+	iterator.index = -1
+	iterator.modCount = iterator.m.modCount
+	iterator.removed = false
+}
+
+// End moves the iterator past the last element (one-past-the-end).
+// Call Prev() to fetch the last element if any.
+func (iterator *Iterator[K, V]) End() {
+	iterator.index = iterator.m.Size()
+	iterator.modCount = iterator.m.modCount
+	iterator.removed = false
+}
+
+// First moves the iterator to the first element and returns true if there was a first element in the container.
+// If First() returns true, then first element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator
+func (iterator *Iterator[K, V]) First() bool {
+	iterator.Begin()
+	return iterator.Next()
+}
+
+// Last moves the iterator to the last element and returns true if there was a last element in the container.
+// If Last() returns true, then last element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Last() bool {
+	iterator.End()
+	return iterator.Prev()
+}
+
+// Seek moves the iterator to the first element whose key is greater than or equal to the given key
+// and returns true if there was such an element in the map.
+// If Seek() returns true, then element's key and value can be retrieved by Key() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	iterator.End()
+	iterator.index, _ = iterator.m.find(key)
+	return iterator.withinRange()
+}
+
+// SeekPrev moves the iterator to the last element whose key is less than or equal to the given key
+// and returns true if there was such an element in the map.
+// If SeekPrev() returns true, then element's key and value can be retrieved by Key() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) SeekPrev(key K) bool {
+	iterator.Begin()
+	index, found := iterator.m.find(key)
+	if !found {
+		index--
+	}
+	iterator.index = index
+	return iterator.withinRange()
+}
+
+// Err returns containers.ErrConcurrentModification if the map was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	if iterator.modCount != iterator.m.modCount && (iterator.index != -1 || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current element from the map and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[K, V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || !iterator.withinRange() {
+		return
+	}
+	iterator.m.removeAt(iterator.index, iterator.index+1)
+	iterator.modCount = iterator.m.modCount
+	iterator.index--
+	iterator.removed = true
+}
+
+func (iterator *Iterator[K, V]) withinRange() bool {
+	return iterator.index >= 0 && iterator.index < iterator.m.Size()
+}
+
+// checkModification panics if the map was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the map.
+func (iterator *Iterator[K, V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.m.modCount
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatmap
+
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ containers.JSONSerializer = (*Map[int, string])(nil)
+var _ containers.JSONDeserializer = (*Map[int, string])(nil)
+var _ encoding.BinaryMarshaler = (*Map[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[int, string])(nil)
+var _ gob.GobEncoder = (*Map[int, string])(nil)
+var _ gob.GobDecoder = (*Map[int, string])(nil)
+var _ json.Marshaler = (*Map[int, string])(nil)
+var _ json.Unmarshaler = (*Map[int, string])(nil)
+var _ containers.JSONWriter = (*Map[int, string])(nil)
+var _ containers.JSONReader = (*Map[int, string])(nil)
+
+// ToJSON outputs the JSON representation of the map, see MarshalJSON.
+func (m *Map[K, V]) ToJSON() ([]byte, error) {
+	return m.MarshalJSON()
+}
+
+// FromJSON populates the map from the input JSON representation, see UnmarshalJSON.
+func (m *Map[K, V]) FromJSON(data []byte) error {
+	return m.UnmarshalJSON(data)
+}
+
+// MarshalBinary outputs the binary representation of the map in key order.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), m.keys, m.values)
+}
+
+// UnmarshalBinary populates the map from the input binary representation.
+// The zero value of a map is ordered by default comparators, see utils.DefaultComparator;
+// returns containers.ErrNoComparator if there are none.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		m.Clear()
+		m.PutAll(keys, values)
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object with its entries in key order.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return containers.MarshalJSONEntries(m.keys, m.values)
+}
+
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, see MarshalJSON.
+// Like UnmarshalBinary, it accepts the zero value of a map.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	keys, values, err := containers.UnmarshalJSONEntries[K, V](data)
+	if err == nil {
+		m.Clear()
+		m.PutAll(keys, values)
+	}
+	return err
+}
+
+// initZero sets the comparator of a map that was not instantiated by a constructor
+// to the default comparator of its keys.
+func (m *Map[K, V]) initZero() error {
+	if m.comparator == nil {
+		comparator := utils.DefaultComparator[K]()
+		if comparator == nil {
+			return containers.ErrNoComparator
+		}
+		m.comparator = comparator
+	}
+	return nil
+}
+
+// WriteJSON writes the JSON representation of the map to the writer in key order, encoding one entry at a time.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	it := m.Iterator()
+	return containers.WriteJSONEntries[K, V](w, &it)
+}
+
+// ReadJSON populates the map from the JSON representation read from the reader, merging the entries
+// into the map once they are all decoded. Like UnmarshalJSON, it accepts the zero value of a map.
+// On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	var (
+		keys   []K
+		values []V
+	)
+	err := containers.ReadJSONEntries(r, func(key K, value V) {
+		keys = append(keys, key)
+		values = append(values, value)
+	})
+	m.Clear()
+	m.PutAll(keys, values)
+	return err
+}
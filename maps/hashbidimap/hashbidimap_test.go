@@ -5,10 +5,19 @@
 package hashbidimap_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
 	"github.com/monitor1379/yagods/maps/hashbidimap"
+	"github.com/monitor1379/yagods/utils"
 )
 
 func TestMapPut(t *testing.T) {
@@ -183,6 +192,106 @@ func TestMapSerialization(t *testing.T) {
 	assert()
 }
 
+func TestMapBinarySerialization(t *testing.T) {
+	m := hashbidimap.New[int, string]()
+	m.Put(1, "a")
+	m.Put(2, "b")
+	m.Put(3, "c")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := hashbidimap.New[int, string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(containers.GetSortedValues[string](restored, utils.StringComparator)), fmt.Sprint(containers.GetSortedValues[string](m, utils.StringComparator)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, found := restored.GetKey("b"); key != 2 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", key, found, 2, true)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = hashbidimap.New[int, string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(containers.GetSortedValues[string](restored, utils.StringComparator)), fmt.Sprint(containers.GetSortedValues[string](m, utils.StringComparator)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, found := restored.GetKey("b"); key != 2 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", key, found, 2, true)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := hashbidimap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+
+	data, err := json.Marshal(struct{ Map *hashbidimap.Map[int, string] }{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"1":"a","2":"b"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Map hashbidimap.Map[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Map.Size()), fmt.Sprint(m.Size()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := hashbidimap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":"a","2":"b"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored hashbidimap.Map[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Size()), fmt.Sprint(m.Size()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestMapConformance(t *testing.T) {
+	containertest.TestMap(t, func() maps.Map[int, string] { return hashbidimap.New[int, string]() })
+}
+
+func FuzzMap(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return hashbidimap.New[int, string]() }, containertest.Unordered)
+}
+
 func sameElements[T comparable](a []T, b []T) bool {
 	if len(a) != len(b) {
 		return false
@@ -5,13 +5,24 @@
 package hashbidimap
 
 import (
+	"encoding"
+	"encoding/gob"
 	"encoding/json"
+	"io"
 
 	"github.com/monitor1379/yagods/containers"
 )
 
 var _ containers.JSONSerializer = (*Map[int, string])(nil)
 var _ containers.JSONDeserializer = (*Map[int, string])(nil)
+var _ encoding.BinaryMarshaler = (*Map[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[int, string])(nil)
+var _ gob.GobEncoder = (*Map[int, string])(nil)
+var _ gob.GobDecoder = (*Map[int, string])(nil)
+var _ json.Marshaler = (*Map[int, string])(nil)
+var _ json.Unmarshaler = (*Map[int, string])(nil)
+var _ containers.JSONWriter = (*Map[int, string])(nil)
+var _ containers.JSONReader = (*Map[int, string])(nil)
 
 // ToJSON outputs the JSON representation of the map.
 func (m *Map[K, V]) ToJSON() ([]byte, error) {
@@ -30,3 +41,72 @@ func (m *Map[K, V]) FromJSON(data []byte) error {
 	}
 	return err
 }
+
+// MarshalBinary outputs the binary representation of the map.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	keys := m.Keys()
+	values := make([]V, len(keys))
+	for i, key := range keys {
+		values[i], _ = m.Get(key)
+	}
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), keys, values)
+}
+
+// UnmarshalBinary populates the map from the input binary representation.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object as encoding/json outputs a Go map.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return m.forwardMap.MarshalJSON()
+}
+
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, see MarshalJSON.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	var elements map[K]V
+	err := json.Unmarshal(data, &elements)
+	if err == nil {
+		m.Clear()
+		for key, value := range elements {
+			m.Put(key, value)
+		}
+	}
+	return err
+}
+
+// WriteJSON writes the JSON representation of the map to the writer, see MarshalJSON.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	data, err := m.MarshalJSON()
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	return err
+}
+
+// ReadJSON populates the map from the JSON representation read from the reader, putting every entry as it is decoded.
+// On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	m.Clear()
+	return containers.ReadJSONEntries(r, func(key K, value V) {
+		m.Put(key, value)
+	})
+}
@@ -5,10 +5,19 @@
 package hashmap_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
 	"github.com/monitor1379/yagods/maps/hashmap"
+	"github.com/monitor1379/yagods/utils"
 )
 
 func TestMapPut(t *testing.T) {
@@ -151,6 +160,157 @@ func TestMapSerialization(t *testing.T) {
 	assert()
 }
 
+// cell is a struct key implementing encoding.TextMarshaler, which the JSON serialization uses
+type cell struct {
+	Row, Column int
+}
+
+func (c cell) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d:%d", c.Row, c.Column)), nil
+}
+
+func (c *cell) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d:%d", &c.Row, &c.Column)
+	return err
+}
+
+func TestMapJSONTextKeys(t *testing.T) {
+	m := hashmap.New[cell, string]()
+	m.Put(cell{1, 2}, "a")
+	m.Put(cell{3, 4}, "b")
+
+	data, err := m.ToJSON()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"1:2":"a","3:4":"b"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restored := hashmap.New[cell, string]()
+	if err := restored.FromJSON(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, found := restored.Get(cell{3, 4}); actualValue != "b" || !found {
+		t.Errorf("Got %v expected %v", actualValue, "b")
+	}
+	if actualValue, expectedValue := restored.Size(), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if marshaled, _ := json.Marshal(m); string(marshaled) != string(data) {
+		t.Errorf("Got %v expected %v", string(marshaled), string(data))
+	}
+
+	points := hashmap.New[point, string]()
+	points.Put(point{1, 2}, "a")
+	if _, err := points.ToJSON(); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+// point is a struct key, which the JSON serialization rejects
+type point struct {
+	X, Y int
+}
+
+func TestMapBinarySerialization(t *testing.T) {
+	m := hashmap.New[point, string]()
+	m.Put(point{1, 2}, "a")
+	m.Put(point{3, 4}, "b")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := hashmap.New[point, string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(containers.GetSortedValues[string](restored, utils.StringComparator)), fmt.Sprint(containers.GetSortedValues[string](m, utils.StringComparator)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found := restored.Get(point{3, 4}); value != "b" || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "b", true)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = hashmap.New[point, string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(containers.GetSortedValues[string](restored, utils.StringComparator)), fmt.Sprint(containers.GetSortedValues[string](m, utils.StringComparator)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found := restored.Get(point{3, 4}); value != "b" || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "b", true)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := hashmap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+
+	data, err := json.Marshal(struct{ Map *hashmap.Map[int, string] }{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"1":"a","2":"b"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Map hashmap.Map[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Map.Size()), fmt.Sprint(m.Size()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := hashmap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":"a","2":"b"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored hashmap.Map[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Size()), fmt.Sprint(m.Size()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestMapConformance(t *testing.T) {
+	containertest.TestMap(t, func() maps.Map[int, string] { return hashmap.New[int, string]() })
+}
+
+func FuzzMap(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return hashmap.New[int, string]() }, containertest.Unordered)
+}
+
 func sameElements[T comparable](a []T, b []T) bool {
 	if len(a) != len(b) {
 		return false
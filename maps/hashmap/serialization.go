@@ -5,22 +5,28 @@
 package hashmap
 
 import (
+	"encoding"
+	"encoding/gob"
 	"encoding/json"
+	"io"
 
 	"github.com/monitor1379/yagods/containers"
-	"github.com/monitor1379/yagods/utils"
 )
 
 var _ containers.JSONSerializer = (*Map[string, int])(nil)
 var _ containers.JSONDeserializer = (*Map[string, int])(nil)
+var _ encoding.BinaryMarshaler = (*Map[string, int])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[string, int])(nil)
+var _ gob.GobEncoder = (*Map[string, int])(nil)
+var _ gob.GobDecoder = (*Map[string, int])(nil)
+var _ json.Marshaler = (*Map[string, int])(nil)
+var _ json.Unmarshaler = (*Map[string, int])(nil)
+var _ containers.JSONWriter = (*Map[string, int])(nil)
+var _ containers.JSONReader = (*Map[string, int])(nil)
 
-// ToJSON outputs the JSON representation of the map.
+// ToJSON outputs the JSON representation of the map, see MarshalJSON.
 func (m *Map[K, V]) ToJSON() ([]byte, error) {
-	elements := make(map[string]V)
-	for key, value := range m.m {
-		elements[utils.ToString(key)] = value
-	}
-	return json.Marshal(&elements)
+	return m.MarshalJSON()
 }
 
 // FromJSON populates the map from the input JSON representation.
@@ -35,3 +41,74 @@ func (m *Map[K, V]) FromJSON(data []byte) error {
 	}
 	return err
 }
+
+// MarshalBinary outputs the binary representation of the map.
+// Keys and values are encoded by the codecs of containers.CodecFor, so that keys of any type round-trip.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	keys, values := make([]K, 0, len(m.m)), make([]V, 0, len(m.m))
+	for key, value := range m.m {
+		keys, values = append(keys, key), append(values, value)
+	}
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), keys, values)
+}
+
+// UnmarshalBinary populates the map from the input binary representation.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.m[key] = values[i]
+		}
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object as encoding/json outputs a Go map.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	if m.m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m.m)
+}
+
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, see MarshalJSON.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	var elements map[K]V
+	err := json.Unmarshal(data, &elements)
+	if err == nil {
+		m.Clear()
+		for key, value := range elements {
+			m.m[key] = value
+		}
+	}
+	return err
+}
+
+// WriteJSON writes the JSON representation of the map to the writer, see MarshalJSON.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	data, err := m.MarshalJSON()
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	return err
+}
+
+// ReadJSON populates the map from the JSON representation read from the reader, putting every entry as it is decoded.
+// On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	m.Clear()
+	return containers.ReadJSONEntries(r, func(key K, value V) {
+		m.m[key] = value
+	})
+}
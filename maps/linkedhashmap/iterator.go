@@ -11,18 +11,19 @@ import (
 
 var _ containers.IteratorWithKey[int, string] = (*Iterator[int, string])(nil)
 var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
 
 // Iterator holding the iterator's state
 type Iterator[K comparable, V any] struct {
 	iterator doublylinkedlist.Iterator[K]
-	table    map[K]V
+	m        *Map[K, V]
 }
 
 // Iterator returns a stateful iterator whose elements are key/value pairs.
 func (m *Map[K, V]) Iterator() Iterator[K, V] {
 	return Iterator[K, V]{
 		iterator: m.ordering.Iterator(),
-		table:    m.table,
+		m:        m,
 	}
 }
 
@@ -45,7 +46,7 @@ func (iterator *Iterator[K, V]) Prev() bool {
 // Does not modify the state of the iterator.
 func (iterator *Iterator[K, V]) Value() V {
 	key := iterator.iterator.Value()
-	return iterator.table[key]
+	return iterator.m.table[key]
 }
 
 // Key returns the current element's key.
@@ -79,3 +80,25 @@ func (iterator *Iterator[K, V]) First() bool {
 func (iterator *Iterator[K, V]) Last() bool {
 	return iterator.iterator.Last()
 }
+
+// Err returns containers.ErrConcurrentModification if the map was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	return iterator.iterator.Err()
+}
+
+// Remove removes the current element from the map and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[K, V]) Remove() {
+	var key K
+	size := iterator.m.Size()
+	if index := iterator.iterator.Index(); index >= 0 && index < size {
+		key = iterator.iterator.Value()
+	}
+	iterator.iterator.Remove()
+	if iterator.m.Size() < size {
+		delete(iterator.m.table, key)
+	}
+}
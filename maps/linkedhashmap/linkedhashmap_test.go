@@ -5,9 +5,18 @@
 package linkedhashmap_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
 	"github.com/monitor1379/yagods/maps/linkedhashmap"
 )
 
@@ -442,6 +451,30 @@ func TestMapIteratorLast(t *testing.T) {
 	}
 }
 
+func TestMapIteratorRemove(t *testing.T) {
+	m := linkedhashmap.New[int, string]()
+	for i := 1; i <= 6; i++ {
+		m.Put(i, fmt.Sprint(i))
+	}
+	it := m.Iterator()
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, found := m.Get(2); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	it.Last()
+	m.Put(7, "7")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestMapSerialization(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		original := linkedhashmap.New[string, string]()
@@ -468,6 +501,162 @@ func TestMapSerialization(t *testing.T) {
 	}
 }
 
+func TestMapBinarySerialization(t *testing.T) {
+	m := linkedhashmap.New[int, string]()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := linkedhashmap.New[int, string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = linkedhashmap.New[int, string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := linkedhashmap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(3, "c")
+
+	data, err := json.Marshal(struct {
+		Map *linkedhashmap.Map[int, string]
+	}{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"2":"b","1":"a","3":"c"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct {
+		Map linkedhashmap.Map[int, string]
+	}
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Map.Keys(), restored.Map.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := linkedhashmap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(3, "c")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"2":"b","1":"a","3":"c"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored linkedhashmap.Map[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestMapFromJSONOrder(t *testing.T) {
+	m := linkedhashmap.New[string, string]()
+	// the key "c" appears as a value before the key "b"
+	if err := m.FromJSON([]byte(`{"a":"c","b":"x","c":"y"}`)); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[a b c]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+type version struct {
+	Major, Minor int
+}
+
+func (v version) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("v%d.%d", v.Major, v.Minor)), nil
+}
+
+func (v *version) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "v%d.%d", &v.Major, &v.Minor)
+	return err
+}
+
+func TestMapJSONMarshalerTextKeys(t *testing.T) {
+	m := linkedhashmap.New[version, string]()
+	m.Put(version{1, 10}, "b")
+	m.Put(version{1, 2}, "a")
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"v1.10":"b","v1.2":"a"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored linkedhashmap.Map[version, string]
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), "[{1 10} {1 2}] [b a]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"1.2":"a"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestMapConformance(t *testing.T) {
+	containertest.TestMap(t, func() maps.Map[int, string] { return linkedhashmap.New[int, string]() })
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		m := linkedhashmap.New[int, string]()
+		for _, key := range keys {
+			m.Put(key, strconv.Itoa(key))
+		}
+		it := m.Iterator()
+		return &it
+	})
+}
+
+func FuzzMap(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return linkedhashmap.New[int, string]() }, containertest.InsertionOrder)
+}
+
 //noinspection GoBoolExpressions
 func assertSerialization(m *linkedhashmap.Map[string, string], txt string, t *testing.T) {
 	if actualValue := m.Keys(); false ||
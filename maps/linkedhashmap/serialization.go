@@ -6,82 +6,120 @@ package linkedhashmap
 
 import (
 	"bytes"
+	"encoding"
+	"encoding/gob"
 	"encoding/json"
+	"io"
 
 	"github.com/monitor1379/yagods/containers"
-	"github.com/monitor1379/yagods/utils"
+	"github.com/monitor1379/yagods/lists/doublylinkedlist"
 )
 
 var _ containers.JSONSerializer = (*Map[int, string])(nil)
 var _ containers.JSONDeserializer = (*Map[int, string])(nil)
-
-// ToJSON outputs the JSON representation of map.
+var _ encoding.BinaryMarshaler = (*Map[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[int, string])(nil)
+var _ gob.GobEncoder = (*Map[int, string])(nil)
+var _ gob.GobDecoder = (*Map[int, string])(nil)
+var _ json.Marshaler = (*Map[int, string])(nil)
+var _ json.Unmarshaler = (*Map[int, string])(nil)
+var _ containers.JSONWriter = (*Map[int, string])(nil)
+var _ containers.JSONReader = (*Map[int, string])(nil)
+
+// ToJSON outputs the JSON representation of map, a JSON object of the entries in insertion order.
+// Keys are encoded as in containers.MarshalJSONEntries, so that keys of any kind become strings.
 func (m *Map[K, V]) ToJSON() ([]byte, error) {
-	var b []byte
-	buf := bytes.NewBuffer(b)
+	return m.MarshalJSON()
+}
 
-	buf.WriteRune('{')
+// FromJSON populates map from the input JSON representation, in the order of the input.
+func (m *Map[K, V]) FromJSON(data []byte) error {
+	keys, values, err := containers.UnmarshalJSONEntries[K, V](data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
 
+// MarshalBinary outputs the binary representation of the map in insertion order.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	keys, values := make([]K, 0, m.Size()), make([]V, 0, m.Size())
 	it := m.Iterator()
-	lastIndex := m.Size() - 1
-	index := 0
-
 	for it.Next() {
-		km, err := json.Marshal(it.Key())
-		if err != nil {
-			return nil, err
-		}
-		buf.Write(km)
-
-		buf.WriteRune(':')
+		keys, values = append(keys, it.Key()), append(values, it.Value())
+	}
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), keys, values)
+}
 
-		vm, err := json.Marshal(it.Value())
-		if err != nil {
-			return nil, err
+// UnmarshalBinary populates the map from the input binary representation, restoring the insertion order.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		if m.ordering == nil {
+			m.ordering = doublylinkedlist.New[K]()
 		}
-		buf.Write(vm)
-
-		if index != lastIndex {
-			buf.WriteRune(',')
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
 		}
-
-		index++
 	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
 
-	buf.WriteRune('}')
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
 
-	return buf.Bytes(), nil
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object with its entries in insertion order.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := m.WriteJSON(&buffer)
+	return buffer.Bytes(), err
 }
 
-// FromJSON populates map from the input JSON representation.
-func (m *Map[K, V]) FromJSON(data []byte) error {
-	elements := make(map[K]V)
-	err := json.Unmarshal(data, &elements)
-	if err != nil {
-		return err
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, restoring the insertion order.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	keys, values, err := containers.UnmarshalJSONEntries[K, V](data)
+	if err == nil {
+		if m.ordering == nil {
+			m.ordering = doublylinkedlist.New[K]()
+		}
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
 	}
+	return err
+}
 
-	index := make(map[K]int)
-	var keys []K
-	for key := range elements {
-		keys = append(keys, key)
-		esc, _ := json.Marshal(key)
-		index[key] = bytes.Index(data, esc)
+// WriteJSON writes the JSON representation of the map to the writer in insertion order, encoding one entry at a time.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	if m.ordering == nil {
+		_, err := io.WriteString(w, "{}")
+		return err
 	}
+	it := m.Iterator()
+	return containers.WriteJSONEntries[K, V](w, &it)
+}
 
-	byIndex := func(a, b K) int {
-		index1 := index[a]
-		index2 := index[b]
-		return index1 - index2
+// ReadJSON populates the map from the JSON representation read from the reader in the order of the input,
+// putting every entry as it is decoded. On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	if m.ordering == nil {
+		m.ordering = doublylinkedlist.New[K]()
 	}
-
-	utils.Sort(keys, byIndex)
-
 	m.Clear()
-
-	for _, key := range keys {
-		m.Put(key, elements[key])
-	}
-
-	return nil
+	return containers.ReadJSONEntries(r, func(key K, value V) {
+		m.Put(key, value)
+	})
 }
@@ -18,7 +18,7 @@ package maps
 import "github.com/monitor1379/yagods/containers"
 
 // Map interface that all maps implement
-type Map[K comparable, V any] interface {
+type Map[K any, V any] interface {
 	Put(key K, value V)
 	Get(key K) (value V, found bool)
 	Remove(key K)
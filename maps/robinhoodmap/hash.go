@@ -0,0 +1,98 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package robinhoodmap
+
+import (
+	"hash/maphash"
+	"math/bits"
+
+	"github.com/monitor1379/yagods/utils"
+)
+
+// Multipliers of the hash functions, odd constants with well mixed bits.
+const (
+	m1 = 0xa0761d6478bd642f
+	m2 = 0xe7037ed1a0b428db
+	m3 = 0x8ebc6af09c88c6e3
+)
+
+// mix multiplies a and b into 128 bits and folds them back into 64.
+func mix(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return hi ^ lo
+}
+
+func hashUint64(key, seed uint64) uint64 {
+	return mix(key^seed^m1, m2)
+}
+
+// hashString hashes the string eight bytes at a time.
+func hashString(key string, seed uint64) uint64 {
+	h := seed ^ uint64(len(key))*m1
+	for ; len(key) >= 8; key = key[8:] {
+		h = mix(h^load64(key), m2)
+	}
+	var tail uint64
+	for i := len(key) - 1; i >= 0; i-- {
+		tail = tail<<8 | uint64(key[i])
+	}
+	return mix(h^tail^m3, m2)
+}
+
+// load64 returns the first eight bytes of the string as a little-endian integer.
+func load64(s string) uint64 {
+	_ = s[7] // one bounds check
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
+
+// randomSeed returns a random non-zero seed.
+func randomSeed() uint64 {
+	for {
+		// a new maphash.Hash has a random seed, which its hash of nothing reveals
+		if seed := new(maphash.Hash).Sum64(); seed != 0 {
+			return seed
+		}
+	}
+}
+
+// hashFunc returns the hash function of the keys for the seed, or for a random seed if it is zero.
+// Integers and strings are hashed by the functions of this package, all other keys by utils.NewComparableHasher,
+// which is seeded randomly regardless of the seed.
+func hashFunc[K comparable](seed uint64) func(key K) uint64 {
+	if seed == 0 {
+		seed = randomSeed()
+	}
+	var hash interface{}
+	switch any(*new(K)).(type) {
+	case int:
+		hash = func(key int) uint64 { return hashUint64(uint64(key), seed) }
+	case int8:
+		hash = func(key int8) uint64 { return hashUint64(uint64(key), seed) }
+	case int16:
+		hash = func(key int16) uint64 { return hashUint64(uint64(key), seed) }
+	case int32:
+		hash = func(key int32) uint64 { return hashUint64(uint64(key), seed) }
+	case int64:
+		hash = func(key int64) uint64 { return hashUint64(uint64(key), seed) }
+	case uint:
+		hash = func(key uint) uint64 { return hashUint64(uint64(key), seed) }
+	case uint8:
+		hash = func(key uint8) uint64 { return hashUint64(uint64(key), seed) }
+	case uint16:
+		hash = func(key uint16) uint64 { return hashUint64(uint64(key), seed) }
+	case uint32:
+		hash = func(key uint32) uint64 { return hashUint64(uint64(key), seed) }
+	case uint64:
+		hash = func(key uint64) uint64 { return hashUint64(key, seed) }
+	case uintptr:
+		hash = func(key uintptr) uint64 { return hashUint64(uint64(key), seed) }
+	case string:
+		hash = func(key string) uint64 { return hashString(key, seed) }
+	default:
+		return utils.NewComparableHasher[K]().Hash
+	}
+	return hash.(func(key K) uint64)
+}
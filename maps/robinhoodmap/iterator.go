@@ -0,0 +1,135 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package robinhoodmap
+
+import "github.com/monitor1379/yagods/containers"
+
+var _ containers.IteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
+
+// Iterator holding the iterator's state
+type Iterator[K comparable, V any] struct {
+	m        *Map[K, V]
+	index    int  // index of the current entry, -1 before the first and len(m.entries) past the last
+	modCount int  // map's modification count the iterator is synchronized with
+	removed  bool // current element was removed, index points to the entry that took its place
+}
+
+// Iterator returns a stateful iterator whose elements are key/value pairs, in the iteration order of the map.
+func (m *Map[K, V]) Iterator() Iterator[K, V] {
+	return Iterator[K, V]{m: m, index: -1, modCount: m.modCount}
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the container.
+// If Next() returns true, then next element's key and value can be retrieved by Key() and Value().
+// If Next() was called for the first time, then it will point the iterator to the first element if it exists.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Next() bool {
+	iterator.checkModification()
+	entries := iterator.m.entries
+	if iterator.removed {
+		iterator.removed = false
+	} else if iterator.index < len(entries) {
+		iterator.index++
+	}
+	for iterator.index < len(entries) && entries[iterator.index].removed {
+		iterator.index++
+	}
+	return iterator.index < len(entries)
+}
+
+// Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
+// If Prev() returns true, then previous element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Prev() bool {
+	iterator.checkModification()
+	iterator.removed = false
+	entries := iterator.m.entries
+	if iterator.index >= 0 {
+		iterator.index--
+	}
+	for iterator.index >= 0 && entries[iterator.index].removed {
+		iterator.index--
+	}
+	return iterator.index >= 0
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Value() V {
+	return iterator.m.entries[iterator.index].value
+}
+
+// Key returns the current element's key.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Key() K {
+	return iterator.m.entries[iterator.index].key
+}
+
+// Begin resets the iterator to its initial state (one-before-first)
+// Call Next() to fetch the first element if any.
+func (iterator *Iterator[K, V]) Begin() {
+	iterator.index = -1
+	iterator.modCount = iterator.m.modCount
+	iterator.removed = false
+}
+
+// End moves the iterator past the last element (one-past-the-end).
+// Call Prev() to fetch the last element if any.
+func (iterator *Iterator[K, V]) End() {
+	iterator.index = len(iterator.m.entries)
+	iterator.modCount = iterator.m.modCount
+	iterator.removed = false
+}
+
+// First moves the iterator to the first element and returns true if there was a first element in the container.
+// If First() returns true, then first element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator
+func (iterator *Iterator[K, V]) First() bool {
+	iterator.Begin()
+	return iterator.Next()
+}
+
+// Last moves the iterator to the last element and returns true if there was a last element in the container.
+// If Last() returns true, then last element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Last() bool {
+	iterator.End()
+	return iterator.Prev()
+}
+
+// Err returns containers.ErrConcurrentModification if the map was modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	if iterator.modCount != iterator.m.modCount && (iterator.index != -1 || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current element from the map and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Without Options.StableOrder, the last element takes the place of the removed one, so Next() moves to it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[K, V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || iterator.index < 0 || iterator.index >= len(iterator.m.entries) {
+		return
+	}
+	iterator.index = iterator.m.removeSlot(iterator.m.slotOf(iterator.index))
+	iterator.modCount = iterator.m.modCount
+	iterator.removed = true
+}
+
+// checkModification panics if the map was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the map.
+func (iterator *Iterator[K, V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.m.modCount
+}
@@ -0,0 +1,376 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package robinhoodmap implements a map backed by an open-addressing hash table with Robin Hood probing.
+//
+// Entries are kept in a dense array, in the order they were put, and the hash table only holds their indexes
+// along with 32 bits of their hashes. A key is searched by linear probing from the slot its hash selects,
+// comparing keys only for matching hashes. Robin Hood insertion gives a slot to the entry that is farther
+// from its home slot, which keeps probe sequences short even at high load factors and lets a search stop as soon as
+// it meets an entry closer to its home than the key would be. Removals shift the following entries of the probe
+// sequence back, so the table never holds tombstones.
+//
+// Iteration follows the array of entries, so its order depends only on the sequence of operations and never on the
+// hashes: two maps given the same operations iterate alike whatever their seeds. The order is the insertion order
+// until an entry is removed, which moves the last entry into its place, unless the map was created with
+// Options.StableOrder, which keeps the insertion order through removals.
+//
+// Structure is not thread safe.
+//
+// Reference: https://en.wikipedia.org/wiki/Hash_table#Robin_Hood_hashing
+package robinhoodmap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/monitor1379/yagods/maps"
+)
+
+var _ maps.Map[string, int] = (*Map[string, int])(nil)
+
+// DefaultLoadFactor is the maximum load factor of maps whose options leave it zero.
+const DefaultLoadFactor = 0.875
+
+// minSlots is the size of the smallest hash table.
+const minSlots = 8
+
+// Options configures a map. The zero value has the default load factor, a random seed and no stable order.
+type Options struct {
+	// LoadFactor is the maximum ratio of entries to slots of the hash table, above which the table doubles.
+	// It must be in (0, 1), zero standing for DefaultLoadFactor. Lower factors trade memory for shorter probes.
+	LoadFactor float64
+	// Seed is the seed of the hash function, zero standing for a random seed.
+	// Maps with the same seed lay out the same keys alike.
+	Seed uint64
+	// StableOrder keeps iteration in insertion order through removals, at the price of leaving a hole
+	// in the array of entries for every removal until the holes outnumber the entries and the array is compacted.
+	StableOrder bool
+}
+
+// Map holds the entries in insertion order and their indexes in a hash table.
+type Map[K comparable, V any] struct {
+	hash     func(key K) uint64 // nil until the map first allocates its table
+	options  Options
+	slots    []slot // hash table, its length a power of two or zero
+	entries  []entry[K, V]
+	size     int // number of entries not removed
+	maxSize  int // number of entries above which the hash table grows
+	modCount int // incremented whenever entries are added, removed or moved, checked by iterators to fail fast
+}
+
+// slot of the hash table, empty if its index is zero.
+type slot struct {
+	hash  uint32 // low bits of the hash of the key
+	index uint32 // index of the entry plus one
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	hash    uint32
+	removed bool // hole left by a removal with Options.StableOrder
+}
+
+// New instantiates a hash map with the default options.
+func New[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{}
+}
+
+// NewWith instantiates a hash map with the options.
+// Panics if the load factor is not in (0, 1).
+func NewWith[K comparable, V any](options Options) *Map[K, V] {
+	if options.LoadFactor < 0 || options.LoadFactor >= 1 {
+		panic(fmt.Sprintf("robinhoodmap: load factor %v is not in (0, 1)", options.LoadFactor))
+	}
+	return &Map[K, V]{options: options}
+}
+
+func (m *Map[K, V]) loadFactor() float64 {
+	if m.options.LoadFactor == 0 {
+		return DefaultLoadFactor
+	}
+	return m.options.LoadFactor
+}
+
+// home returns the slot at which the probe sequence of the hash starts.
+func (m *Map[K, V]) home(hash uint32) int {
+	return int(hash) & (len(m.slots) - 1)
+}
+
+// distance returns how far the slot at index i is from the home slot of the hash.
+func (m *Map[K, V]) distance(i int, hash uint32) int {
+	return (i - m.home(hash)) & (len(m.slots) - 1)
+}
+
+// find returns the index of the slot holding the key, or -1 if the key is not found.
+func (m *Map[K, V]) find(key K, hash uint32) int {
+	if len(m.slots) == 0 {
+		return -1
+	}
+	mask := len(m.slots) - 1
+	for i, d := m.home(hash), 0; ; i, d = (i+1)&mask, d+1 {
+		s := m.slots[i]
+		if s.index == 0 || m.distance(i, s.hash) < d {
+			return -1
+		}
+		if s.hash == hash && m.entries[s.index-1].key == key {
+			return i
+		}
+	}
+}
+
+// slotOf returns the index of the slot of the entry at the index.
+// Unlike find, it does not compare keys, which may not even equal themselves, such as NaN.
+func (m *Map[K, V]) slotOf(index int) int {
+	mask := len(m.slots) - 1
+	i := m.home(m.entries[index].hash)
+	for int(m.slots[i].index) != index+1 {
+		i = (i + 1) & mask
+	}
+	return i
+}
+
+// insert puts the slot into the hash table, which must have room for it.
+func (m *Map[K, V]) insert(s slot) {
+	mask := len(m.slots) - 1
+	for i, d := m.home(s.hash), 0; ; i, d = (i+1)&mask, d+1 {
+		if m.slots[i].index == 0 {
+			m.slots[i] = s
+			return
+		}
+		// the entry closer to its home gives its slot away
+		if other := m.distance(i, m.slots[i].hash); other < d {
+			s, m.slots[i] = m.slots[i], s
+			d = other
+		}
+	}
+}
+
+// delete empties the slot at index i, shifting the rest of its probe sequence back.
+func (m *Map[K, V]) delete(i int) {
+	mask := len(m.slots) - 1
+	for j := (i + 1) & mask; m.slots[j].index != 0 && m.distance(j, m.slots[j].hash) > 0; j = (j + 1) & mask {
+		m.slots[i] = m.slots[j]
+		i = j
+	}
+	m.slots[i] = slot{}
+}
+
+// slotsFor returns the number of slots of a hash table holding size entries.
+func (m *Map[K, V]) slotsFor(size int) int {
+	n := minSlots
+	for float64(size) > float64(n)*m.loadFactor() {
+		n *= 2
+	}
+	return n
+}
+
+// rehash rebuilds the hash table with n slots, compacting the entries first if any were removed.
+func (m *Map[K, V]) rehash(n int) {
+	if m.hash == nil {
+		m.hash = hashFunc[K](m.options.Seed)
+	}
+	if len(m.entries) > m.size {
+		m.compact()
+	}
+	m.modCount++
+	m.slots = make([]slot, n)
+	m.maxSize = int(float64(n) * m.loadFactor())
+	for i, e := range m.entries {
+		m.insert(slot{hash: e.hash, index: uint32(i + 1)})
+	}
+}
+
+// compact removes the holes left by removals from the entries, keeping their order.
+func (m *Map[K, V]) compact() {
+	live := m.entries[:0]
+	for _, e := range m.entries {
+		if !e.removed {
+			live = append(live, e)
+		}
+	}
+	for i := len(live); i < len(m.entries); i++ {
+		m.entries[i] = entry[K, V]{} // let the keys and values be collected
+	}
+	m.entries = live
+}
+
+// Put inserts element into the map.
+func (m *Map[K, V]) Put(key K, value V) {
+	if m.hash == nil {
+		m.rehash(m.slotsFor(1))
+	}
+	hash := uint32(m.hash(key))
+	if i := m.find(key, hash); i >= 0 {
+		m.entries[m.slots[i].index-1].value = value
+		return
+	}
+	if m.size >= m.maxSize {
+		m.rehash(m.slotsFor(m.size + 1))
+	}
+	m.entries = append(m.entries, entry[K, V]{key: key, value: value, hash: hash})
+	m.insert(slot{hash: hash, index: uint32(len(m.entries))})
+	m.size++
+	m.modCount++
+}
+
+// Get searches the element in the map by key and returns its value or the zero value if key is not found in map.
+// Second return parameter is true if key was found, otherwise false.
+func (m *Map[K, V]) Get(key K) (value V, found bool) {
+	if m.size == 0 {
+		return value, false
+	}
+	if i := m.find(key, uint32(m.hash(key))); i >= 0 {
+		return m.entries[m.slots[i].index-1].value, true
+	}
+	return value, false
+}
+
+// Remove removes the element from the map by key.
+func (m *Map[K, V]) Remove(key K) {
+	if m.size == 0 {
+		return
+	}
+	if i := m.find(key, uint32(m.hash(key))); i >= 0 {
+		m.removeSlot(i)
+	}
+}
+
+// removeSlot removes the entry of the slot at index i and returns the index of the entry
+// that takes its place in iteration order, which is len(m.entries) if there is none.
+func (m *Map[K, V]) removeSlot(i int) int {
+	index := int(m.slots[i].index - 1)
+	m.delete(i)
+	m.size--
+	m.modCount++
+	if m.options.StableOrder {
+		m.entries[index] = entry[K, V]{removed: true}
+		if holes := len(m.entries) - m.size; holes > m.size {
+			// compaction drops the holes before the index as well
+			for _, e := range m.entries[:index] {
+				if e.removed {
+					index--
+				}
+			}
+			m.rehash(len(m.slots))
+		}
+		return index
+	}
+	last := len(m.entries) - 1
+	if index != last {
+		// move the last entry into the hole and point its slot to it
+		m.slots[m.slotOf(last)].index = uint32(index + 1)
+		m.entries[index] = m.entries[last]
+	}
+	m.entries[last] = entry[K, V]{}
+	m.entries = m.entries[:last]
+	return index
+}
+
+// Empty returns true if map does not contain any elements
+func (m *Map[K, V]) Empty() bool {
+	return m.Size() == 0
+}
+
+// Size returns number of elements in the map.
+func (m *Map[K, V]) Size() int {
+	return m.size
+}
+
+// Capacity returns the number of elements the map can hold before its hash table grows.
+func (m *Map[K, V]) Capacity() int {
+	return m.maxSize
+}
+
+// Reserve makes room for at least size elements, so that the map does not grow until it holds more.
+func (m *Map[K, V]) Reserve(size int) {
+	m.modCount++
+	if size > m.maxSize {
+		m.rehash(m.slotsFor(size))
+	}
+	if size > cap(m.entries) {
+		entries := make([]entry[K, V], len(m.entries), size)
+		copy(entries, m.entries)
+		m.entries = entries
+	}
+}
+
+// ShrinkToFit releases the memory the map holds beyond its elements: the hash table shrinks to the smallest size
+// the load factor allows and the array of entries to the number of elements.
+func (m *Map[K, V]) ShrinkToFit() {
+	m.modCount++
+	if m.size == 0 {
+		m.slots, m.entries, m.maxSize = nil, nil, 0
+		return
+	}
+	if len(m.entries) > m.size {
+		m.compact()
+	}
+	if cap(m.entries) > m.size {
+		m.entries = append([]entry[K, V](nil), m.entries...)
+	}
+	if n := m.slotsFor(m.size); n < len(m.slots) {
+		m.rehash(n)
+	}
+}
+
+// Keys returns all keys in iteration order.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.size)
+	for _, e := range m.entries {
+		if !e.removed {
+			keys = append(keys, e.key)
+		}
+	}
+	return keys
+}
+
+// Values returns all values in iteration order.
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, m.size)
+	for _, e := range m.entries {
+		if !e.removed {
+			values = append(values, e.value)
+		}
+	}
+	return values
+}
+
+// InterfaceValues returns all elements in the map as type interface{}.
+func (m *Map[K, V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, 0, m.size)
+	for _, e := range m.entries {
+		if !e.removed {
+			values = append(values, e.value)
+		}
+	}
+	return values
+}
+
+// Clear removes all elements from the map, keeping the memory it holds for new elements (see ShrinkToFit).
+func (m *Map[K, V]) Clear() {
+	for i := range m.slots {
+		m.slots[i] = slot{}
+	}
+	for i := range m.entries {
+		m.entries[i] = entry[K, V]{}
+	}
+	m.entries = m.entries[:0]
+	m.size = 0
+	m.modCount++
+}
+
+// String returns a string representation of container
+func (m *Map[K, V]) String() string {
+	str := "RobinHoodMap\n"
+	entries := make([]string, 0, m.size)
+	for _, e := range m.entries {
+		if !e.removed {
+			entries = append(entries, fmt.Sprintf("%v:%v", e.key, e.value))
+		}
+	}
+	str += "map[" + strings.Join(entries, " ") + "]"
+	return str
+}
@@ -0,0 +1,749 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package robinhoodmap_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
+	"github.com/monitor1379/yagods/maps/robinhoodmap"
+)
+
+func TestMapPut(t *testing.T) {
+	m := robinhoodmap.New[int, string]()
+	m.Put(5, "e")
+	m.Put(6, "f")
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(4, "d")
+	m.Put(1, "x")
+	m.Put(2, "b")
+	m.Put(1, "a") //overwrite
+
+	if actualValue := m.Size(); actualValue != 7 {
+		t.Errorf("Got %v expected %v", actualValue, 7)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[5 6 7 3 4 1 2]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), "[e f g c d a b]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// key,expectedValue,expectedFound
+	tests1 := [][]interface{}{
+		{1, "a", true},
+		{2, "b", true},
+		{3, "c", true},
+		{4, "d", true},
+		{5, "e", true},
+		{6, "f", true},
+		{7, "g", true},
+		{8, "", false},
+	}
+
+	for _, test := range tests1 {
+		// retrievals
+		actualValue, actualFound := m.Get(test[0].(int))
+		if actualValue != test[1] || actualFound != test[2] {
+			t.Errorf("Got %v expected %v", actualValue, test[1])
+		}
+	}
+}
+
+func TestMapRemove(t *testing.T) {
+	m := robinhoodmap.New[int, string]()
+	m.Put(5, "e")
+	m.Put(6, "f")
+	m.Put(7, "g")
+	m.Put(3, "c")
+	m.Put(4, "d")
+	m.Put(1, "x")
+	m.Put(2, "b")
+	m.Put(1, "a") //overwrite
+
+	m.Remove(5)
+	m.Remove(6)
+	m.Remove(7)
+	m.Remove(8)
+	m.Remove(5)
+
+	// the last entries take the places of the removed ones
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[2 1 4 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), "[b a d c]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := m.Size(); actualValue != 4 {
+		t.Errorf("Got %v expected %v", actualValue, 4)
+	}
+
+	m.Remove(1)
+	m.Remove(4)
+	m.Remove(2)
+	m.Remove(3)
+	m.Remove(2)
+	m.Remove(2)
+
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := m.Size(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+	if actualValue := m.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestMapStableOrder(t *testing.T) {
+	m := robinhoodmap.NewWith[int, string](robinhoodmap.Options{StableOrder: true})
+	for i := 0; i < 10; i++ {
+		m.Put(i, strconv.Itoa(i))
+	}
+	m.Remove(0)
+	m.Remove(5)
+	m.Put(5, "x")
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 2 3 4 6 7 8 9 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	// removing most entries compacts the holes they leave
+	for i := 1; i < 9; i++ {
+		m.Remove(i)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found := m.Get(9); value != "9" || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "9", true)
+	}
+	it := m.Iterator()
+	if actualValue := it.Last(); actualValue != true || it.Key() != 9 {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, it.Key(), true, 9)
+	}
+	if actualValue := it.Prev(); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+}
+
+func TestMapDeterministicIteration(t *testing.T) {
+	run := func(options robinhoodmap.Options) string {
+		m := robinhoodmap.NewWith[string, int](options)
+		for i := 0; i < 1000; i++ {
+			m.Put(strconv.Itoa(i*7919%1000), i)
+		}
+		for i := 0; i < 1000; i += 3 {
+			m.Remove(strconv.Itoa(i))
+		}
+		return fmt.Sprint(m.Keys())
+	}
+	for _, stable := range []bool{false, true} {
+		expected := run(robinhoodmap.Options{Seed: 1, StableOrder: stable})
+		if actualValue := run(robinhoodmap.Options{Seed: 1, StableOrder: stable}); actualValue != expected {
+			t.Errorf("Got different orders for the same seed")
+		}
+		if actualValue := run(robinhoodmap.Options{StableOrder: stable}); actualValue != expected {
+			t.Errorf("Got different orders for different seeds")
+		}
+	}
+}
+
+func TestMapCapacity(t *testing.T) {
+	m := robinhoodmap.New[int, int]()
+	if actualValue := m.Capacity(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+	m.Put(1, 1)
+	if actualValue, expectedValue := m.Capacity(), 7; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	m = robinhoodmap.NewWith[int, int](robinhoodmap.Options{LoadFactor: 0.5})
+	m.Reserve(100)
+	if actualValue, expectedValue := m.Capacity(), 128; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for i := 0; i < 100; i++ {
+		m.Put(i, i)
+	}
+	if actualValue, expectedValue := m.Capacity(), 128; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for i := 0; i < 90; i++ {
+		m.Remove(i)
+	}
+	m.ShrinkToFit()
+	if actualValue, expectedValue := m.Capacity(), 16; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for i := 0; i < 100; i++ {
+		if value, found := m.Get(i); found != (i >= 90) || found && value != i {
+			t.Errorf("Got %v,%v for key %v", value, found, i)
+		}
+	}
+	m.Clear()
+	if actualValue, expectedValue := m.Capacity(), 16; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.ShrinkToFit()
+	if actualValue, expectedValue := m.Capacity(), 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.Put(1, 1)
+	if value, found := m.Get(1); value != 1 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 1, true)
+	}
+}
+
+func TestMapInvalidLoadFactor(t *testing.T) {
+	for _, loadFactor := range []float64{-0.5, 1, 2} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Got no panic for load factor %v", loadFactor)
+				}
+			}()
+			robinhoodmap.NewWith[int, int](robinhoodmap.Options{LoadFactor: loadFactor})
+		}()
+	}
+}
+
+func TestMapManyKeys(t *testing.T) {
+	for _, loadFactor := range []float64{0.1, 0.5, 0.99} {
+		m := robinhoodmap.NewWith[string, int](robinhoodmap.Options{LoadFactor: loadFactor})
+		for i := 0; i < 10000; i++ {
+			m.Put(strconv.Itoa(i), i)
+		}
+		for i := 0; i < 10000; i += 2 {
+			m.Remove(strconv.Itoa(i))
+		}
+		if actualValue, expectedValue := m.Size(), 5000; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		for i := 0; i < 10000; i++ {
+			if value, found := m.Get(strconv.Itoa(i)); found != (i%2 == 1) || found && value != i {
+				t.Errorf("Got %v,%v for key %v", value, found, i)
+			}
+		}
+	}
+}
+
+func TestMapOtherKeys(t *testing.T) {
+	type point struct{ x, y float64 }
+	m := robinhoodmap.New[point, string]()
+	m.Put(point{1, 2}, "a")
+	m.Put(point{math.Copysign(0, -1), 0}, "b")
+	if value, found := m.Get(point{0, 0}); value != "b" || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "b", true)
+	}
+
+	// NaN is never found, like in a built-in map, but removing other keys moves it
+	n := robinhoodmap.New[float64, int]()
+	n.Put(math.NaN(), 1)
+	n.Put(1, 2)
+	n.Put(math.NaN(), 3)
+	n.Remove(math.NaN())
+	n.Remove(1)
+	if actualValue, expectedValue := fmt.Sprint(n.Values()), "[1 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	n.Put(2, 4)
+	if value, found := n.Get(2); value != 4 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 4, true)
+	}
+}
+
+func TestMapZeroValue(t *testing.T) {
+	var m robinhoodmap.Map[string, int]
+	if value, found := m.Get("a"); found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 0, false)
+	}
+	m.Remove("a")
+	m.Put("a", 1)
+	if value, found := m.Get("a"); value != 1 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, 1, true)
+	}
+}
+
+func TestMapString(t *testing.T) {
+	m := robinhoodmap.New[string, int]()
+	m.Put("b", 2)
+	m.Put("a", 1)
+	if actualValue, expectedValue := m.String(), "RobinHoodMap\nmap[b:2 a:1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapIteratorNextOnEmpty(t *testing.T) {
+	m := robinhoodmap.New[int, string]()
+	it := m.Iterator()
+	for it.Next() {
+		t.Errorf("Shouldn't iterate on empty map")
+	}
+}
+
+func TestMapIteratorPrevOnEmpty(t *testing.T) {
+	m := robinhoodmap.New[int, string]()
+	it := m.Iterator()
+	for it.Prev() {
+		t.Errorf("Shouldn't iterate on empty map")
+	}
+}
+
+func TestMapIterator(t *testing.T) {
+	m := robinhoodmap.New[string, int]()
+	m.Put("c", 1)
+	m.Put("a", 2)
+	m.Put("b", 3)
+
+	it := m.Iterator()
+	count := 0
+	for it.Next() {
+		count++
+		if actualValue, expectedValue := it.Value(), count; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if actualValue, expectedValue := count, 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for it.Prev() {
+		if actualValue, expectedValue := it.Value(), count; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		count--
+	}
+	if actualValue, expectedValue := count, 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if it.First(); it.Key() != "c" {
+		t.Errorf("Got %v expected %v", it.Key(), "c")
+	}
+	if it.Last(); it.Key() != "b" {
+		t.Errorf("Got %v expected %v", it.Key(), "b")
+	}
+}
+
+func TestMapIteratorRemove(t *testing.T) {
+	for _, options := range []robinhoodmap.Options{{}, {StableOrder: true}} {
+		m := robinhoodmap.NewWith[int, string](options)
+		for i := 1; i <= 6; i++ {
+			m.Put(i, strconv.Itoa(i))
+		}
+		it := m.Iterator()
+		var visited []int
+		for it.Next() {
+			visited = append(visited, it.Key())
+			if it.Key()%2 == 0 {
+				it.Remove()
+			}
+		}
+		if actualValue, expectedValue := len(visited), 6; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := m.Size(), 3; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if _, found := m.Get(2); found {
+			t.Errorf("Got %v expected %v", found, false)
+		}
+		if err := it.Err(); err != nil {
+			t.Errorf("Got %v expected %v", err, nil)
+		}
+	}
+
+	// the predecessor of a removed element survives compaction
+	m := robinhoodmap.NewWith[int, string](robinhoodmap.Options{StableOrder: true})
+	for i := 1; i <= 6; i++ {
+		m.Put(i, strconv.Itoa(i))
+	}
+	m.Remove(1)
+	m.Remove(2)
+	m.Remove(3)
+	it := m.Iterator()
+	it.Next()
+	it.Next()
+	it.Remove()
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[4 6]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := it.Prev(), true; actualValue != expectedValue || it.Key() != 4 {
+		t.Errorf("Got %v expected %v", it.Key(), 4)
+	}
+	if actualValue, expectedValue := it.Next(), true; actualValue != expectedValue || it.Key() != 6 {
+		t.Errorf("Got %v expected %v", it.Key(), 6)
+	}
+}
+
+func TestMapIteratorConcurrentModification(t *testing.T) {
+	m := robinhoodmap.New[int, string]()
+	m.Put(1, "a")
+	m.Put(2, "b")
+	it := m.Iterator()
+	m.Put(3, "c")
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+	it.Next()
+	m.Remove(3)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Next()
+}
+
+func TestMapSerialization(t *testing.T) {
+	m := robinhoodmap.New[string, float64]()
+	m.Put("c", 3.0)
+	m.Put("a", 1.0)
+	m.Put("b", 2.0)
+
+	var err error
+	assert := func() {
+		if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[c a b]"; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(m.Values()), "[3 1 2]"; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+
+	assert()
+
+	json, err := m.ToJSON()
+	assert()
+
+	err = m.FromJSON(json)
+	assert()
+}
+
+func TestMapBinarySerialization(t *testing.T) {
+	m := robinhoodmap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	var restored robinhoodmap.Map[int, string]
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), "[2 1] [b a]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = robinhoodmap.Map[int, string]{}
+	if err := gob.NewDecoder(&buffer).Decode(&restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), "[2 1] [b a]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := robinhoodmap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+
+	data, err := json.Marshal(struct {
+		Map *robinhoodmap.Map[int, string]
+	}{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"2":"b","1":"a"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Map robinhoodmap.Map[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Map.Keys()), fmt.Sprint(m.Keys()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := robinhoodmap.New[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"2":"b","1":"a"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored robinhoodmap.Map[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys()), fmt.Sprint(m.Keys()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestMapConformance(t *testing.T) {
+	for _, options := range []robinhoodmap.Options{{}, {StableOrder: true}, {LoadFactor: 0.99}} {
+		options := options
+		containertest.TestMap(t, func() maps.Map[int, string] { return robinhoodmap.NewWith[int, string](options) })
+		containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+			m := robinhoodmap.NewWith[int, string](options)
+			for _, key := range keys {
+				m.Put(key, strconv.Itoa(key))
+			}
+			it := m.Iterator()
+			return &it
+		})
+	}
+}
+
+func FuzzMap(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] {
+		return robinhoodmap.NewWith[int, string](robinhoodmap.Options{LoadFactor: 0.99})
+	}, containertest.Unordered)
+}
+
+func FuzzMapStableOrder(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] {
+		return robinhoodmap.NewWith[int, string](robinhoodmap.Options{StableOrder: true})
+	}, containertest.InsertionOrder)
+}
+
+// The benchmarks compare the map to the built-in map on integer and string keys.
+
+func benchmarkKeys(size int) []string {
+	keys := make([]string, size)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func benchmarkGetInt(b *testing.B, size int) {
+	m := robinhoodmap.New[int, int]()
+	for n := 0; n < size; n++ {
+		m.Put(n, n)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			m.Get(n)
+		}
+	}
+}
+
+func benchmarkBuiltinGetInt(b *testing.B, size int) {
+	m := make(map[int]int)
+	for n := 0; n < size; n++ {
+		m[n] = n
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			_ = m[n]
+		}
+	}
+}
+
+func benchmarkPutInt(b *testing.B, size int) {
+	for i := 0; i < b.N; i++ {
+		m := robinhoodmap.New[int, int]()
+		for n := 0; n < size; n++ {
+			m.Put(n, n)
+		}
+	}
+}
+
+func benchmarkBuiltinPutInt(b *testing.B, size int) {
+	for i := 0; i < b.N; i++ {
+		m := make(map[int]int)
+		for n := 0; n < size; n++ {
+			m[n] = n
+		}
+	}
+}
+
+func benchmarkRemoveInt(b *testing.B, size int) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		m := robinhoodmap.New[int, int]()
+		for n := 0; n < size; n++ {
+			m.Put(n, n)
+		}
+		b.StartTimer()
+		for n := 0; n < size; n++ {
+			m.Remove(n)
+		}
+	}
+}
+
+func benchmarkBuiltinRemoveInt(b *testing.B, size int) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		m := make(map[int]int)
+		for n := 0; n < size; n++ {
+			m[n] = n
+		}
+		b.StartTimer()
+		for n := 0; n < size; n++ {
+			delete(m, n)
+		}
+	}
+}
+
+func benchmarkGetString(b *testing.B, size int) {
+	keys := benchmarkKeys(size)
+	m := robinhoodmap.New[string, int]()
+	for n, key := range keys {
+		m.Put(key, n)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, key := range keys {
+			m.Get(key)
+		}
+	}
+}
+
+func benchmarkBuiltinGetString(b *testing.B, size int) {
+	keys := benchmarkKeys(size)
+	m := make(map[string]int)
+	for n, key := range keys {
+		m[key] = n
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, key := range keys {
+			_ = m[key]
+		}
+	}
+}
+
+func benchmarkPutString(b *testing.B, size int) {
+	keys := benchmarkKeys(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := robinhoodmap.New[string, int]()
+		for n, key := range keys {
+			m.Put(key, n)
+		}
+	}
+}
+
+func benchmarkBuiltinPutString(b *testing.B, size int) {
+	keys := benchmarkKeys(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := make(map[string]int)
+		for n, key := range keys {
+			m[key] = n
+		}
+	}
+}
+
+func BenchmarkRobinHoodMapGetInt1000(b *testing.B) {
+	benchmarkGetInt(b, 1000)
+}
+
+func BenchmarkBuiltinMapGetInt1000(b *testing.B) {
+	benchmarkBuiltinGetInt(b, 1000)
+}
+
+func BenchmarkRobinHoodMapGetInt100000(b *testing.B) {
+	benchmarkGetInt(b, 100000)
+}
+
+func BenchmarkBuiltinMapGetInt100000(b *testing.B) {
+	benchmarkBuiltinGetInt(b, 100000)
+}
+
+func BenchmarkRobinHoodMapPutInt1000(b *testing.B) {
+	benchmarkPutInt(b, 1000)
+}
+
+func BenchmarkBuiltinMapPutInt1000(b *testing.B) {
+	benchmarkBuiltinPutInt(b, 1000)
+}
+
+func BenchmarkRobinHoodMapPutInt100000(b *testing.B) {
+	benchmarkPutInt(b, 100000)
+}
+
+func BenchmarkBuiltinMapPutInt100000(b *testing.B) {
+	benchmarkBuiltinPutInt(b, 100000)
+}
+
+func BenchmarkRobinHoodMapRemoveInt100000(b *testing.B) {
+	benchmarkRemoveInt(b, 100000)
+}
+
+func BenchmarkBuiltinMapRemoveInt100000(b *testing.B) {
+	benchmarkBuiltinRemoveInt(b, 100000)
+}
+
+func BenchmarkRobinHoodMapGetString1000(b *testing.B) {
+	benchmarkGetString(b, 1000)
+}
+
+func BenchmarkBuiltinMapGetString1000(b *testing.B) {
+	benchmarkBuiltinGetString(b, 1000)
+}
+
+func BenchmarkRobinHoodMapGetString100000(b *testing.B) {
+	benchmarkGetString(b, 100000)
+}
+
+func BenchmarkBuiltinMapGetString100000(b *testing.B) {
+	benchmarkBuiltinGetString(b, 100000)
+}
+
+func BenchmarkRobinHoodMapPutString100000(b *testing.B) {
+	benchmarkPutString(b, 100000)
+}
+
+func BenchmarkBuiltinMapPutString100000(b *testing.B) {
+	benchmarkBuiltinPutString(b, 100000)
+}
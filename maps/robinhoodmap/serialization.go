@@ -0,0 +1,101 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package robinhoodmap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+var _ containers.JSONSerializer = (*Map[int, string])(nil)
+var _ containers.JSONDeserializer = (*Map[int, string])(nil)
+var _ encoding.BinaryMarshaler = (*Map[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[int, string])(nil)
+var _ gob.GobEncoder = (*Map[int, string])(nil)
+var _ gob.GobDecoder = (*Map[int, string])(nil)
+var _ json.Marshaler = (*Map[int, string])(nil)
+var _ json.Unmarshaler = (*Map[int, string])(nil)
+var _ containers.JSONWriter = (*Map[int, string])(nil)
+var _ containers.JSONReader = (*Map[int, string])(nil)
+
+// ToJSON outputs the JSON representation of the map, see MarshalJSON.
+func (m *Map[K, V]) ToJSON() ([]byte, error) {
+	return m.MarshalJSON()
+}
+
+// FromJSON populates the map from the input JSON representation, see UnmarshalJSON.
+func (m *Map[K, V]) FromJSON(data []byte) error {
+	return m.UnmarshalJSON(data)
+}
+
+// MarshalBinary outputs the binary representation of the map in iteration order.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), m.Keys(), m.Values())
+}
+
+// UnmarshalBinary populates the map from the input binary representation, putting the entries in the order of the input.
+// The options of the map are kept, a map that was not instantiated by a constructor getting the default options.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		m.Clear()
+		m.Reserve(len(keys))
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object with its entries in iteration order.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := m.WriteJSON(&buffer)
+	return buffer.Bytes(), err
+}
+
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, putting the entries in the order of the input.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	keys, values, err := containers.UnmarshalJSONEntries[K, V](data)
+	if err == nil {
+		m.Clear()
+		m.Reserve(len(keys))
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// WriteJSON writes the JSON representation of the map to the writer in iteration order, encoding one entry at a time.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	it := m.Iterator()
+	return containers.WriteJSONEntries[K, V](w, &it)
+}
+
+// ReadJSON populates the map from the JSON representation read from the reader in the order of the input,
+// putting every entry as it is decoded. On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	m.Clear()
+	return containers.ReadJSONEntries(r, func(key K, value V) {
+		m.Put(key, value)
+	})
+}
@@ -11,15 +11,17 @@ import (
 
 var _ containers.IteratorWithKey[int, string] = (*Iterator[int, string])(nil)
 var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
 
 // Iterator holding the iterator's state
 type Iterator[K comparable, V comparable] struct {
 	iterator rbt.Iterator[K, *data[K, V]]
+	m        *Map[K, V]
 }
 
 // Iterator returns a stateful iterator whose elements are key/value pairs.
 func (m *Map[K, V]) Iterator() Iterator[K, V] {
-	return Iterator[K, V]{iterator: m.forwardMap.Iterator()}
+	return Iterator[K, V]{iterator: m.forwardMap.Iterator(), m: m}
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -74,3 +76,25 @@ func (iterator *Iterator[K, V]) First() bool {
 func (iterator *Iterator[K, V]) Last() bool {
 	return iterator.iterator.Last()
 }
+
+// Err returns containers.ErrConcurrentModification if the map was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	return iterator.iterator.Err()
+}
+
+// Remove removes the current element from the map and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[K, V]) Remove() {
+	var d *data[K, V]
+	size := iterator.m.Size()
+	if iterator.iterator.Err() == nil && size > 0 {
+		d = iterator.iterator.Value()
+	}
+	iterator.iterator.Remove()
+	if iterator.m.Size() < size {
+		iterator.m.inverseMap.Remove(d.value)
+	}
+}
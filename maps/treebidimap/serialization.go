@@ -5,7 +5,11 @@
 package treebidimap
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
 	"encoding/json"
+	"io"
 
 	"github.com/monitor1379/yagods/containers"
 	"github.com/monitor1379/yagods/utils"
@@ -13,6 +17,14 @@ import (
 
 var _ containers.JSONSerializer = (*Map[int, string])(nil)
 var _ containers.JSONDeserializer = (*Map[int, string])(nil)
+var _ encoding.BinaryMarshaler = (*Map[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[int, string])(nil)
+var _ gob.GobEncoder = (*Map[int, string])(nil)
+var _ gob.GobDecoder = (*Map[int, string])(nil)
+var _ json.Marshaler = (*Map[int, string])(nil)
+var _ json.Unmarshaler = (*Map[int, string])(nil)
+var _ containers.JSONWriter = (*Map[int, string])(nil)
+var _ containers.JSONReader = (*Map[int, string])(nil)
 
 // ToJSON outputs the JSON representation of the map.
 func (m *Map[K, V]) ToJSON() ([]byte, error) {
@@ -36,3 +48,98 @@ func (m *Map[K, V]) FromJSON(data []byte) error {
 	}
 	return err
 }
+
+// MarshalBinary outputs the binary representation of the map in key order.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	keys, values := make([]K, 0, m.Size()), make([]V, 0, m.Size())
+	it := m.Iterator()
+	for it.Next() {
+		keys, values = append(keys, it.Key()), append(values, it.Value())
+	}
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), keys, values)
+}
+
+// UnmarshalBinary populates the map from the input binary representation.
+// The zero value of a map is ordered by default comparators, see utils.DefaultComparator;
+// returns containers.ErrNoComparator if there are none.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object with its entries in key order.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := m.WriteJSON(&buffer)
+	return buffer.Bytes(), err
+}
+
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, see MarshalJSON.
+// Like UnmarshalBinary, it accepts the zero value of a map.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	keys, values, err := containers.UnmarshalJSONEntries[K, V](data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// initZero sets the comparators of a map that was not instantiated by a constructor
+// to the default comparators of its keys and values.
+func (m *Map[K, V]) initZero() error {
+	if m.keyComparator == nil {
+		m.keyComparator = utils.DefaultComparator[K]()
+	}
+	if m.valueComparator == nil {
+		m.valueComparator = utils.DefaultComparator[V]()
+	}
+	if m.keyComparator == nil || m.valueComparator == nil {
+		return containers.ErrNoComparator
+	}
+	m.forwardMap.Comparator, m.inverseMap.Comparator = m.keyComparator, m.valueComparator
+	return nil
+}
+
+// WriteJSON writes the JSON representation of the map to the writer in key order, encoding one entry at a time.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	it := m.Iterator()
+	return containers.WriteJSONEntries[K, V](w, &it)
+}
+
+// ReadJSON populates the map from the JSON representation read from the reader, putting every entry as it is decoded.
+// Like UnmarshalJSON, it accepts the zero value of a map. On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	m.Clear()
+	return containers.ReadJSONEntries(r, func(key K, value V) {
+		m.Put(key, value)
+	})
+}
@@ -41,6 +41,17 @@ type data[K comparable, V comparable] struct {
 	value V
 }
 
+// New instantiates a bidirectional map ordering the keys and the values by the < and > operators,
+// see utils.OrderedComparator. Looking keys and values up compares them with the operators instead of calling the comparators.
+func New[K utils.Ordered, V utils.Ordered]() *Map[K, V] {
+	return &Map[K, V]{
+		forwardMap:      *redblacktree.New[K, *data[K, V]](),
+		inverseMap:      *redblacktree.New[V, *data[K, V]](),
+		keyComparator:   utils.OrderedComparator[K],
+		valueComparator: utils.OrderedComparator[V],
+	}
+}
+
 // NewWith instantiates a bidirectional map.
 func NewWith[K comparable, V comparable](keyComparator utils.Comparator[K], valueComparator utils.Comparator[V]) *Map[K, V] {
 	return &Map[K, V]{
@@ -5,9 +5,18 @@
 package treebidimap_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
 	"github.com/monitor1379/yagods/maps/treebidimap"
 	"github.com/monitor1379/yagods/utils"
 )
@@ -475,6 +484,33 @@ func TestMapIteratorLast(t *testing.T) {
 	}
 }
 
+func TestMapIteratorRemove(t *testing.T) {
+	m := treebidimap.NewWith(utils.NumberComparator[int], utils.StringComparator)
+	for i := 1; i <= 6; i++ {
+		m.Put(i, fmt.Sprint(i))
+	}
+	it := m.Iterator()
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, found := m.Get(2); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if _, found := m.GetKey("2"); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	it.Last()
+	m.Put(7, "7")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestMapSerialization(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		original := treebidimap.NewWith(utils.StringComparator, utils.StringComparator)
@@ -501,6 +537,154 @@ func TestMapSerialization(t *testing.T) {
 	}
 }
 
+func TestMapBinarySerialization(t *testing.T) {
+	m := treebidimap.NewWith[int, string](utils.NumberComparator[int], utils.StringComparator)
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := treebidimap.NewWith[int, string](utils.NumberComparator[int], utils.StringComparator)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = treebidimap.NewWith[int, string](utils.NumberComparator[int], utils.StringComparator)
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	zero := &treebidimap.Map[int, string]{}
+	if err := zero.UnmarshalBinary(data); err != nil || zero.Size() != restored.Size() {
+		t.Errorf("Got %v, %v expected %v", zero.Size(), err, restored.Size())
+	}
+	if err := (&treebidimap.Map[[2]int, string]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := treebidimap.NewWith[int, string](utils.NumberComparator[int], utils.StringComparator)
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(10, "c")
+
+	data, err := json.Marshal(struct{ Map *treebidimap.Map[int, string] }{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"1":"a","2":"b","10":"c"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Map treebidimap.Map[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Map.Keys(), restored.Map.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&treebidimap.Map[[2]int, string]{}).UnmarshalJSON([]byte("{}")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := treebidimap.NewWith[int, string](utils.NumberComparator[int], utils.StringComparator)
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(10, "c")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":"a","2":"b","10":"c"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored treebidimap.Map[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestMapNew(t *testing.T) {
+	m := treebidimap.New[int64, float64]()
+	m.Put(3, 0.5)
+	m.Put(1, 1.5)
+	m.Put(2, -1)
+	m.Put(4, 0.5)
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 2 4]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), "[-1 0.5 1.5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, found := m.GetKey(0.5); key != 4 || !found {
+		t.Errorf("Got %v expected %v", key, 4)
+	}
+	selected := m.Select(func(key int64, value float64) bool { return value > 0 })
+	selected.Put(0, 0)
+	if actualValue, expectedValue := fmt.Sprint(selected.Values()), "[0 0.5 1.5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := m.Size(), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return treebidimap.New[int, string]() })
+}
+
+func TestMapConformance(t *testing.T) {
+	containertest.TestSortedMap(t, func() maps.Map[int, string] {
+		return treebidimap.NewWith[int, string](utils.NumberComparator[int], utils.StringComparator)
+	})
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		m := treebidimap.NewWith[int, string](utils.NumberComparator[int], utils.StringComparator)
+		for _, key := range keys {
+			m.Put(key, strconv.Itoa(key))
+		}
+		it := m.Iterator()
+		return &it
+	})
+}
+
+func FuzzMap(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] {
+		return treebidimap.NewWith[int, string](utils.NumberComparator[int], utils.StringComparator)
+	}, containertest.SortedOrder)
+}
+
+func FuzzMapNew(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return treebidimap.New[int, string]() }, containertest.SortedOrder)
+}
+
 //noinspection GoBoolExpressions
 func assertSerialization(m *treebidimap.Map[string, string], txt string, t *testing.T) {
 	if actualValue := m.Keys(); false ||
@@ -4,10 +4,7 @@
 
 package treemap
 
-import (
-	"github.com/monitor1379/yagods/containers"
-	rbt "github.com/monitor1379/yagods/trees/redblacktree"
-)
+import "github.com/monitor1379/yagods/containers"
 
 var _ containers.EnumerableWithKey[*Map[int, string], int, string] = (*Map[int, string])(nil)
 
@@ -22,7 +19,7 @@ func (m *Map[K, V]) Each(f func(key K, value V)) {
 // Map invokes the given function once for each element and returns a container
 // containing the values returned by the given function as key/value pairs.
 func (m *Map[K, V]) Map(f func(key1 K, value1 V) (K, V)) *Map[K, V] {
-	newMap := &Map[K, V]{tree: rbt.NewWith[K, V](m.tree.Comparator)}
+	newMap := m.empty()
 	iterator := m.Iterator()
 	for iterator.Next() {
 		key2, value2 := f(iterator.Key(), iterator.Value())
@@ -33,7 +30,7 @@ func (m *Map[K, V]) Map(f func(key1 K, value1 V) (K, V)) *Map[K, V] {
 
 // Select returns a new container containing all elements for which the given function returns a true value.
 func (m *Map[K, V]) Select(f func(key K, value V) bool) *Map[K, V] {
-	newMap := &Map[K, V]{tree: rbt.NewWith[K, V](m.tree.Comparator)}
+	newMap := m.empty()
 	iterator := m.Iterator()
 	for iterator.Next() {
 		if f(iterator.Key(), iterator.Value()) {
@@ -83,3 +80,10 @@ func (m *Map[K, V]) Find(f func(key K, value V) bool) (K, V, bool) {
 	)
 	return zeroK, zeroV, false
 }
+
+// empty returns a new empty map ordering the keys as the map does, also when instantiated by New.
+func (m *Map[K, V]) empty() *Map[K, V] {
+	tree := *m.tree
+	tree.Clear()
+	return &Map[K, V]{tree: &tree}
+}
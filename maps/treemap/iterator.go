@@ -11,6 +11,7 @@ import (
 
 var _ containers.IteratorWithKey[int, string] = (*Iterator[int, string])(nil)
 var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
 
 // Iterator holding the iterator's state
 type Iterator[K comparable, V any] struct {
@@ -22,6 +23,14 @@ func (m *Map[K, V]) Iterator() Iterator[K, V] {
 	return Iterator[K, V]{iterator: m.tree.Iterator()}
 }
 
+// IteratorAt returns a stateful iterator whose elements are key/value pairs that is initialised
+// at the first element whose key is greater than or equal to the given key (see Seek()).
+func (m *Map[K, V]) IteratorAt(key K) Iterator[K, V] {
+	iterator := m.Iterator()
+	iterator.Seek(key)
+	return iterator
+}
+
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
 // If Next() returns true, then next element's key and value can be retrieved by Key() and Value().
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
@@ -74,3 +83,37 @@ func (iterator *Iterator[K, V]) First() bool {
 func (iterator *Iterator[K, V]) Last() bool {
 	return iterator.iterator.Last()
 }
+
+// Seek moves the iterator to the first element whose key is greater than or equal to the given key
+// and returns true if there was such an element in the map.
+// If Seek() returns true, then element's key and value can be retrieved by Key() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	return iterator.iterator.Seek(key)
+}
+
+// SeekPrev moves the iterator to the last element whose key is less than or equal to the given key
+// and returns true if there was such an element in the map.
+// If SeekPrev() returns true, then element's key and value can be retrieved by Key() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) SeekPrev(key K) bool {
+	return iterator.iterator.SeekPrev(key)
+}
+
+// Err returns containers.ErrConcurrentModification if the map was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	return iterator.iterator.Err()
+}
+
+// Remove removes the current element from the map and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[K, V]) Remove() {
+	iterator.iterator.Remove()
+}
@@ -4,10 +4,27 @@
 
 package treemap
 
-import "github.com/monitor1379/yagods/containers"
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+	rbt "github.com/monitor1379/yagods/trees/redblacktree"
+	"github.com/monitor1379/yagods/utils"
+)
 
 var _ containers.JSONSerializer = (*Map[int, string])(nil)
 var _ containers.JSONDeserializer = (*Map[int, string])(nil)
+var _ encoding.BinaryMarshaler = (*Map[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[int, string])(nil)
+var _ gob.GobEncoder = (*Map[int, string])(nil)
+var _ gob.GobDecoder = (*Map[int, string])(nil)
+var _ json.Marshaler = (*Map[int, string])(nil)
+var _ json.Unmarshaler = (*Map[int, string])(nil)
+var _ containers.JSONWriter = (*Map[int, string])(nil)
+var _ containers.JSONReader = (*Map[int, string])(nil)
 
 // ToJSON outputs the JSON representation of the map.
 func (m *Map[K, V]) ToJSON() ([]byte, error) {
@@ -18,3 +35,76 @@ func (m *Map[K, V]) ToJSON() ([]byte, error) {
 func (m *Map[K, V]) FromJSON(data []byte) error {
 	return m.tree.FromJSON(data)
 }
+
+// MarshalBinary outputs the binary representation of the map in key order.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	return m.tree.MarshalBinary()
+}
+
+// UnmarshalBinary populates the map from the input binary representation.
+// The zero value of a map is ordered by default comparators, see utils.DefaultComparator;
+// returns containers.ErrNoComparator if there are none.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	return m.tree.UnmarshalBinary(data)
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object with its entries in key order.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	if m.tree == nil {
+		return []byte("{}"), nil
+	}
+	return m.tree.MarshalJSON()
+}
+
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, see MarshalJSON.
+// Like UnmarshalBinary, it accepts the zero value of a map.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	return m.tree.UnmarshalJSON(data)
+}
+
+// initZero creates the tree of a map that was not instantiated by a constructor,
+// ordered by the default comparator of its keys.
+func (m *Map[K, V]) initZero() error {
+	if m.tree == nil {
+		comparator := utils.DefaultComparator[K]()
+		if comparator == nil {
+			return containers.ErrNoComparator
+		}
+		m.tree = rbt.NewWith[K, V](comparator)
+	}
+	return nil
+}
+
+// WriteJSON writes the JSON representation of the map to the writer in key order, encoding one entry at a time.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	if m.tree == nil {
+		_, err := io.WriteString(w, "{}")
+		return err
+	}
+	return m.tree.WriteJSON(w)
+}
+
+// ReadJSON populates the map from the JSON representation read from the reader, putting every entry as it is decoded.
+// Like UnmarshalJSON, it accepts the zero value of a map. On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	return m.tree.ReadJSON(r)
+}
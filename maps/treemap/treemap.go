@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/monitor1379/yagods/containers"
 	"github.com/monitor1379/yagods/maps"
 	rbt "github.com/monitor1379/yagods/trees/redblacktree"
 	"github.com/monitor1379/yagods/utils"
@@ -27,6 +28,12 @@ type Map[K comparable, V any] struct {
 	tree *rbt.Tree[K, V]
 }
 
+// New instantiates a tree map ordering the keys by the < and > operators, see utils.OrderedComparator.
+// Looking keys up compares them with the operators instead of calling the comparator.
+func New[K utils.Ordered, V any]() *Map[K, V] {
+	return &Map[K, V]{tree: rbt.New[K, V]()}
+}
+
 // NewWith instantiates a tree map with the custom comparator.
 func NewWith[K comparable, V any](comparator utils.Comparator[K]) *Map[K, V] {
 	return &Map[K, V]{tree: rbt.NewWith[K, V](comparator)}
@@ -42,6 +49,27 @@ func NewWithStringComparator[V any]() *Map[string, V] {
 	return &Map[string, V]{tree: rbt.NewWithStringComparator[V]()}
 }
 
+// FromSorted instantiates a tree map with the custom comparator holding the keys and their values in O(n) time.
+// Returns an error wrapping containers.ErrNotSorted if keys are not in strictly ascending order,
+// or an error if there are not as many values as keys.
+func FromSorted[K comparable, V any](comparator utils.Comparator[K], keys []K, values []V) (*Map[K, V], error) {
+	tree, err := rbt.FromSorted(comparator, keys, values)
+	if err != nil {
+		return nil, err
+	}
+	return &Map[K, V]{tree: tree}, nil
+}
+
+// BulkLoad instantiates a tree map with the custom comparator holding the remaining elements
+// of the iterator in O(n) time. See FromSorted.
+func BulkLoad[K comparable, V any](comparator utils.Comparator[K], iterator containers.IteratorWithKey[K, V]) (*Map[K, V], error) {
+	tree, err := rbt.BulkLoad(comparator, iterator)
+	if err != nil {
+		return nil, err
+	}
+	return &Map[K, V]{tree: tree}, nil
+}
+
 // Put inserts key-value pair into the map.
 // Key should adhere to the comparator's type assertion, otherwise method panics.
 func (m *Map[K, V]) Put(key K, value V) {
@@ -163,6 +191,28 @@ func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
 	return zeroK, zeroV, false
 }
 
+// RemoveRange removes all elements whose keys are within [from, to) from the map.
+// Runs in O(log n + k) time, k being the number of removed elements.
+// Keys should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) RemoveRange(from K, to K) {
+	m.tree.RemoveRange(from, to)
+}
+
+// Split moves all elements whose keys are less than the given key into the left map and
+// all other elements into the right map. The map itself is left empty.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Split(key K) (left *Map[K, V], right *Map[K, V]) {
+	leftTree, rightTree := m.tree.Split(key)
+	return &Map[K, V]{tree: leftTree}, &Map[K, V]{tree: rightTree}
+}
+
+// Join moves all elements of the left and the right map into a new map using left's comparator
+// and leaves both maps empty. All keys of the left map should be less than all keys of the right map,
+// otherwise method panics.
+func Join[K comparable, V any](left *Map[K, V], right *Map[K, V]) *Map[K, V] {
+	return &Map[K, V]{tree: rbt.Join(left.tree, right.tree)}
+}
+
 // String returns a string representation of container
 func (m *Map[K, V]) String() string {
 	str := "TreeMap\nmap["
@@ -5,10 +5,21 @@
 package treemap_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
 	"github.com/monitor1379/yagods/maps/treemap"
+	"github.com/monitor1379/yagods/utils"
 )
 
 func TestMapPut(t *testing.T) {
@@ -496,6 +507,94 @@ func TestMapIteratorLast(t *testing.T) {
 	}
 }
 
+func TestMapIteratorRemove(t *testing.T) {
+	m := treemap.NewWithIntComparator[string]()
+	for i := 1; i <= 6; i++ {
+		m.Put(i, fmt.Sprint(i))
+	}
+	it := m.Iterator()
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, found := m.Get(2); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	it.Last()
+	m.Put(7, "7")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapIteratorSeek(t *testing.T) {
+	m := treemap.NewWithStringComparator[int]()
+	m.Put("b", 1)
+	m.Put("d", 2)
+	m.Put("f", 3)
+	it := m.IteratorAt("c")
+	var keys []string
+	for ok := true; ok; ok = it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if actualValue, expectedValue := fmt.Sprint(keys), "[d f]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := it.SeekPrev("e"); actualValue != true || it.Key() != "d" {
+		t.Errorf("Got %v expected %v", it.Key(), "d")
+	}
+	if actualValue := it.SeekPrev("a"); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue := it.Seek("g"); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+}
+
+func TestMapRemoveRangeSplitJoin(t *testing.T) {
+	m := treemap.NewWithStringComparator[int]()
+	for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		m.Put(key, i)
+	}
+	m.RemoveRange("b", "d")
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[a d e f]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	left, right := m.Split("e")
+	if actualValue, expectedValue := fmt.Sprint(left.Keys(), right.Keys(), m.Size()), "[a d] [e f] 0"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m = treemap.Join(left, right)
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[a d e f] [0 3 4 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapFromSorted(t *testing.T) {
+	m, err := treemap.FromSorted(utils.StringComparator, []string{"a", "b", "c"}, []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[a b c] [1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it := m.Iterator()
+	copied, err := treemap.BulkLoad[string, int](utils.StringComparator, &it)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(copied.Keys(), copied.Values()), "[a b c] [1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, err := treemap.FromSorted(utils.StringComparator, []string{"b", "a"}, []int{1, 2}); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+}
+
 func TestMapSerialization(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		original := treemap.NewWithStringComparator[string]()
@@ -522,6 +621,193 @@ func TestMapSerialization(t *testing.T) {
 	}
 }
 
+func TestMapBinarySerialization(t *testing.T) {
+	m := treemap.NewWithIntComparator[string]()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(2, "b")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := treemap.NewWithIntComparator[string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = treemap.NewWithIntComparator[string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	zero := &treemap.Map[int, string]{}
+	if err := zero.UnmarshalBinary(data); err != nil || zero.Size() != restored.Size() {
+		t.Errorf("Got %v, %v expected %v", zero.Size(), err, restored.Size())
+	}
+	if err := (&treemap.Map[[2]int, string]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := treemap.NewWithIntComparator[string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(10, "c")
+
+	data, err := json.Marshal(struct{ Map *treemap.Map[int, string] }{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"1":"a","2":"b","10":"c"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Map treemap.Map[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Map.Keys(), restored.Map.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&treemap.Map[[2]int, string]{}).UnmarshalJSON([]byte("{}")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := treemap.NewWithIntComparator[string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(10, "c")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":"a","2":"b","10":"c"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored treemap.Map[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(m.Keys(), m.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestMapJSONMarshalerTextKeys(t *testing.T) {
+	day := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	m := treemap.NewWith[time.Time, int](utils.TimeComparator)
+	m.Put(day.AddDate(0, 0, 1), 2)
+	m.Put(day, 1)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"2022-03-01T00:00:00Z":1,"2022-03-02T00:00:00Z":2}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored treemap.Map[time.Time, int]
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, found := restored.Get(day); actualValue != 1 || !found {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	if actualValue, expectedValue := restored.Size(), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapJSONMarshalerFloatKeys(t *testing.T) {
+	m := treemap.New[float64, int]()
+	m.Put(0.5, 2)
+	m.Put(-1.25, 1)
+	m.Put(1e100, 3)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"-1.25":1,"0.5":2,"1e+100":3}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored treemap.Map[float64, int]
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), "[-1.25 0.5 1e+100] [1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapNew(t *testing.T) {
+	m := treemap.New[time.Duration, string]()
+	m.Put(time.Hour, "hour")
+	m.Put(time.Second, "second")
+	m.Put(time.Minute, "minute")
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1s 1m0s 1h0m0s]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, value, found := m.Floor(30 * time.Minute); key != time.Minute || value != "minute" || !found {
+		t.Errorf("Got %v %v %v expected %v %v %v", key, value, found, time.Minute, "minute", true)
+	}
+	selected := m.Select(func(key time.Duration, value string) bool { return key >= time.Minute })
+	selected.Put(time.Millisecond, "millisecond")
+	if actualValue, expectedValue := fmt.Sprint(selected.Keys()), "[1ms 1m0s 1h0m0s]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := m.Size(), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return treemap.New[int, string]() })
+}
+
+func TestMapConformance(t *testing.T) {
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return treemap.NewWithIntComparator[string]() })
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		m := treemap.NewWithIntComparator[string]()
+		for _, key := range keys {
+			m.Put(key, strconv.Itoa(key))
+		}
+		it := m.Iterator()
+		return &it
+	})
+}
+
+func FuzzMap(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return treemap.NewWithIntComparator[string]() }, containertest.SortedOrder)
+}
+
+func FuzzMapNew(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return treemap.New[int, string]() }, containertest.SortedOrder)
+}
+
 //noinspection GoBoolExpressions
 func assertSerialization(m *treemap.Map[string, string], txt string, t *testing.T) {
 	if actualValue := m.Keys(); false ||
@@ -697,3 +983,47 @@ func BenchmarkTreeMapRemove100000(b *testing.B) {
 	b.StartTimer()
 	benchmarkRemove(b, m, size)
 }
+
+func benchmarkSerializationMap(size int) *treemap.Map[int, string] {
+	m := treemap.NewWithIntComparator[string]()
+	for n := 0; n < size; n++ {
+		m.Put(n, fmt.Sprint(n))
+	}
+	return m
+}
+
+func BenchmarkTreeMapToJSON10000(b *testing.B) {
+	m := benchmarkSerializationMap(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := m.ToJSON()
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkTreeMapMarshalBinary10000(b *testing.B) {
+	m := benchmarkSerializationMap(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := m.MarshalBinary()
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkTreeMapFromJSON10000(b *testing.B) {
+	data, _ := benchmarkSerializationMap(10000).ToJSON()
+	m := treemap.NewWithIntComparator[string]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.FromJSON(data)
+	}
+}
+
+func BenchmarkTreeMapUnmarshalBinary10000(b *testing.B) {
+	data, _ := benchmarkSerializationMap(10000).MarshalBinary()
+	m := treemap.NewWithIntComparator[string]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.UnmarshalBinary(data)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package treemultimap
+
+import "github.com/monitor1379/yagods/containers"
+
+var _ containers.EnumerableWithKey[*Map[int, string], int, string] = (*Map[int, string])(nil)
+
+// Each calls the given function once for each element, passing that element's key and value.
+func (m *Map[K, V]) Each(f func(key K, value V)) {
+	iterator := m.Iterator()
+	for iterator.Next() {
+		f(iterator.Key(), iterator.Value())
+	}
+}
+
+// Map invokes the given function once for each element and returns a container
+// containing the values returned by the given function as key/value pairs.
+func (m *Map[K, V]) Map(f func(key1 K, value1 V) (K, V)) *Map[K, V] {
+	newMap := m.empty()
+	iterator := m.Iterator()
+	for iterator.Next() {
+		key2, value2 := f(iterator.Key(), iterator.Value())
+		newMap.Put(key2, value2)
+	}
+	return newMap
+}
+
+// Select returns a new container containing all elements for which the given function returns a true value.
+func (m *Map[K, V]) Select(f func(key K, value V) bool) *Map[K, V] {
+	newMap := m.empty()
+	iterator := m.Iterator()
+	for iterator.Next() {
+		if f(iterator.Key(), iterator.Value()) {
+			newMap.Put(iterator.Key(), iterator.Value())
+		}
+	}
+	return newMap
+}
+
+// Any passes each element of the container to the given function and
+// returns true if the function ever returns true for any element.
+func (m *Map[K, V]) Any(f func(key K, value V) bool) bool {
+	iterator := m.Iterator()
+	for iterator.Next() {
+		if f(iterator.Key(), iterator.Value()) {
+			return true
+		}
+	}
+	return false
+}
+
+// All passes each element of the container to the given function and
+// returns true if the function returns true for all elements.
+func (m *Map[K, V]) All(f func(key K, value V) bool) bool {
+	iterator := m.Iterator()
+	for iterator.Next() {
+		if !f(iterator.Key(), iterator.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find passes each element of the container to the given function and returns
+// the first (key,value) for which the function is true or nil,nil otherwise if no element
+// matches the criteria.
+func (m *Map[K, V]) Find(f func(key K, value V) bool) (K, V, bool) {
+	iterator := m.Iterator()
+	for iterator.Next() {
+		if f(iterator.Key(), iterator.Value()) {
+			return iterator.Key(), iterator.Value(), true
+		}
+	}
+	var (
+		zeroK K
+		zeroV V
+	)
+	return zeroK, zeroV, false
+}
+
+// empty returns a new empty map ordering the keys as the map does, also when instantiated by New.
+func (m *Map[K, V]) empty() *Map[K, V] {
+	tree := *m.tree
+	tree.Clear()
+	return &Map[K, V]{tree: &tree}
+}
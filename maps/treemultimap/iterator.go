@@ -0,0 +1,198 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package treemultimap
+
+import (
+	"github.com/monitor1379/yagods/containers"
+	rbt "github.com/monitor1379/yagods/trees/redblacktree"
+)
+
+var _ containers.IteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
+
+// Iterator holding the iterator's state
+type Iterator[K comparable, V any] struct {
+	m        *Map[K, V]
+	iterator rbt.Iterator[K, []V]
+	between  bool // iterator points to a node of the tree
+	end      bool // iterator is past the last element
+	position int  // position of the current value among the values of the node
+	modCount int  // map's modification count the iterator is synchronized with
+	removed  bool // current value was removed, position points to its predecessor in the node if between
+}
+
+// Iterator returns a stateful iterator whose elements are key/value pairs, one for each value of a key.
+func (m *Map[K, V]) Iterator() Iterator[K, V] {
+	return Iterator[K, V]{m: m, iterator: m.tree.Iterator(), modCount: m.modCount}
+}
+
+// IteratorAt returns a stateful iterator whose elements are key/value pairs that is initialised
+// at the first value of the first key greater than or equal to the given key (see Seek()).
+func (m *Map[K, V]) IteratorAt(key K) Iterator[K, V] {
+	iterator := m.Iterator()
+	iterator.Seek(key)
+	return iterator
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the container.
+// If Next() returns true, then next element's key and value can be retrieved by Key() and Value().
+// If Next() was called for the first time, then it will point the iterator to the first element if it exists.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
+	if iterator.between && iterator.position+1 < len(iterator.iterator.Value()) {
+		iterator.position++
+		return true
+	}
+	iterator.between = iterator.iterator.Next()
+	iterator.end = !iterator.between
+	iterator.position = 0
+	return iterator.between
+}
+
+// Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
+// If Prev() returns true, then previous element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Prev() bool {
+	iterator.checkModification()
+	removed := iterator.removed
+	iterator.removed = false
+	iterator.end = false
+	if iterator.between {
+		if removed && iterator.position >= 0 {
+			return true
+		}
+		if !removed && iterator.position > 0 {
+			iterator.position--
+			return true
+		}
+	}
+	if iterator.between = iterator.iterator.Prev(); iterator.between {
+		iterator.position = len(iterator.iterator.Value()) - 1
+	}
+	return iterator.between
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Value() V {
+	return iterator.iterator.Value()[iterator.position]
+}
+
+// Key returns the current element's key.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Key() K {
+	return iterator.iterator.Key()
+}
+
+// Begin resets the iterator to its initial state (one-before-first)
+// Call Next() to fetch the first element if any.
+func (iterator *Iterator[K, V]) Begin() {
+	iterator.iterator.Begin()
+	iterator.reset()
+}
+
+// End moves the iterator past the last element (one-past-the-end).
+// Call Prev() to fetch the last element if any.
+func (iterator *Iterator[K, V]) End() {
+	iterator.iterator.End()
+	iterator.reset()
+	iterator.end = true
+}
+
+// First moves the iterator to the first element and returns true if there was a first element in the container.
+// If First() returns true, then first element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) First() bool {
+	iterator.Begin()
+	return iterator.Next()
+}
+
+// Last moves the iterator to the last element and returns true if there was a last element in the container.
+// If Last() returns true, then last element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Last() bool {
+	iterator.End()
+	return iterator.Prev()
+}
+
+// Seek moves the iterator to the first value of the first key greater than or equal to the given key
+// and returns true if there was such a key in the container.
+// If Seek() returns true, then element's key and value can be retrieved by Key() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	iterator.reset()
+	iterator.between = iterator.iterator.Seek(key)
+	iterator.end = !iterator.between
+	return iterator.between
+}
+
+// SeekPrev moves the iterator to the last value of the last key less than or equal to the given key
+// and returns true if there was such a key in the container.
+// If SeekPrev() returns true, then element's key and value can be retrieved by Key() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) SeekPrev(key K) bool {
+	iterator.reset()
+	if iterator.between = iterator.iterator.SeekPrev(key); iterator.between {
+		iterator.position = len(iterator.iterator.Value()) - 1
+	}
+	return iterator.between
+}
+
+// Err returns containers.ErrConcurrentModification if the map was modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	if iterator.modCount != iterator.m.modCount && (iterator.between || iterator.end || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current value from the map and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[K, V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || !iterator.between {
+		return
+	}
+	if len(iterator.iterator.Value()) == 1 {
+		// the tree's iterator keeps track of the neighbours of the removed node
+		iterator.iterator.Remove()
+		iterator.m.size--
+		iterator.m.modCount++
+		iterator.between = false
+	} else {
+		iterator.m.removeAt(iterator.m.node(iterator.iterator.Key()), iterator.position)
+		iterator.position--
+	}
+	iterator.modCount = iterator.m.modCount
+	iterator.removed = true
+}
+
+// reset synchronizes the iterator with the map outside of any node.
+func (iterator *Iterator[K, V]) reset() {
+	iterator.between = false
+	iterator.end = false
+	iterator.position = 0
+	iterator.modCount = iterator.m.modCount
+	iterator.removed = false
+}
+
+// checkModification panics if the map was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the map.
+func (iterator *Iterator[K, V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.m.modCount
+}
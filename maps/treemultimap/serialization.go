@@ -0,0 +1,147 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package treemultimap
+
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+	rbt "github.com/monitor1379/yagods/trees/redblacktree"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ containers.JSONSerializer = (*Map[int, string])(nil)
+var _ containers.JSONDeserializer = (*Map[int, string])(nil)
+var _ encoding.BinaryMarshaler = (*Map[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Map[int, string])(nil)
+var _ gob.GobEncoder = (*Map[int, string])(nil)
+var _ gob.GobDecoder = (*Map[int, string])(nil)
+var _ json.Marshaler = (*Map[int, string])(nil)
+var _ json.Unmarshaler = (*Map[int, string])(nil)
+var _ containers.JSONWriter = (*Map[int, string])(nil)
+var _ containers.JSONReader = (*Map[int, string])(nil)
+
+// ToJSON outputs the JSON representation of the map, see MarshalJSON.
+func (m *Map[K, V]) ToJSON() ([]byte, error) {
+	return m.MarshalJSON()
+}
+
+// FromJSON populates the map from the input JSON representation, see UnmarshalJSON.
+func (m *Map[K, V]) FromJSON(data []byte) error {
+	return m.UnmarshalJSON(data)
+}
+
+// MarshalBinary outputs the binary representation of the map in key order: one entry for each value,
+// values of the same key in insertion order.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	keys, values := make([]K, 0, m.size), make([]V, 0, m.size)
+	if m.tree != nil {
+		it := m.tree.Iterator()
+		for it.Next() {
+			for _, value := range it.Value() {
+				keys, values = append(keys, it.Key()), append(values, value)
+			}
+		}
+	}
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), keys, values)
+}
+
+// UnmarshalBinary populates the map from the input binary representation, putting the entries in order.
+// The zero value of a map is ordered by default comparators, see utils.DefaultComparator;
+// returns containers.ErrNoComparator if there are none.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the map for encoding/gob, see MarshalBinary.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode populates the map from the binary representation for encoding/gob, see UnmarshalBinary.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	return m.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the map for encoding/json: an object in key order
+// holding an array of the values of each key in insertion order.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	if m.tree == nil {
+		return []byte("{}"), nil
+	}
+	return m.tree.MarshalJSON()
+}
+
+// UnmarshalJSON populates the map from the JSON representation for encoding/json, see MarshalJSON.
+// Values of a key that occurs more than once in the object are put after the values of its earlier occurrences.
+// Like UnmarshalBinary, it accepts the zero value of a map.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	keys, values, err := containers.UnmarshalJSONEntries[K, []V](data)
+	if err == nil {
+		m.Clear()
+		for i, key := range keys {
+			m.putAll(key, values[i])
+		}
+	}
+	return err
+}
+
+// initZero creates the tree of a map that was not instantiated by a constructor,
+// ordered by the default comparator of its keys.
+func (m *Map[K, V]) initZero() error {
+	if m.tree == nil {
+		comparator := utils.DefaultComparator[K]()
+		if comparator == nil {
+			return containers.ErrNoComparator
+		}
+		m.tree = rbt.NewWith[K, []V](comparator)
+	}
+	return nil
+}
+
+// WriteJSON writes the JSON representation of the map to the writer in key order, encoding the values
+// of one key at a time.
+func (m *Map[K, V]) WriteJSON(w io.Writer) error {
+	if m.tree == nil {
+		_, err := io.WriteString(w, "{}")
+		return err
+	}
+	return m.tree.WriteJSON(w)
+}
+
+// ReadJSON populates the map from the JSON representation read from the reader, putting the values
+// of every key as they are decoded. Like UnmarshalJSON, it accepts the zero value of a map.
+// On error, the map holds the entries read before the error.
+func (m *Map[K, V]) ReadJSON(r io.Reader) error {
+	if err := m.initZero(); err != nil {
+		return err
+	}
+	m.Clear()
+	return containers.ReadJSONEntries(r, m.putAll)
+}
+
+// putAll puts the values under the key in order.
+func (m *Map[K, V]) putAll(key K, values []V) {
+	for _, value := range values {
+		m.Put(key, value)
+	}
+}
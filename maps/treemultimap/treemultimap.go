@@ -0,0 +1,220 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package treemultimap implements a multimap backed by red-black tree.
+//
+// A multimap holds any number of values per key. Elements are ordered by key and,
+// among equal keys, by insertion order.
+//
+// Structure is not thread safe.
+//
+// Reference: https://en.wikipedia.org/wiki/Multimap
+package treemultimap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/monitor1379/yagods/containers"
+	rbt "github.com/monitor1379/yagods/trees/redblacktree"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ containers.Container[string] = (*Map[int, string])(nil)
+
+// Map holds the values of each key in insertion order in a node of a red-black tree
+type Map[K comparable, V any] struct {
+	tree     *rbt.Tree[K, []V]
+	size     int // number of values over all keys
+	modCount int // incremented on every modification, checked by iterators to fail fast
+}
+
+// New instantiates a tree multimap ordering the keys by the < and > operators, see utils.OrderedComparator.
+func New[K utils.Ordered, V any]() *Map[K, V] {
+	return &Map[K, V]{tree: rbt.New[K, []V]()}
+}
+
+// NewWith instantiates a tree multimap with the custom comparator.
+func NewWith[K comparable, V any](comparator utils.Comparator[K]) *Map[K, V] {
+	return &Map[K, V]{tree: rbt.NewWith[K, []V](comparator)}
+}
+
+// NewWithIntComparator instantiates a tree multimap with the IntComparator, i.e. keys are of type int.
+func NewWithIntComparator[V any]() *Map[int, V] {
+	return &Map[int, V]{tree: rbt.NewWithIntComparator[[]V]()}
+}
+
+// NewWithStringComparator instantiates a tree multimap with the StringComparator, i.e. keys are of type string.
+func NewWithStringComparator[V any]() *Map[string, V] {
+	return &Map[string, V]{tree: rbt.NewWithStringComparator[[]V]()}
+}
+
+// Put adds the value to the values of the key, after all values put before under an equal key.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Put(key K, value V) {
+	if node := m.node(key); node != nil {
+		node.Value = append(node.Value, value)
+	} else {
+		m.tree.Put(key, []V{value})
+	}
+	m.size++
+	m.modCount++
+}
+
+// Get returns the first value put under the key.
+// Second return parameter is true if key was found, otherwise false.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Get(key K) (value V, found bool) {
+	if node := m.node(key); node != nil {
+		return node.Value[0], true
+	}
+	return value, false
+}
+
+// GetAll returns the values of the key in insertion order, or nil if key is not found.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) GetAll(key K) []V {
+	if node := m.node(key); node != nil {
+		return append([]V(nil), node.Value...)
+	}
+	return nil
+}
+
+// Count returns the number of values of the key in O(log n) time.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Count(key K) int {
+	if node := m.node(key); node != nil {
+		return len(node.Value)
+	}
+	return 0
+}
+
+// RemoveOne removes the first value put under the key and returns true if key was found.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) RemoveOne(key K) bool {
+	node := m.node(key)
+	if node == nil {
+		return false
+	}
+	m.removeAt(node, 0)
+	return true
+}
+
+// RemoveAll removes all values of the key and returns how many there were.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) RemoveAll(key K) int {
+	node := m.node(key)
+	if node == nil {
+		return 0
+	}
+	count := len(node.Value)
+	m.tree.Remove(key)
+	m.size -= count
+	m.modCount++
+	return count
+}
+
+// RemoveRange removes all values whose keys are within [from, to) from the map.
+// Runs in O(log n + k) time, k being the number of removed keys.
+// Keys should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) RemoveRange(from K, to K) {
+	it := m.tree.Iterator()
+	for found := it.Seek(from); found && m.tree.Comparator(it.Key(), to) < 0; found = it.Next() {
+		m.size -= len(it.Value())
+	}
+	m.tree.RemoveRange(from, to)
+	m.modCount++
+}
+
+// Range returns the keys and values of all elements whose keys are within [from, to), keys[i] holding values[i],
+// in order.
+// Keys should adhere to the comparator's type assertion, otherwise method panics.
+func (m *Map[K, V]) Range(from K, to K) (keys []K, values []V) {
+	it := m.tree.Iterator()
+	for found := it.Seek(from); found && m.tree.Comparator(it.Key(), to) < 0; found = it.Next() {
+		for _, value := range it.Value() {
+			keys, values = append(keys, it.Key()), append(values, value)
+		}
+	}
+	return keys, values
+}
+
+// Empty returns true if map does not contain any elements
+func (m *Map[K, V]) Empty() bool {
+	return m.size == 0
+}
+
+// Size returns number of values over all keys in the map.
+func (m *Map[K, V]) Size() int {
+	return m.size
+}
+
+// Keys returns the distinct keys in-order.
+func (m *Map[K, V]) Keys() []K {
+	return m.tree.Keys()
+}
+
+// Values returns all values in-order based on the key, values of the same key in insertion order.
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, m.size)
+	it := m.tree.Iterator()
+	for it.Next() {
+		values = append(values, it.Value()...)
+	}
+	return values
+}
+
+// InterfaceValues returns all elements in the map as type interface{}.
+func (m *Map[K, V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, 0, m.size)
+	for _, value := range m.Values() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Clear removes all elements from the map.
+func (m *Map[K, V]) Clear() {
+	m.tree.Clear()
+	m.size = 0
+	m.modCount++
+}
+
+// String returns a string representation of container
+func (m *Map[K, V]) String() string {
+	str := "TreeMultiMap\nmap["
+	it := m.Iterator()
+	for it.Next() {
+		str += fmt.Sprintf("%v:%v ", it.Key(), it.Value())
+	}
+	return strings.TrimRight(str, " ") + "]"
+}
+
+// node returns the tree node of the key or nil if key is not found.
+func (m *Map[K, V]) node(key K) *rbt.Node[K, []V] {
+	if node, found := m.tree.Floor(key); found && m.tree.Comparator(node.Key, key) == 0 {
+		return node
+	}
+	return nil
+}
+
+// removeAt removes the value at the position from the node, and the node once it holds no value.
+func (m *Map[K, V]) removeAt(node *rbt.Node[K, []V], position int) {
+	values := node.Value
+	var zero V
+	switch {
+	case len(values) == 1:
+		m.tree.Remove(node.Key)
+	case position == 0:
+		// dropping the front keeps removal of the oldest value O(1), the next growth reclaims the space
+		values[0] = zero
+		node.Value = values[1:]
+	default:
+		copy(values[position:], values[position+1:])
+		values[len(values)-1] = zero
+		node.Value = values[:len(values)-1]
+	}
+	m.size--
+	m.modCount++
+}
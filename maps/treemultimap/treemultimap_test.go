@@ -0,0 +1,658 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package treemultimap_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps/treemultimap"
+	"github.com/monitor1379/yagods/utils"
+)
+
+func TestMapPut(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[string]()
+	m.Put(5, "e")
+	m.Put(1, "a")
+	m.Put(3, "c1")
+	m.Put(3, "c2")
+	m.Put(1, "a2")
+	m.Put(3, "c3")
+
+	if actualValue, expectedValue := m.Size(), 6; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys()), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Values()), "[a a2 c1 c2 c3 e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.GetAll(3)), "[c1 c2 c3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := m.GetAll(4); actualValue != nil {
+		t.Errorf("Got %v expected %v", actualValue, nil)
+	}
+
+	tests := [][]interface{}{
+		{1, "a", true, 2},
+		{2, "", false, 0},
+		{3, "c1", true, 3},
+		{5, "e", true, 1},
+		{6, "", false, 0},
+	}
+	for _, test := range tests {
+		key := test[0].(int)
+		actualValue, actualFound := m.Get(key)
+		if actualValue != test[1] || actualFound != test[2] {
+			t.Errorf("Got %v %v expected %v %v", actualValue, actualFound, test[1], test[2])
+		}
+		if actualValue, expectedValue := m.Count(key), test[3]; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+
+	values := m.GetAll(3)
+	values[0] = "x"
+	if actualValue, _ := m.Get(3); actualValue != "c1" {
+		t.Errorf("Got %v expected %v", actualValue, "c1")
+	}
+	if actualValue, expectedValue := m.String(), "TreeMultiMap\nmap[1:a 1:a2 3:c1 3:c2 3:c3 5:e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapRemove(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[string]()
+	for i := 0; i < 4; i++ {
+		m.Put(2, "b"+strconv.Itoa(i))
+		m.Put(1, "a"+strconv.Itoa(i))
+	}
+
+	if actualValue := m.RemoveOne(2); !actualValue {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := m.RemoveOne(3); actualValue {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.GetAll(2)), "[b1 b2 b3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.Put(2, "b4")
+	if actualValue, expectedValue := fmt.Sprint(m.GetAll(2)), "[b1 b2 b3 b4]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := m.RemoveAll(1), 4; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := m.RemoveAll(1), 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values(), m.Size()), "[2] [b1 b2 b3 b4] 4"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for m.RemoveOne(2) {
+	}
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values(), m.Size(), m.Empty()), "[] [] 0 true"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	m.Put(1, "a")
+	m.Clear()
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values(), m.Size(), m.Empty()), "[] [] 0 true"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapRange(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[string]()
+	for i := 0; i < 10; i++ {
+		m.Put(i/2, strconv.Itoa(i))
+	}
+
+	keys, values := m.Range(1, 3)
+	if actualValue, expectedValue := fmt.Sprint(keys, values), "[1 1 2 2] [2 3 4 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if keys, values := m.Range(7, 9); keys != nil || values != nil {
+		t.Errorf("Got %v %v expected an empty range", keys, values)
+	}
+
+	m.RemoveRange(1, 3)
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values(), m.Size()), "[0 3 4] [0 1 6 7 8 9] 6"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	m.RemoveRange(-1, 10)
+	if actualValue, expectedValue := m.Size(), 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapEventLog(t *testing.T) {
+	type event struct {
+		at   time.Time
+		name string
+	}
+	start := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	log := treemultimap.NewWith[time.Time, string](utils.TimeComparator)
+	events := []event{
+		{start.Add(time.Second), "b"},
+		{start, "a"},
+		{start.Add(time.Second), "c"},
+		{start.Add(2 * time.Second), "d"},
+		{start.Add(time.Second), "e"},
+	}
+	for _, e := range events {
+		log.Put(e.at, e.name)
+	}
+	if actualValue, expectedValue := fmt.Sprint(log.Values()), "[a b c e d]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := log.Count(start.Add(time.Second)), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapNew(t *testing.T) {
+	m := treemultimap.New[time.Duration, string]()
+	m.Put(time.Hour, "hour")
+	m.Put(time.Second, "second")
+	m.Put(time.Hour, "another hour")
+	if actualValue, expectedValue := fmt.Sprint(m.Keys(), m.Values()), "[1s 1h0m0s] [second hour another hour]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	selected := m.Select(func(key time.Duration, value string) bool { return key >= time.Minute })
+	selected.Put(time.Millisecond, "millisecond")
+	if actualValue, expectedValue := fmt.Sprint(selected.Keys(), selected.Size()), "[1ms 1h0m0s] 3"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := m.Size(), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapEach(t *testing.T) {
+	m := treemultimap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("a", 2)
+	var entries []string
+	m.Each(func(key string, value int) {
+		entries = append(entries, fmt.Sprintf("%v:%v", key, value))
+	})
+	if actualValue, expectedValue := fmt.Sprint(entries), "[a:1 a:2 c:3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapMapSelect(t *testing.T) {
+	m := treemultimap.NewWithStringComparator[int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("a", 2)
+	m.Put("b", 2)
+
+	mapped := m.Map(func(key string, value int) (string, int) {
+		return "x", value * value
+	})
+	if actualValue, expectedValue := fmt.Sprint(mapped.Keys(), mapped.Values()), "[x] [1 4 4 9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	selected := m.Select(func(key string, value int) bool {
+		return value >= 2
+	})
+	if actualValue, expectedValue := fmt.Sprint(selected.Keys(), selected.Values()), "[a b c] [2 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapAnyAllFind(t *testing.T) {
+	m := treemultimap.NewWithStringComparator[int]()
+	m.Put("a", 1)
+	m.Put("a", 2)
+	m.Put("b", 3)
+
+	if actualValue := m.Any(func(key string, value int) bool { return key == "a" && value == 2 }); !actualValue {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := m.All(func(key string, value int) bool { return value < 3 }); actualValue {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	key, value, found := m.Find(func(key string, value int) bool { return value > 1 })
+	if key != "a" || value != 2 || !found {
+		t.Errorf("Got %v %v %v expected %v %v %v", key, value, found, "a", 2, true)
+	}
+	if _, _, found := m.Find(func(key string, value int) bool { return value > 3 }); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+}
+
+// entry is an element of the model the map is checked against.
+type entry struct {
+	key   int
+	value int
+}
+
+// put inserts the entry into the model after all entries with lower or equal keys.
+func put(model []entry, e entry) []entry {
+	index := len(model)
+	for index > 0 && model[index-1].key > e.key {
+		index--
+	}
+	return append(model[:index], append([]entry{e}, model[index:]...)...)
+}
+
+func checkModel(t *testing.T, m *treemultimap.Map[int, int], model []entry) {
+	t.Helper()
+	if actualValue, expectedValue := m.Size(), len(model); actualValue != expectedValue {
+		t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var forward []entry
+	for it := m.Iterator(); it.Next(); {
+		forward = append(forward, entry{it.Key(), it.Value()})
+	}
+	if actualValue, expectedValue := fmt.Sprint(forward), fmt.Sprint(model); actualValue != expectedValue {
+		t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var backward []entry
+	for it := m.Iterator(); it.Last(); it.End() {
+		for ok := true; ok; ok = it.Prev() {
+			backward = append([]entry{{it.Key(), it.Value()}}, backward...)
+		}
+		break
+	}
+	if actualValue, expectedValue := fmt.Sprint(backward), fmt.Sprint(model); actualValue != expectedValue {
+		t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestMapIteratorNextPrev(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[int]()
+	var model []entry
+	for i := 0; i < 20; i++ {
+		e := entry{i % 5, i}
+		m.Put(e.key, e.value)
+		model = put(model, e)
+	}
+	checkModel(t, m, model)
+
+	it := m.Iterator()
+	for i := 0; i < 3; i++ {
+		it.Next()
+	}
+	it.Prev()
+	if actualValue, expectedValue := fmt.Sprintf("%v %v", it.Key(), it.Value()), "0 5"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if it.Next(); fmt.Sprintf("%v %v", it.Key(), it.Value()) != "0 10" {
+		t.Errorf("Got %v %v expected %v %v", it.Key(), it.Value(), 0, 10)
+	}
+}
+
+func TestMapIteratorOnEmpty(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[int]()
+	it := m.Iterator()
+	if it.Next() || it.Prev() || it.First() || it.Last() {
+		t.Errorf("Shouldn't iterate on empty map")
+	}
+}
+
+func TestMapIteratorSeek(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[string]()
+	for i := 0; i < 6; i++ {
+		m.Put(2*(i/2), strconv.Itoa(i))
+	}
+
+	it := m.IteratorAt(1)
+	if actualValue, expectedValue := fmt.Sprintf("%v %v", it.Key(), it.Value()), "2 2"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if it.Prev(); fmt.Sprintf("%v %v", it.Key(), it.Value()) != "0 1" {
+		t.Errorf("Got %v %v expected %v %v", it.Key(), it.Value(), 0, "1")
+	}
+	if found := it.SeekPrev(3); !found || fmt.Sprintf("%v %v", it.Key(), it.Value()) != "2 3" {
+		t.Errorf("Got %v %v %v expected %v %v %v", found, it.Key(), it.Value(), true, 2, "3")
+	}
+	if it.Next(); fmt.Sprintf("%v %v", it.Key(), it.Value()) != "4 4" {
+		t.Errorf("Got %v %v expected %v %v", it.Key(), it.Value(), 4, "4")
+	}
+	if found := it.Seek(5); found || it.Next() {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if found := it.SeekPrev(-1); found || it.Prev() {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if it.Next(); fmt.Sprintf("%v %v", it.Key(), it.Value()) != "0 0" {
+		t.Errorf("Got %v %v expected %v %v", it.Key(), it.Value(), 0, "0")
+	}
+}
+
+func TestMapIteratorRemove(t *testing.T) {
+	rand.Seed(1)
+	for round := 0; round < 50; round++ {
+		m := treemultimap.NewWithIntComparator[int]()
+		var model []entry
+		for i := 0; i < 30; i++ {
+			e := entry{rand.Intn(8), i}
+			m.Put(e.key, e.value)
+			model = put(model, e)
+		}
+		it := m.Iterator()
+		// index points to the current entry, or to the predecessor of the removed one
+		for index, removed := -1, false; len(model) > 0; {
+			if rand.Intn(2) == 0 {
+				if ok := it.Next(); ok != (index+1 < len(model)) {
+					t.Fatalf("Got %v expected %v", ok, !ok)
+				}
+				if index < len(model) {
+					index++
+				}
+			} else if removed {
+				if ok := it.Prev(); ok != (index >= 0) {
+					t.Fatalf("Got %v expected %v", ok, !ok)
+				}
+			} else {
+				if ok := it.Prev(); ok != (index > 0) {
+					t.Fatalf("Got %v expected %v", ok, !ok)
+				}
+				if index >= 0 {
+					index--
+				}
+			}
+			removed = false
+			if index < 0 || index >= len(model) {
+				continue
+			}
+			if actualValue, expectedValue := fmt.Sprint(entry{it.Key(), it.Value()}), fmt.Sprint(model[index]); actualValue != expectedValue {
+				t.Fatalf("Got %v expected %v", actualValue, expectedValue)
+			}
+			if rand.Intn(3) == 0 {
+				it.Remove()
+				it.Remove()
+				model = append(model[:index], model[index+1:]...)
+				index, removed = index-1, true
+				checkModel(t, m, model)
+			}
+		}
+		if actualValue, expectedValue := m.Empty(), true; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+}
+
+func TestMapIteratorConcurrentModification(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[string]()
+	m.Put(1, "a")
+	m.Put(1, "b")
+	it := m.Iterator()
+	it.Next()
+	m.Put(1, "c")
+	if err := it.Err(); !errors.Is(err, containers.ErrConcurrentModification) {
+		t.Errorf("Got %v expected %v", err, containers.ErrConcurrentModification)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != containers.ErrConcurrentModification {
+				t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+			}
+		}()
+		it.Next()
+	}()
+	if it.First(); it.Err() != nil || it.Value() != "a" {
+		t.Errorf("Got %v %v expected %v", it.Err(), it.Value(), "a")
+	}
+
+	// only the initial state is exempt, not the state past the last element
+	it.Begin()
+	m.Put(2, "d")
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+	for it.Next() {
+	}
+	m.RemoveOne(2)
+	if err := it.Err(); !errors.Is(err, containers.ErrConcurrentModification) {
+		t.Errorf("Got %v expected %v", err, containers.ErrConcurrentModification)
+	}
+}
+
+func TestMapConformance(t *testing.T) {
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		m := treemultimap.NewWithIntComparator[string]()
+		for _, key := range keys {
+			m.Put(key, strconv.Itoa(key))
+		}
+		it := m.Iterator()
+		return &it
+	})
+}
+
+func TestMapBinarySerialization(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[string]()
+	m.Put(3, "c")
+	m.Put(1, "a")
+	m.Put(3, "b")
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := treemultimap.NewWithIntComparator[string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := restored.String(), m.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(m); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	var zero treemultimap.Map[int, string]
+	if err := gob.NewDecoder(&buffer).Decode(&zero); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := zero.String(), m.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	if data, err := (&treemultimap.Map[int, string]{}).MarshalBinary(); err != nil || (&zero).UnmarshalBinary(data) != nil || !zero.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", zero.Size(), err)
+	}
+	if err := (&treemultimap.Map[[2]int, string]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapJSONMarshaler(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[string]()
+	m.Put(2, "b")
+	m.Put(10, "c")
+	m.Put(2, "a")
+
+	data, err := json.Marshal(struct {
+		Map *treemultimap.Map[int, string]
+	}{m})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Map":{"2":["b","a"],"10":["c"]}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Map treemultimap.Map[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := restored.Map.String(), m.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.Map.FromJSON([]byte(`{"1":["x"],"2":["y"],"1":["z"]}`)); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Map.Keys(), restored.Map.Values()), "[1 2] [x z y]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Map":{"1":"x"}}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&treemultimap.Map[[2]int, string]{}).UnmarshalJSON([]byte("{}")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestMapJSONStream(t *testing.T) {
+	m := treemultimap.NewWithIntComparator[string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(2, "c")
+
+	var buffer bytes.Buffer
+	if err := m.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":["a"],"2":["b","c"]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored treemultimap.Map[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := restored.String(), m.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func FuzzMap(f *testing.F) {
+	f.Add([]byte{0, 5, 0, 3, 0, 5, 1, 5, 2, 3, 3, 9, 0, 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := treemultimap.NewWithIntComparator[int]()
+		var model []entry
+		for step := 0; len(data) >= 2; data, step = data[2:], step+1 {
+			key := int(data[1] % 8)
+			switch data[0] % 4 {
+			case 0:
+				m.Put(key, step)
+				model = put(model, entry{key, step})
+			case 1:
+				removed := m.RemoveOne(key)
+				found := false
+				for i, e := range model {
+					if e.key == key {
+						model, found = append(model[:i], model[i+1:]...), true
+						break
+					}
+				}
+				if removed != found {
+					t.Fatalf("Got %v expected %v", removed, found)
+				}
+			case 2:
+				count := 0
+				for i := 0; i < len(model); {
+					if model[i].key == key {
+						model, count = append(model[:i], model[i+1:]...), count+1
+					} else {
+						i++
+					}
+				}
+				if actualValue := m.RemoveAll(key); actualValue != count {
+					t.Fatalf("Got %v expected %v", actualValue, count)
+				}
+			case 3:
+				if len(model) == 0 {
+					continue
+				}
+				index := int(data[1]) % len(model)
+				it := m.Iterator()
+				for i := 0; i <= index; i++ {
+					it.Next()
+				}
+				it.Remove()
+				model = append(model[:index], model[index+1:]...)
+				// the follower comes next, then the predecessor of the removed entry
+				if index < len(model) && (!it.Next() || it.Value() != model[index].value) {
+					t.Fatalf("Got %v expected %v", it.Value(), model[index].value)
+				}
+				if index > 0 && (!it.Prev() || it.Value() != model[index-1].value) {
+					t.Fatalf("Got %v expected %v", it.Value(), model[index-1].value)
+				}
+			}
+			count := 0
+			for _, e := range model {
+				if e.key == key {
+					count++
+				}
+			}
+			if actualValue := m.Count(key); actualValue != count {
+				t.Fatalf("Got %v expected %v", actualValue, count)
+			}
+			checkModel(t, m, model)
+		}
+	})
+}
+
+func benchmarkPut(b *testing.B, m *treemultimap.Map[int, int], size int, keys int) {
+	for i := 0; i < b.N; i++ {
+		m.Clear()
+		for n := 0; n < size; n++ {
+			m.Put(n%keys, n)
+		}
+	}
+}
+
+func benchmarkCount(b *testing.B, m *treemultimap.Map[int, int], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			m.Count(n)
+		}
+	}
+}
+
+func BenchmarkTreeMultiMapPut1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	m := treemultimap.NewWithIntComparator[int]()
+	b.StartTimer()
+	benchmarkPut(b, m, size, size/10)
+}
+
+func BenchmarkTreeMultiMapPut100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	m := treemultimap.NewWithIntComparator[int]()
+	b.StartTimer()
+	benchmarkPut(b, m, size, size/10)
+}
+
+func BenchmarkTreeMultiMapCount100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	m := treemultimap.NewWithIntComparator[int]()
+	for n := 0; n < size; n++ {
+		m.Put(n%(size/10), n)
+	}
+	b.StartTimer()
+	benchmarkCount(b, m, size)
+}
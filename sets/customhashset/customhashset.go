@@ -0,0 +1,144 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package customhashset implements a set backed by a hash table that hashes and compares its items by a utils.Hasher.
+//
+// Unlike hashset, items need not be comparable with ==, and can be equal other than by ==.
+// Items are not copied, so an item such as a byte slice must not be modified while it is in the set.
+//
+// Structure is not thread safe.
+//
+// References: http://en.wikipedia.org/wiki/Set_%28abstract_data_type%29
+package customhashset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/monitor1379/yagods/sets"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ sets.Set[int] = (*Set[int])(nil)
+var _ sets.Set[[]byte] = (*Set[[]byte])(nil)
+
+// Set holds the items in buckets of items having the same hash.
+type Set[V any] struct {
+	hasher  utils.Hasher[V]
+	buckets map[uint64][]V
+	size    int
+}
+
+// New instantiates a new empty set hashing and comparing its items by the hasher and adds the passed values, if any, to the set
+func New[V any](hasher utils.Hasher[V], values ...V) *Set[V] {
+	set := &Set[V]{hasher: hasher, buckets: make(map[uint64][]V)}
+	if len(values) > 0 {
+		set.Add(values...)
+	}
+	return set
+}
+
+// Hasher returns the hasher of the items.
+func (set *Set[V]) Hasher() utils.Hasher[V] {
+	return set.hasher
+}
+
+// find returns the hash of the item and its index in its bucket, or -1 if the item is not found.
+func (set *Set[V]) find(item V) (uint64, int) {
+	hash := set.hasher.Hash(item)
+	for i, other := range set.buckets[hash] {
+		if set.hasher.Equal(other, item) {
+			return hash, i
+		}
+	}
+	return hash, -1
+}
+
+// Add adds the items (one or more) to the set.
+// Items already present in the set are kept as they were first added.
+func (set *Set[V]) Add(items ...V) {
+	for _, item := range items {
+		if hash, i := set.find(item); i < 0 {
+			set.buckets[hash] = append(set.buckets[hash], item)
+			set.size++
+		}
+	}
+}
+
+// Remove removes the items (one or more) from the set.
+func (set *Set[V]) Remove(items ...V) {
+	for _, item := range items {
+		hash, i := set.find(item)
+		if i < 0 {
+			continue
+		}
+		bucket := set.buckets[hash]
+		if len(bucket) == 1 {
+			delete(set.buckets, hash)
+		} else {
+			last := len(bucket) - 1
+			var zero V
+			bucket[i], bucket[last] = bucket[last], zero
+			set.buckets[hash] = bucket[:last]
+		}
+		set.size--
+	}
+}
+
+// Contains check if items (one or more) are present in the set.
+// All items have to be present in the set for the method to return true.
+// Returns true if no arguments are passed at all, i.e. set is always superset of empty set.
+func (set *Set[V]) Contains(items ...V) bool {
+	for _, item := range items {
+		if _, i := set.find(item); i < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty returns true if set does not contain any elements.
+func (set *Set[V]) Empty() bool {
+	return set.Size() == 0
+}
+
+// Size returns number of elements within the set.
+func (set *Set[V]) Size() int {
+	return set.size
+}
+
+// Clear clears all values in the set.
+func (set *Set[V]) Clear() {
+	set.buckets = make(map[uint64][]V)
+	set.size = 0
+}
+
+// Values returns all items in the set.
+func (set *Set[V]) Values() []V {
+	values := make([]V, 0, set.size)
+	for _, bucket := range set.buckets {
+		values = append(values, bucket...)
+	}
+	return values
+}
+
+// InterfaceValues returns all elements in the set as type interface{}.
+func (set *Set[V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, 0, set.size)
+	for _, value := range set.Values() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// String returns a string representation of container
+func (set *Set[V]) String() string {
+	str := "CustomHashSet\n"
+	items := []string{}
+	for _, item := range set.Values() {
+		items = append(items, fmt.Sprintf("%v", item))
+	}
+	str += strings.Join(items, ", ")
+	return str
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package customhashset_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/sets"
+	"github.com/monitor1379/yagods/sets/customhashset"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// sortedStrings returns the values formatted by fmt.Sprint in ascending order.
+func sortedStrings[T any](values []T) []string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = fmt.Sprint(value)
+	}
+	sort.Strings(strs)
+	return strs
+}
+
+func TestSetNew(t *testing.T) {
+	set := customhashset.New(utils.NewComparableHasher[int](), 2, 1)
+
+	if actualValue := set.Size(); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+	if actualValue := set.Contains(1); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(2); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(3); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestSetAdd(t *testing.T) {
+	set := customhashset.New(utils.NewComparableHasher[int]())
+	set.Add()
+	set.Add(1)
+	set.Add(2)
+	set.Add(2, 3)
+	set.Add()
+	if actualValue := set.Empty(); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue := set.Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+}
+
+func TestSetContains(t *testing.T) {
+	set := customhashset.New(utils.NewComparableHasher[int]())
+	set.Add(3, 1, 2)
+	set.Add(2, 3)
+	set.Add()
+	if actualValue := set.Contains(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(1); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(1, 2, 3); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(1, 2, 3, 4); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+}
+
+func TestSetRemove(t *testing.T) {
+	set := customhashset.New(utils.NewComparableHasher[int]())
+	set.Add(3, 1, 2)
+	set.Remove()
+	if actualValue := set.Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+	set.Remove(1)
+	if actualValue := set.Size(); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+	set.Remove(3)
+	set.Remove(3)
+	set.Remove()
+	set.Remove(2)
+	if actualValue := set.Size(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+}
+
+func TestSetBytesItems(t *testing.T) {
+	set := customhashset.New(utils.NewBytesHasher(), []byte("a"), []byte("b"), []byte("a"), nil, []byte{})
+	if actualValue := set.Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+	if actualValue := set.Contains([]byte("a"), []byte{}); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	set.Remove([]byte("a"), nil)
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[[98]]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetCaseInsensitiveItems(t *testing.T) {
+	set := customhashset.New(utils.NewCaseInsensitiveStringHasher(), "Go", "GO", "go", "Rust")
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(set.Values())), "[Go Rust]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := set.Contains("rUST"); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestSetCollisions(t *testing.T) {
+	// every item has the same hash
+	hasher := utils.NewHasher(func(item int) uint64 { return 0 }, utils.ComparableEqual[int])
+	set := customhashset.New(hasher, 0, 1, 2, 3, 4, 5)
+	set.Remove(0, 2, 4)
+	if actualValue, expectedValue := fmt.Sprint(sortedStrings(set.Values())), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := set.Contains(0); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+}
+
+func TestSetString(t *testing.T) {
+	set := customhashset.New(utils.NewStringHasher(), "a")
+	if actualValue, expectedValue := set.String(), "CustomHashSet\na"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetSerialization(t *testing.T) {
+	set := customhashset.New(utils.NewStringHasher())
+	set.Add("a", "b", "c")
+
+	var err error
+	assert := func() {
+		if actualValue, expectedValue := set.Size(), 3; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue := set.Contains("a", "b", "c"); actualValue != true {
+			t.Errorf("Got %v expected %v", actualValue, true)
+		}
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+
+	assert()
+
+	json, err := set.ToJSON()
+	assert()
+
+	err = set.FromJSON(json)
+	assert()
+}
+
+func TestSetBinarySerialization(t *testing.T) {
+	set := customhashset.New(utils.NewBytesHasher(), []byte("ab"), []byte("cd"))
+
+	data, err := set.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := customhashset.New(utils.NewBytesHasher())
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Size(), restored.Contains([]byte("ab"), []byte("cd"))), "2 true"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(set); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = customhashset.New(utils.NewBytesHasher())
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Size(), restored.Contains([]byte("ab"), []byte("cd"))), "2 true"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	var zero customhashset.Set[[]byte]
+	if err := zero.UnmarshalBinary(data); !errors.Is(err, containers.ErrNoHasher) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoHasher)
+	}
+}
+
+func TestSetJSONMarshaler(t *testing.T) {
+	set := customhashset.New(utils.NewBytesHasher(), []byte("a"))
+
+	data, err := json.Marshal(struct{ Set *customhashset.Set[[]byte] }{set})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Set":["YQ=="]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restored := struct{ Set *customhashset.Set[[]byte] }{customhashset.New(utils.NewBytesHasher())}
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Set.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Set":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	var zero struct{ Set customhashset.Set[[]byte] }
+	if err := json.Unmarshal(data, &zero); !errors.Is(err, containers.ErrNoHasher) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoHasher)
+	}
+}
+
+func TestSetJSONStream(t *testing.T) {
+	set := customhashset.New(utils.NewStringHasher(), "a")
+
+	var buffer bytes.Buffer
+	if err := set.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `["a"]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restored := customhashset.New(utils.NewStringHasher())
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestSetConformance(t *testing.T) {
+	containertest.TestSet(t, func() sets.Set[int] { return customhashset.New(utils.NewComparableHasher[int]()) })
+}
+
+func FuzzSet(f *testing.F) {
+	// few distinct hashes, so that items collide
+	hasher := utils.NewHasher(func(item int) uint64 { return uint64(item % 3) }, utils.ComparableEqual[int])
+	containertest.FuzzSet(f, func() sets.Set[int] { return customhashset.New(hasher) }, containertest.Unordered)
+}
+
+func benchmarkContains(b *testing.B, set *customhashset.Set[int], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			set.Contains(n)
+		}
+	}
+}
+
+func benchmarkAdd(b *testing.B, set *customhashset.Set[int], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			set.Add(n)
+		}
+	}
+}
+
+func BenchmarkCustomHashSetContains10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	set := customhashset.New(utils.NewComparableHasher[int]())
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkContains(b, set, size)
+}
+
+func BenchmarkCustomHashSetAdd10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	set := customhashset.New(utils.NewComparableHasher[int]())
+	b.StartTimer()
+	benchmarkAdd(b, set, size)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package customhashset
+
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+var _ containers.JSONSerializer = (*Set[int])(nil)
+var _ containers.JSONDeserializer = (*Set[string])(nil)
+var _ encoding.BinaryMarshaler = (*Set[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*Set[int])(nil)
+var _ gob.GobEncoder = (*Set[int])(nil)
+var _ gob.GobDecoder = (*Set[int])(nil)
+var _ json.Marshaler = (*Set[int])(nil)
+var _ json.Unmarshaler = (*Set[int])(nil)
+var _ containers.JSONWriter = (*Set[int])(nil)
+var _ containers.JSONReader = (*Set[int])(nil)
+
+// ToJSON outputs the JSON representation of the set.
+func (set *Set[V]) ToJSON() ([]byte, error) {
+	return json.Marshal(set.Values())
+}
+
+// FromJSON populates the set from the input JSON representation.
+// The set must have been instantiated by New, otherwise containers.ErrNoHasher is returned.
+func (set *Set[V]) FromJSON(data []byte) error {
+	return set.UnmarshalJSON(data)
+}
+
+// MarshalBinary outputs the binary representation of the set.
+// Items are encoded by the codec of containers.CodecFor.
+func (set *Set[V]) MarshalBinary() ([]byte, error) {
+	return containers.EncodeValues(containers.CodecFor[V](), set.Values())
+}
+
+// UnmarshalBinary populates the set from the input binary representation.
+// The set must have been instantiated by New, otherwise containers.ErrNoHasher is returned.
+func (set *Set[V]) UnmarshalBinary(data []byte) error {
+	if set.hasher == nil {
+		return containers.ErrNoHasher
+	}
+	values, err := containers.DecodeValues(containers.CodecFor[V](), data)
+	if err == nil {
+		set.Clear()
+		set.Add(values...)
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the set for encoding/gob, see MarshalBinary.
+func (set *Set[V]) GobEncode() ([]byte, error) {
+	return set.MarshalBinary()
+}
+
+// GobDecode populates the set from the binary representation for encoding/gob, see UnmarshalBinary.
+func (set *Set[V]) GobDecode(data []byte) error {
+	return set.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the set for encoding/json: an array of its items in no particular order.
+func (set *Set[V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.Values())
+}
+
+// UnmarshalJSON populates the set from the JSON representation for encoding/json, see MarshalJSON.
+// The set must have been instantiated by New, otherwise containers.ErrNoHasher is returned.
+func (set *Set[V]) UnmarshalJSON(data []byte) error {
+	if set.hasher == nil {
+		return containers.ErrNoHasher
+	}
+	var values []V
+	err := json.Unmarshal(data, &values)
+	if err == nil {
+		set.Clear()
+		set.Add(values...)
+	}
+	return err
+}
+
+// WriteJSON writes the JSON representation of the set to the writer, see MarshalJSON.
+func (set *Set[V]) WriteJSON(w io.Writer) error {
+	data, err := set.MarshalJSON()
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	return err
+}
+
+// ReadJSON populates the set from the JSON representation read from the reader, adding every item as it is decoded.
+// On error, the set holds the items read before the error.
+func (set *Set[V]) ReadJSON(r io.Reader) error {
+	if set.hasher == nil {
+		return containers.ErrNoHasher
+	}
+	set.Clear()
+	return containers.ReadJSONValues(r, func(item V) {
+		set.Add(item)
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatset
+
+import "github.com/monitor1379/yagods/containers"
+
+var _ containers.EnumerableWithIndex[*Set[int], int] = (*Set[int])(nil)
+
+// Each calls the given function once for each element, passing that element's index and value.
+func (set *Set[V]) Each(f func(index int, value V)) {
+	iterator := set.Iterator()
+	for iterator.Next() {
+		f(iterator.Index(), iterator.Value())
+	}
+}
+
+// Map invokes the given function once for each element and returns a
+// container containing the values returned by the given function.
+func (set *Set[V]) Map(f func(index int, value V) V) *Set[V] {
+	values := make([]V, 0, set.Size())
+	iterator := set.Iterator()
+	for iterator.Next() {
+		values = append(values, f(iterator.Index(), iterator.Value()))
+	}
+	newSet := set.empty()
+	newSet.Add(values...)
+	return newSet
+}
+
+// Select returns a new container containing all elements for which the given function returns a true value.
+func (set *Set[V]) Select(f func(index int, value V) bool) *Set[V] {
+	newSet := set.empty()
+	iterator := set.Iterator()
+	for iterator.Next() {
+		if f(iterator.Index(), iterator.Value()) {
+			newSet.Add(iterator.Value())
+		}
+	}
+	return newSet
+}
+
+// Any passes each element of the container to the given function and
+// returns true if the function ever returns true for any element.
+func (set *Set[V]) Any(f func(index int, value V) bool) bool {
+	iterator := set.Iterator()
+	for iterator.Next() {
+		if f(iterator.Index(), iterator.Value()) {
+			return true
+		}
+	}
+	return false
+}
+
+// All passes each element of the container to the given function and
+// returns true if the function returns true for all elements.
+func (set *Set[V]) All(f func(index int, value V) bool) bool {
+	iterator := set.Iterator()
+	for iterator.Next() {
+		if !f(iterator.Index(), iterator.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find passes each element of the container to the given function and returns
+// the first (index,value) for which the function is true or -1,nil otherwise
+// if no element matches the criteria.
+func (set *Set[V]) Find(f func(index int, value V) bool) (int, V, bool) {
+	iterator := set.Iterator()
+	for iterator.Next() {
+		if f(iterator.Index(), iterator.Value()) {
+			return iterator.Index(), iterator.Value(), true
+		}
+	}
+	var zeroV V
+	return -1, zeroV, false
+}
+
+// empty returns a new empty set ordering the values as the set does, also when instantiated by New.
+func (set *Set[V]) empty() *Set[V] {
+	m := *set.m
+	m.Clear()
+	return &Set[V]{m: &m}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package flatset implements a set backed by a sorted slice, see flatmap.
+//
+// Items are ordered in the set. Adding several items at once merges them into the set in a single pass.
+//
+// Structure is not thread safe.
+//
+// Reference: http://en.wikipedia.org/wiki/Set_%28abstract_data_type%29
+package flatset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/maps/flatmap"
+	"github.com/monitor1379/yagods/sets"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ sets.Set[int] = (*Set[int])(nil)
+
+// Set holds elements in a flat map
+type Set[V any] struct {
+	m *flatmap.Map[V, struct{}]
+}
+
+// New instantiates a new set ordering the values by the < and > operators, see utils.OrderedComparator,
+// and adds the values to it. Looking values up compares them with the operators instead of calling the comparator.
+func New[V utils.Ordered](values ...V) *Set[V] {
+	set := &Set[V]{m: flatmap.New[V, struct{}]()}
+	set.Add(values...)
+	return set
+}
+
+// NewWith instantiates a new set with the custom comparator and adds the values to it.
+func NewWith[V any](comparator utils.Comparator[V], values ...V) *Set[V] {
+	set := &Set[V]{m: flatmap.NewWith[V, struct{}](comparator)}
+	set.Add(values...)
+	return set
+}
+
+// NewWithIntComparator instantiates a new set with the IntComparator, i.e. keys are of type int.
+func NewWithIntComparator(values ...int) *Set[int] {
+	set := &Set[int]{m: flatmap.NewWithIntComparator[struct{}]()}
+	set.Add(values...)
+	return set
+}
+
+// NewWithStringComparator instantiates a new set with the StringComparator, i.e. keys are of type string.
+func NewWithStringComparator(values ...string) *Set[string] {
+	set := &Set[string]{m: flatmap.NewWithStringComparator[struct{}]()}
+	set.Add(values...)
+	return set
+}
+
+// FromSorted instantiates a set with the custom comparator holding a copy of the items in O(n) time.
+// Returns an error wrapping containers.ErrNotSorted if items are not in strictly ascending order.
+func FromSorted[V any](comparator utils.Comparator[V], items []V) (*Set[V], error) {
+	m, err := flatmap.FromSorted(comparator, items, make([]struct{}, len(items)))
+	if err != nil {
+		return nil, err
+	}
+	return &Set[V]{m: m}, nil
+}
+
+// BulkLoad instantiates a set with the custom comparator holding the remaining values
+// of the iterator in O(n) time. See FromSorted.
+func BulkLoad[V any](comparator utils.Comparator[V], iterator containers.Iterator[V]) (*Set[V], error) {
+	var items []V
+	for iterator.Next() {
+		items = append(items, iterator.Value())
+	}
+	return FromSorted(comparator, items)
+}
+
+// Add adds the items (one or more) to the set.
+// Several items are sorted and merged into the set in a single pass, see flatmap.Map.PutAll.
+func (set *Set[V]) Add(items ...V) {
+	if len(items) > 0 {
+		set.m.PutAll(items, make([]struct{}, len(items)))
+	}
+}
+
+// Remove removes the items (one or more) from the set.
+func (set *Set[V]) Remove(items ...V) {
+	for _, item := range items {
+		set.m.Remove(item)
+	}
+}
+
+// Contains checks weather items (one or more) are present in the set.
+// All items have to be present in the set for the method to return true.
+// Returns true if no arguments are passed at all, i.e. set is always superset of empty set.
+func (set *Set[V]) Contains(items ...V) bool {
+	for _, item := range items {
+		if _, contains := set.m.Get(item); !contains {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty returns true if set does not contain any elements.
+func (set *Set[V]) Empty() bool {
+	return set.Size() == 0
+}
+
+// Size returns number of elements within the set.
+func (set *Set[V]) Size() int {
+	if set.m == nil {
+		return 0
+	}
+	return set.m.Size()
+}
+
+// Clear clears all values in the set.
+func (set *Set[V]) Clear() {
+	set.m.Clear()
+}
+
+// Values returns all items in the set in order.
+func (set *Set[V]) Values() []V {
+	if set.m == nil {
+		return []V{}
+	}
+	return set.m.Keys()
+}
+
+// InterfaceValues returns all elements in the set as type interface{}.
+func (set *Set[V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, set.Size())
+	for i, value := range set.Values() {
+		values[i] = value
+	}
+	return values
+}
+
+// Min returns the minimum item of the set and true, or false if the set is empty.
+func (set *Set[V]) Min() (item V, found bool) {
+	if set.Empty() {
+		return item, false
+	}
+	item, _ = set.m.Min()
+	return item, true
+}
+
+// Max returns the maximum item of the set and true, or false if the set is empty.
+func (set *Set[V]) Max() (item V, found bool) {
+	if set.Empty() {
+		return item, false
+	}
+	item, _ = set.m.Max()
+	return item, true
+}
+
+// Floor finds the largest item that is smaller than or equal to the given item.
+// Second return parameter is false if there is no such item, either because the set is empty,
+// or because all items in the set are larger than the given item.
+// Item should adhere to the comparator's type assertion, otherwise method panics.
+func (set *Set[V]) Floor(item V) (V, bool) {
+	floor, _, found := set.m.Floor(item)
+	return floor, found
+}
+
+// Ceiling finds the smallest item that is larger than or equal to the given item.
+// Second return parameter is false if there is no such item, either because the set is empty,
+// or because all items in the set are smaller than the given item.
+// Item should adhere to the comparator's type assertion, otherwise method panics.
+func (set *Set[V]) Ceiling(item V) (V, bool) {
+	ceiling, _, found := set.m.Ceiling(item)
+	return ceiling, found
+}
+
+// RemoveRange removes all items within [from, to) from the set.
+// Items should adhere to the comparator's type assertion, otherwise method panics.
+func (set *Set[V]) RemoveRange(from V, to V) {
+	set.m.RemoveRange(from, to)
+}
+
+// Split moves all items less than the given item into the left set and
+// all other items into the right set. The set itself is left empty.
+// Item should adhere to the comparator's type assertion, otherwise method panics.
+func (set *Set[V]) Split(item V) (left *Set[V], right *Set[V]) {
+	leftMap, rightMap := set.m.Split(item)
+	return &Set[V]{m: leftMap}, &Set[V]{m: rightMap}
+}
+
+// Join moves all items of the left and the right set into a new set using left's comparator
+// and leaves both sets empty. All items of the left set should be less than all items of the right set,
+// otherwise method panics.
+func Join[V any](left *Set[V], right *Set[V]) *Set[V] {
+	return &Set[V]{m: flatmap.Join(left.m, right.m)}
+}
+
+// String returns a string representation of container
+func (set *Set[V]) String() string {
+	str := "FlatSet\n"
+	items := []string{}
+	for _, v := range set.Values() {
+		items = append(items, fmt.Sprintf("%v", v))
+	}
+	str += strings.Join(items, ", ")
+	return str
+}
@@ -0,0 +1,803 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatset_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/sets"
+	"github.com/monitor1379/yagods/sets/flatset"
+	"github.com/monitor1379/yagods/utils"
+)
+
+func TestSetNew(t *testing.T) {
+	set := flatset.NewWithIntComparator(2, 1)
+	if actualValue := set.Size(); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+	values := set.Values()
+	if actualValue := values[0]; actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	if actualValue := values[1]; actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+}
+
+func TestSetAdd(t *testing.T) {
+	set := flatset.NewWithIntComparator()
+	set.Add()
+	set.Add(1)
+	set.Add(2)
+	set.Add(2, 3)
+	set.Add()
+	if actualValue := set.Empty(); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	if actualValue := set.Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+	if actualValue, expectedValue := fmt.Sprintf("%d%d%d", toInterfaces(set.Values())...), "123"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func toInterfaces[T any](ts []T) []interface{} {
+	is := make([]interface{}, 0)
+	for _, t := range ts {
+		is = append(is, t)
+	}
+	return is
+}
+
+func TestSetContains(t *testing.T) {
+	set := flatset.NewWithIntComparator()
+	set.Add(3, 1, 2)
+	if actualValue := set.Contains(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(1); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(1, 2, 3); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(1, 2, 3, 4); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+}
+
+func TestSetRemove(t *testing.T) {
+	set := flatset.NewWithIntComparator()
+	set.Add(3, 1, 2)
+	set.Remove()
+	if actualValue := set.Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+	set.Remove(1)
+	if actualValue := set.Size(); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+	set.Remove(3)
+	set.Remove(3)
+	set.Remove()
+	set.Remove(2)
+	if actualValue := set.Size(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+}
+
+func TestSetEach(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+	set.Each(func(index int, value string) {
+		switch index {
+		case 0:
+			if actualValue, expectedValue := value, "a"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case 1:
+			if actualValue, expectedValue := value, "b"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case 2:
+			if actualValue, expectedValue := value, "c"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		default:
+			t.Errorf("Too many")
+		}
+	})
+}
+
+func TestSetMap(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+	mappedSet := set.Map(func(index int, value string) string {
+		return "mapped: " + value
+	})
+	if actualValue, expectedValue := mappedSet.Contains("mapped: a", "mapped: b", "mapped: c"), true; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := mappedSet.Contains("mapped: a", "mapped: b", "mapped: x"), false; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if mappedSet.Size() != 3 {
+		t.Errorf("Got %v expected %v", mappedSet.Size(), 3)
+	}
+}
+
+func TestSetSelect(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+	selectedSet := set.Select(func(index int, value string) bool {
+		return value >= "a" && value <= "b"
+	})
+	if actualValue, expectedValue := selectedSet.Contains("a", "b"), true; actualValue != expectedValue {
+		fmt.Println("A: ", selectedSet.Contains("b"))
+		t.Errorf("Got %v (%v) expected %v (%v)", actualValue, selectedSet.Values(), expectedValue, "[a b]")
+	}
+	if actualValue, expectedValue := selectedSet.Contains("a", "b", "c"), false; actualValue != expectedValue {
+		t.Errorf("Got %v (%v) expected %v (%v)", actualValue, selectedSet.Values(), expectedValue, "[a b]")
+	}
+	if selectedSet.Size() != 2 {
+		t.Errorf("Got %v expected %v", selectedSet.Size(), 3)
+	}
+}
+
+func TestSetAny(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+	any := set.Any(func(index int, value string) bool {
+		return value == "c"
+	})
+	if any != true {
+		t.Errorf("Got %v expected %v", any, true)
+	}
+	any = set.Any(func(index int, value string) bool {
+		return value == "x"
+	})
+	if any != false {
+		t.Errorf("Got %v expected %v", any, false)
+	}
+}
+
+func TestSetAll(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+	all := set.All(func(index int, value string) bool {
+		return value >= "a" && value <= "c"
+	})
+	if all != true {
+		t.Errorf("Got %v expected %v", all, true)
+	}
+	all = set.All(func(index int, value string) bool {
+		return value >= "a" && value <= "b"
+	})
+	if all != false {
+		t.Errorf("Got %v expected %v", all, false)
+	}
+}
+
+func TestSetFind(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+	foundIndex, foundValue, found := set.Find(func(index int, value string) bool {
+		return value == "c"
+	})
+	if foundValue != "c" || foundIndex != 2 || !found {
+		t.Errorf("Got %v at %v expected %v at %v", foundValue, foundIndex, "c", 2)
+	}
+	foundIndex, foundValue, found = set.Find(func(index int, value string) bool {
+		return value == "x"
+	})
+	if foundValue != "" || foundIndex != -1 || found {
+		t.Errorf("Got %v at %v expected %v at %v", foundValue, foundIndex, nil, nil)
+	}
+}
+
+func TestSetChaining(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+}
+
+func TestSetIteratorNextOnEmpty(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	it := set.Iterator()
+	for it.Next() {
+		t.Errorf("Shouldn't iterate on empty set")
+	}
+}
+
+func TestSetIteratorPrevOnEmpty(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	it := set.Iterator()
+	for it.Prev() {
+		t.Errorf("Shouldn't iterate on empty set")
+	}
+}
+
+func TestSetIteratorNext(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+	it := set.Iterator()
+	count := 0
+	for it.Next() {
+		count++
+		index := it.Index()
+		value := it.Value()
+		switch index {
+		case 0:
+			if actualValue, expectedValue := value, "a"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case 1:
+			if actualValue, expectedValue := value, "b"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case 2:
+			if actualValue, expectedValue := value, "c"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		default:
+			t.Errorf("Too many")
+		}
+		if actualValue, expectedValue := index, count-1; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if actualValue, expectedValue := count, 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetIteratorPrev(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("c", "a", "b")
+	it := set.Iterator()
+	for it.Prev() {
+	}
+	count := 0
+	for it.Next() {
+		count++
+		index := it.Index()
+		value := it.Value()
+		switch index {
+		case 0:
+			if actualValue, expectedValue := value, "a"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case 1:
+			if actualValue, expectedValue := value, "b"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		case 2:
+			if actualValue, expectedValue := value, "c"; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+		default:
+			t.Errorf("Too many")
+		}
+		if actualValue, expectedValue := index, count-1; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if actualValue, expectedValue := count, 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetIteratorBegin(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	it := set.Iterator()
+	it.Begin()
+	set.Add("a", "b", "c")
+	for it.Next() {
+	}
+	it.Begin()
+	it.Next()
+	if index, value := it.Index(), it.Value(); index != 0 || value != "a" {
+		t.Errorf("Got %v,%v expected %v,%v", index, value, 0, "a")
+	}
+}
+
+func TestSetIteratorEnd(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	it := set.Iterator()
+
+	if index := it.Index(); index != -1 {
+		t.Errorf("Got %v expected %v", index, -1)
+	}
+
+	it.End()
+	if index := it.Index(); index != 0 {
+		t.Errorf("Got %v expected %v", index, 0)
+	}
+
+	set.Add("a", "b", "c")
+	it.End()
+	if index := it.Index(); index != set.Size() {
+		t.Errorf("Got %v expected %v", index, set.Size())
+	}
+
+	it.Prev()
+	if index, value := it.Index(), it.Value(); index != set.Size()-1 || value != "c" {
+		t.Errorf("Got %v,%v expected %v,%v", index, value, set.Size()-1, "c")
+	}
+}
+
+func TestSetIteratorFirst(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("a", "b", "c")
+	it := set.Iterator()
+	if actualValue, expectedValue := it.First(), true; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if index, value := it.Index(), it.Value(); index != 0 || value != "a" {
+		t.Errorf("Got %v,%v expected %v,%v", index, value, 0, "a")
+	}
+}
+
+func TestSetIteratorLast(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("a", "b", "c")
+	it := set.Iterator()
+	if actualValue, expectedValue := it.Last(), true; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if index, value := it.Index(), it.Value(); index != 2 || value != "c" {
+		t.Errorf("Got %v,%v expected %v,%v", index, value, 2, "c")
+	}
+}
+
+func TestSetIteratorRemove(t *testing.T) {
+	set := flatset.NewWithIntComparator(1, 2, 3, 4, 5, 6)
+	it := set.Iterator()
+	for it.Next() {
+		if it.Value()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := set.Contains(2); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	it.Last()
+	it.Remove()
+	if actualValue := it.Prev(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "1 3"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	set.Add(7)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetIteratorSeek(t *testing.T) {
+	set := flatset.NewWithIntComparator(10, 20, 30, 40)
+	it := set.IteratorAt(15)
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "1 20"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := it.Next(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "2 30"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it.SeekPrev(35)
+	it.Prev()
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "1 20"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := it.Seek(50); actualValue != false || it.Index() != 4 {
+		t.Errorf("Got %v expected %v", it.Index(), 4)
+	}
+	if actualValue := it.SeekPrev(5); actualValue != false || it.Index() != -1 {
+		t.Errorf("Got %v expected %v", it.Index(), -1)
+	}
+	it.Seek(40)
+	if actualValue := it.Next(); actualValue != false || it.Index() != 4 {
+		t.Errorf("Got %v expected %v", it.Index(), 4)
+	}
+}
+
+func TestSetRemoveRangeSplitJoin(t *testing.T) {
+	set := flatset.NewWithIntComparator(1, 2, 3, 4, 5, 6)
+	set.RemoveRange(2, 4)
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 4 5 6]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	left, right := set.Split(5)
+	if actualValue, expectedValue := fmt.Sprint(left.Values(), right.Values(), set.Size()), "[1 4] [5 6] 0"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	set = flatset.Join(left, right)
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 4 5 6]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetFromSorted(t *testing.T) {
+	set, err := flatset.FromSorted(utils.NumberComparator[int], []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it := set.Iterator()
+	copied, err := flatset.BulkLoad[int](utils.NumberComparator[int], &it)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(copied.Values()), "[1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, err := flatset.FromSorted(utils.NumberComparator[int], []int{1, 1}); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+}
+
+func TestSetMinMaxFloorCeiling(t *testing.T) {
+	set := flatset.New[int]()
+	if _, found := set.Min(); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if _, found := set.Max(); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	set.Add(7, 3, 5, 1, 3)
+	if item, found := set.Min(); item != 1 || !found {
+		t.Errorf("Got %v %v expected %v %v", item, found, 1, true)
+	}
+	if item, found := set.Max(); item != 7 || !found {
+		t.Errorf("Got %v %v expected %v %v", item, found, 7, true)
+	}
+	tests := [][]interface{}{
+		{0, 0, false, 1, true},
+		{3, 3, true, 3, true},
+		{4, 3, true, 5, true},
+		{8, 7, true, 0, false},
+	}
+	for _, test := range tests {
+		floor, floorFound := set.Floor(test[0].(int))
+		ceiling, ceilingFound := set.Ceiling(test[0].(int))
+		if actualValue, expectedValue := fmt.Sprint(floor, floorFound, ceiling, ceilingFound), fmt.Sprint(test[1:]...); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+}
+
+func TestSetAddBatch(t *testing.T) {
+	set := flatset.NewWithStringComparator("d", "b")
+	set.Add("e", "a", "b", "c", "a")
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[a b c d e]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it := set.IteratorAt("c")
+	if actualValue, expectedValue := it.Index(), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetSerialization(t *testing.T) {
+	set := flatset.NewWithStringComparator()
+	set.Add("a", "b", "c")
+
+	var err error
+	assert := func() {
+		if actualValue, expectedValue := set.Size(), 3; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue := set.Contains("a", "b", "c"); actualValue != true {
+			t.Errorf("Got %v expected %v", actualValue, true)
+		}
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+
+	assert()
+
+	json, err := set.ToJSON()
+	assert()
+
+	err = set.FromJSON(json)
+	assert()
+}
+
+func TestSetBinarySerialization(t *testing.T) {
+	set := flatset.NewWithIntComparator()
+	set.Add(3, 1, 2)
+
+	data, err := set.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := flatset.NewWithIntComparator()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(set); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = flatset.NewWithIntComparator()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	zero := &flatset.Set[int]{}
+	if err := zero.UnmarshalBinary(data); err != nil || zero.Size() != restored.Size() {
+		t.Errorf("Got %v, %v expected %v", zero.Size(), err, restored.Size())
+	}
+	if err := (&flatset.Set[[2]int]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestSetJSONMarshaler(t *testing.T) {
+	set := flatset.NewWithIntComparator()
+	set.Add(3, 1, 2)
+
+	data, err := json.Marshal(struct{ Set *flatset.Set[int] }{set})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Set":[1,2,3]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Set flatset.Set[int] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Set.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Set":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&flatset.Set[[2]int]{}).UnmarshalJSON([]byte("[]")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestSetJSONStream(t *testing.T) {
+	set := flatset.NewWithIntComparator()
+	set.Add(3, 1, 2)
+
+	var buffer bytes.Buffer
+	if err := set.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `[1,2,3]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored flatset.Set[int]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestSetNewOrdered(t *testing.T) {
+	set := flatset.New(2.5, -1, 0.5, 2.5)
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[-1 0.5 2.5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := set.Contains(0.5, 2.5); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(1); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	mapped := set.Map(func(index int, value float64) float64 { return -value })
+	mapped.Add(0)
+	if actualValue, expectedValue := fmt.Sprint(mapped.Values()), "[-2.5 -0.5 0 1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	containertest.TestSet(t, func() sets.Set[int] { return flatset.New[int]() })
+}
+
+func TestSetConformance(t *testing.T) {
+	containertest.TestSet(t, func() sets.Set[int] { return flatset.NewWithIntComparator() })
+	containertest.TestReverseIteratorWithIndex(t, func(values []int) containers.ReverseIteratorWithIndex[int] {
+		it := flatset.NewWithIntComparator(values...).Iterator()
+		return &it
+	})
+}
+
+func FuzzSet(f *testing.F) {
+	containertest.FuzzSet(f, func() sets.Set[int] { return flatset.NewWithIntComparator() }, containertest.SortedOrder)
+}
+
+func FuzzSetNew(f *testing.F) {
+	containertest.FuzzSet(f, func() sets.Set[int] { return flatset.New[int]() }, containertest.SortedOrder)
+}
+
+func benchmarkContains(b *testing.B, set *flatset.Set[int], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			set.Contains(n)
+		}
+	}
+}
+
+func benchmarkAdd(b *testing.B, set *flatset.Set[int], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			set.Add(n)
+		}
+	}
+}
+
+func benchmarkRemove(b *testing.B, set *flatset.Set[int], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			set.Remove(n)
+		}
+	}
+}
+
+func BenchmarkFlatSetContains100(b *testing.B) {
+	b.StopTimer()
+	size := 100
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkContains(b, set, size)
+}
+
+func BenchmarkFlatSetContains1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkContains(b, set, size)
+}
+
+func BenchmarkFlatSetContains10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkContains(b, set, size)
+}
+
+func BenchmarkFlatSetContains100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkContains(b, set, size)
+}
+
+func BenchmarkFlatSetAdd100(b *testing.B) {
+	b.StopTimer()
+	size := 100
+	set := flatset.NewWithIntComparator()
+	b.StartTimer()
+	benchmarkAdd(b, set, size)
+}
+
+func BenchmarkFlatSetAdd1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkAdd(b, set, size)
+}
+
+func BenchmarkFlatSetAdd10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkAdd(b, set, size)
+}
+
+func BenchmarkFlatSetAdd100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkAdd(b, set, size)
+}
+
+func BenchmarkFlatSetRemove100(b *testing.B) {
+	b.StopTimer()
+	size := 100
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkRemove(b, set, size)
+}
+
+func BenchmarkFlatSetRemove1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkRemove(b, set, size)
+}
+
+func BenchmarkFlatSetRemove10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkRemove(b, set, size)
+}
+
+func BenchmarkFlatSetRemove100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	set := flatset.NewWithIntComparator()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	benchmarkRemove(b, set, size)
+}
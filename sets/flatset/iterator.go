@@ -0,0 +1,118 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatset
+
+import (
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/maps/flatmap"
+)
+
+var _ containers.ReverseIteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
+
+// Iterator returns a stateful iterator whose values can be fetched by an index.
+type Iterator[V any] struct {
+	iterator flatmap.Iterator[V, struct{}]
+}
+
+// Iterator holding the iterator's state
+func (set *Set[V]) Iterator() Iterator[V] {
+	return Iterator[V]{iterator: set.m.Iterator()}
+}
+
+// IteratorAt returns a stateful iterator whose values can be fetched by an index that is initialised
+// at the first element greater than or equal to the given value (see Seek()).
+func (set *Set[V]) IteratorAt(value V) Iterator[V] {
+	iterator := set.Iterator()
+	iterator.Seek(value)
+	return iterator
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the container.
+// If Next() returns true, then next element's index and value can be retrieved by Index() and Value().
+// If Next() was called for the first time, then it will point the iterator to the first element if it exists.
+// Modifies the state of the iterator.
+func (iterator *Iterator[V]) Next() bool {
+	return iterator.iterator.Next()
+}
+
+// Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
+// If Prev() returns true, then previous element's index and value can be retrieved by Index() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[V]) Prev() bool {
+	return iterator.iterator.Prev()
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Value() V {
+	return iterator.iterator.Key()
+}
+
+// Index returns the current element's index, in O(1) time also after Seek() or SeekPrev().
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Index() int {
+	return iterator.iterator.Index()
+}
+
+// Begin resets the iterator to its initial state (one-before-first)
+// Call Next() to fetch the first element if any.
+func (iterator *Iterator[V]) Begin() {
+	iterator.iterator.Begin()
+}
+
+// End moves the iterator past the last element (one-past-the-end).
+// Call Prev() to fetch the last element if any.
+func (iterator *Iterator[V]) End() {
+	iterator.iterator.End()
+}
+
+// First moves the iterator to the first element and returns true if there was a first element in the container.
+// If First() returns true, then first element's index and value can be retrieved by Index() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[V]) First() bool {
+	return iterator.iterator.First()
+}
+
+// Last moves the iterator to the last element and returns true if there was a last element in the container.
+// If Last() returns true, then last element's index and value can be retrieved by Index() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[V]) Last() bool {
+	return iterator.iterator.Last()
+}
+
+// Seek moves the iterator to the first element greater than or equal to the given value
+// and returns true if there was such an element in the set.
+// If Seek() returns true, then element's index and value can be retrieved by Index() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Value should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[V]) Seek(value V) bool {
+	return iterator.iterator.Seek(value)
+}
+
+// SeekPrev moves the iterator to the last element less than or equal to the given value
+// and returns true if there was such an element in the set.
+// If SeekPrev() returns true, then element's index and value can be retrieved by Index() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Value should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[V]) SeekPrev(value V) bool {
+	return iterator.iterator.SeekPrev(value)
+}
+
+// Err returns containers.ErrConcurrentModification if the set was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Err() error {
+	return iterator.iterator.Err()
+}
+
+// Remove removes the current element from the set and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[V]) Remove() {
+	iterator.iterator.Remove()
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatset
+
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/maps/flatmap"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ containers.JSONSerializer = (*Set[int])(nil)
+var _ containers.JSONDeserializer = (*Set[string])(nil)
+var _ encoding.BinaryMarshaler = (*Set[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*Set[int])(nil)
+var _ gob.GobEncoder = (*Set[int])(nil)
+var _ gob.GobDecoder = (*Set[int])(nil)
+var _ json.Marshaler = (*Set[int])(nil)
+var _ json.Unmarshaler = (*Set[int])(nil)
+var _ containers.JSONWriter = (*Set[int])(nil)
+var _ containers.JSONReader = (*Set[int])(nil)
+
+// ToJSON outputs the JSON representation of the set.
+func (set *Set[V]) ToJSON() ([]byte, error) {
+	return json.Marshal(set.Values())
+}
+
+// FromJSON populates the set from the input JSON representation.
+func (set *Set[V]) FromJSON(data []byte) error {
+	elements := []V{}
+	err := json.Unmarshal(data, &elements)
+	if err == nil {
+		set.Clear()
+		set.Add(elements...)
+	}
+	return err
+}
+
+// MarshalBinary outputs the binary representation of the set in order.
+// Items are encoded by the codec of containers.CodecFor.
+func (set *Set[V]) MarshalBinary() ([]byte, error) {
+	return containers.EncodeValues(containers.CodecFor[V](), set.Values())
+}
+
+// UnmarshalBinary populates the set from the input binary representation.
+// The zero value of a set is ordered by default comparators, see utils.DefaultComparator;
+// returns containers.ErrNoComparator if there are none.
+func (set *Set[V]) UnmarshalBinary(data []byte) error {
+	if err := set.initZero(); err != nil {
+		return err
+	}
+	values, err := containers.DecodeValues(containers.CodecFor[V](), data)
+	if err == nil {
+		set.Clear()
+		set.Add(values...)
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the set for encoding/gob, see MarshalBinary.
+func (set *Set[V]) GobEncode() ([]byte, error) {
+	return set.MarshalBinary()
+}
+
+// GobDecode populates the set from the binary representation for encoding/gob, see UnmarshalBinary.
+func (set *Set[V]) GobDecode(data []byte) error {
+	return set.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the set for encoding/json: an array of its items in order.
+func (set *Set[V]) MarshalJSON() ([]byte, error) {
+	if set.m == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(set.Values())
+}
+
+// UnmarshalJSON populates the set from the JSON representation for encoding/json, see MarshalJSON.
+// Like UnmarshalBinary, it accepts the zero value of a set.
+func (set *Set[V]) UnmarshalJSON(data []byte) error {
+	if err := set.initZero(); err != nil {
+		return err
+	}
+	var values []V
+	err := json.Unmarshal(data, &values)
+	if err == nil {
+		set.Clear()
+		set.Add(values...)
+	}
+	return err
+}
+
+// initZero creates the map of a set that was not instantiated by a constructor,
+// ordered by the default comparator of its items.
+func (set *Set[V]) initZero() error {
+	if set.m == nil {
+		comparator := utils.DefaultComparator[V]()
+		if comparator == nil {
+			return containers.ErrNoComparator
+		}
+		set.m = flatmap.NewWith[V, struct{}](comparator)
+	}
+	return nil
+}
+
+// WriteJSON writes the JSON representation of the set to the writer in order, encoding one item at a time.
+func (set *Set[V]) WriteJSON(w io.Writer) error {
+	if set.m == nil {
+		_, err := io.WriteString(w, "[]")
+		return err
+	}
+	it := set.Iterator()
+	return containers.WriteJSONValues[V](w, &it)
+}
+
+// ReadJSON populates the set from the JSON representation read from the reader, merging the items
+// into the set once they are all decoded. Like UnmarshalJSON, it accepts the zero value of a set.
+// On error, the set holds the items read before the error.
+func (set *Set[V]) ReadJSON(r io.Reader) error {
+	if err := set.initZero(); err != nil {
+		return err
+	}
+	var items []V
+	err := containers.ReadJSONValues(r, func(item V) {
+		items = append(items, item)
+	})
+	set.Clear()
+	set.Add(items...)
+	return err
+}
@@ -5,8 +5,17 @@
 package hashset_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/sets"
 	"github.com/monitor1379/yagods/sets/hashset"
 )
 
@@ -107,6 +116,96 @@ func TestSetSerialization(t *testing.T) {
 	assert()
 }
 
+func TestSetBinarySerialization(t *testing.T) {
+	set := hashset.New[int]()
+	set.Add(1, 2, 3)
+
+	data, err := set.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := hashset.New[int]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Size(), restored.Contains(1, 2, 3)), fmt.Sprint(set.Size(), set.Contains(1, 2, 3)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(set); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = hashset.New[int]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Size(), restored.Contains(1, 2, 3)), fmt.Sprint(set.Size(), set.Contains(1, 2, 3)); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestSetJSONMarshaler(t *testing.T) {
+	set := hashset.New[string]()
+	set.Add("a")
+
+	data, err := json.Marshal(struct{ Set *hashset.Set[string] }{set})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Set":["a"]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Set hashset.Set[string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Set.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Set":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestSetJSONStream(t *testing.T) {
+	set := hashset.New[string]()
+	set.Add("a")
+
+	var buffer bytes.Buffer
+	if err := set.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `["a"]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored hashset.Set[string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestSetConformance(t *testing.T) {
+	containertest.TestSet(t, func() sets.Set[int] { return hashset.New[int]() })
+}
+
+func FuzzSet(f *testing.F) {
+	containertest.FuzzSet(f, func() sets.Set[int] { return hashset.New[int]() }, containertest.Unordered)
+}
+
 func benchmarkContains(b *testing.B, set *hashset.Set[int], size int) {
 	for i := 0; i < b.N; i++ {
 		for n := 0; n < size; n++ {
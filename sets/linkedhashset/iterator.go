@@ -10,15 +10,17 @@ import (
 )
 
 var _ containers.ReverseIteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
 
 // Iterator holding the iterator's state
 type Iterator[V comparable] struct {
 	iterator doublylinkedlist.Iterator[V]
+	set      *Set[V]
 }
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
 func (set *Set[V]) Iterator() Iterator[V] {
-	return Iterator[V]{iterator: set.ordering.Iterator()}
+	return Iterator[V]{iterator: set.ordering.Iterator(), set: set}
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -73,3 +75,25 @@ func (iterator *Iterator[V]) First() bool {
 func (iterator *Iterator[V]) Last() bool {
 	return iterator.iterator.Last()
 }
+
+// Err returns containers.ErrConcurrentModification if the set was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Err() error {
+	return iterator.iterator.Err()
+}
+
+// Remove removes the current element from the set and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[V]) Remove() {
+	var item V
+	size := iterator.set.Size()
+	if index := iterator.iterator.Index(); index >= 0 && index < size {
+		item = iterator.iterator.Value()
+	}
+	iterator.iterator.Remove()
+	if iterator.set.Size() < size {
+		delete(iterator.set.table, item)
+	}
+}
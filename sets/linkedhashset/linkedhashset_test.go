@@ -5,9 +5,17 @@
 package linkedhashset_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/sets"
 	"github.com/monitor1379/yagods/sets/linkedhashset"
 )
 
@@ -334,6 +342,34 @@ func TestSetIteratorLast(t *testing.T) {
 	}
 }
 
+func TestSetIteratorRemove(t *testing.T) {
+	set := linkedhashset.New(1, 2, 3, 4, 5, 6)
+	it := set.Iterator()
+	for it.Next() {
+		if it.Value()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := set.Contains(2); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	it.Last()
+	it.Remove()
+	if actualValue := it.Prev(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "1 3"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	set.Add(7)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestSetSerialization(t *testing.T) {
 	set := linkedhashset.New[string]()
 	set.Add("a", "b", "c")
@@ -360,6 +396,100 @@ func TestSetSerialization(t *testing.T) {
 	assert()
 }
 
+func TestSetBinarySerialization(t *testing.T) {
+	set := linkedhashset.New[string]()
+	set.Add("c", "a", "b")
+
+	data, err := set.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := linkedhashset.New[string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(set); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = linkedhashset.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestSetJSONMarshaler(t *testing.T) {
+	set := linkedhashset.New[string]()
+	set.Add("b", "a", "c")
+
+	data, err := json.Marshal(struct{ Set *linkedhashset.Set[string] }{set})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Set":["b","a","c"]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Set linkedhashset.Set[string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Set.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Set":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestSetJSONStream(t *testing.T) {
+	set := linkedhashset.New[string]()
+	set.Add("b", "a", "c")
+
+	var buffer bytes.Buffer
+	if err := set.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `["b","a","c"]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored linkedhashset.Set[string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestSetConformance(t *testing.T) {
+	containertest.TestSet(t, func() sets.Set[int] { return linkedhashset.New[int]() })
+	containertest.TestReverseIteratorWithIndex(t, func(values []int) containers.ReverseIteratorWithIndex[int] {
+		it := linkedhashset.New(values...).Iterator()
+		return &it
+	})
+}
+
+func FuzzSet(f *testing.F) {
+	containertest.FuzzSet(f, func() sets.Set[int] { return linkedhashset.New[int]() }, containertest.InsertionOrder)
+}
+
 func benchmarkContains(b *testing.B, set *linkedhashset.Set[int], size int) {
 	for i := 0; i < b.N; i++ {
 		for n := 0; n < size; n++ {
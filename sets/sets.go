@@ -12,7 +12,7 @@ package sets
 import "github.com/monitor1379/yagods/containers"
 
 // Set interface that all sets implement
-type Set[V comparable] interface {
+type Set[V any] interface {
 	Add(elements ...V)
 	Remove(elements ...V)
 	Contains(elements ...V) bool
@@ -4,10 +4,7 @@
 
 package treeset
 
-import (
-	"github.com/monitor1379/yagods/containers"
-	rbt "github.com/monitor1379/yagods/trees/redblacktree"
-)
+import "github.com/monitor1379/yagods/containers"
 
 var _ containers.EnumerableWithIndex[*Set[int], int] = (*Set[int])(nil)
 
@@ -22,7 +19,7 @@ func (set *Set[V]) Each(f func(index int, value V)) {
 // Map invokes the given function once for each element and returns a
 // container containing the values returned by the given function.
 func (set *Set[V]) Map(f func(index int, value V) V) *Set[V] {
-	newSet := &Set[V]{tree: rbt.NewWith[V, struct{}](set.tree.Comparator)}
+	newSet := set.empty()
 	iterator := set.Iterator()
 	for iterator.Next() {
 		newSet.Add(f(iterator.Index(), iterator.Value()))
@@ -32,7 +29,7 @@ func (set *Set[V]) Map(f func(index int, value V) V) *Set[V] {
 
 // Select returns a new container containing all elements for which the given function returns a true value.
 func (set *Set[V]) Select(f func(index int, value V) bool) *Set[V] {
-	newSet := &Set[V]{tree: rbt.NewWith[V, struct{}](set.tree.Comparator)}
+	newSet := set.empty()
 	iterator := set.Iterator()
 	for iterator.Next() {
 		if f(iterator.Index(), iterator.Value()) {
@@ -79,3 +76,10 @@ func (set *Set[V]) Find(f func(index int, value V) bool) (int, V, bool) {
 	var zeroV V
 	return -1, zeroV, false
 }
+
+// empty returns a new empty set ordering the values as the set does, also when instantiated by New.
+func (set *Set[V]) empty() *Set[V] {
+	tree := *set.tree
+	tree.Clear()
+	return &Set[V]{tree: &tree}
+}
@@ -10,38 +10,70 @@ import (
 )
 
 var _ containers.ReverseIteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
 type Iterator[V comparable] struct {
 	index    int
 	iterator rbt.Iterator[V, struct{}]
 	tree     *rbt.Tree[V, struct{}]
+	removed  bool // current element was removed, index points to its predecessor
 }
 
+// unknownIndex marks the index of an iterator moved by Seek() or SeekPrev(), computed on demand by Index()
+const unknownIndex = -2
+
 // Iterator holding the iterator's state
 func (set *Set[V]) Iterator() Iterator[V] {
 	return Iterator[V]{index: -1, iterator: set.tree.Iterator(), tree: set.tree}
 }
 
+// IteratorAt returns a stateful iterator whose values can be fetched by an index that is initialised
+// at the first element greater than or equal to the given value (see Seek()).
+func (set *Set[V]) IteratorAt(value V) Iterator[V] {
+	iterator := set.Iterator()
+	iterator.Seek(value)
+	return iterator
+}
+
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
 // If Next() returns true, then next element's index and value can be retrieved by Index() and Value().
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
 // Modifies the state of the iterator.
 func (iterator *Iterator[V]) Next() bool {
-	if iterator.index < iterator.tree.Size() {
+	if err := iterator.iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.removed = false
+	if iterator.index != unknownIndex && iterator.index < iterator.tree.Size() {
 		iterator.index++
 	}
-	return iterator.iterator.Next()
+	if !iterator.iterator.Next() {
+		iterator.index = iterator.tree.Size()
+		return false
+	}
+	return true
 }
 
 // Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
 // If Prev() returns true, then previous element's index and value can be retrieved by Index() and Value().
 // Modifies the state of the iterator.
 func (iterator *Iterator[V]) Prev() bool {
-	if iterator.index >= 0 {
+	if err := iterator.iterator.Err(); err != nil {
+		panic(err)
+	}
+	if iterator.removed {
+		iterator.removed = false
+		return iterator.iterator.Prev()
+	}
+	if iterator.index != unknownIndex && iterator.index >= 0 {
 		iterator.index--
 	}
-	return iterator.iterator.Prev()
+	if !iterator.iterator.Prev() {
+		iterator.index = -1
+		return false
+	}
+	return true
 }
 
 // Value returns the current element's value.
@@ -51,8 +83,15 @@ func (iterator *Iterator[V]) Value() V {
 }
 
 // Index returns the current element's index.
+// After Seek() or SeekPrev() the index is computed on the first call in O(n) time.
 // Does not modify the state of the iterator.
 func (iterator *Iterator[V]) Index() int {
+	if iterator.index == unknownIndex {
+		iterator.index = 0
+		for it := iterator.tree.Iterator(); it.Next() && iterator.tree.Comparator(it.Key(), iterator.iterator.Key()) < 0; {
+			iterator.index++
+		}
+	}
 	return iterator.index
 }
 
@@ -60,6 +99,7 @@ func (iterator *Iterator[V]) Index() int {
 // Call Next() to fetch the first element if any.
 func (iterator *Iterator[V]) Begin() {
 	iterator.index = -1
+	iterator.removed = false
 	iterator.iterator.Begin()
 }
 
@@ -67,6 +107,7 @@ func (iterator *Iterator[V]) Begin() {
 // Call Prev() to fetch the last element if any.
 func (iterator *Iterator[V]) End() {
 	iterator.index = iterator.tree.Size()
+	iterator.removed = false
 	iterator.iterator.End()
 }
 
@@ -85,3 +126,54 @@ func (iterator *Iterator[V]) Last() bool {
 	iterator.End()
 	return iterator.Prev()
 }
+
+// Seek moves the iterator to the first element greater than or equal to the given value
+// and returns true if there was such an element in the set.
+// If Seek() returns true, then element's value can be retrieved by Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Value should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[V]) Seek(value V) bool {
+	iterator.End()
+	if iterator.iterator.Seek(value) {
+		iterator.index = unknownIndex
+		return true
+	}
+	return false
+}
+
+// SeekPrev moves the iterator to the last element less than or equal to the given value
+// and returns true if there was such an element in the set.
+// If SeekPrev() returns true, then element's value can be retrieved by Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Value should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[V]) SeekPrev(value V) bool {
+	iterator.Begin()
+	if iterator.iterator.SeekPrev(value) {
+		iterator.index = unknownIndex
+		return true
+	}
+	return false
+}
+
+// Err returns containers.ErrConcurrentModification if the set was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Err() error {
+	return iterator.iterator.Err()
+}
+
+// Remove removes the current element from the set and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[V]) Remove() {
+	size := iterator.tree.Size()
+	iterator.iterator.Remove()
+	if iterator.tree.Size() < size {
+		if iterator.index != unknownIndex {
+			iterator.index--
+		}
+		iterator.removed = true
+	}
+}
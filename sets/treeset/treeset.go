@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/monitor1379/yagods/containers"
 	"github.com/monitor1379/yagods/sets"
 	rbt "github.com/monitor1379/yagods/trees/redblacktree"
 	"github.com/monitor1379/yagods/utils"
@@ -27,6 +28,16 @@ type Set[V comparable] struct {
 
 var itemExists = struct{}{}
 
+// New instantiates a new set ordering the values by the < and > operators, see utils.OrderedComparator,
+// and adds the values to it. Looking values up compares them with the operators instead of calling the comparator.
+func New[V utils.Ordered](values ...V) *Set[V] {
+	set := &Set[V]{tree: rbt.New[V, struct{}]()}
+	if len(values) > 0 {
+		set.Add(values...)
+	}
+	return set
+}
+
 // NewWith instantiates a new empty set with the custom comparator.
 func NewWith[V comparable](comparator utils.Comparator[V], values ...V) *Set[V] {
 	set := &Set[V]{tree: rbt.NewWith[V, struct{}](comparator)}
@@ -54,6 +65,26 @@ func NewWithStringComparator(values ...string) *Set[string] {
 	return set
 }
 
+// FromSorted instantiates a set with the custom comparator holding the items in O(n) time.
+// Returns an error wrapping containers.ErrNotSorted if items are not in strictly ascending order.
+func FromSorted[V comparable](comparator utils.Comparator[V], items []V) (*Set[V], error) {
+	tree, err := rbt.FromSorted(comparator, items, make([]struct{}, len(items)))
+	if err != nil {
+		return nil, err
+	}
+	return &Set[V]{tree: tree}, nil
+}
+
+// BulkLoad instantiates a set with the custom comparator holding the remaining values
+// of the iterator in O(n) time. See FromSorted.
+func BulkLoad[V comparable](comparator utils.Comparator[V], iterator containers.Iterator[V]) (*Set[V], error) {
+	var items []V
+	for iterator.Next() {
+		items = append(items, iterator.Value())
+	}
+	return FromSorted(comparator, items)
+}
+
 // Add adds the items (one or more) to the set.
 func (set *Set[V]) Add(items ...V) {
 	for _, item := range items {
@@ -109,6 +140,28 @@ func (set *Set[V]) InterfaceValues() []interface{} {
 	return values
 }
 
+// RemoveRange removes all items within [from, to) from the set.
+// Runs in O(log n + k) time, k being the number of removed items.
+// Items should adhere to the comparator's type assertion, otherwise method panics.
+func (set *Set[V]) RemoveRange(from V, to V) {
+	set.tree.RemoveRange(from, to)
+}
+
+// Split moves all items less than the given item into the left set and
+// all other items into the right set. The set itself is left empty.
+// Item should adhere to the comparator's type assertion, otherwise method panics.
+func (set *Set[V]) Split(item V) (left *Set[V], right *Set[V]) {
+	leftTree, rightTree := set.tree.Split(item)
+	return &Set[V]{tree: leftTree}, &Set[V]{tree: rightTree}
+}
+
+// Join moves all items of the left and the right set into a new set using left's comparator
+// and leaves both sets empty. All items of the left set should be less than all items of the right set,
+// otherwise method panics.
+func Join[V comparable](left *Set[V], right *Set[V]) *Set[V] {
+	return &Set[V]{tree: rbt.Join(left.tree, right.tree)}
+}
+
 // String returns a string representation of container
 func (set *Set[V]) String() string {
 	str := "TreeSet\n"
@@ -5,10 +5,19 @@
 package treeset_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/sets"
 	"github.com/monitor1379/yagods/sets/treeset"
+	"github.com/monitor1379/yagods/utils"
 )
 
 func TestSetNew(t *testing.T) {
@@ -351,6 +360,100 @@ func TestSetIteratorLast(t *testing.T) {
 	}
 }
 
+func TestSetIteratorRemove(t *testing.T) {
+	set := treeset.NewWithIntComparator(1, 2, 3, 4, 5, 6)
+	it := set.Iterator()
+	for it.Next() {
+		if it.Value()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 3 5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := set.Contains(2); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	it.Last()
+	it.Remove()
+	if actualValue := it.Prev(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "1 3"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	set.Add(7)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetIteratorSeek(t *testing.T) {
+	set := treeset.NewWithIntComparator(10, 20, 30, 40)
+	it := set.IteratorAt(15)
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "1 20"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := it.Next(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "2 30"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it.SeekPrev(35)
+	it.Prev()
+	if actualValue, expectedValue := fmt.Sprint(it.Index(), it.Value()), "1 20"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := it.Seek(50); actualValue != false || it.Index() != 4 {
+		t.Errorf("Got %v expected %v", it.Index(), 4)
+	}
+	if actualValue := it.SeekPrev(5); actualValue != false || it.Index() != -1 {
+		t.Errorf("Got %v expected %v", it.Index(), -1)
+	}
+	it.Seek(40)
+	if actualValue := it.Next(); actualValue != false || it.Index() != 4 {
+		t.Errorf("Got %v expected %v", it.Index(), 4)
+	}
+}
+
+func TestSetRemoveRangeSplitJoin(t *testing.T) {
+	set := treeset.NewWithIntComparator(1, 2, 3, 4, 5, 6)
+	set.RemoveRange(2, 4)
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 4 5 6]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	left, right := set.Split(5)
+	if actualValue, expectedValue := fmt.Sprint(left.Values(), right.Values(), set.Size()), "[1 4] [5 6] 0"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	set = treeset.Join(left, right)
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 4 5 6]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestSetFromSorted(t *testing.T) {
+	set, err := treeset.FromSorted(utils.NumberComparator[int], []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it := set.Iterator()
+	copied, err := treeset.BulkLoad[int](utils.NumberComparator[int], &it)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(copied.Values()), "[1 2 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if _, err := treeset.FromSorted(utils.NumberComparator[int], []int{1, 1}); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+}
+
 func TestSetSerialization(t *testing.T) {
 	set := treeset.NewWithStringComparator()
 	set.Add("a", "b", "c")
@@ -377,6 +480,133 @@ func TestSetSerialization(t *testing.T) {
 	assert()
 }
 
+func TestSetBinarySerialization(t *testing.T) {
+	set := treeset.NewWithIntComparator()
+	set.Add(3, 1, 2)
+
+	data, err := set.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := treeset.NewWithIntComparator()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(set); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = treeset.NewWithIntComparator()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	zero := &treeset.Set[int]{}
+	if err := zero.UnmarshalBinary(data); err != nil || zero.Size() != restored.Size() {
+		t.Errorf("Got %v, %v expected %v", zero.Size(), err, restored.Size())
+	}
+	if err := (&treeset.Set[[2]int]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestSetJSONMarshaler(t *testing.T) {
+	set := treeset.NewWithIntComparator()
+	set.Add(3, 1, 2)
+
+	data, err := json.Marshal(struct{ Set *treeset.Set[int] }{set})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Set":[1,2,3]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Set treeset.Set[int] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Set.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Set":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&treeset.Set[[2]int]{}).UnmarshalJSON([]byte("[]")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestSetJSONStream(t *testing.T) {
+	set := treeset.NewWithIntComparator()
+	set.Add(3, 1, 2)
+
+	var buffer bytes.Buffer
+	if err := set.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `[1,2,3]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored treeset.Set[int]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(set.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestSetNewOrdered(t *testing.T) {
+	set := treeset.New(2.5, -1, 0.5, 2.5)
+	if actualValue, expectedValue := fmt.Sprint(set.Values()), "[-1 0.5 2.5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := set.Contains(0.5, 2.5); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains(1); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	mapped := set.Map(func(index int, value float64) float64 { return -value })
+	mapped.Add(0)
+	if actualValue, expectedValue := fmt.Sprint(mapped.Values()), "[-2.5 -0.5 0 1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	containertest.TestSet(t, func() sets.Set[int] { return treeset.New[int]() })
+}
+
+func TestSetConformance(t *testing.T) {
+	containertest.TestSet(t, func() sets.Set[int] { return treeset.NewWithIntComparator() })
+	containertest.TestReverseIteratorWithIndex(t, func(values []int) containers.ReverseIteratorWithIndex[int] {
+		it := treeset.NewWithIntComparator(values...).Iterator()
+		return &it
+	})
+}
+
+func FuzzSet(f *testing.F) {
+	containertest.FuzzSet(f, func() sets.Set[int] { return treeset.NewWithIntComparator() }, containertest.SortedOrder)
+}
+
+func FuzzSetNew(f *testing.F) {
+	containertest.FuzzSet(f, func() sets.Set[int] { return treeset.New[int]() }, containertest.SortedOrder)
+}
+
 func benchmarkContains(b *testing.B, set *treeset.Set[int], size int) {
 	for i := 0; i < b.N; i++ {
 		for n := 0; n < size; n++ {
@@ -20,25 +20,30 @@ import (
 var _ stacks.Stack[int] = (*Stack[int])(nil)
 
 // Stack holds elements in an array-list
-type Stack[V comparable] struct {
-	list *arraylist.List[V]
+type Stack[V any] struct {
+	list     *arraylist.List[V]
+	modCount int // number of structural modifications, checked by iterators
 }
 
 // New instantiates a new empty stack
-func New[V comparable]() *Stack[V] {
-	return &Stack[V]{list: arraylist.New[V]()}
+func New[V any]() *Stack[V] {
+	return &Stack[V]{list: &arraylist.List[V]{}}
 }
 
 // Push adds a value onto the top of the stack
 func (stack *Stack[V]) Push(value V) {
 	stack.list.Add(value)
+	stack.modCount++
 }
 
 // Pop removes top element on stack and returns it, or nil if stack is empty.
 // Second return parameter is true, unless the stack was empty and there was nothing to pop.
 func (stack *Stack[V]) Pop() (value V, ok bool) {
 	value, ok = stack.list.Get(stack.list.Size() - 1)
-	stack.list.Remove(stack.list.Size() - 1)
+	if ok {
+		stack.list.Remove(stack.list.Size() - 1)
+		stack.modCount++
+	}
 	return
 }
 
@@ -61,6 +66,7 @@ func (stack *Stack[V]) Size() int {
 // Clear removes all elements from the stack.
 func (stack *Stack[V]) Clear() {
 	stack.list.Clear()
+	stack.modCount++
 }
 
 // Values returns all elements in the stack (LIFO order).
@@ -7,16 +7,19 @@ package arraystack
 import "github.com/monitor1379/yagods/containers"
 
 var _ containers.ReverseIteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
-type Iterator[V comparable] struct {
-	stack *Stack[V]
-	index int
+type Iterator[V any] struct {
+	stack    *Stack[V]
+	index    int
+	modCount int  // stack's modification count the iterator is synchronized with
+	removed  bool // current element was removed, index points to its predecessor
 }
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
 func (stack *Stack[V]) Iterator() Iterator[V] {
-	return Iterator[V]{stack: stack, index: -1}
+	return Iterator[V]{stack: stack, index: -1, modCount: stack.modCount}
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -24,6 +27,8 @@ func (stack *Stack[V]) Iterator() Iterator[V] {
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
 // Modifies the state of the iterator.
 func (iterator *Iterator[V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
 	if iterator.index < iterator.stack.Size() {
 		iterator.index++
 	}
@@ -34,6 +39,11 @@ func (iterator *Iterator[V]) Next() bool {
 // If Prev() returns true, then previous element's index and value can be retrieved by Index() and Value().
 // Modifies the state of the iterator.
 func (iterator *Iterator[V]) Prev() bool {
+	iterator.checkModification()
+	if iterator.removed {
+		iterator.removed = false
+		return iterator.stack.withinRange(iterator.index)
+	}
 	if iterator.index >= 0 {
 		iterator.index--
 	}
@@ -57,12 +67,16 @@ func (iterator *Iterator[V]) Index() int {
 // Call Next() to fetch the first element if any.
 func (iterator *Iterator[V]) Begin() {
 	iterator.index = -1
+	iterator.modCount = iterator.stack.modCount
+	iterator.removed = false
 }
 
 // End moves the iterator past the last element (one-past-the-end).
 // Call Prev() to fetch the last element if any.
 func (iterator *Iterator[V]) End() {
 	iterator.index = iterator.stack.Size()
+	iterator.modCount = iterator.stack.modCount
+	iterator.removed = false
 }
 
 // First moves the iterator to the first element and returns true if there was a first element in the container.
@@ -80,3 +94,37 @@ func (iterator *Iterator[V]) Last() bool {
 	iterator.End()
 	return iterator.Prev()
 }
+
+// Err returns containers.ErrConcurrentModification if the stack was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Err() error {
+	if iterator.modCount != iterator.stack.modCount && (iterator.index != -1 || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current element from the stack and keeps the iterator valid.
+// Next() moves to the element that followed the removed one and Prev() to the element that preceded it.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || !iterator.stack.withinRange(iterator.index) {
+		return
+	}
+	iterator.stack.list.Remove(iterator.stack.list.Size() - iterator.index - 1) // in reverse (LIFO)
+	iterator.stack.modCount++
+	iterator.modCount = iterator.stack.modCount
+	iterator.index--
+	iterator.removed = true
+}
+
+// checkModification panics if the stack was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the stack.
+func (iterator *Iterator[V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.stack.modCount
+}
@@ -4,10 +4,26 @@
 
 package arraystack
 
-import "github.com/monitor1379/yagods/containers"
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/lists/arraylist"
+)
 
 var _ containers.JSONSerializer = (*Stack[int])(nil)
 var _ containers.JSONDeserializer = (*Stack[string])(nil)
+var _ encoding.BinaryMarshaler = (*Stack[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*Stack[int])(nil)
+var _ gob.GobEncoder = (*Stack[int])(nil)
+var _ gob.GobDecoder = (*Stack[int])(nil)
+var _ json.Marshaler = (*Stack[int])(nil)
+var _ json.Unmarshaler = (*Stack[int])(nil)
+var _ containers.JSONWriter = (*Stack[int])(nil)
+var _ containers.JSONReader = (*Stack[int])(nil)
 
 // ToJSON outputs the JSON representation of the stack.
 func (stack *Stack[V]) ToJSON() ([]byte, error) {
@@ -16,5 +32,66 @@ func (stack *Stack[V]) ToJSON() ([]byte, error) {
 
 // FromJSON populates the stack from the input JSON representation.
 func (stack *Stack[V]) FromJSON(data []byte) error {
+	stack.modCount++
 	return stack.list.FromJSON(data)
 }
+
+// MarshalBinary outputs the binary representation of the stack.
+func (stack *Stack[V]) MarshalBinary() ([]byte, error) {
+	return stack.list.MarshalBinary()
+}
+
+// UnmarshalBinary populates the stack from the input binary representation.
+func (stack *Stack[V]) UnmarshalBinary(data []byte) error {
+	if stack.list == nil {
+		stack.list = &arraylist.List[V]{}
+	}
+	stack.modCount++
+	return stack.list.UnmarshalBinary(data)
+}
+
+// GobEncode outputs the binary representation of the stack for encoding/gob, see MarshalBinary.
+func (stack *Stack[V]) GobEncode() ([]byte, error) {
+	return stack.MarshalBinary()
+}
+
+// GobDecode populates the stack from the binary representation for encoding/gob, see UnmarshalBinary.
+func (stack *Stack[V]) GobDecode(data []byte) error {
+	return stack.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the stack for encoding/json, see ToJSON.
+func (stack *Stack[V]) MarshalJSON() ([]byte, error) {
+	if stack.list == nil {
+		return []byte("[]"), nil
+	}
+	return stack.list.MarshalJSON()
+}
+
+// UnmarshalJSON populates the stack from the JSON representation for encoding/json, see MarshalJSON.
+func (stack *Stack[V]) UnmarshalJSON(data []byte) error {
+	if stack.list == nil {
+		stack.list = &arraylist.List[V]{}
+	}
+	stack.modCount++
+	return stack.list.UnmarshalJSON(data)
+}
+
+// WriteJSON writes the JSON representation of the stack to the writer, see MarshalJSON.
+func (stack *Stack[V]) WriteJSON(w io.Writer) error {
+	if stack.list == nil {
+		_, err := io.WriteString(w, "[]")
+		return err
+	}
+	return stack.list.WriteJSON(w)
+}
+
+// ReadJSON populates the stack from the JSON representation read from the reader, see MarshalJSON.
+// On error, the stack holds the elements read before the error.
+func (stack *Stack[V]) ReadJSON(r io.Reader) error {
+	if stack.list == nil {
+		stack.list = &arraylist.List[V]{}
+	}
+	stack.modCount++
+	return stack.list.ReadJSON(r)
+}
@@ -7,16 +7,19 @@ package linkedliststack
 import "github.com/monitor1379/yagods/containers"
 
 var _ containers.IteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
-type Iterator[V comparable] struct {
-	stack *Stack[V]
-	index int
+type Iterator[V any] struct {
+	stack    *Stack[V]
+	index    int
+	modCount int  // stack's modification count the iterator is synchronized with
+	removed  bool // current element was removed, index points to its predecessor
 }
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
 func (stack *Stack[V]) Iterator() Iterator[V] {
-	return Iterator[V]{stack: stack, index: -1}
+	return Iterator[V]{stack: stack, index: -1, modCount: stack.modCount}
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -24,6 +27,8 @@ func (stack *Stack[V]) Iterator() Iterator[V] {
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
 // Modifies the state of the iterator.
 func (iterator *Iterator[V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
 	if iterator.index < iterator.stack.Size() {
 		iterator.index++
 	}
@@ -47,6 +52,8 @@ func (iterator *Iterator[V]) Index() int {
 // Call Next() to fetch the first element if any.
 func (iterator *Iterator[V]) Begin() {
 	iterator.index = -1
+	iterator.modCount = iterator.stack.modCount
+	iterator.removed = false
 }
 
 // First moves the iterator to the first element and returns true if there was a first element in the container.
@@ -56,3 +63,37 @@ func (iterator *Iterator[V]) First() bool {
 	iterator.Begin()
 	return iterator.Next()
 }
+
+// Err returns containers.ErrConcurrentModification if the stack was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Err() error {
+	if iterator.modCount != iterator.stack.modCount && (iterator.index != -1 || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current element from the stack and keeps the iterator valid.
+// Next() moves to the element that followed the removed one.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || !iterator.stack.withinRange(iterator.index) {
+		return
+	}
+	iterator.stack.list.Remove(iterator.index)
+	iterator.stack.modCount++
+	iterator.modCount = iterator.stack.modCount
+	iterator.index--
+	iterator.removed = true
+}
+
+// checkModification panics if the stack was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the stack.
+func (iterator *Iterator[V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.stack.modCount
+}
@@ -5,9 +5,17 @@
 package linkedliststack_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/stacks"
 	"github.com/monitor1379/yagods/stacks/linkedliststack"
 )
 
@@ -73,6 +81,21 @@ func TestStackPop(t *testing.T) {
 	}
 }
 
+func TestStackNonComparableValues(t *testing.T) {
+	stack := linkedliststack.New[[]int]()
+	stack.Push([]int{1})
+	stack.Push([]int{2, 3})
+	if actualValue, ok := stack.Peek(); fmt.Sprint(actualValue) != "[2 3]" || !ok {
+		t.Errorf("Got %v expected %v", actualValue, "[2 3]")
+	}
+	if actualValue, ok := stack.Pop(); fmt.Sprint(actualValue) != "[2 3]" || !ok {
+		t.Errorf("Got %v expected %v", actualValue, "[2 3]")
+	}
+	if actualValue, expectedValue := fmt.Sprint(stack.Values()), "[[1]]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestStackIterator(t *testing.T) {
 	stack := linkedliststack.New[string]()
 	stack.Push("a")
@@ -158,6 +181,26 @@ func toInterfaces[T any](ts []T) []interface{} {
 	return is
 }
 
+func TestStackIteratorRemove(t *testing.T) {
+	stack := linkedliststack.New[int]()
+	for i := 1; i <= 5; i++ {
+		stack.Push(i)
+	}
+	it := stack.Iterator()
+	for it.Next() {
+		if it.Value()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(stack.Values()), "[5 3 1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	stack.Push(6)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestStackSerialization(t *testing.T) {
 	stack := linkedliststack.New[string]()
 	stack.Push("a")
@@ -186,6 +229,110 @@ func TestStackSerialization(t *testing.T) {
 	assert()
 }
 
+func TestStackBinarySerialization(t *testing.T) {
+	stack := linkedliststack.New[int]()
+	stack.Push(1)
+	stack.Push(2)
+	stack.Push(3)
+
+	data, err := stack.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := linkedliststack.New[int]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(stack.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(stack); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = linkedliststack.New[int]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(stack.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestStackJSONMarshaler(t *testing.T) {
+	stack := linkedliststack.New[int]()
+	stack.Push(1)
+	stack.Push(2)
+	stack.Push(3)
+
+	data, err := json.Marshal(struct{ Stack *linkedliststack.Stack[int] }{stack})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Stack":[3,2,1]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Stack linkedliststack.Stack[int] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Stack.Values()), fmt.Sprint(stack.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Stack":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestStackJSONStream(t *testing.T) {
+	stack := linkedliststack.New[int]()
+	stack.Push(1)
+	stack.Push(2)
+	stack.Push(3)
+
+	var buffer bytes.Buffer
+	if err := stack.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `[3,2,1]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored linkedliststack.Stack[int]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(stack.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestStackConformance(t *testing.T) {
+	containertest.TestStack(t, func() stacks.Stack[int] { return linkedliststack.New[int]() })
+	containertest.TestIteratorWithIndex(t, func(values []int) containers.IteratorWithIndex[int] {
+		stack := linkedliststack.New[int]()
+		for _, value := range values {
+			stack.Push(value)
+		}
+		it := stack.Iterator()
+		return &it
+	})
+}
+
+func FuzzStack(f *testing.F) {
+	containertest.FuzzStack(f, func() stacks.Stack[int] { return linkedliststack.New[int]() })
+}
+
 func benchmarkPush(b *testing.B, stack *linkedliststack.Stack[int], size int) {
 	for i := 0; i < b.N; i++ {
 		for n := 0; n < size; n++ {
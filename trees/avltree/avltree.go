@@ -21,8 +21,11 @@ var _ trees.Tree[int, string] = (*Tree[int, string])(nil)
 // Tree holds elements of the AVL tree.
 type Tree[K comparable, V any] struct {
 	Root       *Node[K, V]         // Root node
-	Comparator utils.Comparator[K] // Key comparator
+	Comparator utils.Comparator[K] // Key comparator, changed through SetComparator
 	size       int                 // Total number of keys in the tree
+	modCount   int                 // Number of structural modifications, checked by iterators
+
+	lookup func(n *Node[K, V], key K) *Node[K, V] // Search comparing ordered keys directly, set by New and cleared by SetComparator
 }
 
 // Node is a single element within the tree
@@ -34,6 +37,12 @@ type Node[K comparable, V any] struct {
 	b        int8
 }
 
+// New instantiates an AVL tree ordering the keys by the < and > operators, see utils.OrderedComparator.
+// Get compares the keys with the operators instead of calling the comparator.
+func New[K utils.Ordered, V any]() *Tree[K, V] {
+	return &Tree[K, V]{Comparator: utils.OrderedComparator[K], lookup: lookupOrdered[K, V]}
+}
+
 // NewWith instantiates an AVL tree with the custom comparator.
 func NewWith[K comparable, V any](comparator utils.Comparator[K]) *Tree[K, V] {
 	return &Tree[K, V]{Comparator: comparator}
@@ -49,6 +58,13 @@ func NewWithStringComparator[V any]() *Tree[string, V] {
 	return &Tree[string, V]{Comparator: utils.StringComparator}
 }
 
+// SetComparator replaces the comparator of the tree, which Get then calls instead of comparing the keys with the operators.
+// The nodes are not reordered, so it should be called on an empty tree.
+func (t *Tree[K, V]) SetComparator(comparator utils.Comparator[K]) {
+	t.Comparator = comparator
+	t.lookup = nil
+}
+
 // Put inserts node into the tree.
 // Key should adhere to the comparator's type assertion, otherwise method panics.
 func (t *Tree[K, V]) Put(key K, value V) {
@@ -59,6 +75,13 @@ func (t *Tree[K, V]) Put(key K, value V) {
 // Second return parameter is true if key was found, otherwise false.
 // Key should adhere to the comparator's type assertion, otherwise method panics.
 func (t *Tree[K, V]) Get(key K) (V, bool) {
+	if t.lookup != nil {
+		if n := t.lookup(t.Root, key); n != nil {
+			return n.Value, true
+		}
+		var zeroV V
+		return zeroV, false
+	}
 	n := t.Root
 	for n != nil {
 		cmp := t.Comparator(key, n.Key)
@@ -194,6 +217,7 @@ func (t *Tree[K, V]) Ceiling(key K) (floor *Node[K, V], found bool) {
 func (t *Tree[K, V]) Clear() {
 	t.Root = nil
 	t.size = 0
+	t.modCount++
 }
 
 // String returns a string representation of container
@@ -209,10 +233,27 @@ func (n *Node[K, V]) String() string {
 	return fmt.Sprintf("%v", n.Key)
 }
 
+// lookupOrdered returns the node holding the key in the subtree of the node, or nil if there is none.
+// It is the search of trees instantiated by New, comparing the keys as utils.OrderedComparator does.
+func lookupOrdered[K utils.Ordered, V any](n *Node[K, V], key K) *Node[K, V] {
+	for n != nil {
+		switch {
+		case key < n.Key:
+			n = n.Children[0]
+		case key > n.Key:
+			n = n.Children[1]
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
 func (t *Tree[K, V]) put(key K, value V, p *Node[K, V], qp **Node[K, V]) bool {
 	q := *qp
 	if q == nil {
 		t.size++
+		t.modCount++
 		*qp = &Node[K, V]{Key: key, Value: value, Parent: p}
 		return true
 	}
@@ -247,6 +288,7 @@ func (t *Tree[K, V]) remove(key K, qp **Node[K, V]) bool {
 	c := t.Comparator(key, q.Key)
 	if c == 0 {
 		t.size--
+		t.modCount++
 		if q.Children[1] == nil {
 			if q.Children[0] != nil {
 				q.Children[0].Parent = q.Parent
@@ -0,0 +1,60 @@
+// Copyright (c) 2017, Benjamin Scher Purcell. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package avltree
+
+import (
+	"fmt"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// FromSorted instantiates a perfectly balanced AVL tree with the custom comparator
+// holding the keys and their values in O(n) time.
+// Returns an error wrapping containers.ErrNotSorted if keys are not in strictly ascending order,
+// or an error if there are not as many values as keys.
+func FromSorted[K comparable, V any](comparator utils.Comparator[K], keys []K, values []V) (*Tree[K, V], error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("avltree: got %d keys but %d values", len(keys), len(values))
+	}
+	for i := 1; i < len(keys); i++ {
+		if comparator(keys[i-1], keys[i]) >= 0 {
+			return nil, fmt.Errorf("avltree: key at index %d: %w", i, containers.ErrNotSorted)
+		}
+	}
+	t := NewWith[K, V](comparator)
+	t.Root, _ = build(keys, values, nil)
+	t.size = len(keys)
+	return t, nil
+}
+
+// BulkLoad instantiates a perfectly balanced AVL tree with the custom comparator holding
+// the remaining elements of the iterator in O(n) time. See FromSorted.
+func BulkLoad[K comparable, V any](comparator utils.Comparator[K], iterator containers.IteratorWithKey[K, V]) (*Tree[K, V], error) {
+	var keys []K
+	var values []V
+	for iterator.Next() {
+		keys = append(keys, iterator.Key())
+		values = append(values, iterator.Value())
+	}
+	return FromSorted(comparator, keys, values)
+}
+
+// build links the keys into a balanced subtree below p and returns its root and height.
+func build[K comparable, V any](keys []K, values []V, p *Node[K, V]) (*Node[K, V], int) {
+	if len(keys) == 0 {
+		return nil, 0
+	}
+	m := len(keys) / 2
+	n := &Node[K, V]{Key: keys[m], Value: values[m], Parent: p}
+	left, leftHeight := build(keys[:m], values[:m], n)
+	right, rightHeight := build(keys[m+1:], values[m+1:], n)
+	n.Children = [2]*Node[K, V]{left, right}
+	n.b = int8(rightHeight - leftHeight)
+	if leftHeight > rightHeight {
+		return n, leftHeight + 1
+	}
+	return n, rightHeight + 1
+}
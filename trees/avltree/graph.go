@@ -0,0 +1,53 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package avltree
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Assert Graph implementation
+var _ containers.DOTWriter = (*Tree[string, int])(nil)
+var _ containers.MermaidWriter = (*Tree[string, int])(nil)
+
+// ToGraph returns the structure of the tree as a graph of its nodes, each one labeled with its entry
+// and its balance factor, the height of its right subtree minus the height of its left subtree, on a second line.
+// The label hook formats the entry of a node, nil formats its key.
+// A missing child of a node with one child is an invisible node, so that the other child stays on its side.
+func (tree *Tree[K, V]) ToGraph(label func(key K, value V) string) *containers.Graph {
+	if label == nil {
+		label = func(key K, value V) string { return fmt.Sprint(key) }
+	}
+	graph := &containers.Graph{Name: "avltree"}
+	var add func(node *Node[K, V]) int
+	add = func(node *Node[K, V]) int {
+		if node == nil {
+			return graph.AddNode(containers.GraphNode{Invisible: true})
+		}
+		index := graph.AddNode(containers.GraphNode{Label: fmt.Sprintf("%s\nbalance %d", label(node.Key, node.Value), node.b)})
+		if node.Children[0] != nil || node.Children[1] != nil {
+			graph.AddEdge(index, add(node.Children[0]))
+			graph.AddEdge(index, add(node.Children[1]))
+		}
+		return index
+	}
+	if tree.Root != nil {
+		add(tree.Root)
+	}
+	return graph
+}
+
+// ToDOT writes the structure of the tree to the writer in the DOT language of Graphviz, see ToGraph.
+func (tree *Tree[K, V]) ToDOT(w io.Writer) error {
+	return tree.ToGraph(nil).WriteDOT(w)
+}
+
+// ToMermaid writes the structure of the tree to the writer as a Mermaid flowchart, see ToGraph.
+func (tree *Tree[K, V]) ToMermaid(w io.Writer) error {
+	return tree.ToGraph(nil).WriteMermaid(w)
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2017, Benjamin Scher Purcell. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package avltree
+
+// RemoveRange removes all nodes whose keys are within [from, to) from the tree.
+// Runs in O(log n + k) time, k being the number of removed nodes.
+// Keys should adhere to the comparator's type assertion, otherwise method panics.
+func (t *Tree[K, V]) RemoveRange(from K, to K) {
+	if t.Root == nil || t.Comparator(from, to) >= 0 {
+		return
+	}
+	left, leftHeight, rest, restHeight := t.split(t.Root, height(t.Root), from)
+	middle, _, right, rightHeight := t.split(rest, restHeight, to)
+	t.Root, _ = t.join(left, leftHeight, nil, right, rightHeight)
+	if removed := count(middle); removed > 0 {
+		t.size -= removed
+		t.modCount++
+	}
+}
+
+// Split moves all nodes whose keys are less than the given key into the left tree and
+// all other nodes into the right tree. The tree itself is left empty.
+// Runs in O(log n + min(|left|, |right|)) time, since nodes do not keep track of their subtree's size.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (t *Tree[K, V]) Split(key K) (left *Tree[K, V], right *Tree[K, V]) {
+	left, right = NewWith[K, V](t.Comparator), NewWith[K, V](t.Comparator)
+	left.lookup, right.lookup = t.lookup, t.lookup
+	if t.Root != nil {
+		left.Root, _, right.Root, _ = t.split(t.Root, height(t.Root), key)
+		detach(left.Root)
+		detach(right.Root)
+		left.size, right.size = countSmaller(left, right, t.size)
+	}
+	t.Clear()
+	return left, right
+}
+
+// Join moves all nodes of the left and the right tree into a new tree using left's comparator
+// and leaves both trees empty. All keys of the left tree should be less than all keys of the right tree,
+// otherwise method panics.
+// Runs in O(log n) time.
+func Join[K comparable, V any](left *Tree[K, V], right *Tree[K, V]) *Tree[K, V] {
+	t := NewWith[K, V](left.Comparator)
+	t.lookup = left.lookup
+	if !left.Empty() && !right.Empty() && t.Comparator(left.Right().Key, right.Left().Key) >= 0 {
+		panic("Invalid join, all keys of left should be less than all keys of right")
+	}
+	t.Root, _ = t.join(left.Root, height(left.Root), nil, right.Root, height(right.Root))
+	t.size = left.size + right.size
+	left.Clear()
+	right.Clear()
+	return t
+}
+
+// split splits the subtree rooted at n with the given height into the subtrees holding keys
+// less than the key and keys greater than or equal to the key, returning their roots and heights.
+func (t *Tree[K, V]) split(n *Node[K, V], h int, key K) (*Node[K, V], int, *Node[K, V], int) {
+	if n == nil {
+		return nil, 0, nil, 0
+	}
+	left, right := n.Children[0], n.Children[1]
+	leftHeight, rightHeight := h-1, h-1
+	if n.b > 0 {
+		leftHeight--
+	} else if n.b < 0 {
+		rightHeight--
+	}
+	c := t.Comparator(key, n.Key)
+	switch {
+	case c == 0:
+		right, rightHeight = t.join(nil, 0, n, right, rightHeight)
+		return left, leftHeight, right, rightHeight
+	case c < 0:
+		leftLeft, leftLeftHeight, leftRight, leftRightHeight := t.split(left, leftHeight, key)
+		right, rightHeight = t.join(leftRight, leftRightHeight, n, right, rightHeight)
+		return leftLeft, leftLeftHeight, right, rightHeight
+	default:
+		rightLeft, rightLeftHeight, rightRight, rightRightHeight := t.split(right, rightHeight, key)
+		left, leftHeight = t.join(left, leftHeight, n, rightLeft, rightLeftHeight)
+		return left, leftHeight, rightRight, rightRightHeight
+	}
+}
+
+// join links the left subtree, the middle node and the right subtree (keys in that order) into one
+// AVL tree and returns its root and height. The middle node may be nil.
+func (t *Tree[K, V]) join(left *Node[K, V], leftHeight int, middle *Node[K, V], right *Node[K, V], rightHeight int) (*Node[K, V], int) {
+	if middle == nil {
+		if left == nil {
+			return detach(right), rightHeight
+		}
+		if right == nil {
+			return detach(left), leftHeight
+		}
+		// use the minimum of the right subtree as the middle node
+		rest := &Tree[K, V]{Root: detach(right), Comparator: t.Comparator}
+		first := rest.bottom(0)
+		middle = &Node[K, V]{Key: first.Key, Value: first.Value}
+		rest.Remove(first.Key)
+		right, rightHeight = rest.Root, height(rest.Root)
+	}
+	left, right = detach(left), detach(right)
+	middle.Parent, middle.Children = nil, [2]*Node[K, V]{}
+	switch {
+	case leftHeight > rightHeight+1:
+		root := left
+		if joinSide(&root, leftHeight, middle, right, rightHeight, 1, nil) {
+			leftHeight++
+		}
+		return root, leftHeight
+	case rightHeight > leftHeight+1:
+		root := right
+		if joinSide(&root, rightHeight, middle, left, leftHeight, 0, nil) {
+			rightHeight++
+		}
+		return root, rightHeight
+	}
+	middle.Children = [2]*Node[K, V]{left, right}
+	setParent(left, middle)
+	setParent(right, middle)
+	middle.b = int8(rightHeight - leftHeight)
+	if leftHeight > rightHeight {
+		return middle, leftHeight + 1
+	}
+	return middle, rightHeight + 1
+}
+
+// joinSide descends along side a of the subtree *qp with the given height until it finds a subtree
+// of about the other subtree's height, replaces it with the middle node linking both and rebalances.
+// Returns true if the height of *qp grew.
+func joinSide[K comparable, V any](qp **Node[K, V], h int, middle *Node[K, V], other *Node[K, V], otherHeight int, a int, p *Node[K, V]) bool {
+	q := *qp
+	c := int8(2*a - 1)
+	if h <= otherHeight+1 {
+		middle.Children[a^1], middle.Children[a] = q, other
+		middle.b = c * int8(otherHeight-h)
+		middle.Parent = p
+		setParent(q, middle)
+		setParent(other, middle)
+		*qp = middle
+		return true
+	}
+	childHeight := h - 1
+	if q.b == -c {
+		childHeight--
+	}
+	if joinSide(&q.Children[a], childHeight, middle, other, otherHeight, a, q) {
+		return joinFix(c, qp)
+	}
+	return false
+}
+
+// joinFix rebalances *t after its subtree on side c grew by a join and returns true if the height of *t grew.
+// Unlike after an insertion the grown subtree may be balanced, which a single rotation fixes.
+func joinFix[K comparable, V any](c int8, t **Node[K, V]) bool {
+	s := *t
+	if s.b == c && s.Children[(c+1)/2].b == 0 {
+		s = rotate(c, s)
+		s.b = -c
+		*t = s
+		return true
+	}
+	return putFix(c, t)
+}
+
+// height returns the number of nodes on the longest path from the node down to a leaf.
+func height[K comparable, V any](n *Node[K, V]) int {
+	h := 0
+	for n != nil {
+		h++
+		if n.b > 0 {
+			n = n.Children[1]
+		} else {
+			n = n.Children[0]
+		}
+	}
+	return h
+}
+
+func detach[K comparable, V any](n *Node[K, V]) *Node[K, V] {
+	if n != nil {
+		n.Parent = nil
+	}
+	return n
+}
+
+func setParent[K comparable, V any](n *Node[K, V], p *Node[K, V]) {
+	if n != nil {
+		n.Parent = p
+	}
+}
+
+func count[K comparable, V any](n *Node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return count(n.Children[0]) + 1 + count(n.Children[1])
+}
+
+// countSmaller returns the sizes of two trees holding total nodes together,
+// counting only the nodes of the smaller one.
+func countSmaller[K comparable, V any](left *Tree[K, V], right *Tree[K, V], total int) (int, int) {
+	leftIterator, rightIterator := left.Iterator(), right.Iterator()
+	for n := 0; ; n++ {
+		if !leftIterator.Next() {
+			return n, total - n
+		}
+		if !rightIterator.Next() {
+			return total - n, n
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package avltree
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// Structure format of an AVL tree: the nodes in pre-order, each one a header followed by its entry.
+// The header holds the flags below and the balance factor plus one, shifted by balanceShift.
+const (
+	hasLeft      = 1 << iota // the node has a left child
+	hasRight                 // the node has a right child
+	balanceShift = iota
+)
+
+// MarshalStructure outputs the structure format of the tree, which records the shape and the balance factors of its nodes.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (tree *Tree[K, V]) MarshalStructure() ([]byte, error) {
+	encoder := containers.NewStructureEncoder[K, V](tree.size)
+	if err := marshalNode(encoder, tree.Root); err != nil {
+		return nil, err
+	}
+	return encoder.Bytes(), nil
+}
+
+// UnmarshalStructure replaces the elements of the tree with the exact tree recorded in the structure format, in O(n) time.
+// Returns an error wrapping containers.ErrInvalidBinary if the data is not in the structure format of an AVL tree,
+// or containers.ErrInvalidStructure if the recorded tree is not a valid AVL tree for the comparator of the tree.
+// The tree is left unchanged on error. Like UnmarshalBinary, it accepts the zero value of a tree.
+func (tree *Tree[K, V]) UnmarshalStructure(data []byte) error {
+	if err := tree.initZero(); err != nil {
+		return err
+	}
+	decoder, count, err := containers.NewStructureDecoder[K, V](data)
+	if err != nil {
+		return err
+	}
+	r := &restorer[K, V]{
+		decoder:    decoder,
+		comparator: tree.Comparator,
+		count:      count,
+		maxDepth:   2 * bits.Len(uint(count)),
+	}
+	var root *Node[K, V]
+	if count > 0 {
+		if root, _, err = r.node(nil, 0); err != nil {
+			return err
+		}
+	}
+	if r.restored != count {
+		return fmt.Errorf("avltree: %d nodes but a count of %d: %w", r.restored, count, containers.ErrInvalidBinary)
+	}
+	if err := decoder.End(); err != nil {
+		return err
+	}
+	tree.Clear()
+	tree.Root, tree.size = root, count
+	return nil
+}
+
+func marshalNode[K comparable, V any](encoder *containers.StructureEncoder[K, V], node *Node[K, V]) error {
+	if node == nil {
+		return nil
+	}
+	header := uint64(node.b+1) << balanceShift
+	if node.Children[0] != nil {
+		header |= hasLeft
+	}
+	if node.Children[1] != nil {
+		header |= hasRight
+	}
+	encoder.Uvarint(header)
+	if err := encoder.Entry(node.Key, node.Value); err != nil {
+		return err
+	}
+	if err := marshalNode(encoder, node.Children[0]); err != nil {
+		return err
+	}
+	return marshalNode(encoder, node.Children[1])
+}
+
+// restorer links the nodes of a structure format, checking the order of the keys and the balance factors.
+type restorer[K comparable, V any] struct {
+	decoder    *containers.StructureDecoder[K, V]
+	comparator utils.Comparator[K]
+	count      int         // number of nodes in the header
+	restored   int         // number of nodes restored so far
+	maxDepth   int         // depth no AVL tree of count nodes reaches
+	last       *Node[K, V] // last node in order so far
+}
+
+// node restores the subtree below the parent and returns its root and height.
+func (r *restorer[K, V]) node(parent *Node[K, V], depth int) (*Node[K, V], int, error) {
+	if r.restored == r.count {
+		return nil, 0, fmt.Errorf("avltree: more nodes than a count of %d: %w", r.count, containers.ErrInvalidBinary)
+	}
+	if depth >= r.maxDepth {
+		return nil, 0, fmt.Errorf("avltree: path deeper than %d nodes: %w", r.maxDepth, containers.ErrInvalidStructure)
+	}
+	header, err := r.decoder.Uvarint()
+	if err != nil {
+		return nil, 0, err
+	}
+	balance := header >> balanceShift
+	if balance > 2 {
+		return nil, 0, fmt.Errorf("avltree: invalid node header %d: %w", header, containers.ErrInvalidBinary)
+	}
+	key, value, err := r.decoder.Entry()
+	if err != nil {
+		return nil, 0, err
+	}
+	node := &Node[K, V]{Key: key, Value: value, Parent: parent, b: int8(balance) - 1}
+	r.restored++
+
+	leftHeight, rightHeight := 0, 0
+	if header&hasLeft != 0 {
+		if node.Children[0], leftHeight, err = r.node(node, depth+1); err != nil {
+			return nil, 0, err
+		}
+	}
+	if r.last != nil && r.comparator(r.last.Key, node.Key) >= 0 {
+		return nil, 0, fmt.Errorf("avltree: key %v follows key %v: %w", node.Key, r.last.Key, containers.ErrInvalidStructure)
+	}
+	r.last = node
+	if header&hasRight != 0 {
+		if node.Children[1], rightHeight, err = r.node(node, depth+1); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if int(node.b) != rightHeight-leftHeight {
+		return nil, 0, fmt.Errorf("avltree: node %v has balance factor %d but subtrees of heights %d and %d: %w",
+			node.Key, node.b, leftHeight, rightHeight, containers.ErrInvalidStructure)
+	}
+	if leftHeight > rightHeight {
+		return node, leftHeight + 1, nil
+	}
+	return node, rightHeight + 1, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package avltree
+
+import (
+	"fmt"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Validate checks the invariants of the tree: keys in strictly ascending order for the comparator,
+// subtree heights differing by at most one and matching the balance factor of every node,
+// parent pointers matching the children and a size matching the number of nodes.
+// Returns nil for a valid tree, otherwise an error wrapping containers.ErrInvalidStructure naming the offending node.
+func (tree *Tree[K, V]) Validate() error {
+	v := &validator[K, V]{tree: tree}
+	if _, err := v.validate(tree.Root, nil); err != nil {
+		return err
+	}
+	if v.count != tree.size {
+		return fmt.Errorf("avltree: %d nodes but a size of %d: %w", v.count, tree.size, containers.ErrInvalidStructure)
+	}
+	return nil
+}
+
+type validator[K comparable, V any] struct {
+	tree  *Tree[K, V]
+	count int         // number of nodes visited so far
+	last  *Node[K, V] // last node in order so far
+}
+
+// validate checks the subtree of the node below the parent and returns its height.
+func (v *validator[K, V]) validate(node *Node[K, V], parent *Node[K, V]) (int, error) {
+	if node == nil {
+		return 0, nil
+	}
+	v.count++
+	if node.Parent != parent {
+		return 0, fmt.Errorf("avltree: node %v does not point to its parent: %w", node.Key, containers.ErrInvalidStructure)
+	}
+	leftHeight, err := v.validate(node.Children[0], node)
+	if err != nil {
+		return 0, err
+	}
+	if v.last != nil && v.tree.Comparator(v.last.Key, node.Key) >= 0 {
+		return 0, fmt.Errorf("avltree: key %v follows key %v: %w", node.Key, v.last.Key, containers.ErrInvalidStructure)
+	}
+	v.last = node
+	rightHeight, err := v.validate(node.Children[1], node)
+	if err != nil {
+		return 0, err
+	}
+	if balance := rightHeight - leftHeight; balance < -1 || balance > 1 {
+		return 0, fmt.Errorf("avltree: node %v has subtrees of heights %d and %d: %w", node.Key, leftHeight, rightHeight, containers.ErrInvalidStructure)
+	} else if int(node.b) != balance {
+		return 0, fmt.Errorf("avltree: node %v has balance factor %d but subtrees of heights %d and %d: %w",
+			node.Key, node.b, leftHeight, rightHeight, containers.ErrInvalidStructure)
+	}
+	if leftHeight > rightHeight {
+		return leftHeight + 1, nil
+	}
+	return rightHeight + 1, nil
+}
@@ -23,14 +23,22 @@ import (
 var _ trees.Tree[int, int] = (*Heap[int])(nil)
 
 // Heap holds elements in an array-list
-type Heap[V comparable] struct {
+type Heap[V any] struct {
 	list       *arraylist.List[V]
-	Comparator utils.Comparator[V]
+	Comparator utils.Comparator[V]            // value comparator, changed through SetComparator
+	modCount   int                            // number of structural modifications, checked by iterators
+	down, up   func(heap *Heap[V], index int) // bubbling comparing ordered values directly, set by New and cleared by SetComparator
+}
+
+// New instantiates a new empty min-heap ordering the values by the < and > operators, see utils.OrderedComparator.
+// Bubbling values up and down compares them with the operators instead of calling the comparator.
+func New[V utils.Ordered]() *Heap[V] {
+	return &Heap[V]{list: &arraylist.List[V]{}, Comparator: utils.OrderedComparator[V], down: bubbleDownOrdered[V], up: bubbleUpOrdered[V]}
 }
 
 // NewWith instantiates a new empty heap tree with the custom comparator.
-func NewWith[V comparable](comparator utils.Comparator[V]) *Heap[V] {
-	return &Heap[V]{list: arraylist.New[V](), Comparator: comparator}
+func NewWith[V any](comparator utils.Comparator[V]) *Heap[V] {
+	return &Heap[V]{list: &arraylist.List[V]{}, Comparator: comparator}
 }
 
 // NewWithIntComparator instantiates a new empty heap with the IntComparator, i.e. elements are of type int.
@@ -43,6 +51,13 @@ func NewWithStringComparator() *Heap[string] {
 	return &Heap[string]{list: arraylist.New[string](), Comparator: utils.StringComparator}
 }
 
+// SetComparator replaces the comparator of the heap, which then calls it instead of comparing the values with the operators.
+// The values are not reordered, so it should be called on an empty heap.
+func (heap *Heap[V]) SetComparator(comparator utils.Comparator[V]) {
+	heap.Comparator = comparator
+	heap.down, heap.up = nil, nil
+}
+
 // Push adds a value onto the heap and bubbles it up accordingly.
 func (heap *Heap[V]) Push(values ...V) {
 	if len(values) == 1 {
@@ -58,6 +73,7 @@ func (heap *Heap[V]) Push(values ...V) {
 			heap.bubbleDownIndex(i)
 		}
 	}
+	heap.modCount++
 }
 
 // Pop removes top element on heap and returns it, or nil if heap is empty.
@@ -71,6 +87,7 @@ func (heap *Heap[V]) Pop() (value V, ok bool) {
 	heap.list.Swap(0, lastIndex)
 	heap.list.Remove(lastIndex)
 	heap.bubbleDown()
+	heap.modCount++
 	return
 }
 
@@ -93,6 +110,7 @@ func (heap *Heap[V]) Size() int {
 // Clear removes all elements from the heap.
 func (heap *Heap[V]) Clear() {
 	heap.list.Clear()
+	heap.modCount++
 }
 
 // Values returns all elements in the heap.
@@ -129,6 +147,10 @@ func (heap *Heap[V]) bubbleDown() {
 // Performs the "bubble down" operation. This is to place the element that is at the index
 // of the heap in its correct place so that the heap maintains the min/max-heap order property.
 func (heap *Heap[V]) bubbleDownIndex(index int) {
+	if heap.down != nil {
+		heap.down(heap, index)
+		return
+	}
 	size := heap.list.Size()
 	for leftIndex := index<<1 + 1; leftIndex < size; leftIndex = index<<1 + 1 {
 		rightIndex := index<<1 + 2
@@ -153,7 +175,16 @@ func (heap *Heap[V]) bubbleDownIndex(index int) {
 // element (i.e. last element in the list) in its correct place so that
 // the heap maintains the min/max-heap order property.
 func (heap *Heap[V]) bubbleUp() {
-	index := heap.list.Size() - 1
+	heap.bubbleUpIndex(heap.list.Size() - 1)
+}
+
+// Performs the "bubble up" operation. This is to place the element that is at the index
+// of the heap in its correct place so that the heap maintains the min/max-heap order property.
+func (heap *Heap[V]) bubbleUpIndex(index int) {
+	if heap.up != nil {
+		heap.up(heap, index)
+		return
+	}
 	for parentIndex := (index - 1) >> 1; index > 0; parentIndex = (index - 1) >> 1 {
 		indexValue, _ := heap.list.Get(index)
 		parentValue, _ := heap.list.Get(parentIndex)
@@ -165,6 +196,55 @@ func (heap *Heap[V]) bubbleUp() {
 	}
 }
 
+// bubbleDownOrdered is bubbleDownIndex for heaps instantiated by New, comparing the values as utils.OrderedComparator does.
+func bubbleDownOrdered[V utils.Ordered](heap *Heap[V], index int) {
+	size := heap.list.Size()
+	for leftIndex := index<<1 + 1; leftIndex < size; leftIndex = index<<1 + 1 {
+		rightIndex := index<<1 + 2
+		smallerIndex := leftIndex
+		smallerValue, _ := heap.list.Get(leftIndex)
+		if rightValue, _ := heap.list.Get(rightIndex); rightIndex < size && smallerValue > rightValue {
+			smallerIndex, smallerValue = rightIndex, rightValue
+		}
+		if indexValue, _ := heap.list.Get(index); !(indexValue > smallerValue) {
+			break
+		}
+		heap.list.Swap(index, smallerIndex)
+		index = smallerIndex
+	}
+}
+
+// bubbleUpOrdered is bubbleUpIndex for heaps instantiated by New, comparing the values as utils.OrderedComparator does.
+func bubbleUpOrdered[V utils.Ordered](heap *Heap[V], index int) {
+	for parentIndex := (index - 1) >> 1; index > 0; parentIndex = (index - 1) >> 1 {
+		indexValue, _ := heap.list.Get(index)
+		if parentValue, _ := heap.list.Get(parentIndex); !(parentValue > indexValue) {
+			break
+		}
+		heap.list.Swap(index, parentIndex)
+		index = parentIndex
+	}
+}
+
+// removeIndex removes the element at the index and restores the heap property by moving the last element into its place.
+// If the last element had to bubble up above the index, then it is returned and the second return parameter is true.
+func (heap *Heap[V]) removeIndex(index int) (moved V, movedUp bool) {
+	lastIndex := heap.list.Size() - 1
+	heap.list.Swap(index, lastIndex)
+	heap.list.Remove(lastIndex)
+	heap.modCount++
+	if index == lastIndex {
+		return
+	}
+	moved, _ = heap.list.Get(index)
+	if parentValue, ok := heap.list.Get((index - 1) >> 1); index > 0 && ok && heap.Comparator(parentValue, moved) > 0 {
+		heap.bubbleUpIndex(index)
+		return moved, true
+	}
+	heap.bubbleDownIndex(index)
+	return moved, false
+}
+
 // Check that the index is within bounds of the list
 func (heap *Heap[V]) withinRange(index int) bool {
 	return index >= 0 && index < heap.list.Size()
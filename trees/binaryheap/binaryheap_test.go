@@ -5,10 +5,20 @@
 package binaryheap_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/lists/arraylist"
 	"github.com/monitor1379/yagods/trees/binaryheap"
+	"github.com/monitor1379/yagods/utils"
 )
 
 func TestBinaryHeapPush(t *testing.T) {
@@ -261,6 +271,63 @@ func TestBinaryHeapIteratorLast(t *testing.T) {
 	}
 }
 
+func TestBinaryHeapIteratorRemove(t *testing.T) {
+	heap := binaryheap.NewWithIntComparator()
+	for i := 0; i < 1000; i++ {
+		heap.Push(rand.Intn(1000))
+	}
+	it := heap.Iterator()
+	count := 0
+	for it.Next() {
+		count++
+		if it.Value()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if count != 1000 {
+		t.Errorf("Got %v expected %v", count, 1000)
+	}
+	prev := -1
+	for !heap.Empty() {
+		value, _ := heap.Pop()
+		if value%2 == 0 || value < prev {
+			t.Errorf("Unexpected value %v after %v", value, prev)
+		}
+		prev = value
+	}
+	heap.Push(1)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBinaryHeapNonComparableValues(t *testing.T) {
+	heap := binaryheap.NewWith(func(a, b []int) int { return utils.NumberComparator(a[0], b[0]) })
+	for i := 0; i < 100; i++ {
+		heap.Push([]int{(i * 37) % 100})
+	}
+	it := heap.Iterator()
+	for it.Next() {
+		if it.Value()[0]%2 == 1 {
+			it.Remove()
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if err := heap.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	for i := 0; i < 100; i += 2 {
+		if value, ok := heap.Pop(); !ok || value[0] != i {
+			t.Errorf("Got %v expected %v", value, i)
+		}
+	}
+	if actualValue := heap.Size(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+}
+
 func TestBinaryHeapSerialization(t *testing.T) {
 	heap := binaryheap.NewWithStringComparator()
 
@@ -293,6 +360,264 @@ func TestBinaryHeapSerialization(t *testing.T) {
 	assert()
 }
 
+func TestBinaryHeapBinarySerialization(t *testing.T) {
+	heap := binaryheap.NewWithIntComparator()
+	heap.Push(3, 1, 2, 5, 4)
+
+	data, err := heap.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := binaryheap.NewWithIntComparator()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(heap.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(heap); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = binaryheap.NewWithIntComparator()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(heap.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestBinaryHeapJSONMarshaler(t *testing.T) {
+	heap := binaryheap.NewWithIntComparator()
+	heap.Push(3, 1, 2)
+
+	data, err := json.Marshal(struct{ Heap *binaryheap.Heap[int] }{heap})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Heap":[1,3,2]}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Heap binaryheap.Heap[int] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Heap.Values()), fmt.Sprint(heap.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Heap":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&binaryheap.Heap[[2]int]{}).UnmarshalJSON([]byte("[]")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestBinaryHeapJSONStream(t *testing.T) {
+	heap := binaryheap.NewWithIntComparator()
+	heap.Push(3, 1, 2)
+
+	var buffer bytes.Buffer
+	if err := heap.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `[1,3,2]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored binaryheap.Heap[int]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(heap.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestBinaryHeapStructure(t *testing.T) {
+	heap := binaryheap.NewWithIntComparator()
+	for i := 0; i < 100; i++ {
+		heap.Push((i * 37) % 100)
+	}
+
+	data, err := heap.MarshalStructure()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	var restored binaryheap.Heap[int]
+	if err := restored.UnmarshalStructure(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Values()), fmt.Sprint(heap.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, ok := restored.Pop(); actualValue != 0 || !ok {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	// [1 0] is no heap and is kept as is by UnmarshalBinary only
+	data, _ = arraylist.New(1, 0).MarshalBinary()
+	if err := restored.UnmarshalStructure(data); !errors.Is(err, containers.ErrInvalidStructure) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidStructure)
+	}
+	if actualValue, expectedValue := restored.Size(), 99; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.UnmarshalStructure(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+}
+
+func TestBinaryHeapValidate(t *testing.T) {
+	var zero binaryheap.Heap[int]
+	if err := zero.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	heap := binaryheap.NewWithIntComparator()
+	for i := 0; i < 100; i++ {
+		heap.Push((i * 37) % 100)
+	}
+	for i := 0; i < 50; i++ {
+		heap.Pop()
+		if err := heap.Validate(); err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+
+	// UnmarshalBinary keeps the layout of [0 2 1 3 0]
+	data, _ := arraylist.New(0, 2, 1, 3, 0).MarshalBinary()
+	if err := heap.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	err := heap.Validate()
+	if !errors.Is(err, containers.ErrInvalidStructure) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidStructure)
+	}
+	if expectedValue := "element 0 at index 4 is smaller than its parent 2 at index 1"; err == nil || !strings.Contains(err.Error(), expectedValue) {
+		t.Errorf("Got %v expected %v", err, expectedValue)
+	}
+}
+
+func TestBinaryHeapGraph(t *testing.T) {
+	heap := binaryheap.NewWithIntComparator()
+	heap.Push(3, 1, 2)
+
+	var builder strings.Builder
+	if err := heap.ToDOT(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue := `digraph binaryheap {
+	ordering=out;
+	n0 [label="1\nindex 0"];
+	n1 [label="3\nindex 1"];
+	n2 [label="2\nindex 2"];
+	n0 -> n1;
+	n0 -> n2;
+}
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	builder.Reset()
+	if err := heap.ToMermaid(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue = `flowchart TD
+	%% binaryheap
+	n0["1<br>index 0"]
+	n1["3<br>index 1"]
+	n2["2<br>index 2"]
+	n0 --> n1
+	n0 --> n2
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	graph := heap.ToGraph(func(value int) string { return fmt.Sprintf("%03d", value) })
+	if actualValue, expectedValue := graph.Nodes[1].Label, "003\nindex 1"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var zero binaryheap.Heap[int]
+	if actualValue := len(zero.ToGraph(nil).Nodes); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+}
+
+func TestBinaryHeapNew(t *testing.T) {
+	heap := binaryheap.New[float64]()
+	heap.Push(2.5, -1, 0.5)
+	heap.Push(-3)
+	heap.Push(1)
+	if err := heap.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	var values []float64
+	for value, ok := heap.Pop(); ok; value, ok = heap.Pop() {
+		values = append(values, value)
+	}
+	if actualValue, expectedValue := fmt.Sprint(values), "[-3 -1 0.5 1 2.5]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	words := binaryheap.New[string]()
+	words.Push("c", "a", "b")
+	if actualValue, ok := words.Peek(); actualValue != "a" || !ok {
+		t.Errorf("Got %v expected %v", actualValue, "a")
+	}
+}
+
+func TestBinaryHeapSetComparator(t *testing.T) {
+	heap := binaryheap.New[int]()
+	heap.SetComparator(utils.Reverse(heap.Comparator))
+	heap.Push(3, 1, 5)
+	heap.Push(2)
+	heap.Push(4)
+	if err := heap.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	var values []int
+	for value, ok := heap.Pop(); ok; value, ok = heap.Pop() {
+		values = append(values, value)
+	}
+	if actualValue, expectedValue := fmt.Sprint(values), "[5 4 3 2 1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBinaryHeapConformance(t *testing.T) {
+	heap := binaryheap.NewWithIntComparator()
+	for i := 0; i < 100; i++ {
+		heap.Push((i * 37) % 100)
+	}
+	containertest.TestSerialization[int](t, heap, func() containers.Container[int] { return binaryheap.NewWithIntComparator() }, true)
+	containertest.TestReverseIteratorWithIndex(t, func(values []int) containers.ReverseIteratorWithIndex[int] {
+		heap := binaryheap.NewWithIntComparator()
+		heap.Push(values...)
+		it := heap.Iterator()
+		return &it
+	})
+}
+
+func FuzzBinaryHeap(f *testing.F) {
+	containertest.FuzzHeap(f, func() containertest.Heap { return binaryheap.NewWithIntComparator() })
+}
+
+func FuzzBinaryHeapNew(f *testing.F) {
+	containertest.FuzzHeap(f, func() containertest.Heap { return binaryheap.New[int]() })
+}
+
 func benchmarkPush(b *testing.B, heap *binaryheap.Heap[int], size int) {
 	for i := 0; i < b.N; i++ {
 		for n := 0; n < size; n++ {
@@ -0,0 +1,47 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package binaryheap
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Assert Graph implementation
+var _ containers.DOTWriter = (*Heap[int])(nil)
+var _ containers.MermaidWriter = (*Heap[int])(nil)
+
+// ToGraph returns the heap as the binary tree of its array layout, where the children of the element at index i
+// are at the indexes 2i+1 and 2i+2. Each node is labeled with its element and its index on a second line.
+// The label hook formats the label of an element, nil formats it with fmt.Sprint.
+func (heap *Heap[V]) ToGraph(label func(value V) string) *containers.Graph {
+	if label == nil {
+		label = func(value V) string { return fmt.Sprint(value) }
+	}
+	graph := &containers.Graph{Name: "binaryheap"}
+	if heap.list == nil {
+		return graph
+	}
+	// nodes are added in array order, so that the node of an element has its index
+	for i, value := range heap.list.Values() {
+		graph.AddNode(containers.GraphNode{Label: fmt.Sprintf("%s\nindex %d", label(value), i)})
+		if i > 0 {
+			graph.AddEdge((i-1)/2, i)
+		}
+	}
+	return graph
+}
+
+// ToDOT writes the heap to the writer in the DOT language of Graphviz, see ToGraph.
+func (heap *Heap[V]) ToDOT(w io.Writer) error {
+	return heap.ToGraph(nil).WriteDOT(w)
+}
+
+// ToMermaid writes the heap to the writer as a Mermaid flowchart, see ToGraph.
+func (heap *Heap[V]) ToMermaid(w io.Writer) error {
+	return heap.ToGraph(nil).WriteMermaid(w)
+}
@@ -7,16 +7,22 @@ package binaryheap
 import "github.com/monitor1379/yagods/containers"
 
 var _ containers.ReverseIteratorWithIndex[int] = (*Iterator[int])(nil)
+var _ containers.FailFastIterator = (*Iterator[int])(nil)
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
-type Iterator[V comparable] struct {
-	heap  *Heap[V]
-	index int
+type Iterator[V any] struct {
+	heap      *Heap[V]
+	index     int
+	modCount  int  // heap's modification count the iterator is synchronized with
+	removed   bool // current element was removed, index points to its predecessor
+	forgotten []V  // elements moved behind the iterator by Remove(), visited after the last index
+	draining  bool // iterator is positioned on value, an element taken from forgotten
+	value     V
 }
 
 // Iterator returns a stateful iterator whose values can be fetched by an index.
 func (heap *Heap[V]) Iterator() Iterator[V] {
-	return Iterator[V]{heap: heap, index: -1}
+	return Iterator[V]{heap: heap, index: -1, modCount: heap.modCount}
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -24,16 +30,33 @@ func (heap *Heap[V]) Iterator() Iterator[V] {
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
 // Modifies the state of the iterator.
 func (iterator *Iterator[V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
 	if iterator.index < iterator.heap.Size() {
 		iterator.index++
 	}
-	return iterator.heap.withinRange(iterator.index)
+	if iterator.heap.withinRange(iterator.index) {
+		return true
+	}
+	if len(iterator.forgotten) > 0 {
+		iterator.value, iterator.forgotten = iterator.forgotten[0], iterator.forgotten[1:]
+		iterator.draining = true
+		return true
+	}
+	iterator.draining = false
+	return false
 }
 
 // Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
 // If Prev() returns true, then previous element's index and value can be retrieved by Index() and Value().
 // Modifies the state of the iterator.
 func (iterator *Iterator[V]) Prev() bool {
+	iterator.checkModification()
+	if iterator.removed {
+		iterator.removed = false
+		return iterator.heap.withinRange(iterator.index)
+	}
+	iterator.draining = false
 	if iterator.index >= 0 {
 		iterator.index--
 	}
@@ -43,6 +66,9 @@ func (iterator *Iterator[V]) Prev() bool {
 // Value returns the current element's value.
 // Does not modify the state of the iterator.
 func (iterator *Iterator[V]) Value() V {
+	if iterator.draining {
+		return iterator.value
+	}
 	value, _ := iterator.heap.list.Get(iterator.index)
 	return value
 }
@@ -57,12 +83,14 @@ func (iterator *Iterator[V]) Index() int {
 // Call Next() to fetch the first element if any.
 func (iterator *Iterator[V]) Begin() {
 	iterator.index = -1
+	iterator.reset()
 }
 
 // End moves the iterator past the last element (one-past-the-end).
 // Call Prev() to fetch the last element if any.
 func (iterator *Iterator[V]) End() {
 	iterator.index = iterator.heap.Size()
+	iterator.reset()
 }
 
 // First moves the iterator to the first element and returns true if there was a first element in the container.
@@ -80,3 +108,78 @@ func (iterator *Iterator[V]) Last() bool {
 	iterator.End()
 	return iterator.Prev()
 }
+
+// Err returns containers.ErrConcurrentModification if the heap was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[V]) Err() error {
+	if iterator.modCount != iterator.heap.modCount && (iterator.index != -1 || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current element from the heap and keeps the iterator valid.
+//
+// Restoring the heap property may move an element that was not visited yet in front of the iterator.
+// Such elements are remembered and visited by Next() after the last index, in which case Index() returns the heap's size.
+// Does nothing if the iterator is not positioned on an element.
+func (iterator *Iterator[V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed {
+		return
+	}
+	if iterator.draining {
+		for index := 0; index < iterator.heap.Size(); index++ {
+			if value, _ := iterator.heap.list.Get(index); iterator.heap.Comparator(value, iterator.value) == 0 && same(value, iterator.value) {
+				iterator.heap.removeIndex(index)
+				break
+			}
+		}
+		iterator.modCount = iterator.heap.modCount
+		iterator.index = iterator.heap.Size()
+		iterator.draining = false
+		return
+	}
+	if !iterator.heap.withinRange(iterator.index) {
+		return
+	}
+	moved, movedUp := iterator.heap.removeIndex(iterator.index)
+	iterator.modCount = iterator.heap.modCount
+	if movedUp {
+		// index now holds an already visited element, continue right after it
+		iterator.forgotten = append(iterator.forgotten, moved)
+	} else {
+		// index now holds the moved element, visit it next
+		iterator.index--
+	}
+	iterator.removed = true
+}
+
+// same reports whether the values are the same element of a heap, i.e. equal with the == operator.
+// Values of a type that is not comparable are the same if the comparator finds them equal.
+func same[V any](a, b V) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = true
+		}
+	}()
+	return any(a) == any(b)
+}
+
+// reset synchronizes the iterator with the heap and forgets any state left over from Remove().
+func (iterator *Iterator[V]) reset() {
+	iterator.modCount = iterator.heap.modCount
+	iterator.removed = false
+	iterator.forgotten = nil
+	iterator.draining = false
+}
+
+// checkModification panics if the heap was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the heap.
+func (iterator *Iterator[V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.heap.modCount
+}
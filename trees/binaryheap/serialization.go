@@ -4,10 +4,27 @@
 
 package binaryheap
 
-import "github.com/monitor1379/yagods/containers"
+import (
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/lists/arraylist"
+	"github.com/monitor1379/yagods/utils"
+)
 
 var _ containers.JSONSerializer = (*Heap[int])(nil)
 var _ containers.JSONDeserializer = (*Heap[string])(nil)
+var _ encoding.BinaryMarshaler = (*Heap[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*Heap[int])(nil)
+var _ gob.GobEncoder = (*Heap[int])(nil)
+var _ gob.GobDecoder = (*Heap[int])(nil)
+var _ json.Marshaler = (*Heap[int])(nil)
+var _ json.Unmarshaler = (*Heap[int])(nil)
+var _ containers.JSONWriter = (*Heap[int])(nil)
+var _ containers.JSONReader = (*Heap[int])(nil)
 
 // ToJSON outputs the JSON representation of the heap.
 func (heap *Heap[V]) ToJSON() ([]byte, error) {
@@ -16,5 +33,91 @@ func (heap *Heap[V]) ToJSON() ([]byte, error) {
 
 // FromJSON populates the heap from the input JSON representation.
 func (heap *Heap[V]) FromJSON(data []byte) error {
+	heap.modCount++
 	return heap.list.FromJSON(data)
 }
+
+// MarshalBinary outputs the binary representation of the heap.
+func (heap *Heap[V]) MarshalBinary() ([]byte, error) {
+	return heap.list.MarshalBinary()
+}
+
+// UnmarshalBinary populates the heap from the input binary representation.
+// The elements keep their order, which is a valid heap only for the comparator of the marshaled heap.
+func (heap *Heap[V]) UnmarshalBinary(data []byte) error {
+	if heap.list == nil {
+		heap.list = &arraylist.List[V]{}
+	}
+	heap.modCount++
+	return heap.list.UnmarshalBinary(data)
+}
+
+// GobEncode outputs the binary representation of the heap for encoding/gob, see MarshalBinary.
+func (heap *Heap[V]) GobEncode() ([]byte, error) {
+	return heap.MarshalBinary()
+}
+
+// GobDecode populates the heap from the binary representation for encoding/gob, see UnmarshalBinary.
+func (heap *Heap[V]) GobDecode(data []byte) error {
+	return heap.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the heap for encoding/json: an array of its elements in array order.
+func (heap *Heap[V]) MarshalJSON() ([]byte, error) {
+	if heap.list == nil {
+		return []byte("[]"), nil
+	}
+	return heap.list.MarshalJSON()
+}
+
+// UnmarshalJSON populates the heap from the JSON representation for encoding/json, pushing the elements,
+// so that any array of elements is accepted. The zero value of a heap is ordered by the default comparator
+// of its elements, see utils.DefaultComparator; returns containers.ErrNoComparator if they have none.
+func (heap *Heap[V]) UnmarshalJSON(data []byte) error {
+	if heap.Comparator == nil {
+		if heap.Comparator = utils.DefaultComparator[V](); heap.Comparator == nil {
+			return containers.ErrNoComparator
+		}
+	}
+	var values []V
+	err := json.Unmarshal(data, &values)
+	if err == nil {
+		if heap.list == nil {
+			heap.list = &arraylist.List[V]{}
+		}
+		heap.Clear()
+		heap.Push(values...)
+	}
+	return err
+}
+
+// WriteJSON writes the JSON representation of the heap to the writer, see MarshalJSON.
+func (heap *Heap[V]) WriteJSON(w io.Writer) error {
+	if heap.list == nil {
+		_, err := io.WriteString(w, "[]")
+		return err
+	}
+	return heap.list.WriteJSON(w)
+}
+
+// ReadJSON populates the heap from the JSON representation read from the reader, see UnmarshalJSON.
+// The elements are added as they are decoded and the heap is built once the input is consumed.
+// On error, the heap holds the elements read before the error.
+func (heap *Heap[V]) ReadJSON(r io.Reader) error {
+	if heap.Comparator == nil {
+		if heap.Comparator = utils.DefaultComparator[V](); heap.Comparator == nil {
+			return containers.ErrNoComparator
+		}
+	}
+	if heap.list == nil {
+		heap.list = &arraylist.List[V]{}
+	}
+	heap.Clear()
+	err := containers.ReadJSONValues(r, func(value V) {
+		heap.list.Add(value)
+	})
+	for i := heap.list.Size() / 2; i >= 0; i-- {
+		heap.bubbleDownIndex(i)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package binaryheap
+
+import (
+	"fmt"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/lists/arraylist"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// MarshalStructure outputs the structure of the heap, its elements in array layout.
+// It is the binary representation of MarshalBinary, which keeps the layout as well.
+func (heap *Heap[V]) MarshalStructure() ([]byte, error) {
+	if heap.list == nil {
+		return containers.EncodeValues[V](containers.CodecFor[V](), nil)
+	}
+	return heap.list.MarshalBinary()
+}
+
+// UnmarshalStructure replaces the elements of the heap with the array layout of the marshaled heap, in O(n) time
+// without re-heapifying. Returns an error wrapping containers.ErrInvalidBinary if the data is not in the binary format,
+// or containers.ErrInvalidStructure if an element is smaller than its parent according to the comparator of the heap.
+// The heap is left unchanged on error. The zero value of a heap is ordered by the default comparator of its elements,
+// see utils.DefaultComparator; returns containers.ErrNoComparator if they have none.
+func (heap *Heap[V]) UnmarshalStructure(data []byte) error {
+	if heap.Comparator == nil {
+		if heap.Comparator = utils.DefaultComparator[V](); heap.Comparator == nil {
+			return containers.ErrNoComparator
+		}
+	}
+	values, err := containers.DecodeValues(containers.CodecFor[V](), data)
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(values); i++ {
+		if parent := (i - 1) / 2; heap.Comparator(values[parent], values[i]) > 0 {
+			return fmt.Errorf("binaryheap: element %v at index %d is smaller than its parent %v at index %d: %w",
+				values[i], i, values[parent], parent, containers.ErrInvalidStructure)
+		}
+	}
+	if heap.list == nil {
+		heap.list = &arraylist.List[V]{}
+	}
+	heap.Clear()
+	heap.list.Add(values...)
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package binaryheap
+
+import (
+	"fmt"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Validate checks the heap property: no element is smaller than its parent according to the comparator.
+// Returns nil for a valid heap, otherwise an error wrapping containers.ErrInvalidStructure naming the offending indexes.
+func (heap *Heap[V]) Validate() error {
+	if heap.list == nil {
+		return nil
+	}
+	for i := 1; i < heap.list.Size(); i++ {
+		parent := (i - 1) / 2
+		value, _ := heap.list.Get(i)
+		parentValue, _ := heap.list.Get(parent)
+		if heap.Comparator(parentValue, value) > 0 {
+			return fmt.Errorf("binaryheap: element %v at index %d is smaller than its parent %v at index %d: %w",
+				value, i, parentValue, parent, containers.ErrInvalidStructure)
+		}
+	}
+	return nil
+}
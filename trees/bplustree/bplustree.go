@@ -0,0 +1,595 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bplustree implements a B+ tree.
+//
+// A B+ tree of order m is a B-tree whose internal nodes only hold keys routing the search,
+// while all key-value pairs are stored in the leaves:
+// - Every node has at most m children and every leaf at most m-1 entries.
+// - Every non-leaf node (except root) has at least ⌈m/2⌉ children.
+// - Every leaf (except root) has at least ⌈m/2⌉-1 entries.
+// - All leaves appear in the same level and are linked to their neighbours.
+//
+// Linked leaves let iterators move between leaves without climbing up the tree, which makes range scans cheap.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/B%2B_tree
+package bplustree
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/monitor1379/yagods/trees"
+	"github.com/monitor1379/yagods/utils"
+)
+
+var _ trees.Tree[int, string] = (*Tree[int, string])(nil)
+
+// Tree holds elements of the B+ tree
+type Tree[K comparable, V any] struct {
+	Root       *Node[K, V]         // Root node
+	Comparator utils.Comparator[K] // Key comparator
+	size       int                 // Total number of keys in the tree
+	m          int                 // order (maximum number of children)
+	modCount   int                 // number of structural modifications, checked by iterators
+}
+
+// Node is a single element within the tree, either an internal node or a leaf
+type Node[K comparable, V any] struct {
+	Parent   *Node[K, V]
+	Keys     []K           // Routing keys of an internal node or keys of a leaf's entries
+	Children []*Node[K, V] // Children nodes of an internal node
+	Values   []V           // Values of a leaf's entries
+	Prev     *Node[K, V]   // Previous leaf
+	Next     *Node[K, V]   // Next leaf
+}
+
+// NewWith instantiates a B+ tree with the order (maximum number of children) and a custom key comparator.
+func NewWith[K comparable, V any](order int, comparator utils.Comparator[K]) *Tree[K, V] {
+	if order < 3 {
+		panic("Invalid order, should be at least 3")
+	}
+	return &Tree[K, V]{m: order, Comparator: comparator}
+}
+
+// NewWithIntComparator instantiates a B+ tree with the order (maximum number of children) and the IntComparator, i.e. keys are of type int.
+func NewWithIntComparator[V any](order int) *Tree[int, V] {
+	return NewWith[int, V](order, utils.NumberComparator[int])
+}
+
+// NewWithStringComparator instantiates a B+ tree with the order (maximum number of children) and the StringComparator, i.e. keys are of type string.
+func NewWithStringComparator[V any](order int) *Tree[string, V] {
+	return NewWith[string, V](order, utils.StringComparator)
+}
+
+// Put inserts key-value pair node into the tree.
+// If key already exists, then its value is updated with the new value.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Put(key K, value V) {
+	if tree.Root == nil {
+		// Assert key is of comparator's type for initial tree
+		tree.Comparator(key, key)
+		tree.Root = &Node[K, V]{Keys: []K{key}, Values: []V{value}}
+		tree.size++
+		tree.modCount++
+		return
+	}
+	leaf := tree.leaf(key)
+	index, found := tree.search(leaf, key)
+	if found {
+		leaf.Keys[index] = key
+		leaf.Values[index] = value
+		return
+	}
+	var zeroK K
+	var zeroV V
+	leaf.Keys = append(leaf.Keys, zeroK)
+	copy(leaf.Keys[index+1:], leaf.Keys[index:])
+	leaf.Keys[index] = key
+	leaf.Values = append(leaf.Values, zeroV)
+	copy(leaf.Values[index+1:], leaf.Values[index:])
+	leaf.Values[index] = value
+	tree.size++
+	tree.modCount++
+	if len(leaf.Keys) > tree.maxEntries() {
+		tree.splitLeaf(leaf)
+	}
+}
+
+// Get searches the node in the tree by key and returns its value or nil if key is not found in tree.
+// Second return parameter is true if key was found, otherwise false.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Get(key K) (value V, found bool) {
+	if tree.Root == nil {
+		return value, false
+	}
+	leaf := tree.leaf(key)
+	if index, found := tree.search(leaf, key); found {
+		return leaf.Values[index], true
+	}
+	return value, false
+}
+
+// Remove remove the node from the tree by key.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Remove(key K) {
+	if tree.Root == nil {
+		return
+	}
+	leaf := tree.leaf(key)
+	index, found := tree.search(leaf, key)
+	if !found {
+		return
+	}
+	var zeroK K
+	var zeroV V
+	copy(leaf.Keys[index:], leaf.Keys[index+1:])
+	leaf.Keys[len(leaf.Keys)-1] = zeroK
+	leaf.Keys = leaf.Keys[:len(leaf.Keys)-1]
+	copy(leaf.Values[index:], leaf.Values[index+1:])
+	leaf.Values[len(leaf.Values)-1] = zeroV
+	leaf.Values = leaf.Values[:len(leaf.Values)-1]
+	tree.size--
+	tree.modCount++
+	tree.rebalanceLeaf(leaf)
+}
+
+// Empty returns true if tree does not contain any nodes
+func (tree *Tree[K, V]) Empty() bool {
+	return tree.size == 0
+}
+
+// Size returns number of nodes in the tree.
+func (tree *Tree[K, V]) Size() int {
+	return tree.size
+}
+
+// Keys returns all keys in-order
+func (tree *Tree[K, V]) Keys() []K {
+	keys := make([]K, 0, tree.size)
+	for leaf := tree.Left(); leaf != nil; leaf = leaf.Next {
+		keys = append(keys, leaf.Keys...)
+	}
+	return keys
+}
+
+// Values returns all values in-order based on the key.
+func (tree *Tree[K, V]) Values() []V {
+	values := make([]V, 0, tree.size)
+	for leaf := tree.Left(); leaf != nil; leaf = leaf.Next {
+		values = append(values, leaf.Values...)
+	}
+	return values
+}
+
+// InterfaceValues returns all elements in the l as type interface{}.
+func (tree *Tree[K, V]) InterfaceValues() []interface{} {
+	values := make([]interface{}, 0, tree.size)
+	for leaf := tree.Left(); leaf != nil; leaf = leaf.Next {
+		for _, value := range leaf.Values {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Clear removes all nodes from the tree.
+func (tree *Tree[K, V]) Clear() {
+	tree.Root = nil
+	tree.size = 0
+	tree.modCount++
+}
+
+// Height returns the height of the tree.
+func (tree *Tree[K, V]) Height() int {
+	height := 0
+	for node := tree.Root; node != nil; node = node.firstChild() {
+		height++
+	}
+	return height
+}
+
+// Left returns the left-most (min) leaf or nil if tree is empty.
+func (tree *Tree[K, V]) Left() *Node[K, V] {
+	node := tree.Root
+	for node != nil && !node.isLeaf() {
+		node = node.Children[0]
+	}
+	return node
+}
+
+// LeftKey returns the left-most (min) key or nil if tree is empty.
+func (tree *Tree[K, V]) LeftKey() K {
+	if left := tree.Left(); left != nil {
+		return left.Keys[0]
+	}
+	var zeroK K
+	return zeroK
+}
+
+// LeftValue returns the left-most value or nil if tree is empty.
+func (tree *Tree[K, V]) LeftValue() V {
+	if left := tree.Left(); left != nil {
+		return left.Values[0]
+	}
+	var zeroV V
+	return zeroV
+}
+
+// Right returns the right-most (max) leaf or nil if tree is empty.
+func (tree *Tree[K, V]) Right() *Node[K, V] {
+	node := tree.Root
+	for node != nil && !node.isLeaf() {
+		node = node.Children[len(node.Children)-1]
+	}
+	return node
+}
+
+// RightKey returns the right-most (max) key or nil if tree is empty.
+func (tree *Tree[K, V]) RightKey() K {
+	if right := tree.Right(); right != nil {
+		return right.Keys[len(right.Keys)-1]
+	}
+	var zeroK K
+	return zeroK
+}
+
+// RightValue returns the right-most value or nil if tree is empty.
+func (tree *Tree[K, V]) RightValue() V {
+	if right := tree.Right(); right != nil {
+		return right.Values[len(right.Values)-1]
+	}
+	var zeroV V
+	return zeroV
+}
+
+// Floor finds the floor key-value pair for the input key.
+// Third return parameter is true if floor was found, otherwise false.
+//
+// Floor key is defined as the largest key that is smaller than or equal to the given key.
+// A floor key may not be found, either because the tree is empty, or because
+// all keys in the tree are larger than the given key.
+//
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Floor(key K) (floorKey K, floorValue V, found bool) {
+	if leaf, index := tree.floor(key); leaf != nil {
+		return leaf.Keys[index], leaf.Values[index], true
+	}
+	return floorKey, floorValue, false
+}
+
+// Ceiling finds the ceiling key-value pair for the input key.
+// Third return parameter is true if ceiling was found, otherwise false.
+//
+// Ceiling key is defined as the smallest key that is larger than or equal to the given key.
+// A ceiling key may not be found, either because the tree is empty, or because
+// all keys in the tree are smaller than the given key.
+//
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Ceiling(key K) (ceilingKey K, ceilingValue V, found bool) {
+	if leaf, index := tree.ceiling(key); leaf != nil {
+		return leaf.Keys[index], leaf.Values[index], true
+	}
+	return ceilingKey, ceilingValue, false
+}
+
+// String returns a string representation of container (for debugging purposes)
+func (tree *Tree[K, V]) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("BPlusTree\n")
+	if !tree.Empty() {
+		tree.output(&buffer, tree.Root, 0)
+	}
+	return buffer.String()
+}
+
+func (tree *Tree[K, V]) output(buffer *bytes.Buffer, node *Node[K, V], level int) {
+	for e := 0; e < len(node.Keys)+1; e++ {
+		if e < len(node.Children) {
+			tree.output(buffer, node.Children[e], level+1)
+		}
+		if e < len(node.Keys) {
+			buffer.WriteString(strings.Repeat("    ", level))
+			buffer.WriteString(fmt.Sprintf("%v", node.Keys[e]) + "\n")
+		}
+	}
+}
+
+func (node *Node[K, V]) isLeaf() bool {
+	return len(node.Children) == 0
+}
+
+func (node *Node[K, V]) firstChild() *Node[K, V] {
+	if node.isLeaf() {
+		return nil
+	}
+	return node.Children[0]
+}
+
+func (tree *Tree[K, V]) maxEntries() int {
+	return tree.m - 1
+}
+
+func (tree *Tree[K, V]) minEntries() int {
+	return (tree.m+1)/2 - 1 // ceil(m/2)-1
+}
+
+func (tree *Tree[K, V]) minChildren() int {
+	return (tree.m + 1) / 2 // ceil(m/2)
+}
+
+// search searches only within the single node among its keys and returns the index of the first key
+// larger than or equal to the given key
+func (tree *Tree[K, V]) search(node *Node[K, V], key K) (index int, found bool) {
+	low, high := 0, len(node.Keys)-1
+	for low <= high {
+		mid := (high + low) / 2
+		compare := tree.Comparator(key, node.Keys[mid])
+		switch {
+		case compare > 0:
+			low = mid + 1
+		case compare < 0:
+			high = mid - 1
+		default:
+			return mid, true
+		}
+	}
+	return low, false
+}
+
+// route returns the index of the child of an internal node whose subtree holds the given key
+func (tree *Tree[K, V]) route(node *Node[K, V], key K) int {
+	index, found := tree.search(node, key)
+	if found {
+		index++
+	}
+	return index
+}
+
+// leaf returns the leaf whose range of keys holds the given key, the tree must not be empty
+func (tree *Tree[K, V]) leaf(key K) *Node[K, V] {
+	node := tree.Root
+	for !node.isLeaf() {
+		node = node.Children[tree.route(node, key)]
+	}
+	return node
+}
+
+// floor returns the leaf and entry index of the largest key smaller than or equal to the given key,
+// or (nil, -1) if there is no such key.
+func (tree *Tree[K, V]) floor(key K) (*Node[K, V], int) {
+	if tree.Root == nil {
+		return nil, -1
+	}
+	leaf := tree.leaf(key)
+	index, found := tree.search(leaf, key)
+	if found {
+		return leaf, index
+	}
+	if index > 0 {
+		return leaf, index - 1
+	}
+	if leaf.Prev != nil {
+		return leaf.Prev, len(leaf.Prev.Keys) - 1
+	}
+	return nil, -1
+}
+
+// ceiling returns the leaf and entry index of the smallest key larger than or equal to the given key,
+// or (nil, -1) if there is no such key.
+func (tree *Tree[K, V]) ceiling(key K) (*Node[K, V], int) {
+	if tree.Root == nil {
+		return nil, -1
+	}
+	leaf := tree.leaf(key)
+	index, _ := tree.search(leaf, key)
+	if index < len(leaf.Keys) {
+		return leaf, index
+	}
+	if leaf.Next != nil {
+		return leaf.Next, 0
+	}
+	return nil, -1
+}
+
+// splitLeaf moves the upper half of an overfull leaf into a new leaf and copies its first key into the parent
+func (tree *Tree[K, V]) splitLeaf(leaf *Node[K, V]) {
+	middle := len(leaf.Keys) / 2
+	right := &Node[K, V]{
+		Keys:   append([]K(nil), leaf.Keys[middle:]...),
+		Values: append([]V(nil), leaf.Values[middle:]...),
+		Prev:   leaf,
+		Next:   leaf.Next,
+	}
+	if leaf.Next != nil {
+		leaf.Next.Prev = right
+	}
+	leaf.Next = right
+	leaf.Keys = append([]K(nil), leaf.Keys[:middle]...)
+	leaf.Values = append([]V(nil), leaf.Values[:middle]...)
+	tree.insertIntoParent(leaf, right.Keys[0], right)
+}
+
+// splitInternal moves the upper half of an overfull internal node into a new node and moves its middle key into the parent
+func (tree *Tree[K, V]) splitInternal(node *Node[K, V]) {
+	middle := len(node.Keys) / 2
+	key := node.Keys[middle]
+	right := &Node[K, V]{
+		Keys:     append([]K(nil), node.Keys[middle+1:]...),
+		Children: append([]*Node[K, V](nil), node.Children[middle+1:]...),
+	}
+	setParent(right.Children, right)
+	node.Keys = append([]K(nil), node.Keys[:middle]...)
+	node.Children = append([]*Node[K, V](nil), node.Children[:middle+1]...)
+	tree.insertIntoParent(node, key, right)
+}
+
+// insertIntoParent links the new right sibling of the node with the key separating them into the parent
+func (tree *Tree[K, V]) insertIntoParent(node *Node[K, V], key K, right *Node[K, V]) {
+	parent := node.Parent
+	if parent == nil {
+		tree.Root = &Node[K, V]{Keys: []K{key}, Children: []*Node[K, V]{node, right}}
+		setParent(tree.Root.Children, tree.Root)
+		return
+	}
+	index := tree.route(parent, key)
+	parent.Keys = append(parent.Keys, key)
+	copy(parent.Keys[index+1:], parent.Keys[index:])
+	parent.Keys[index] = key
+	parent.Children = append(parent.Children, nil)
+	copy(parent.Children[index+2:], parent.Children[index+1:])
+	parent.Children[index+1] = right
+	right.Parent = parent
+	if len(parent.Children) > tree.m {
+		tree.splitInternal(parent)
+	}
+}
+
+// rebalanceLeaf refills a leaf that has too few entries after a removal
+// by borrowing an entry from or merging with a sibling.
+func (tree *Tree[K, V]) rebalanceLeaf(leaf *Node[K, V]) {
+	if leaf == tree.Root {
+		if len(leaf.Keys) == 0 {
+			tree.Root = nil
+		}
+		return
+	}
+	if len(leaf.Keys) >= tree.minEntries() {
+		return
+	}
+	parent := leaf.Parent
+	index := childIndex(parent, leaf)
+
+	// try to borrow from left sibling
+	if index > 0 {
+		left := parent.Children[index-1]
+		if last := len(left.Keys) - 1; last+1 > tree.minEntries() {
+			leaf.Keys = append([]K{left.Keys[last]}, leaf.Keys...)
+			leaf.Values = append([]V{left.Values[last]}, leaf.Values...)
+			left.Keys, left.Values = left.Keys[:last], left.Values[:last]
+			parent.Keys[index-1] = leaf.Keys[0]
+			return
+		}
+	}
+
+	// try to borrow from right sibling
+	if index < len(parent.Children)-1 {
+		right := parent.Children[index+1]
+		if len(right.Keys) > tree.minEntries() {
+			leaf.Keys = append(leaf.Keys, right.Keys[0])
+			leaf.Values = append(leaf.Values, right.Values[0])
+			right.Keys = append([]K(nil), right.Keys[1:]...)
+			right.Values = append([]V(nil), right.Values[1:]...)
+			parent.Keys[index] = right.Keys[0]
+			return
+		}
+	}
+
+	// merge with siblings
+	if index > 0 {
+		tree.mergeLeaves(parent.Children[index-1], leaf)
+		deleteKeyAndChild(parent, index-1)
+	} else {
+		tree.mergeLeaves(leaf, parent.Children[index+1])
+		deleteKeyAndChild(parent, index)
+	}
+	tree.rebalanceInternal(parent)
+}
+
+// mergeLeaves moves all entries of the right leaf into its left neighbour and unlinks the right leaf
+func (tree *Tree[K, V]) mergeLeaves(left *Node[K, V], right *Node[K, V]) {
+	left.Keys = append(left.Keys, right.Keys...)
+	left.Values = append(left.Values, right.Values...)
+	left.Next = right.Next
+	if right.Next != nil {
+		right.Next.Prev = left
+	}
+}
+
+// rebalanceInternal refills an internal node that has too few children after a merge
+// by rotating a child through the parent from a sibling or merging with a sibling.
+func (tree *Tree[K, V]) rebalanceInternal(node *Node[K, V]) {
+	if node == tree.Root {
+		if len(node.Keys) == 0 {
+			tree.Root = node.Children[0]
+			tree.Root.Parent = nil
+		}
+		return
+	}
+	if len(node.Children) >= tree.minChildren() {
+		return
+	}
+	parent := node.Parent
+	index := childIndex(parent, node)
+
+	// try to borrow from left sibling
+	if index > 0 {
+		left := parent.Children[index-1]
+		if last := len(left.Children) - 1; last+1 > tree.minChildren() {
+			child := left.Children[last]
+			child.Parent = node
+			node.Keys = append([]K{parent.Keys[index-1]}, node.Keys...)
+			node.Children = append([]*Node[K, V]{child}, node.Children...)
+			parent.Keys[index-1] = left.Keys[last-1]
+			left.Keys, left.Children = left.Keys[:last-1], left.Children[:last]
+			return
+		}
+	}
+
+	// try to borrow from right sibling
+	if index < len(parent.Children)-1 {
+		right := parent.Children[index+1]
+		if len(right.Children) > tree.minChildren() {
+			child := right.Children[0]
+			child.Parent = node
+			node.Keys = append(node.Keys, parent.Keys[index])
+			node.Children = append(node.Children, child)
+			parent.Keys[index] = right.Keys[0]
+			right.Keys = append([]K(nil), right.Keys[1:]...)
+			right.Children = append([]*Node[K, V](nil), right.Children[1:]...)
+			return
+		}
+	}
+
+	// merge with siblings
+	left, right, separator := node, node, index
+	if index > 0 {
+		left, separator = parent.Children[index-1], index-1
+	} else {
+		right = parent.Children[index+1]
+	}
+	left.Keys = append(append(left.Keys, parent.Keys[separator]), right.Keys...)
+	left.Children = append(left.Children, right.Children...)
+	setParent(right.Children, left)
+	deleteKeyAndChild(parent, separator)
+	tree.rebalanceInternal(parent)
+}
+
+// childIndex returns the index of the child within its parent's children
+func childIndex[K comparable, V any](parent *Node[K, V], child *Node[K, V]) int {
+	for index, node := range parent.Children {
+		if node == child {
+			return index
+		}
+	}
+	return -1
+}
+
+// deleteKeyAndChild removes the key at index and the child to its right from an internal node
+func deleteKeyAndChild[K comparable, V any](node *Node[K, V], index int) {
+	copy(node.Keys[index:], node.Keys[index+1:])
+	node.Keys = node.Keys[:len(node.Keys)-1]
+	copy(node.Children[index+1:], node.Children[index+2:])
+	node.Children[len(node.Children)-1] = nil
+	node.Children = node.Children[:len(node.Children)-1]
+}
+
+func setParent[K comparable, V any](nodes []*Node[K, V], parent *Node[K, V]) {
+	for _, node := range nodes {
+		node.Parent = parent
+	}
+}
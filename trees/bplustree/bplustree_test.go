@@ -0,0 +1,804 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bplustree_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
+	"github.com/monitor1379/yagods/trees/bplustree"
+	"github.com/monitor1379/yagods/trees/btree"
+)
+
+func TestBPlusTreeGet(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	if actualValue, found := tree.Get(1); actualValue != "" || found {
+		t.Errorf("Got %v expected %v", actualValue, "")
+	}
+	tree.Put(1, "a")
+	tree.Put(2, "b")
+	tree.Put(3, "c")
+	tree.Put(4, "d")
+	tree.Put(5, "e")
+	tree.Put(6, "f")
+	tree.Put(7, "g")
+
+	tests := [][]interface{}{
+		{0, "", false},
+		{1, "a", true},
+		{2, "b", true},
+		{3, "c", true},
+		{4, "d", true},
+		{5, "e", true},
+		{6, "f", true},
+		{7, "g", true},
+		{8, "", false},
+	}
+
+	for _, test := range tests {
+		if value, found := tree.Get(test[0].(int)); value != test[1] || found != test[2] {
+			t.Errorf("Got %v,%v expected %v,%v", value, found, test[1], test[2])
+		}
+	}
+}
+
+func TestBPlusTreePut(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	assertValidTree(t, tree, 3, 0)
+
+	tree.Put(1, "a")
+	assertValidTree(t, tree, 3, 1)
+	assertValidTreeNode(t, tree.Root, []int{1}, 0, false)
+
+	tree.Put(2, "b")
+	assertValidTree(t, tree, 3, 2)
+	assertValidTreeNode(t, tree.Root, []int{1, 2}, 0, false)
+
+	tree.Put(3, "c")
+	assertValidTree(t, tree, 3, 3)
+	assertValidTreeNode(t, tree.Root, []int{2}, 2, false)
+	assertValidTreeNode(t, tree.Root.Children[0], []int{1}, 0, true)
+	assertValidTreeNode(t, tree.Root.Children[1], []int{2, 3}, 0, true)
+
+	tree.Put(4, "d")
+	assertValidTree(t, tree, 3, 4)
+	assertValidTreeNode(t, tree.Root, []int{2, 3}, 3, false)
+	assertValidTreeNode(t, tree.Root.Children[0], []int{1}, 0, true)
+	assertValidTreeNode(t, tree.Root.Children[1], []int{2}, 0, true)
+	assertValidTreeNode(t, tree.Root.Children[2], []int{3, 4}, 0, true)
+
+	tree.Put(5, "e")
+	assertValidTree(t, tree, 3, 5)
+	assertValidTreeNode(t, tree.Root, []int{3}, 2, false)
+	assertValidTreeNode(t, tree.Root.Children[0], []int{2}, 2, true)
+	assertValidTreeNode(t, tree.Root.Children[1], []int{4}, 2, true)
+	assertValidTreeNode(t, tree.Root.Children[1].Children[0], []int{3}, 0, true)
+	assertValidTreeNode(t, tree.Root.Children[1].Children[1], []int{4, 5}, 0, true)
+
+	tree.Put(5, "E")
+	assertValidTree(t, tree, 3, 5)
+	if actualValue, expectedValue := fmt.Sprint(tree.Values()), "[a b c d E]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBPlusTreeRemove(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	tree.Remove(1)
+	assertValidTree(t, tree, 3, 0)
+
+	for i := 1; i <= 7; i++ {
+		tree.Put(i, fmt.Sprint(i))
+	}
+	assertValidTree(t, tree, 3, 7)
+
+	tree.Remove(8)
+	assertValidTree(t, tree, 3, 7)
+
+	tree.Remove(4)
+	tree.Remove(1)
+	tree.Remove(7)
+	assertValidTree(t, tree, 3, 4)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[2 3 5 6]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	for _, key := range []int{3, 2, 6, 5} {
+		tree.Remove(key)
+	}
+	assertValidTree(t, tree, 3, 0)
+	if actualValue := tree.Root; actualValue != nil {
+		t.Errorf("Got %v expected %v", actualValue, nil)
+	}
+}
+
+func TestBPlusTreeRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for order := 3; order <= 8; order++ {
+		tree := bplustree.NewWithIntComparator[int](order)
+		expected := make(map[int]int)
+		for i := 0; i < 2000; i++ {
+			key := r.Intn(300)
+			if r.Intn(3) == 0 {
+				tree.Remove(key)
+				delete(expected, key)
+			} else {
+				tree.Put(key, i)
+				expected[key] = i
+			}
+			if i%100 == 0 {
+				assertValidTree(t, tree, order, len(expected))
+			}
+		}
+		assertValidTree(t, tree, order, len(expected))
+		keys := make([]int, 0, len(expected))
+		for key := range expected {
+			keys = append(keys, key)
+		}
+		sort.Ints(keys)
+		if actualValue, expectedValue := fmt.Sprint(tree.Keys()), fmt.Sprint(keys); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		for key, value := range expected {
+			if actualValue, found := tree.Get(key); actualValue != value || !found {
+				t.Errorf("Got %v,%v expected %v,%v", actualValue, found, value, true)
+			}
+		}
+	}
+}
+
+func TestBPlusTreeHeight(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[int](3)
+	if actualValue, expectedValue := tree.Height(), 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	tree.Put(1, 0)
+	if actualValue, expectedValue := tree.Height(), 1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for i := 2; i <= 5; i++ {
+		tree.Put(i, 0)
+	}
+	if actualValue, expectedValue := tree.Height(), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for i := 1; i <= 4; i++ {
+		tree.Remove(i)
+	}
+	if actualValue, expectedValue := tree.Height(), 1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBPlusTreeLeftAndRight(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+
+	if actualValue := tree.Left(); actualValue != nil {
+		t.Errorf("Got %v expected %v", actualValue, nil)
+	}
+	if actualValue := tree.Right(); actualValue != nil {
+		t.Errorf("Got %v expected %v", actualValue, nil)
+	}
+
+	tree.Put(1, "a")
+	tree.Put(5, "e")
+	tree.Put(6, "f")
+	tree.Put(7, "g")
+	tree.Put(3, "c")
+	tree.Put(4, "d")
+	tree.Put(1, "x") // overwrite
+	tree.Put(2, "b")
+
+	if actualValue, expectedValue := tree.LeftKey(), 1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := tree.LeftValue(), "x"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := tree.RightKey(), 7; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := tree.RightValue(), "g"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBPlusTreeFloorAndCeiling(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	if _, _, found := tree.Floor(1); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if _, _, found := tree.Ceiling(1); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	for i := 10; i <= 100; i += 10 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	tests := [][]interface{}{
+		{5, 0, "", false, 10, "10", true},
+		{10, 10, "10", true, 10, "10", true},
+		{15, 10, "10", true, 20, "20", true},
+		{49, 40, "40", true, 50, "50", true},
+		{50, 50, "50", true, 50, "50", true},
+		{99, 90, "90", true, 100, "100", true},
+		{100, 100, "100", true, 100, "100", true},
+		{105, 100, "100", true, 0, "", false},
+	}
+
+	for _, test := range tests {
+		key := test[0].(int)
+		if actualKey, actualValue, found := tree.Floor(key); actualKey != test[1] || actualValue != test[2] || found != test[3] {
+			t.Errorf("Got %v,%v,%v expected %v,%v,%v", actualKey, actualValue, found, test[1], test[2], test[3])
+		}
+		if actualKey, actualValue, found := tree.Ceiling(key); actualKey != test[4] || actualValue != test[5] || found != test[6] {
+			t.Errorf("Got %v,%v,%v expected %v,%v,%v", actualKey, actualValue, found, test[4], test[5], test[6])
+		}
+	}
+}
+
+func TestBPlusTreeIteratorValuesAndKeys(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](4)
+	tree.Put(4, "d")
+	tree.Put(5, "e")
+	tree.Put(6, "f")
+	tree.Put(3, "c")
+	tree.Put(1, "a")
+	tree.Put(7, "g")
+	tree.Put(2, "b")
+	tree.Put(1, "x") // override
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[1 2 3 4 5 6 7]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Values()), "[x b c d e f g]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.InterfaceValues()), "[x b c d e f g]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := tree.Size(); actualValue != 7 {
+		t.Errorf("Got %v expected %v", actualValue, 7)
+	}
+}
+
+func TestBPlusTreeIteratorOnEmpty(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	it := tree.Iterator()
+	for it.Next() {
+		t.Errorf("Shouldn't iterate on empty tree")
+	}
+	for it.Prev() {
+		t.Errorf("Shouldn't iterate on empty tree")
+	}
+	if it.First() || it.Last() {
+		t.Errorf("Shouldn't iterate on empty tree")
+	}
+}
+
+func TestBPlusTreeIteratorNextAndPrev(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[int](3)
+	for i := 20; i >= 1; i-- {
+		tree.Put(i, i*10)
+	}
+	it := tree.Iterator()
+	count := 0
+	for it.Next() {
+		count++
+		if actualValue, expectedValue := it.Key(), count; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := it.Value(), count*10; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if actualValue, expectedValue := count, 20; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for it.Prev() {
+		if actualValue, expectedValue := it.Key(), count; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		count--
+	}
+	if actualValue, expectedValue := count, 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBPlusTreeIteratorFirstAndLast(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	tree.Put(3, "c")
+	tree.Put(1, "a")
+	tree.Put(2, "b")
+	it := tree.Iterator()
+	if actualValue, expectedValue := it.First(), true; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, value := it.Key(), it.Value(); key != 1 || value != "a" {
+		t.Errorf("Got %v,%v expected %v,%v", key, value, 1, "a")
+	}
+	if actualValue, expectedValue := it.Last(), true; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if key, value := it.Key(), it.Value(); key != 3 || value != "c" {
+		t.Errorf("Got %v,%v expected %v,%v", key, value, 3, "c")
+	}
+}
+
+func TestBPlusTreeIteratorSeek(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	for i := 10; i <= 100; i += 10 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	// range scan over [25, 65)
+	var keys []int
+	for it := tree.IteratorAt(25); it.Key() < 65; it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if actualValue, expectedValue := fmt.Sprint(keys), "[30 40 50 60]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// reverse range scan over (25, 65]
+	keys = nil
+	it := tree.Iterator()
+	for found := it.SeekPrev(65); found && it.Key() > 25; found = it.Prev() {
+		keys = append(keys, it.Key())
+	}
+	if actualValue, expectedValue := fmt.Sprint(keys), "[60 50 40 30]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := it.Seek(101), false; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := it.Prev(), true; actualValue != expectedValue || it.Key() != 100 {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, it.Key(), expectedValue, 100)
+	}
+	if actualValue, expectedValue := it.SeekPrev(9), false; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := it.Next(), true; actualValue != expectedValue || it.Key() != 10 {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, it.Key(), expectedValue, 10)
+	}
+}
+
+func TestBPlusTreeIteratorRemove(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	for i := 1; i <= 10; i++ {
+		tree.Put(i, fmt.Sprint(i))
+	}
+	it := tree.Iterator()
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[1 3 5 7 9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	assertValidTree(t, tree, 3, 5)
+
+	for it.Prev() {
+		it.Remove()
+	}
+	assertValidTree(t, tree, 3, 0)
+}
+
+func TestBPlusTreeIteratorConcurrentModification(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	tree.Put(1, "a")
+	tree.Put(2, "b")
+	it := tree.Iterator()
+	it.Next()
+	tree.Put(3, "c")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; !errors.Is(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Next()
+}
+
+func TestBPlusTreeString(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[int](3)
+	if actualValue, expectedValue := tree.String(), "BPlusTree\n"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	tree.Put(1, 1)
+	tree.Put(2, 2)
+	tree.Put(3, 3)
+	if actualValue, expectedValue := tree.String(), "BPlusTree\n    1\n2\n    2\n    3\n"; actualValue != expectedValue {
+		t.Errorf("Got %q expected %q", actualValue, expectedValue)
+	}
+}
+
+func TestBPlusTreeSerialization(t *testing.T) {
+	tree := bplustree.NewWithStringComparator[string](3)
+	tree.Put("c", "3")
+	tree.Put("b", "2")
+	tree.Put("a", "1")
+
+	var err error
+	assert := func() {
+		if actualValue, expectedValue := tree.Size(), 3; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[a b c]"; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := fmt.Sprint(tree.Values()), "[1 2 3]"; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+
+	assert()
+
+	json, err := tree.ToJSON()
+	assert()
+
+	err = tree.FromJSON(json)
+	assert()
+}
+
+func TestBPlusTreeBinarySerialization(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	for i := 0; i < 20; i++ {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := bplustree.NewWithIntComparator[string](3)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(tree); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = bplustree.NewWithIntComparator[string](3)
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	zero := &bplustree.Tree[int, string]{}
+	if err := zero.UnmarshalBinary(data); err != nil || zero.Size() != restored.Size() {
+		t.Errorf("Got %v, %v expected %v", zero.Size(), err, restored.Size())
+	}
+	if err := (&bplustree.Tree[[2]int, string]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestBPlusTreeJSONMarshaler(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	tree.Put(2, "b")
+	tree.Put(1, "a")
+	tree.Put(10, "c")
+
+	data, err := json.Marshal(struct{ Tree *bplustree.Tree[int, string] }{tree})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Tree":{"1":"a","2":"b","10":"c"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Tree bplustree.Tree[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Tree.Keys(), restored.Tree.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Tree":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&bplustree.Tree[[2]int, string]{}).UnmarshalJSON([]byte("{}")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestBPlusTreeJSONStream(t *testing.T) {
+	tree := bplustree.NewWithIntComparator[string](3)
+	tree.Put(2, "b")
+	tree.Put(1, "a")
+	tree.Put(10, "c")
+
+	var buffer bytes.Buffer
+	if err := tree.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":"a","2":"b","10":"c"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored bplustree.Tree[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestBPlusTreeConformance(t *testing.T) {
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return bplustree.NewWithIntComparator[string](3) })
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		tree := bplustree.NewWithIntComparator[string](3)
+		for _, key := range keys {
+			tree.Put(key, strconv.Itoa(key))
+		}
+		it := tree.Iterator()
+		return &it
+	})
+}
+
+func FuzzBPlusTree(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return bplustree.NewWithIntComparator[string](3) }, containertest.SortedOrder)
+}
+
+// assertValidTree checks the size and the structural invariants of the tree:
+// node occupancy, key order, parent pointers, uniform leaf depth and the leaf chain.
+func assertValidTree[V any](t *testing.T, tree *bplustree.Tree[int, V], order int, expectedSize int) {
+	t.Helper()
+	if actualValue, expectedValue := tree.Size(), expectedSize; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v for tree size", actualValue, expectedValue)
+	}
+	if tree.Root == nil {
+		if expectedSize != 0 {
+			t.Errorf("Got nil root for tree of size %v", expectedSize)
+		}
+		return
+	}
+	if tree.Root.Parent != nil {
+		t.Errorf("Got root with parent")
+	}
+	var leaves []*bplustree.Node[int, V]
+	var walk func(node *bplustree.Node[int, V], depth int, low *int, high *int)
+	leafDepth := -1
+	walk = func(node *bplustree.Node[int, V], depth int, low *int, high *int) {
+		if !sort.IntsAreSorted(node.Keys) {
+			t.Errorf("Got unsorted keys %v", node.Keys)
+		}
+		for _, key := range node.Keys {
+			if (low != nil && key < *low) || (high != nil && key >= *high) {
+				t.Errorf("Got key %v out of range", key)
+			}
+		}
+		if len(node.Children) == 0 {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if leafDepth != depth {
+				t.Errorf("Got leaf at depth %v expected %v", depth, leafDepth)
+			}
+			if len(node.Keys) != len(node.Values) {
+				t.Errorf("Got %v keys and %v values in leaf", len(node.Keys), len(node.Values))
+			}
+			if len(node.Keys) > order-1 || (node != tree.Root && len(node.Keys) < (order+1)/2-1) {
+				t.Errorf("Got %v entries in leaf of order %v", len(node.Keys), order)
+			}
+			leaves = append(leaves, node)
+			return
+		}
+		if len(node.Children) != len(node.Keys)+1 {
+			t.Errorf("Got %v children for %v keys", len(node.Children), len(node.Keys))
+		}
+		if len(node.Children) > order || (node != tree.Root && len(node.Children) < (order+1)/2) {
+			t.Errorf("Got %v children in node of order %v", len(node.Children), order)
+		}
+		for i, child := range node.Children {
+			if child.Parent != node {
+				t.Errorf("Got wrong parent for child %v", child.Keys)
+			}
+			childLow, childHigh := low, high
+			if i > 0 {
+				childLow = &node.Keys[i-1]
+			}
+			if i < len(node.Keys) {
+				childHigh = &node.Keys[i]
+			}
+			walk(child, depth+1, childLow, childHigh)
+		}
+	}
+	walk(tree.Root, 0, nil, nil)
+
+	var keys []int
+	for i, leaf := range leaves {
+		if (i == 0 && leaf.Prev != nil) || (i > 0 && leaf.Prev != leaves[i-1]) {
+			t.Errorf("Got wrong previous leaf for %v", leaf.Keys)
+		}
+		if (i == len(leaves)-1 && leaf.Next != nil) || (i < len(leaves)-1 && leaf.Next != leaves[i+1]) {
+			t.Errorf("Got wrong next leaf for %v", leaf.Keys)
+		}
+		keys = append(keys, leaf.Keys...)
+	}
+	if actualValue, expectedValue := len(keys), expectedSize; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v for number of keys in leaves", actualValue, expectedValue)
+	}
+}
+
+func assertValidTreeNode(t *testing.T, node *bplustree.Node[int, string], keys []int, expectedChildren int, hasParent bool) {
+	t.Helper()
+	if actualValue, expectedValue := node.Parent != nil, hasParent; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v for hasParent", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(node.Keys), fmt.Sprint(keys); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v for keys", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := len(node.Children), expectedChildren; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v for children size", actualValue, expectedValue)
+	}
+}
+
+func benchmarkGet(b *testing.B, tree *bplustree.Tree[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			tree.Get(n)
+		}
+	}
+}
+
+func benchmarkPut(b *testing.B, tree *bplustree.Tree[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			tree.Put(n, struct{}{})
+		}
+	}
+}
+
+func benchmarkRemove(b *testing.B, tree *bplustree.Tree[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			tree.Remove(n)
+		}
+	}
+}
+
+func benchmarkRangeScan(b *testing.B, tree *bplustree.Tree[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for it := tree.IteratorAt(size / 4); it.Key() < size*3/4 && it.Next(); {
+		}
+	}
+}
+
+func benchmarkBTreeRangeScan(b *testing.B, tree *btree.Tree[int, struct{}], size int) {
+	for i := 0; i < b.N; i++ {
+		for it := tree.IteratorAt(size / 4); it.Key() < size*3/4 && it.Next(); {
+		}
+	}
+}
+
+func newTree(size int) *bplustree.Tree[int, struct{}] {
+	tree := bplustree.NewWithIntComparator[struct{}](128)
+	for n := 0; n < size; n++ {
+		tree.Put(n, struct{}{})
+	}
+	return tree
+}
+
+func newBTree(size int) *btree.Tree[int, struct{}] {
+	tree := btree.NewWithIntComparator[struct{}](128)
+	for n := 0; n < size; n++ {
+		tree.Put(n, struct{}{})
+	}
+	return tree
+}
+
+func BenchmarkBPlusTreeGet1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	tree := newTree(size)
+	b.StartTimer()
+	benchmarkGet(b, tree, size)
+}
+
+func BenchmarkBPlusTreeGet100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	tree := newTree(size)
+	b.StartTimer()
+	benchmarkGet(b, tree, size)
+}
+
+func BenchmarkBPlusTreePut1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	tree := bplustree.NewWithIntComparator[struct{}](128)
+	b.StartTimer()
+	benchmarkPut(b, tree, size)
+}
+
+func BenchmarkBPlusTreePut100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	tree := bplustree.NewWithIntComparator[struct{}](128)
+	b.StartTimer()
+	benchmarkPut(b, tree, size)
+}
+
+func BenchmarkBPlusTreeRemove1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	tree := newTree(size)
+	b.StartTimer()
+	benchmarkRemove(b, tree, size)
+}
+
+func BenchmarkBPlusTreeRemove100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	tree := newTree(size)
+	b.StartTimer()
+	benchmarkRemove(b, tree, size)
+}
+
+func BenchmarkBPlusTreeRangeScan1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	tree := newTree(size)
+	b.StartTimer()
+	benchmarkRangeScan(b, tree, size)
+}
+
+func BenchmarkBPlusTreeRangeScan100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	tree := newTree(size)
+	b.StartTimer()
+	benchmarkRangeScan(b, tree, size)
+}
+
+// BTree counterparts of the range scans above, for comparison
+func BenchmarkBTreeRangeScan1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	tree := newBTree(size)
+	b.StartTimer()
+	benchmarkBTreeRangeScan(b, tree, size)
+}
+
+func BenchmarkBTreeRangeScan100000(b *testing.B) {
+	b.StopTimer()
+	size := 100000
+	tree := newBTree(size)
+	b.StartTimer()
+	benchmarkBTreeRangeScan(b, tree, size)
+}
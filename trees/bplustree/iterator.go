@@ -0,0 +1,226 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bplustree
+
+import "github.com/monitor1379/yagods/containers"
+
+var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
+
+// Iterator holding the iterator's state
+type Iterator[K comparable, V any] struct {
+	tree     *Tree[K, V]
+	node     *Node[K, V] // current leaf
+	index    int         // index of the current entry within the leaf
+	position position
+	modCount int  // tree's modification count the iterator is synchronized with
+	removed  bool // current entry was removed, node and index point to its predecessor
+}
+
+type position byte
+
+const (
+	begin, between, end position = 0, 1, 2
+)
+
+// Iterator returns a stateful iterator whose elements are key/value pairs.
+func (tree *Tree[K, V]) Iterator() Iterator[K, V] {
+	return Iterator[K, V]{tree: tree, node: nil, position: begin, modCount: tree.modCount}
+}
+
+// IteratorAt returns a stateful iterator whose elements are key/value pairs that is initialised
+// at the first element whose key is greater than or equal to the given key (see Seek()).
+func (tree *Tree[K, V]) IteratorAt(key K) Iterator[K, V] {
+	iterator := tree.Iterator()
+	iterator.Seek(key)
+	return iterator
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the container.
+// If Next() returns true, then next element's key and value can be retrieved by Key() and Value().
+// If Next() was called for the first time, then it will point the iterator to the first element if it exists.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
+	switch iterator.position {
+	case end:
+		// already at end
+	case begin:
+		// start at the left-most leaf
+		if left := iterator.tree.Left(); left != nil {
+			iterator.node, iterator.index = left, 0
+			iterator.position = between
+			return true
+		}
+	default:
+		// move within the current leaf or follow the link to the next leaf
+		if iterator.index+1 < len(iterator.node.Keys) {
+			iterator.index++
+			return true
+		}
+		if iterator.node.Next != nil {
+			iterator.node, iterator.index = iterator.node.Next, 0
+			return true
+		}
+	}
+	iterator.End()
+	return false
+}
+
+// Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
+// If Prev() returns true, then previous element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Prev() bool {
+	iterator.checkModification()
+	if iterator.removed {
+		iterator.removed = false
+		return iterator.position == between
+	}
+	switch iterator.position {
+	case begin:
+		// already at beginning
+	case end:
+		// start at the right-most leaf
+		if right := iterator.tree.Right(); right != nil {
+			iterator.node, iterator.index = right, len(right.Keys)-1
+			iterator.position = between
+			return true
+		}
+	default:
+		// move within the current leaf or follow the link to the previous leaf
+		if iterator.index > 0 {
+			iterator.index--
+			return true
+		}
+		if iterator.node.Prev != nil {
+			iterator.node, iterator.index = iterator.node.Prev, len(iterator.node.Prev.Keys)-1
+			return true
+		}
+	}
+	iterator.Begin()
+	return false
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Value() V {
+	return iterator.node.Values[iterator.index]
+}
+
+// Key returns the current element's key.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Key() K {
+	return iterator.node.Keys[iterator.index]
+}
+
+// Begin resets the iterator to its initial state (one-before-first)
+// Call Next() to fetch the first element if any.
+func (iterator *Iterator[K, V]) Begin() {
+	iterator.node = nil
+	iterator.index = 0
+	iterator.position = begin
+	iterator.modCount = iterator.tree.modCount
+	iterator.removed = false
+}
+
+// End moves the iterator past the last element (one-past-the-end).
+// Call Prev() to fetch the last element if any.
+func (iterator *Iterator[K, V]) End() {
+	iterator.node = nil
+	iterator.index = 0
+	iterator.position = end
+	iterator.modCount = iterator.tree.modCount
+	iterator.removed = false
+}
+
+// First moves the iterator to the first element and returns true if there was a first element in the container.
+// If First() returns true, then first element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator
+func (iterator *Iterator[K, V]) First() bool {
+	iterator.Begin()
+	return iterator.Next()
+}
+
+// Last moves the iterator to the last element and returns true if there was a last element in the container.
+// If Last() returns true, then last element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Last() bool {
+	iterator.End()
+	return iterator.Prev()
+}
+
+// Seek moves the iterator to the first element whose key is greater than or equal to the given key
+// and returns true if there was such an element in the container.
+// If Seek() returns true, then element's key and value can be retrieved by Key() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Together with Next() this gives a range scan that walks the linked leaves.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	iterator.End()
+	if node, index := iterator.tree.ceiling(key); node != nil {
+		iterator.node, iterator.index = node, index
+		iterator.position = between
+		return true
+	}
+	return false
+}
+
+// SeekPrev moves the iterator to the last element whose key is less than or equal to the given key
+// and returns true if there was such an element in the container.
+// If SeekPrev() returns true, then element's key and value can be retrieved by Key() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) SeekPrev(key K) bool {
+	iterator.Begin()
+	if node, index := iterator.tree.floor(key); node != nil {
+		iterator.node, iterator.index = node, index
+		iterator.position = between
+		return true
+	}
+	return false
+}
+
+// Err returns containers.ErrConcurrentModification if the tree was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	if iterator.modCount != iterator.tree.modCount && (iterator.position != begin || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current entry from the tree and keeps the iterator valid.
+// Next() moves to the entry that followed the removed one and Prev() to the entry that preceded it.
+// Does nothing if the iterator is not positioned on an entry.
+func (iterator *Iterator[K, V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || iterator.position != between {
+		return
+	}
+	key := iterator.Key()
+	iterator.tree.Remove(key)
+	iterator.modCount = iterator.tree.modCount
+	// merging and borrowing move entries between leaves, so look the predecessor up again
+	if node, index := iterator.tree.floor(key); node != nil {
+		iterator.node, iterator.index = node, index
+	} else {
+		iterator.node, iterator.index = nil, 0
+		iterator.position = begin
+	}
+	iterator.removed = true
+}
+
+// checkModification panics if the tree was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the tree.
+func (iterator *Iterator[K, V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.tree.modCount
+}
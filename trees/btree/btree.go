@@ -30,9 +30,12 @@ var _ trees.Tree[int, string] = (*Tree[int, string])(nil)
 // Tree holds elements of the B-tree
 type Tree[K comparable, V any] struct {
 	Root       *Node[K, V]         // Root node
-	Comparator utils.Comparator[K] // Key comparator
+	Comparator utils.Comparator[K] // Key comparator, changed through SetComparator
 	size       int                 // Total number of keys in the tree
 	m          int                 // order (maximum number of children)
+	modCount   int                 // number of structural modifications, checked by iterators
+
+	searchEntries func(entries []*Entry[K, V], key K) (int, bool) // search comparing ordered keys directly, set by New and cleared by SetComparator
 }
 
 // Node is a single element within the tree
@@ -48,6 +51,14 @@ type Entry[K comparable, V any] struct {
 	Value V
 }
 
+// New instantiates a B-tree with the order (maximum number of children) ordering the keys by the < and > operators,
+// see utils.OrderedComparator. Searching the nodes compares the keys with the operators instead of calling the comparator.
+func New[K utils.Ordered, V any](order int) *Tree[K, V] {
+	tree := NewWith[K, V](order, utils.OrderedComparator[K])
+	tree.searchEntries = searchOrdered[K, V]
+	return tree
+}
+
 // NewWith instantiates a B-tree with the order (maximum number of children) and a custom key comparator.
 func NewWith[K comparable, V any](order int, comparator utils.Comparator[K]) *Tree[K, V] {
 	if order < 3 {
@@ -66,6 +77,13 @@ func NewWithStringComparator[V any](order int) *Tree[string, V] {
 	return NewWith[string, V](order, utils.StringComparator)
 }
 
+// SetComparator replaces the comparator of the tree, which then calls it instead of comparing the keys with the operators.
+// The entries are not reordered, so it should be called on an empty tree.
+func (tree *Tree[K, V]) SetComparator(comparator utils.Comparator[K]) {
+	tree.Comparator = comparator
+	tree.searchEntries = nil
+}
+
 // Put inserts key-value pair node into the tree.
 // If key already exists, then its value is updated with the new value.
 // Key should adhere to the comparator's type assertion, otherwise method panics.
@@ -75,11 +93,13 @@ func (tree *Tree[K, V]) Put(key K, value V) {
 	if tree.Root == nil {
 		tree.Root = &Node[K, V]{Entries: []*Entry[K, V]{entry}, Children: []*Node[K, V]{}}
 		tree.size++
+		tree.modCount++
 		return
 	}
 
 	if tree.insert(tree.Root, entry) {
 		tree.size++
+		tree.modCount++
 	}
 }
 
@@ -102,6 +122,7 @@ func (tree *Tree[K, V]) Remove(key K) {
 	if found {
 		tree.delete(node, index)
 		tree.size--
+		tree.modCount++
 	}
 }
 
@@ -149,6 +170,7 @@ func (tree *Tree[K, V]) InterfaceValues() []interface{} {
 func (tree *Tree[K, V]) Clear() {
 	tree.Root = nil
 	tree.size = 0
+	tree.modCount++
 }
 
 // Height returns the height of the tree.
@@ -276,6 +298,9 @@ func (tree *Tree[K, V]) middle() int {
 
 // search searches only within the single node among its entries
 func (tree *Tree[K, V]) search(node *Node[K, V], key K) (index int, found bool) {
+	if tree.searchEntries != nil {
+		return tree.searchEntries(node.Entries, key)
+	}
 	low, high := 0, len(node.Entries)-1
 	var mid int
 	for low <= high {
@@ -293,6 +318,24 @@ func (tree *Tree[K, V]) search(node *Node[K, V], key K) (index int, found bool)
 	return low, false
 }
 
+// searchOrdered is the search of trees instantiated by New among the entries of a node,
+// comparing the keys as utils.OrderedComparator does.
+func searchOrdered[K utils.Ordered, V any](entries []*Entry[K, V], key K) (index int, found bool) {
+	low, high := 0, len(entries)-1
+	for low <= high {
+		mid := (high + low) / 2
+		switch {
+		case key > entries[mid].Key:
+			low = mid + 1
+		case key < entries[mid].Key:
+			high = mid - 1
+		default:
+			return mid, true
+		}
+	}
+	return low, false
+}
+
 // searchRecursively searches recursively down the tree starting at the startNode
 func (tree *Tree[K, V]) searchRecursively(startNode *Node[K, V], key K) (node *Node[K, V], index int, found bool) {
 	if tree.Empty() {
@@ -311,6 +354,54 @@ func (tree *Tree[K, V]) searchRecursively(startNode *Node[K, V], key K) (node *N
 	}
 }
 
+// floor returns the node and entry index of the largest key smaller than or equal to the given key,
+// or (nil, -1) if there is no such key.
+func (tree *Tree[K, V]) floor(key K) (*Node[K, V], int) {
+	var floorNode *Node[K, V]
+	floorIndex := -1
+	if tree.Empty() {
+		return floorNode, floorIndex
+	}
+	node := tree.Root
+	for {
+		index, found := tree.search(node, key)
+		if found {
+			return node, index
+		}
+		if index > 0 {
+			floorNode, floorIndex = node, index-1
+		}
+		if tree.isLeaf(node) {
+			return floorNode, floorIndex
+		}
+		node = node.Children[index]
+	}
+}
+
+// ceiling returns the node and entry index of the smallest key larger than or equal to the given key,
+// or (nil, -1) if there is no such key.
+func (tree *Tree[K, V]) ceiling(key K) (*Node[K, V], int) {
+	var ceilingNode *Node[K, V]
+	ceilingIndex := -1
+	if tree.Empty() {
+		return ceilingNode, ceilingIndex
+	}
+	node := tree.Root
+	for {
+		index, found := tree.search(node, key)
+		if found {
+			return node, index
+		}
+		if index < len(node.Entries) {
+			ceilingNode, ceilingIndex = node, index
+		}
+		if tree.isLeaf(node) {
+			return ceilingNode, ceilingIndex
+		}
+		node = node.Children[index]
+	}
+}
+
 func (tree *Tree[K, V]) insert(node *Node[K, V], entry *Entry[K, V]) (inserted bool) {
 	if tree.isLeaf(node) {
 		return tree.insertIntoLeaf(node, entry)
@@ -5,10 +5,20 @@
 package btree_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
 	"github.com/monitor1379/yagods/trees/btree"
+	"github.com/monitor1379/yagods/utils"
 )
 
 func TestBTreeGet1(t *testing.T) {
@@ -1083,6 +1093,253 @@ func assertValidTreeNode[V comparable](t *testing.T, node *btree.Node[int, V], e
 	}
 }
 
+func TestBTreeIteratorRemove(t *testing.T) {
+	tree := btree.NewWithIntComparator[string](3)
+	for i := 1; i <= 10; i++ {
+		tree.Put(i, fmt.Sprint(i))
+	}
+	it := tree.Iterator()
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[1 3 5 7 9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for it.End(); it.Prev(); {
+		if it.Key() > 4 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[1 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+}
+
+func TestBTreeIteratorConcurrentModification(t *testing.T) {
+	tree := btree.NewWithIntComparator[string](3)
+	tree.Put(1, "a")
+	tree.Put(2, "b")
+	it := tree.Iterator()
+	it.Next()
+	tree.Put(1, "x")
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+	tree.Put(3, "c")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Next()
+	t.Errorf("Shouldn't reach here")
+}
+
+func TestBTreeIteratorSeek(t *testing.T) {
+	tree := btree.NewWithIntComparator[string](3)
+	for i := 1; i <= 9; i += 2 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+	it := tree.Iterator()
+	tests := []struct {
+		key, seek, seekPrev int
+		found, foundPrev    bool
+	}{
+		{0, 1, 0, true, false},
+		{1, 1, 1, true, true},
+		{4, 5, 3, true, true},
+		{9, 9, 9, true, true},
+		{10, 0, 9, false, true},
+	}
+	for _, test := range tests {
+		if actualValue := it.Seek(test.key); actualValue != test.found {
+			t.Errorf("Got %v expected %v", actualValue, test.found)
+		}
+		if test.found && it.Key() != test.seek {
+			t.Errorf("Got %v expected %v", it.Key(), test.seek)
+		}
+		if actualValue := it.SeekPrev(test.key); actualValue != test.foundPrev {
+			t.Errorf("Got %v expected %v", actualValue, test.foundPrev)
+		}
+		if test.foundPrev && it.Key() != test.seekPrev {
+			t.Errorf("Got %v expected %v", it.Key(), test.seekPrev)
+		}
+	}
+	it.Seek(4)
+	var keys []int
+	for ok := true; ok; ok = it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if actualValue, expectedValue := fmt.Sprint(keys), "[5 7 9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it.Seek(10)
+	if actualValue := it.Prev(); actualValue != true || it.Key() != 9 {
+		t.Errorf("Got %v expected %v", it.Key(), 9)
+	}
+	it.SeekPrev(0)
+	if actualValue := it.Next(); actualValue != true || it.Key() != 1 {
+		t.Errorf("Got %v expected %v", it.Key(), 1)
+	}
+	it = tree.IteratorAt(6)
+	if actualValue, expectedValue := it.Key(), 7; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue := it.Prev(); actualValue != true || it.Key() != 5 {
+		t.Errorf("Got %v expected %v", it.Key(), 5)
+	}
+}
+
+func TestBTreeIteratorSeekLarge(t *testing.T) {
+	tree := btree.NewWithIntComparator[int](4)
+	for i := 0; i < 1000; i += 2 {
+		tree.Put(i, i)
+	}
+	it := tree.Iterator()
+	for i := -1; i < 1000; i++ {
+		ceiling, floor := i+i&1, i-i&1
+		if found := it.Seek(i); found != (ceiling < 1000) || found && it.Key() != ceiling {
+			t.Errorf("Seek(%v) got %v expected %v", i, it.Key(), ceiling)
+		}
+		if found := it.SeekPrev(i); found != (floor >= 0) || found && it.Key() != floor {
+			t.Errorf("SeekPrev(%v) got %v expected %v", i, it.Key(), floor)
+		}
+	}
+}
+
+func TestBTreeRemoveRange(t *testing.T) {
+	tree := btree.NewWithIntComparator[int](3)
+	for i := 0; i < 100; i++ {
+		tree.Put(i, i)
+	}
+	tree.RemoveRange(10, 95)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[0 1 2 3 4 5 6 7 8 9 95 96 97 98 99]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := tree.Size(), 15; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	tree.RemoveRange(50, 20)
+	tree.RemoveRange(-5, 3)
+	tree.RemoveRange(97, 1000)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[3 4 5 6 7 8 9 95 96]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	tree.Put(50, 50)
+	tree.Remove(4)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[3 5 6 7 8 9 50 95 96]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBTreeSplitJoin(t *testing.T) {
+	tree := btree.NewWithIntComparator[int](3)
+	for i := 0; i < 1000; i++ {
+		tree.Put(i, i)
+	}
+	for _, test := range [][2]int{{-1, 0}, {0, 0}, {1, 1}, {333, 333}, {999, 999}, {1000, 1000}} {
+		left, right := tree.Split(test[0])
+		if actualValue := tree.Size(); actualValue != 0 {
+			t.Errorf("Got %v expected %v", actualValue, 0)
+		}
+		if actualValue, expectedValue := left.Size(), test[1]; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := right.Size(), 1000-left.Size(); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if !left.Empty() && left.RightKey() != left.Size()-1 {
+			t.Errorf("Got %v expected %v", left.RightKey(), left.Size()-1)
+		}
+		if !right.Empty() && right.LeftKey() != left.Size() {
+			t.Errorf("Got %v expected %v", right.LeftKey(), left.Size())
+		}
+		tree = btree.Join(left, right)
+		if actualValue, expectedValue := tree.Size(), 1000; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue := left.Size() + right.Size(); actualValue != 0 {
+			t.Errorf("Got %v expected %v", actualValue, 0)
+		}
+		for i, key := range tree.Keys() {
+			if key != i {
+				t.Errorf("Got %v expected %v", key, i)
+			}
+		}
+	}
+}
+
+func TestBTreeJoinOverlapping(t *testing.T) {
+	left, right := btree.NewWithIntComparator[int](3), btree.NewWithIntComparator[int](3)
+	left.Put(2, 2)
+	right.Put(1, 1)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected panic")
+		}
+	}()
+	btree.Join(left, right)
+}
+
+func TestBTreeFromSorted(t *testing.T) {
+	keys := make([]int, 100)
+	values := make([]string, 100)
+	for i := range keys {
+		keys[i], values[i] = i*2, fmt.Sprint(i*2)
+	}
+	tree, err := btree.FromSorted(4, utils.NumberComparator[int], keys, values)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := tree.Size(), 100; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), fmt.Sprint(keys); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, found := tree.Get(42); actualValue != "42" || !found {
+		t.Errorf("Got %v expected %v", actualValue, "42")
+	}
+	tree.Put(1, "1")
+	tree.Remove(42)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()[:4]), "[0 1 2 4]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if _, err := btree.FromSorted(4, utils.NumberComparator[int], []int{1, 3, 2}, []string{"a", "b", "c"}); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+	if _, err := btree.FromSorted(4, utils.NumberComparator[int], []int{1, 2, 2}, []string{"a", "b", "c"}); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+	if _, err := btree.FromSorted(4, utils.NumberComparator[int], []int{1, 2}, []string{"a"}); err == nil {
+		t.Errorf("Got %v expected error", err)
+	}
+}
+
+func TestBTreeBulkLoad(t *testing.T) {
+	source := btree.NewWithIntComparator[string](3)
+	for i := 0; i < 10; i++ {
+		source.Put(i, fmt.Sprint(i))
+	}
+	it := source.Iterator()
+	tree, err := btree.BulkLoad[int, string](4, utils.NumberComparator[int], &it)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys(), tree.Values()), fmt.Sprint(source.Keys(), source.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestBTreeSerialization(t *testing.T) {
 	tree := btree.NewWithStringComparator[string](3)
 	tree.Put("c", "3")
@@ -1114,6 +1371,337 @@ func TestBTreeSerialization(t *testing.T) {
 	assert()
 }
 
+func TestBTreeBinarySerialization(t *testing.T) {
+	tree := btree.NewWithIntComparator[string](3)
+	for i := 0; i < 20; i++ {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := btree.NewWithIntComparator[string](3)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(tree); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = btree.NewWithIntComparator[string](3)
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	zero := &btree.Tree[int, string]{}
+	if err := zero.UnmarshalBinary(data); err != nil || zero.Size() != restored.Size() {
+		t.Errorf("Got %v, %v expected %v", zero.Size(), err, restored.Size())
+	}
+	if err := (&btree.Tree[[2]int, string]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestBTreeJSONMarshaler(t *testing.T) {
+	tree := btree.NewWithIntComparator[string](3)
+	tree.Put(2, "b")
+	tree.Put(1, "a")
+	tree.Put(10, "c")
+
+	data, err := json.Marshal(struct{ Tree *btree.Tree[int, string] }{tree})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Tree":{"1":"a","2":"b","10":"c"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct{ Tree btree.Tree[int, string] }
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Tree.Keys(), restored.Tree.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Tree":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&btree.Tree[[2]int, string]{}).UnmarshalJSON([]byte("{}")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestBTreeJSONStream(t *testing.T) {
+	tree := btree.NewWithIntComparator[string](3)
+	tree.Put(2, "b")
+	tree.Put(1, "a")
+	tree.Put(10, "c")
+
+	var buffer bytes.Buffer
+	if err := tree.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":"a","2":"b","10":"c"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored btree.Tree[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestBTreeStructure(t *testing.T) {
+	tree := btree.NewWithIntComparator[string](4)
+	for i := 0; i < 1000; i++ {
+		tree.Put((i*7919)%1000, fmt.Sprint(i))
+	}
+	for i := 0; i < 1000; i += 3 {
+		tree.Remove((i * 7) % 1000)
+	}
+
+	data, err := tree.MarshalStructure()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	var restored btree.Tree[int, string]
+	if err := restored.UnmarshalStructure(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := restored.String(), tree.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restoredData, _ := restored.MarshalStructure()
+	if !bytes.Equal(restoredData, data) {
+		t.Errorf("Got %v expected %v", restoredData, data)
+	}
+	restored.Put(-1, "x")
+	if actualValue, expectedValue := restored.Size(), tree.Size()+1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	reversed := btree.NewWith[int, string](4, func(a, b int) int { return b - a })
+	if err := reversed.UnmarshalStructure(data); !errors.Is(err, containers.ErrInvalidStructure) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidStructure)
+	}
+	if actualValue := reversed.Size(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+	if err := restored.UnmarshalStructure(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	if err := restored.UnmarshalStructure(append(data[:len(data):len(data)], 0)); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	binary, _ := tree.MarshalBinary()
+	if err := restored.UnmarshalStructure(binary); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+
+	small := btree.NewWithIntComparator[string](4)
+	small.Put(1, "a")
+	small.Put(2, "b")
+	small.Put(3, "c")
+	data, _ = small.MarshalStructure()
+	// version, count, order of the tree, whose root holds as many entries as order 4 allows
+	data[3] = 3
+	if err := restored.UnmarshalStructure(data); !errors.Is(err, containers.ErrInvalidStructure) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidStructure)
+	}
+	small.Put(4, "d")
+	data, _ = small.MarshalStructure()
+	larger := btree.NewWithIntComparator[string](5)
+	if err := larger.UnmarshalStructure(data); err != nil || larger.String() != small.String() {
+		t.Errorf("Got %v expected %v", larger.String(), small.String())
+	}
+	if actualValue, expectedValue := larger.Height(), 2; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBTreeValidate(t *testing.T) {
+	var zero btree.Tree[int, string]
+	if err := zero.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	tree := btree.NewWithIntComparator[string](5)
+	for i := 0; i < 1000; i++ {
+		tree.Put((i*7919)%1000, fmt.Sprint(i))
+	}
+	for i := 0; i < 1000; i += 3 {
+		tree.Remove((i * 7) % 1000)
+		if err := tree.Validate(); err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+
+	tests := []struct {
+		corrupt func(tree *btree.Tree[int, string])
+		message string
+	}{
+		{func(tree *btree.Tree[int, string]) { tree.Root.Children[0].Children[1].Entries[0].Key = 5 }, "key 5 precedes key 4"},
+		{func(tree *btree.Tree[int, string]) { tree.Root.Children[1].Entries[0].Key = 3 }, "key 3 follows key 4"},
+		{func(tree *btree.Tree[int, string]) { tree.Root.Children[1].Children[0].Parent = tree.Root }, "node 5 does not point to its parent"},
+		{func(tree *btree.Tree[int, string]) { tree.Root.Children[0].Entries = nil }, "node of 0 entries after key <nil> in a tree of order 3"},
+		{func(tree *btree.Tree[int, string]) { tree.Root.Children = tree.Root.Children[:1] }, "node 4 has 1 entries but 1 children"},
+		{func(tree *btree.Tree[int, string]) { tree.Root.Children[1].Children = nil }, "leaf 6 at depth 1, other leaves at depth 2"},
+		{func(tree *btree.Tree[int, string]) { tree.Root.Children[0].Children[0] = nil }, "nil child after key <nil>"},
+	}
+	for _, test := range tests {
+		// 4 with the children 2 and 6 and the leaves 1, 3, 5 and 7
+		small := btree.NewWithIntComparator[string](3)
+		for i := 1; i <= 7; i++ {
+			small.Put(i, "")
+		}
+		test.corrupt(small)
+		err := small.Validate()
+		if !errors.Is(err, containers.ErrInvalidStructure) {
+			t.Errorf("Got %v expected %v", err, containers.ErrInvalidStructure)
+		}
+		if err != nil && !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Got %v expected %v", err, test.message)
+		}
+	}
+}
+
+func TestBTreeGraph(t *testing.T) {
+	tree := btree.NewWithIntComparator[string](3)
+	for i := 1; i <= 4; i++ {
+		tree.Put(i, fmt.Sprint("v", i))
+	}
+
+	var builder strings.Builder
+	if err := tree.ToDOT(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue := `digraph btree {
+	ordering=out;
+	n0 [shape=record, label="2"];
+	n1 [shape=record, label="1"];
+	n2 [shape=record, label="3|4"];
+	n0 -> n1;
+	n0 -> n2;
+}
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	builder.Reset()
+	if err := tree.ToMermaid(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue = `flowchart TD
+	%% btree
+	n0["2"]
+	n1["1"]
+	n2["3 | 4"]
+	n0 --> n1
+	n0 --> n2
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	graph := tree.ToGraph(func(key int, value string) string { return value })
+	if actualValue, expectedValue := fmt.Sprint(graph.Nodes[2].Fields), "[v3 v4]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBTreeNew(t *testing.T) {
+	type name string
+	tree := btree.New[name, int](3)
+	for i, key := range []name{"e", "b", "g", "a", "d", "c", "f"} {
+		tree.Put(key, i)
+	}
+	tree.Put("a", 7)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[a b c d e f g]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, found := tree.Get("a"); actualValue != 7 || !found {
+		t.Errorf("Got %v expected %v", actualValue, 7)
+	}
+	if _, found := tree.Get("h"); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	left, right := tree.Split("d")
+	left.Remove("b")
+	right.Put("h", 8)
+	tree = btree.Join(left, right)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[a c d e f g h]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return btree.New[int, string](3) })
+}
+
+func TestBTreeSetComparator(t *testing.T) {
+	tree := btree.New[int, string](3)
+	tree.SetComparator(utils.Reverse(tree.Comparator))
+	for _, key := range []int{3, 1, 5, 2, 4} {
+		tree.Put(key, fmt.Sprint(key))
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[5 4 3 2 1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for _, key := range []int{1, 2, 3, 4, 5} {
+		if actualValue, found := tree.Get(key); actualValue != fmt.Sprint(key) || !found {
+			t.Errorf("Got %v expected %v", actualValue, key)
+		}
+	}
+	tree.Remove(3)
+	if _, found := tree.Get(3); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+}
+
+func TestBTreeConformance(t *testing.T) {
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return btree.NewWithIntComparator[string](3) })
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		tree := btree.NewWithIntComparator[string](3)
+		for _, key := range keys {
+			tree.Put(key, strconv.Itoa(key))
+		}
+		it := tree.Iterator()
+		return &it
+	})
+}
+
+func FuzzBTree(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return btree.NewWithIntComparator[string](3) }, containertest.SortedOrder)
+}
+
+func FuzzBTreeNew(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return btree.New[int, string](3) }, containertest.SortedOrder)
+}
+
 func benchmarkGet(b *testing.B, tree *btree.Tree[int, struct{}], size int) {
 	for i := 0; i < b.N; i++ {
 		for n := 0; n < size; n++ {
@@ -0,0 +1,85 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+import (
+	"fmt"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// FromSorted instantiates a B-tree with the order (maximum number of children) and the custom comparator
+// holding the keys and their values in O(n) time. Nodes are packed as full as the order allows.
+// Returns an error wrapping containers.ErrNotSorted if keys are not in strictly ascending order,
+// or an error if there are not as many values as keys.
+func FromSorted[K comparable, V any](order int, comparator utils.Comparator[K], keys []K, values []V) (*Tree[K, V], error) {
+	tree := NewWith[K, V](order, comparator)
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("btree: got %d keys but %d values", len(keys), len(values))
+	}
+	for i := 1; i < len(keys); i++ {
+		if comparator(keys[i-1], keys[i]) >= 0 {
+			return nil, fmt.Errorf("btree: key at index %d: %w", i, containers.ErrNotSorted)
+		}
+	}
+	if len(keys) > 0 {
+		entries := make([]*Entry[K, V], len(keys))
+		for i := range keys {
+			entries[i] = &Entry[K, V]{Key: keys[i], Value: values[i]}
+		}
+		tree.Root = tree.build(entries)
+	}
+	tree.size = len(keys)
+	return tree, nil
+}
+
+// BulkLoad instantiates a B-tree with the order (maximum number of children) and the custom comparator
+// holding the remaining elements of the iterator in O(n) time. See FromSorted.
+func BulkLoad[K comparable, V any](order int, comparator utils.Comparator[K], iterator containers.IteratorWithKey[K, V]) (*Tree[K, V], error) {
+	var keys []K
+	var values []V
+	for iterator.Next() {
+		keys = append(keys, iterator.Key())
+		values = append(values, iterator.Value())
+	}
+	return FromSorted(order, comparator, keys, values)
+}
+
+// build builds the tree bottom-up, level by level. Each level is split into as few nodes as possible
+// with the entries spread evenly among them, and the entries between the nodes form the level above.
+func (tree *Tree[K, V]) build(entries []*Entry[K, V]) *Node[K, V] {
+	var children []*Node[K, V]
+	for {
+		if len(entries) <= tree.maxEntries() {
+			root := &Node[K, V]{Entries: entries, Children: children}
+			setParent(children, root)
+			return root
+		}
+		count := (len(entries) + tree.maxChildren()) / tree.maxChildren() // ceil((entries+1)/(maxEntries+1))
+		perNode := len(entries) - (count - 1)
+		nodes := make([]*Node[K, V], count)
+		separators := make([]*Entry[K, V], 0, count-1)
+		for i, start, childStart := 0, 0, 0; i < count; i++ {
+			size := perNode / count
+			if i < perNode%count {
+				size++
+			}
+			node := &Node[K, V]{Entries: append([]*Entry[K, V](nil), entries[start:start+size]...)}
+			if children != nil {
+				node.Children = append([]*Node[K, V](nil), children[childStart:childStart+size+1]...)
+				setParent(node.Children, node)
+				childStart += size + 1
+			}
+			nodes[i] = node
+			start += size
+			if i < count-1 {
+				separators = append(separators, entries[start])
+				start++
+			}
+		}
+		entries, children = separators, nodes
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Assert Graph implementation
+var _ containers.DOTWriter = (*Tree[string, int])(nil)
+var _ containers.MermaidWriter = (*Tree[string, int])(nil)
+
+// ToGraph returns the structure of the tree as a graph of its nodes, each one a record of the entries it groups.
+// The label hook formats the label of an entry, nil labels an entry with its key.
+func (tree *Tree[K, V]) ToGraph(label func(key K, value V) string) *containers.Graph {
+	if label == nil {
+		label = func(key K, value V) string { return fmt.Sprint(key) }
+	}
+	graph := &containers.Graph{Name: "btree"}
+	var add func(node *Node[K, V]) int
+	add = func(node *Node[K, V]) int {
+		fields := make([]string, len(node.Entries))
+		for i, entry := range node.Entries {
+			fields[i] = label(entry.Key, entry.Value)
+		}
+		index := graph.AddNode(containers.GraphNode{Fields: fields})
+		for _, child := range node.Children {
+			graph.AddEdge(index, add(child))
+		}
+		return index
+	}
+	if tree.Root != nil {
+		add(tree.Root)
+	}
+	return graph
+}
+
+// ToDOT writes the structure of the tree to the writer in the DOT language of Graphviz, see ToGraph.
+func (tree *Tree[K, V]) ToDOT(w io.Writer) error {
+	return tree.ToGraph(nil).WriteDOT(w)
+}
+
+// ToMermaid writes the structure of the tree to the writer as a Mermaid flowchart, see ToGraph.
+func (tree *Tree[K, V]) ToMermaid(w io.Writer) error {
+	return tree.ToGraph(nil).WriteMermaid(w)
+}
@@ -7,6 +7,7 @@ package btree
 import "github.com/monitor1379/yagods/containers"
 
 var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
 
 // Iterator holding the iterator's state
 type Iterator[K comparable, V any] struct {
@@ -14,6 +15,8 @@ type Iterator[K comparable, V any] struct {
 	node     *Node[K, V]
 	entry    *Entry[K, V]
 	position position
+	modCount int  // tree's modification count the iterator is synchronized with
+	removed  bool // current entry was removed, entry points to its predecessor
 }
 
 type position byte
@@ -24,7 +27,15 @@ const (
 
 // Iterator returns a stateful iterator whose elements are key/value pairs.
 func (tree *Tree[K, V]) Iterator() Iterator[K, V] {
-	return Iterator[K, V]{tree: tree, node: nil, position: begin}
+	return Iterator[K, V]{tree: tree, node: nil, position: begin, modCount: tree.modCount}
+}
+
+// IteratorAt returns a stateful iterator whose elements are key/value pairs that is initialised
+// at the first element whose key is greater than or equal to the given key (see Seek()).
+func (tree *Tree[K, V]) IteratorAt(key K) Iterator[K, V] {
+	iterator := tree.Iterator()
+	iterator.Seek(key)
+	return iterator
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -32,6 +43,8 @@ func (tree *Tree[K, V]) Iterator() Iterator[K, V] {
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
 // Modifies the state of the iterator.
 func (iterator *Iterator[K, V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
 	// If already at end, go to end
 	if iterator.position == end {
 		goto end
@@ -91,6 +104,11 @@ between:
 // If Prev() returns true, then previous element's key and value can be retrieved by Key() and Value().
 // Modifies the state of the iterator.
 func (iterator *Iterator[K, V]) Prev() bool {
+	iterator.checkModification()
+	if iterator.removed {
+		iterator.removed = false
+		return iterator.position == between
+	}
 	// If already at beginning, go to begin
 	if iterator.position == begin {
 		goto begin
@@ -164,6 +182,8 @@ func (iterator *Iterator[K, V]) Begin() {
 	iterator.node = nil
 	iterator.position = begin
 	iterator.entry = nil
+	iterator.modCount = iterator.tree.modCount
+	iterator.removed = false
 }
 
 // End moves the iterator past the last element (one-past-the-end).
@@ -172,6 +192,8 @@ func (iterator *Iterator[K, V]) End() {
 	iterator.node = nil
 	iterator.position = end
 	iterator.entry = nil
+	iterator.modCount = iterator.tree.modCount
+	iterator.removed = false
 }
 
 // First moves the iterator to the first element and returns true if there was a first element in the container.
@@ -189,3 +211,79 @@ func (iterator *Iterator[K, V]) Last() bool {
 	iterator.End()
 	return iterator.Prev()
 }
+
+// Seek moves the iterator to the first element whose key is greater than or equal to the given key
+// and returns true if there was such an element in the container.
+// If Seek() returns true, then element's key and value can be retrieved by Key() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	iterator.End()
+	if node, index := iterator.tree.ceiling(key); node != nil {
+		iterator.node = node
+		iterator.entry = node.Entries[index]
+		iterator.position = between
+		return true
+	}
+	return false
+}
+
+// SeekPrev moves the iterator to the last element whose key is less than or equal to the given key
+// and returns true if there was such an element in the container.
+// If SeekPrev() returns true, then element's key and value can be retrieved by Key() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) SeekPrev(key K) bool {
+	iterator.Begin()
+	if node, index := iterator.tree.floor(key); node != nil {
+		iterator.node = node
+		iterator.entry = node.Entries[index]
+		iterator.position = between
+		return true
+	}
+	return false
+}
+
+// Err returns containers.ErrConcurrentModification if the tree was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	if iterator.modCount != iterator.tree.modCount && (iterator.position != begin || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current entry from the tree and keeps the iterator valid.
+// Next() moves to the entry that followed the removed one and Prev() to the entry that preceded it.
+// Does nothing if the iterator is not positioned on an entry.
+func (iterator *Iterator[K, V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || iterator.position != between {
+		return
+	}
+	key := iterator.entry.Key
+	iterator.tree.Remove(key)
+	iterator.modCount = iterator.tree.modCount
+	// rebalancing moves entries between nodes, so look the predecessor up again
+	if node, index := iterator.tree.floor(key); node != nil {
+		iterator.node = node
+		iterator.entry = node.Entries[index]
+	} else {
+		iterator.node = nil
+		iterator.entry = nil
+		iterator.position = begin
+	}
+	iterator.removed = true
+}
+
+// checkModification panics if the tree was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the tree.
+func (iterator *Iterator[K, V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.tree.modCount
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+// RemoveRange removes all entries whose keys are within [from, to) from the tree.
+// Runs in O(log n + k) time, k being the number of removed entries.
+// Keys should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) RemoveRange(from K, to K) {
+	if tree.Root == nil || tree.Comparator(from, to) >= 0 {
+		return
+	}
+	left, leftHeight, rest, restHeight := tree.splitAt(tree.Root, tree.Root.height(), from)
+	middle, _, right, rightHeight := tree.splitAt(rest, restHeight, to)
+	tree.Root, _ = tree.join(left, leftHeight, nil, right, rightHeight)
+	if removed := count(middle); removed > 0 {
+		tree.size -= removed
+		tree.modCount++
+	}
+}
+
+// Split moves all entries whose keys are less than the given key into the left tree and
+// all other entries into the right tree. The tree itself is left empty.
+// Runs in O(log n + min(|left|, |right|)) time, counting the entries of the smaller part.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Split(key K) (left *Tree[K, V], right *Tree[K, V]) {
+	left, right = NewWith[K, V](tree.m, tree.Comparator), NewWith[K, V](tree.m, tree.Comparator)
+	left.searchEntries, right.searchEntries = tree.searchEntries, tree.searchEntries
+	if tree.Root != nil {
+		left.Root, _, right.Root, _ = tree.splitAt(tree.Root, tree.Root.height(), key)
+		detach(left.Root)
+		detach(right.Root)
+		left.size, right.size = countSmaller(left.Root, right.Root, tree.size)
+	}
+	tree.Clear()
+	return left, right
+}
+
+// Join moves all entries of the left and the right tree into a new tree using left's order and comparator
+// and leaves both trees empty. All keys of the left tree should be less than all keys of the right tree,
+// otherwise method panics.
+// Runs in O(log n) time if both trees have the same order, otherwise entries of the right tree are inserted one by one.
+func Join[K comparable, V any](left *Tree[K, V], right *Tree[K, V]) *Tree[K, V] {
+	tree := NewWith[K, V](left.m, left.Comparator)
+	tree.searchEntries = left.searchEntries
+	if !left.Empty() && !right.Empty() && tree.Comparator(left.RightKey(), right.LeftKey()) >= 0 {
+		panic("Invalid join, all keys of left should be less than all keys of right")
+	}
+	if left.m != right.m {
+		// nodes of the right tree could hold too many entries for the new tree's order
+		for it := right.Iterator(); it.Next(); {
+			left.Put(it.Key(), it.Value())
+		}
+		right.Clear()
+	}
+	tree.Root, _ = tree.join(left.Root, left.Root.height(), nil, right.Root, right.Root.height())
+	tree.size = left.size + right.size
+	left.Clear()
+	right.Clear()
+	return tree
+}
+
+// splitAt splits the subtree rooted at node with the given height into the subtrees holding keys
+// less than the key and keys greater than or equal to the key, returning their roots and heights.
+func (tree *Tree[K, V]) splitAt(node *Node[K, V], height int, key K) (*Node[K, V], int, *Node[K, V], int) {
+	if node == nil {
+		return nil, 0, nil, 0
+	}
+	index, found := tree.search(node, key)
+	if found {
+		left, leftHeight := tree.piece(node, 0, index, height)
+		right, rightHeight := tree.piece(node, index+1, len(node.Entries), height)
+		right, rightHeight = tree.join(nil, 0, node.Entries[index], right, rightHeight)
+		return left, leftHeight, right, rightHeight
+	}
+	var left, right *Node[K, V]
+	var leftHeight, rightHeight int
+	if !tree.isLeaf(node) {
+		left, leftHeight, right, rightHeight = tree.splitAt(node.Children[index], height-1, key)
+	}
+	if index > 0 {
+		piece, pieceHeight := tree.piece(node, 0, index-1, height)
+		left, leftHeight = tree.join(piece, pieceHeight, node.Entries[index-1], left, leftHeight)
+	}
+	if index < len(node.Entries) {
+		piece, pieceHeight := tree.piece(node, index+1, len(node.Entries), height)
+		right, rightHeight = tree.join(right, rightHeight, node.Entries[index], piece, pieceHeight)
+	}
+	return left, leftHeight, right, rightHeight
+}
+
+// piece returns a new root holding the node's entries within [from, to) along with the children around them
+// and its height, or the child at from if the range is empty.
+func (tree *Tree[K, V]) piece(node *Node[K, V], from int, to int, height int) (*Node[K, V], int) {
+	if from == to {
+		if tree.isLeaf(node) {
+			return nil, 0
+		}
+		return detach(node.Children[from]), height - 1
+	}
+	piece := &Node[K, V]{Entries: append([]*Entry[K, V](nil), node.Entries[from:to]...)}
+	if !tree.isLeaf(node) {
+		piece.Children = append([]*Node[K, V](nil), node.Children[from:to+1]...)
+		setParent(piece.Children, piece)
+	}
+	return piece, height
+}
+
+// join links the left subtree, the middle entry and the right subtree (keys in that order) into one
+// B-tree and returns its root and height. The middle entry may be nil.
+func (tree *Tree[K, V]) join(left *Node[K, V], leftHeight int, middle *Entry[K, V], right *Node[K, V], rightHeight int) (*Node[K, V], int) {
+	if middle == nil {
+		if left == nil {
+			return detach(right), rightHeight
+		}
+		if right == nil {
+			return detach(left), leftHeight
+		}
+		// use the minimum of the right subtree as the middle entry
+		rest := &Tree[K, V]{Root: detach(right), Comparator: tree.Comparator, m: tree.m}
+		leaf := right
+		for !rest.isLeaf(leaf) {
+			leaf = leaf.Children[0]
+		}
+		middle = leaf.Entries[0]
+		rest.delete(leaf, 0)
+		right, rightHeight = rest.Root, rest.Root.height()
+	}
+	left, right = detach(left), detach(right)
+	sub := &Tree[K, V]{Comparator: tree.Comparator, m: tree.m}
+	switch {
+	case left == nil && right == nil:
+		return &Node[K, V]{Entries: []*Entry[K, V]{middle}}, 1
+	case leftHeight == rightHeight:
+		root := &Node[K, V]{Entries: []*Entry[K, V]{middle}, Children: []*Node[K, V]{left, right}}
+		setParent(root.Children, root)
+		sub.Root = root
+		sub.refill(right)
+		if sub.Root == root {
+			sub.refill(left)
+		}
+		if sub.Root == root {
+			return root, leftHeight + 1
+		}
+		return sub.Root, leftHeight
+	case leftHeight > rightHeight:
+		// descend along the right spine of the left subtree to the level above the right subtree
+		sub.Root = left
+		node := left
+		for height := leftHeight; height > rightHeight+1; height-- {
+			node = node.Children[len(node.Children)-1]
+		}
+		node.Entries = append(node.Entries, middle)
+		if right != nil {
+			right.Parent = node
+			node.Children = append(node.Children, right)
+			sub.refill(right)
+		}
+		sub.split(node)
+		if sub.Root == left {
+			return left, leftHeight
+		}
+		return sub.Root, leftHeight + 1
+	default:
+		// descend along the left spine of the right subtree to the level above the left subtree
+		sub.Root = right
+		node := right
+		for height := rightHeight; height > leftHeight+1; height-- {
+			node = node.Children[0]
+		}
+		node.Entries = append([]*Entry[K, V]{middle}, node.Entries...)
+		if left != nil {
+			left.Parent = node
+			node.Children = append([]*Node[K, V]{left}, node.Children...)
+			sub.refill(left)
+		}
+		sub.split(node)
+		if sub.Root == right {
+			return right, rightHeight
+		}
+		return sub.Root, rightHeight + 1
+	}
+}
+
+// refill borrows entries from or merges the node with its siblings until it holds enough entries.
+func (tree *Tree[K, V]) refill(node *Node[K, V]) {
+	for node.Parent != nil && len(node.Entries) < tree.minEntries() {
+		tree.rebalance(node, node.Entries[0].Key)
+	}
+}
+
+func detach[K comparable, V any](node *Node[K, V]) *Node[K, V] {
+	if node != nil {
+		node.Parent = nil
+	}
+	return node
+}
+
+func count[K comparable, V any](node *Node[K, V]) int {
+	if node == nil {
+		return 0
+	}
+	n := len(node.Entries)
+	for _, child := range node.Children {
+		n += count(child)
+	}
+	return n
+}
+
+// countSmaller returns the number of entries in the left and the right subtree holding total entries together,
+// in time proportional to the smaller of both.
+func countSmaller[K comparable, V any](left *Node[K, V], right *Node[K, V], total int) (int, int) {
+	for limit := 1; ; limit *= 2 {
+		if n := countUpTo(left, limit); n <= limit {
+			return n, total - n
+		}
+		if n := countUpTo(right, limit); n <= limit {
+			return total - n, n
+		}
+	}
+}
+
+// countUpTo counts the entries of the subtree, giving up as soon as more than limit were found.
+func countUpTo[K comparable, V any](node *Node[K, V], limit int) int {
+	if node == nil {
+		return 0
+	}
+	n := len(node.Entries)
+	for _, child := range node.Children {
+		if n > limit {
+			break
+		}
+		n += countUpTo(child, limit-n)
+	}
+	return n
+}
@@ -5,7 +5,11 @@
 package btree
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
 	"encoding/json"
+	"io"
 
 	"github.com/monitor1379/yagods/containers"
 	"github.com/monitor1379/yagods/utils"
@@ -13,6 +17,14 @@ import (
 
 var _ containers.JSONSerializer = (*Tree[int, string])(nil)
 var _ containers.JSONDeserializer = (*Tree[int, string])(nil)
+var _ encoding.BinaryMarshaler = (*Tree[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Tree[int, string])(nil)
+var _ gob.GobEncoder = (*Tree[int, string])(nil)
+var _ gob.GobDecoder = (*Tree[int, string])(nil)
+var _ json.Marshaler = (*Tree[int, string])(nil)
+var _ json.Unmarshaler = (*Tree[int, string])(nil)
+var _ containers.JSONWriter = (*Tree[int, string])(nil)
+var _ containers.JSONReader = (*Tree[int, string])(nil)
 
 // ToJSON outputs the JSON representation of the tree.
 func (tree *Tree[K, V]) ToJSON() ([]byte, error) {
@@ -36,3 +48,93 @@ func (tree *Tree[K, V]) FromJSON(data []byte) error {
 	}
 	return err
 }
+
+// MarshalBinary outputs the binary representation of the tree in key order.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (tree *Tree[K, V]) MarshalBinary() ([]byte, error) {
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), tree.Keys(), tree.Values())
+}
+
+// UnmarshalBinary populates the tree from the input binary representation.
+// The zero value of a tree is populated with the default comparator of its keys, see utils.DefaultComparator,
+// and an order of 32; returns containers.ErrNoComparator if the keys have none.
+func (tree *Tree[K, V]) UnmarshalBinary(data []byte) error {
+	if err := tree.initZero(); err != nil {
+		return err
+	}
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err == nil {
+		tree.Clear()
+		for i, key := range keys {
+			tree.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// GobEncode outputs the binary representation of the tree for encoding/gob, see MarshalBinary.
+func (tree *Tree[K, V]) GobEncode() ([]byte, error) {
+	return tree.MarshalBinary()
+}
+
+// GobDecode populates the tree from the binary representation for encoding/gob, see UnmarshalBinary.
+func (tree *Tree[K, V]) GobDecode(data []byte) error {
+	return tree.UnmarshalBinary(data)
+}
+
+// MarshalJSON outputs the JSON representation of the tree for encoding/json: an object with its entries in key order.
+func (tree *Tree[K, V]) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := tree.WriteJSON(&buffer)
+	return buffer.Bytes(), err
+}
+
+// UnmarshalJSON populates the tree from the JSON representation for encoding/json, see MarshalJSON.
+// Like UnmarshalBinary, it accepts the zero value of a tree.
+func (tree *Tree[K, V]) UnmarshalJSON(data []byte) error {
+	if err := tree.initZero(); err != nil {
+		return err
+	}
+	keys, values, err := containers.UnmarshalJSONEntries[K, V](data)
+	if err == nil {
+		tree.Clear()
+		for i, key := range keys {
+			tree.Put(key, values[i])
+		}
+	}
+	return err
+}
+
+// defaultOrder is the order of a tree unmarshaled into its zero value.
+const defaultOrder = 32
+
+// initZero sets the comparator and order of a tree that was not instantiated by a constructor.
+func (tree *Tree[K, V]) initZero() error {
+	if tree.Comparator == nil {
+		if tree.Comparator = utils.DefaultComparator[K](); tree.Comparator == nil {
+			return containers.ErrNoComparator
+		}
+	}
+	if tree.m == 0 {
+		tree.m = defaultOrder
+	}
+	return nil
+}
+
+// WriteJSON writes the JSON representation of the tree to the writer in key order, encoding one entry at a time.
+func (tree *Tree[K, V]) WriteJSON(w io.Writer) error {
+	it := tree.Iterator()
+	return containers.WriteJSONEntries[K, V](w, &it)
+}
+
+// ReadJSON populates the tree from the JSON representation read from the reader, putting every entry as it is decoded.
+// Like UnmarshalJSON, it accepts the zero value of a tree. On error, the tree holds the entries read before the error.
+func (tree *Tree[K, V]) ReadJSON(r io.Reader) error {
+	if err := tree.initZero(); err != nil {
+		return err
+	}
+	tree.Clear()
+	return containers.ReadJSONEntries(r, func(key K, value V) {
+		tree.Put(key, value)
+	})
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// Structure format of a B-tree: the order of the tree followed by the nodes in pre-order.
+// Each node is a header holding its number of entries shifted left by one, with the lowest bit set
+// for internal nodes, followed by its entries and then its children.
+
+// MarshalStructure outputs the structure format of the tree, which records its order and the entries of every node.
+// Keys and values are encoded by the codecs of containers.CodecFor.
+func (tree *Tree[K, V]) MarshalStructure() ([]byte, error) {
+	encoder := containers.NewStructureEncoder[K, V](tree.size)
+	encoder.Uvarint(uint64(tree.m))
+	if err := marshalNode(encoder, tree.Root); err != nil {
+		return nil, err
+	}
+	return encoder.Bytes(), nil
+}
+
+// UnmarshalStructure replaces the elements of the tree with the exact tree recorded in the structure format, in O(n) time.
+// The tree takes the order of the recorded tree.
+// Returns an error wrapping containers.ErrInvalidBinary if the data is not in the structure format of a B-tree,
+// or containers.ErrInvalidStructure if the recorded tree is not a valid B-tree for the comparator of the tree.
+// The tree is left unchanged on error. Like UnmarshalBinary, it accepts the zero value of a tree.
+func (tree *Tree[K, V]) UnmarshalStructure(data []byte) error {
+	if err := tree.initZero(); err != nil {
+		return err
+	}
+	decoder, count, err := containers.NewStructureDecoder[K, V](data)
+	if err != nil {
+		return err
+	}
+	order, err := decoder.Uvarint()
+	if err != nil {
+		return err
+	}
+	if order < 3 || order > math.MaxInt32 {
+		return fmt.Errorf("btree: invalid order %d: %w", order, containers.ErrInvalidBinary)
+	}
+	r := &restorer[K, V]{
+		decoder:    decoder,
+		comparator: tree.Comparator,
+		shape:      &Tree[K, V]{m: int(order)},
+		count:      count,
+		maxDepth:   bits.Len(uint(count)),
+		leafDepth:  -1,
+	}
+	var root *Node[K, V]
+	if count > 0 {
+		if root, err = r.node(nil, 0, nil, nil); err != nil {
+			return err
+		}
+	}
+	if r.restored != count {
+		return fmt.Errorf("btree: %d entries but a count of %d: %w", r.restored, count, containers.ErrInvalidBinary)
+	}
+	if err := decoder.End(); err != nil {
+		return err
+	}
+	tree.Clear()
+	tree.Root, tree.size, tree.m = root, count, int(order)
+	return nil
+}
+
+func marshalNode[K comparable, V any](encoder *containers.StructureEncoder[K, V], node *Node[K, V]) error {
+	if node == nil {
+		return nil
+	}
+	header := uint64(len(node.Entries)) << 1
+	if len(node.Children) > 0 {
+		header |= 1
+	}
+	encoder.Uvarint(header)
+	for _, entry := range node.Entries {
+		if err := encoder.Entry(entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+	for _, child := range node.Children {
+		if err := marshalNode(encoder, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restorer links the nodes of a structure format, checking the order of the keys, the number of entries
+// of every node and the depth of the leaves.
+type restorer[K comparable, V any] struct {
+	decoder    *containers.StructureDecoder[K, V]
+	comparator utils.Comparator[K]
+	shape      *Tree[K, V] // empty tree of the recorded order, for the bounds of the number of entries
+	count      int         // number of entries in the header
+	restored   int         // number of entries restored so far
+	maxDepth   int         // depth no B-tree of count entries reaches
+	leafDepth  int         // depth of the first leaf, -1 before it is restored
+}
+
+// node restores the subtree below the parent whose keys lie between the keys of the lower and upper entries,
+// which are nil if unbounded.
+func (r *restorer[K, V]) node(parent *Node[K, V], depth int, lower *Entry[K, V], upper *Entry[K, V]) (*Node[K, V], error) {
+	if depth > r.maxDepth {
+		return nil, fmt.Errorf("btree: path deeper than %d nodes: %w", r.maxDepth, containers.ErrInvalidStructure)
+	}
+	header, err := r.decoder.Uvarint()
+	if err != nil {
+		return nil, err
+	}
+	size, internal := header>>1, header&1 == 1
+	minEntries := r.shape.minEntries()
+	if parent == nil {
+		minEntries = 1
+	}
+	if size > uint64(r.count-r.restored) {
+		return nil, fmt.Errorf("btree: more entries than a count of %d: %w", r.count, containers.ErrInvalidBinary)
+	}
+	if size < uint64(minEntries) || size > uint64(r.shape.maxEntries()) {
+		return nil, fmt.Errorf("btree: node of %d entries after key %v in a tree of order %d: %w", size, entryKey(lower), r.shape.m, containers.ErrInvalidStructure)
+	}
+	node := &Node[K, V]{Parent: parent, Entries: make([]*Entry[K, V], size)}
+	for i := range node.Entries {
+		key, value, err := r.decoder.Entry()
+		if err != nil {
+			return nil, err
+		}
+		node.Entries[i] = &Entry[K, V]{Key: key, Value: value}
+		previous := lower
+		if i > 0 {
+			previous = node.Entries[i-1]
+		}
+		if previous != nil && r.comparator(previous.Key, key) >= 0 {
+			return nil, fmt.Errorf("btree: key %v follows key %v: %w", key, previous.Key, containers.ErrInvalidStructure)
+		}
+	}
+	r.restored += int(size)
+	if last := node.Entries[size-1]; upper != nil && r.comparator(last.Key, upper.Key) >= 0 {
+		return nil, fmt.Errorf("btree: key %v precedes key %v: %w", last.Key, upper.Key, containers.ErrInvalidStructure)
+	}
+
+	if !internal {
+		if r.leafDepth == -1 {
+			r.leafDepth = depth
+		}
+		if depth != r.leafDepth {
+			return nil, fmt.Errorf("btree: leaf %v at depth %d, other leaves at depth %d: %w", node.Entries[0].Key, depth, r.leafDepth, containers.ErrInvalidStructure)
+		}
+		return node, nil
+	}
+	node.Children = make([]*Node[K, V], size+1)
+	for i := range node.Children {
+		childLower, childUpper := lower, upper
+		if i > 0 {
+			childLower = node.Entries[i-1]
+		}
+		if i < int(size) {
+			childUpper = node.Entries[i]
+		}
+		if node.Children[i], err = r.node(node, depth+1, childLower, childUpper); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// entryKey returns the key of the entry, or "nil" if there is none, for error messages.
+func entryKey[K comparable, V any](entry *Entry[K, V]) interface{} {
+	if entry == nil {
+		return nil
+	}
+	return entry.Key
+}
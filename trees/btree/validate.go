@@ -0,0 +1,90 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+import (
+	"fmt"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Validate checks the invariants of the tree: keys in strictly ascending order for the comparator,
+// between minEntries and maxEntries entries in every node but the root, one child more than entries
+// in every internal node, all leaves at the same depth, parent pointers matching the children
+// and a size matching the number of entries.
+// Returns nil for a valid tree, otherwise an error wrapping containers.ErrInvalidStructure naming the offending node.
+func (tree *Tree[K, V]) Validate() error {
+	v := &validator[K, V]{tree: tree, leafDepth: -1}
+	if tree.Root != nil {
+		if err := v.validate(tree.Root, nil, 0, nil, nil); err != nil {
+			return err
+		}
+	}
+	if v.count != tree.size {
+		return fmt.Errorf("btree: %d entries but a size of %d: %w", v.count, tree.size, containers.ErrInvalidStructure)
+	}
+	return nil
+}
+
+type validator[K comparable, V any] struct {
+	tree      *Tree[K, V]
+	count     int // number of entries visited so far
+	leafDepth int // depth of the first leaf, -1 before it is visited
+}
+
+// validate checks the subtree of the node below the parent whose keys lie between the keys of the lower and upper entries,
+// which are nil if unbounded.
+func (v *validator[K, V]) validate(node *Node[K, V], parent *Node[K, V], depth int, lower *Entry[K, V], upper *Entry[K, V]) error {
+	if node == nil {
+		return fmt.Errorf("btree: nil child after key %v: %w", entryKey(lower), containers.ErrInvalidStructure)
+	}
+	minEntries := v.tree.minEntries()
+	if parent == nil {
+		minEntries = 1
+	}
+	if size := len(node.Entries); size < minEntries || size > v.tree.maxEntries() {
+		return fmt.Errorf("btree: node of %d entries after key %v in a tree of order %d: %w", size, entryKey(lower), v.tree.m, containers.ErrInvalidStructure)
+	}
+	v.count += len(node.Entries)
+	if node.Parent != parent {
+		return fmt.Errorf("btree: node %v does not point to its parent: %w", node.Entries[0].Key, containers.ErrInvalidStructure)
+	}
+	previous := lower
+	for _, entry := range node.Entries {
+		if previous != nil && v.tree.Comparator(previous.Key, entry.Key) >= 0 {
+			return fmt.Errorf("btree: key %v follows key %v: %w", entry.Key, previous.Key, containers.ErrInvalidStructure)
+		}
+		previous = entry
+	}
+	if upper != nil && v.tree.Comparator(previous.Key, upper.Key) >= 0 {
+		return fmt.Errorf("btree: key %v precedes key %v: %w", previous.Key, upper.Key, containers.ErrInvalidStructure)
+	}
+
+	if len(node.Children) == 0 {
+		if v.leafDepth == -1 {
+			v.leafDepth = depth
+		}
+		if depth != v.leafDepth {
+			return fmt.Errorf("btree: leaf %v at depth %d, other leaves at depth %d: %w", node.Entries[0].Key, depth, v.leafDepth, containers.ErrInvalidStructure)
+		}
+		return nil
+	}
+	if len(node.Children) != len(node.Entries)+1 {
+		return fmt.Errorf("btree: node %v has %d entries but %d children: %w", node.Entries[0].Key, len(node.Entries), len(node.Children), containers.ErrInvalidStructure)
+	}
+	for i, child := range node.Children {
+		childLower, childUpper := lower, upper
+		if i > 0 {
+			childLower = node.Entries[i-1]
+		}
+		if i < len(node.Entries) {
+			childUpper = node.Entries[i]
+		}
+		if err := v.validate(child, node, depth+1, childLower, childUpper); err != nil {
+			return err
+		}
+	}
+	return nil
+}
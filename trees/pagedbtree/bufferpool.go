@@ -0,0 +1,123 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pagedbtree
+
+import "container/list"
+
+var _ PageStore = (*BufferPool)(nil)
+
+// BufferPool is a PageStore caching the most recently used pages of another store in memory.
+// Writes go through to the underlying store, so the pool never holds the only copy of a page.
+type BufferPool struct {
+	store    PageStore
+	capacity int
+	pages    map[PageID]*list.Element
+	lru      *list.List // front is the most recently used page
+	hits     int
+	misses   int
+}
+
+type cachedPage struct {
+	id   PageID
+	data []byte
+}
+
+// NewBufferPool instantiates a buffer pool caching up to capacity pages of the store.
+func NewBufferPool(store PageStore, capacity int) *BufferPool {
+	if capacity < 1 {
+		panic("Invalid capacity, should be at least 1")
+	}
+	return &BufferPool{store: store, capacity: capacity, pages: make(map[PageID]*list.Element), lru: list.New()}
+}
+
+// PageSize returns the page size of the underlying store.
+func (pool *BufferPool) PageSize() int {
+	return pool.store.PageSize()
+}
+
+// PageCount returns the page count of the underlying store.
+func (pool *BufferPool) PageCount() uint64 {
+	return pool.store.PageCount()
+}
+
+// Allocate allocates a page in the underlying store.
+func (pool *BufferPool) Allocate() (PageID, error) {
+	return pool.store.Allocate()
+}
+
+// Free drops the page from the cache and frees it in the underlying store.
+func (pool *BufferPool) Free(id PageID) error {
+	if element, found := pool.pages[id]; found {
+		pool.lru.Remove(element)
+		delete(pool.pages, id)
+	}
+	return pool.store.Free(id)
+}
+
+// ReadPage reads the page from the cache, loading it from the underlying store on a miss.
+func (pool *BufferPool) ReadPage(id PageID, page []byte) error {
+	if element, found := pool.pages[id]; found {
+		pool.hits++
+		pool.lru.MoveToFront(element)
+		copy(page, element.Value.(*cachedPage).data)
+		return nil
+	}
+	pool.misses++
+	if err := pool.store.ReadPage(id, page); err != nil {
+		return err
+	}
+	pool.cache(id, page)
+	return nil
+}
+
+// WritePage writes the page to the underlying store and caches it.
+func (pool *BufferPool) WritePage(id PageID, page []byte) error {
+	if err := pool.store.WritePage(id, page); err != nil {
+		if element, found := pool.pages[id]; found {
+			pool.lru.Remove(element)
+			delete(pool.pages, id)
+		}
+		return err
+	}
+	pool.cache(id, page)
+	return nil
+}
+
+// Sync syncs the underlying store.
+func (pool *BufferPool) Sync() error {
+	return pool.store.Sync()
+}
+
+// Close empties the cache and closes the underlying store.
+func (pool *BufferPool) Close() error {
+	pool.pages = make(map[PageID]*list.Element)
+	pool.lru.Init()
+	return pool.store.Close()
+}
+
+// Stats returns the number of reads served from the cache and the number of reads that missed it.
+func (pool *BufferPool) Stats() (hits int, misses int) {
+	return pool.hits, pool.misses
+}
+
+func (pool *BufferPool) cache(id PageID, page []byte) {
+	if element, found := pool.pages[id]; found {
+		copy(element.Value.(*cachedPage).data, page)
+		pool.lru.MoveToFront(element)
+		return
+	}
+	var data []byte
+	if pool.lru.Len() >= pool.capacity {
+		// recycle the buffer of the least recently used page
+		oldest := pool.lru.Back()
+		evicted := pool.lru.Remove(oldest).(*cachedPage)
+		delete(pool.pages, evicted.id)
+		data = evicted.data
+	} else {
+		data = make([]byte, pool.store.PageSize())
+	}
+	copy(data, page)
+	pool.pages[id] = pool.lru.PushFront(&cachedPage{id: id, data: data})
+}
@@ -0,0 +1,323 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pagedbtree
+
+import "github.com/monitor1379/yagods/containers"
+
+var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
+
+// Iterator holding the iterator's state
+//
+// As there are no parent links between pages, the iterator keeps the path from the root to the current entry.
+// Every step of the path holds the index of the child descended into, except the last one,
+// which holds the index of the current entry.
+type Iterator[K comparable, V any] struct {
+	tree     *Tree[K, V]
+	path     []step[K, V]
+	position position
+	modCount int   // tree's modification count the iterator is synchronized with
+	removed  bool  // current entry was removed, path points to its predecessor
+	err      error // error reading a page
+}
+
+type position byte
+
+const (
+	begin, between, end position = 0, 1, 2
+)
+
+// Iterator returns a stateful iterator whose elements are key/value pairs.
+func (tree *Tree[K, V]) Iterator() Iterator[K, V] {
+	return Iterator[K, V]{tree: tree, position: begin, modCount: tree.modCount}
+}
+
+// IteratorAt returns a stateful iterator whose elements are key/value pairs that is initialised
+// at the first element whose key is greater than or equal to the given key (see Seek()).
+func (tree *Tree[K, V]) IteratorAt(key K) Iterator[K, V] {
+	iterator := tree.Iterator()
+	iterator.Seek(key)
+	return iterator
+}
+
+// Next moves the iterator to the next element and returns true if there was a next element in the container.
+// If Next() returns true, then next element's key and value can be retrieved by Key() and Value().
+// If Next() was called for the first time, then it will point the iterator to the first element if it exists.
+// Returns false if a page could not be read, see Err().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
+	if iterator.err != nil {
+		return false
+	}
+	switch iterator.position {
+	case end:
+	case begin:
+		if !iterator.tree.root.empty() && iterator.descend(iterator.tree.root, false) {
+			iterator.position = between
+			return true
+		}
+	default:
+		last := &iterator.path[len(iterator.path)-1]
+		if !last.node.isLeaf() {
+			// go down to the left-most entry of the child right of the current entry
+			last.index++
+			if iterator.descend(last.node.children[last.index], false) {
+				return true
+			}
+		} else if last.index+1 < len(last.node.keys) {
+			last.index++
+			return true
+		} else if iterator.ascend(false) {
+			return true
+		}
+	}
+	iterator.reset(end)
+	return false
+}
+
+// Prev moves the iterator to the previous element and returns true if there was a previous element in the container.
+// If Prev() returns true, then previous element's key and value can be retrieved by Key() and Value().
+// Returns false if a page could not be read, see Err().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Prev() bool {
+	iterator.checkModification()
+	if iterator.removed {
+		iterator.removed = false
+		return iterator.position == between
+	}
+	if iterator.err != nil {
+		return false
+	}
+	switch iterator.position {
+	case begin:
+	case end:
+		if !iterator.tree.root.empty() && iterator.descend(iterator.tree.root, true) {
+			iterator.position = between
+			return true
+		}
+	default:
+		last := &iterator.path[len(iterator.path)-1]
+		if !last.node.isLeaf() {
+			// go down to the right-most entry of the child left of the current entry
+			if iterator.descend(last.node.children[last.index], true) {
+				return true
+			}
+		} else if last.index > 0 {
+			last.index--
+			return true
+		} else if iterator.ascend(true) {
+			return true
+		}
+	}
+	iterator.reset(begin)
+	return false
+}
+
+// Value returns the current element's value.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Value() V {
+	last := iterator.path[len(iterator.path)-1]
+	return last.node.values[last.index]
+}
+
+// Key returns the current element's key.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Key() K {
+	last := iterator.path[len(iterator.path)-1]
+	return last.node.keys[last.index]
+}
+
+// Begin resets the iterator to its initial state (one-before-first)
+// Call Next() to fetch the first element if any.
+func (iterator *Iterator[K, V]) Begin() {
+	iterator.err = nil
+	iterator.reset(begin)
+}
+
+// End moves the iterator past the last element (one-past-the-end).
+// Call Prev() to fetch the last element if any.
+func (iterator *Iterator[K, V]) End() {
+	iterator.err = nil
+	iterator.reset(end)
+}
+
+// First moves the iterator to the first element and returns true if there was a first element in the container.
+// If First() returns true, then first element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator
+func (iterator *Iterator[K, V]) First() bool {
+	iterator.Begin()
+	return iterator.Next()
+}
+
+// Last moves the iterator to the last element and returns true if there was a last element in the container.
+// If Last() returns true, then last element's key and value can be retrieved by Key() and Value().
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Last() bool {
+	iterator.End()
+	return iterator.Prev()
+}
+
+// Seek moves the iterator to the first element whose key is greater than or equal to the given key
+// and returns true if there was such an element in the container.
+// If Seek() returns true, then element's key and value can be retrieved by Key() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	iterator.End()
+	if iterator.find(key, false) {
+		iterator.position = between
+		return true
+	}
+	iterator.reset(end)
+	return false
+}
+
+// SeekPrev moves the iterator to the last element whose key is less than or equal to the given key
+// and returns true if there was such an element in the container.
+// If SeekPrev() returns true, then element's key and value can be retrieved by Key() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) SeekPrev(key K) bool {
+	iterator.Begin()
+	if iterator.find(key, true) {
+		iterator.position = between
+		return true
+	}
+	iterator.reset(begin)
+	return false
+}
+
+// Err returns the error that stopped the iteration if a page could not be read,
+// or containers.ErrConcurrentModification if the tree was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	if iterator.err != nil {
+		return iterator.err
+	}
+	if iterator.modCount != iterator.tree.modCount && (iterator.position != begin || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current entry from the tree and keeps the iterator valid.
+// Next() moves to the entry that followed the removed one and Prev() to the entry that preceded it.
+// Does nothing if the iterator is not positioned on an entry.
+func (iterator *Iterator[K, V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || iterator.position != between {
+		return
+	}
+	key := iterator.Key()
+	if err := iterator.tree.Remove(key); err != nil {
+		iterator.err = err
+		return
+	}
+	// removal copies and rebalances nodes, so look the predecessor up again
+	iterator.SeekPrev(key)
+	iterator.removed = true
+}
+
+// checkModification panics if the tree was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the tree.
+func (iterator *Iterator[K, V]) checkModification() {
+	if iterator.err == nil {
+		if err := iterator.Err(); err != nil {
+			panic(err)
+		}
+	}
+	iterator.modCount = iterator.tree.modCount
+}
+
+func (iterator *Iterator[K, V]) reset(position position) {
+	iterator.path = iterator.path[:0]
+	iterator.position = position
+	iterator.modCount = iterator.tree.modCount
+	iterator.removed = false
+}
+
+// load reads the referenced node, recording the error if the page could not be read.
+func (iterator *Iterator[K, V]) load(r ref[K, V]) *node[K, V] {
+	node, err := iterator.tree.node(r)
+	if err != nil {
+		iterator.err = err
+	}
+	return node
+}
+
+// descend goes down from the referenced node to its left-most or right-most entry.
+func (iterator *Iterator[K, V]) descend(r ref[K, V], rightmost bool) bool {
+	for {
+		node := iterator.load(r)
+		if node == nil {
+			return false
+		}
+		index := 0
+		if rightmost {
+			index = len(node.children) - 1
+			if node.isLeaf() {
+				index = len(node.keys) - 1
+			}
+		}
+		iterator.path = append(iterator.path, step[K, V]{node, index})
+		if node.isLeaf() {
+			return true
+		}
+		r = node.children[index]
+	}
+}
+
+// ascend leaves the current leaf and goes up to the first ancestor entry after (or before) the current entry.
+func (iterator *Iterator[K, V]) ascend(backward bool) bool {
+	iterator.path = iterator.path[:len(iterator.path)-1]
+	for len(iterator.path) > 0 {
+		last := &iterator.path[len(iterator.path)-1]
+		if !backward && last.index < len(last.node.keys) {
+			return true
+		}
+		if backward && last.index > 0 {
+			last.index--
+			return true
+		}
+		iterator.path = iterator.path[:len(iterator.path)-1]
+	}
+	return false
+}
+
+// find goes down to the given key and positions the iterator at its ceiling (or floor).
+func (iterator *Iterator[K, V]) find(key K, floor bool) bool {
+	if iterator.tree.root.empty() {
+		return false
+	}
+	r := iterator.tree.root
+	for {
+		node := iterator.load(r)
+		if node == nil {
+			return false
+		}
+		index, found := iterator.tree.search(node, key)
+		iterator.path = append(iterator.path, step[K, V]{node, index})
+		if found {
+			return true
+		}
+		if node.isLeaf() {
+			last := &iterator.path[len(iterator.path)-1]
+			switch {
+			case !floor && index < len(node.keys):
+				return true
+			case floor && index > 0:
+				last.index--
+				return true
+			}
+			return iterator.ascend(floor)
+		}
+		r = node.children[index]
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pagedbtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// Page layout
+//
+// Pages 0 and 1 hold alternating meta records, the one with the higher transaction id and a valid checksum
+// being current:
+//
+//	magic [8]byte | page size uint32 | order uint32 | txid uint64 | root uint64 | size uint64 | crc32 uint32
+//
+// All other pages hold a single node, followed by the checksum of the page in its last four bytes:
+//
+//	kind byte | count uint16 | count * (key length uvarint | key | value length uvarint | value) |
+//	internal nodes only: (count+1) * child uint64 | ... | crc32 uint32
+//
+// Root page 0 stands for the empty tree, as page 0 never holds a node.
+
+const (
+	metaPages      = 2
+	metaSize       = 8 + 4 + 4 + 8 + 8 + 8
+	nodeHeaderSize = 1 + 2
+	checksumSize   = 4
+	childSize      = 8
+	leafKind       = 1
+	internalKind   = 2
+)
+
+var magic = [8]byte{'Y', 'A', 'G', 'O', 'D', 'S', 'B', 'T'}
+
+// ErrCorruptPage is returned when a page fails its checksum or does not hold what the tree expects,
+// e.g. after a torn write.
+var ErrCorruptPage = errors.New("pagedbtree: corrupt page")
+
+type meta struct {
+	pageSize int
+	order    int
+	txid     uint64
+	root     PageID
+	size     int
+}
+
+func encodeMeta(m meta, page []byte) {
+	for i := range page {
+		page[i] = 0
+	}
+	copy(page, magic[:])
+	binary.BigEndian.PutUint32(page[8:], uint32(m.pageSize))
+	binary.BigEndian.PutUint32(page[12:], uint32(m.order))
+	binary.BigEndian.PutUint64(page[16:], m.txid)
+	binary.BigEndian.PutUint64(page[24:], uint64(m.root))
+	binary.BigEndian.PutUint64(page[32:], uint64(m.size))
+	binary.BigEndian.PutUint32(page[metaSize:], crc32.ChecksumIEEE(page[:metaSize]))
+}
+
+func decodeMeta(page []byte) (m meta, ok bool) {
+	if !bytes.Equal(page[:len(magic)], magic[:]) || binary.BigEndian.Uint32(page[metaSize:]) != crc32.ChecksumIEEE(page[:metaSize]) {
+		return m, false
+	}
+	m.pageSize = int(binary.BigEndian.Uint32(page[8:]))
+	m.order = int(binary.BigEndian.Uint32(page[12:]))
+	m.txid = binary.BigEndian.Uint64(page[16:])
+	m.root = PageID(binary.BigEndian.Uint64(page[24:]))
+	m.size = int(binary.BigEndian.Uint64(page[32:]))
+	return m, true
+}
+
+// maxEntrySize returns the largest encoded size of a single entry such that a node
+// of the given order with the maximum number of entries always fits into a page.
+func maxEntrySize(pageSize int, order int) int {
+	return (pageSize - nodeHeaderSize - checksumSize - order*childSize) / (order - 1)
+}
+
+// entrySize returns the encoded size of an entry with the given key and value lengths.
+func entrySize(keyLength int, valueLength int) int {
+	return uvarintSize(keyLength) + keyLength + uvarintSize(valueLength) + valueLength
+}
+
+func uvarintSize(n int) int {
+	var buffer [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buffer[:], uint64(n))
+}
+
+func (tree *Tree[K, V]) encodeNode(node *node[K, V], page []byte) error {
+	for i := range page {
+		page[i] = 0
+	}
+	kind := byte(leafKind)
+	if !node.isLeaf() {
+		kind = internalKind
+	}
+	page[0] = kind
+	binary.BigEndian.PutUint16(page[1:], uint16(len(node.keys)))
+	offset := nodeHeaderSize
+	for i := range node.keys {
+		key, err := tree.KeyCodec.Encode(node.keys[i])
+		if err != nil {
+			return err
+		}
+		value, err := tree.ValueCodec.Encode(node.values[i])
+		if err != nil {
+			return err
+		}
+		if entrySize(len(key), len(value)) > tree.maxEntrySize {
+			return ErrEntryTooLarge
+		}
+		offset += binary.PutUvarint(page[offset:], uint64(len(key)))
+		offset += copy(page[offset:], key)
+		offset += binary.PutUvarint(page[offset:], uint64(len(value)))
+		offset += copy(page[offset:], value)
+	}
+	for _, child := range node.children {
+		binary.BigEndian.PutUint64(page[offset:], uint64(child.page))
+		offset += childSize
+	}
+	end := len(page) - checksumSize
+	binary.BigEndian.PutUint32(page[end:], crc32.ChecksumIEEE(page[:end]))
+	return nil
+}
+
+func (tree *Tree[K, V]) decodeNode(page []byte) (*node[K, V], error) {
+	end := len(page) - checksumSize
+	if binary.BigEndian.Uint32(page[end:]) != crc32.ChecksumIEEE(page[:end]) {
+		return nil, ErrCorruptPage
+	}
+	kind, count := page[0], int(binary.BigEndian.Uint16(page[1:]))
+	if (kind != leafKind && kind != internalKind) || (kind == internalKind && count == 0) || count > tree.m-1 {
+		return nil, ErrCorruptPage
+	}
+	node := &node[K, V]{keys: make([]K, count, count+1), values: make([]V, count, count+1)}
+	offset := nodeHeaderSize
+	field := func() ([]byte, error) {
+		length, n := binary.Uvarint(page[offset:end])
+		if n <= 0 || length > uint64(end-offset-n) {
+			return nil, ErrCorruptPage
+		}
+		offset += n
+		data := page[offset : offset+int(length)]
+		offset += int(length)
+		return data, nil
+	}
+	for i := 0; i < count; i++ {
+		key, err := field()
+		if err != nil {
+			return nil, err
+		}
+		if node.keys[i], err = tree.KeyCodec.Decode(key); err != nil {
+			return nil, err
+		}
+		value, err := field()
+		if err != nil {
+			return nil, err
+		}
+		if node.values[i], err = tree.ValueCodec.Decode(value); err != nil {
+			return nil, err
+		}
+	}
+	if kind == internalKind {
+		if offset+(count+1)*childSize > end {
+			return nil, ErrCorruptPage
+		}
+		node.children = make([]ref[K, V], count+1, count+2)
+		for i := range node.children {
+			id := PageID(binary.BigEndian.Uint64(page[offset:]))
+			if id < metaPages {
+				return nil, ErrCorruptPage
+			}
+			node.children[i].page = id
+			offset += childSize
+		}
+	}
+	return node, nil
+}
@@ -0,0 +1,673 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pagedbtree implements a B-tree whose nodes are stored in fixed-size pages of a PageStore,
+// so that the tree can grow larger than the available memory and survive restarts.
+//
+// The tree has the same structure as the in-memory btree: every node has at most m children
+// and every non-root node at least ⌈m/2⌉ children, with entries stored in all nodes.
+// Keys and values are converted to bytes by pluggable codecs (see containers.Codec).
+//
+// Changes are copy-on-write: a modified node is written to a new page when the changes are committed,
+// while its old page stays untouched until the commit is durable. Commit syncs the new pages and then
+// switches the root by writing one of two alternating meta pages, which is synced as well.
+// After a crash the tree is reopened at the last commit whose meta page was written completely,
+// every page being protected by a checksum. Uncommitted changes are kept in memory and can be discarded by Rollback.
+//
+// Opening a tree visits all of its pages once to find the unused ones.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/B-tree, https://en.wikipedia.org/wiki/Shadow_paging
+package pagedbtree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// ErrEntryTooLarge is returned when an encoded key-value pair does not fit into a page together
+// with the maximum number of other entries of a node (see MaxEntrySize).
+var ErrEntryTooLarge = errors.New("pagedbtree: entry too large for page")
+
+// Tree holds elements of the paged B-tree
+type Tree[K comparable, V any] struct {
+	Comparator utils.Comparator[K] // Key comparator
+	KeyCodec   containers.Codec[K] // Key encoder
+	ValueCodec containers.Codec[V] // Value encoder
+
+	store        PageStore
+	m            int       // order (maximum number of children)
+	maxEntrySize int       // largest encoded entry that always fits into a node's page
+	root         ref[K, V] // current, possibly uncommitted root
+	size         int       // total number of keys in the tree
+	committed    meta      // last durable meta record
+	freed        []PageID  // pages of the committed tree replaced by uncommitted changes
+	page         []byte    // page buffer
+	modCount     int       // number of structural modifications, checked by iterators
+}
+
+// ref refers to a child node, either committed in a page or modified in memory
+type ref[K comparable, V any] struct {
+	page PageID      // page of the committed node, 0 if the node was modified
+	node *node[K, V] // modified node, nil if unchanged since the last commit
+}
+
+type node[K comparable, V any] struct {
+	keys     []K
+	values   []V
+	children []ref[K, V]
+}
+
+func (node *node[K, V]) isLeaf() bool {
+	return len(node.children) == 0
+}
+
+func (r ref[K, V]) empty() bool {
+	return r.page == 0 && r.node == nil
+}
+
+// Open opens the tree stored in the store, or initializes an empty tree of the given order (maximum number of children)
+// if the store holds no pages. The order of an existing tree has to match.
+func Open[K comparable, V any](store PageStore, order int, comparator utils.Comparator[K], keyCodec containers.Codec[K], valueCodec containers.Codec[V]) (*Tree[K, V], error) {
+	if order < 3 {
+		panic("Invalid order, should be at least 3")
+	}
+	tree := &Tree[K, V]{
+		Comparator:   comparator,
+		KeyCodec:     keyCodec,
+		ValueCodec:   valueCodec,
+		store:        store,
+		m:            order,
+		maxEntrySize: maxEntrySize(store.PageSize(), order),
+		page:         make([]byte, store.PageSize()),
+	}
+	if store.PageSize() < metaSize+checksumSize || tree.maxEntrySize < 2 {
+		return nil, fmt.Errorf("pagedbtree: page size %d too small for order %d", store.PageSize(), order)
+	}
+	initialize := tree.load
+	if store.PageCount() == 0 {
+		initialize = tree.initialize
+	}
+	if err := initialize(); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// MaxEntrySize returns the largest encoded size of a key-value pair accepted by Put,
+// including the length prefixes of key and value.
+func (tree *Tree[K, V]) MaxEntrySize() int {
+	return tree.maxEntrySize
+}
+
+// Put inserts key-value pair node into the tree.
+// If key already exists, then its value is updated with the new value.
+// Returns ErrEntryTooLarge if the encoded pair exceeds MaxEntrySize, leaving the tree unchanged.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Put(key K, value V) error {
+	if err := tree.checkEntry(key, value); err != nil {
+		return err
+	}
+	if tree.root.empty() {
+		tree.root.node = &node[K, V]{keys: []K{key}, values: []V{value}}
+		tree.size++
+		tree.modCount++
+		return nil
+	}
+	current, err := tree.mutable(&tree.root)
+	if err != nil {
+		return err
+	}
+	var path []step[K, V]
+	for {
+		index, found := tree.search(current, key)
+		if found {
+			current.keys[index] = key
+			current.values[index] = value
+			return nil
+		}
+		if current.isLeaf() {
+			current.keys = insertAt(current.keys, index, key)
+			current.values = insertAt(current.values, index, value)
+			break
+		}
+		path = append(path, step[K, V]{current, index})
+		if current, err = tree.mutable(&current.children[index]); err != nil {
+			return err
+		}
+	}
+	tree.size++
+	tree.modCount++
+	tree.split(current, path)
+	return nil
+}
+
+// Get searches the node in the tree by key and returns its value or nil if key is not found in tree.
+// Second return parameter is true if key was found, otherwise false.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Get(key K) (value V, found bool, err error) {
+	if tree.root.empty() {
+		return value, false, nil
+	}
+	current := tree.root
+	for {
+		node, err := tree.node(current)
+		if err != nil {
+			return value, false, err
+		}
+		index, found := tree.search(node, key)
+		if found {
+			return node.values[index], true, nil
+		}
+		if node.isLeaf() {
+			return value, false, nil
+		}
+		current = node.children[index]
+	}
+}
+
+// Remove remove the node from the tree by key.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Remove(key K) error {
+	// look the key up first, so that removing a missing key does not copy any nodes
+	if _, found, err := tree.Get(key); err != nil || !found {
+		return err
+	}
+	current, err := tree.mutable(&tree.root)
+	if err != nil {
+		return err
+	}
+	var path []step[K, V]
+	index, found := tree.search(current, key)
+	for !found {
+		path = append(path, step[K, V]{current, index})
+		if current, err = tree.mutable(&current.children[index]); err != nil {
+			return err
+		}
+		index, found = tree.search(current, key)
+	}
+	if !current.isLeaf() {
+		// replace the entry by its in-order predecessor, the right-most entry of the left subtree
+		internal, internalIndex := current, index
+		path = append(path, step[K, V]{current, index})
+		if current, err = tree.mutable(&current.children[index]); err != nil {
+			return err
+		}
+		for !current.isLeaf() {
+			last := len(current.children) - 1
+			path = append(path, step[K, V]{current, last})
+			if current, err = tree.mutable(&current.children[last]); err != nil {
+				return err
+			}
+		}
+		index = len(current.keys) - 1
+		internal.keys[internalIndex], internal.values[internalIndex] = current.keys[index], current.values[index]
+	}
+	current.keys = deleteAt(current.keys, index)
+	current.values = deleteAt(current.values, index)
+	tree.size--
+	tree.modCount++
+	return tree.rebalance(current, path)
+}
+
+// Empty returns true if tree does not contain any nodes
+func (tree *Tree[K, V]) Empty() bool {
+	return tree.size == 0
+}
+
+// Size returns number of nodes in the tree.
+func (tree *Tree[K, V]) Size() int {
+	return tree.size
+}
+
+// Keys returns all keys in-order
+func (tree *Tree[K, V]) Keys() ([]K, error) {
+	keys := make([]K, 0, tree.size)
+	it := tree.Iterator()
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	return keys, it.Err()
+}
+
+// Values returns all values in-order based on the key.
+func (tree *Tree[K, V]) Values() ([]V, error) {
+	values := make([]V, 0, tree.size)
+	it := tree.Iterator()
+	for it.Next() {
+		values = append(values, it.Value())
+	}
+	return values, it.Err()
+}
+
+// Clear removes all nodes from the tree. The pages of the committed tree are freed by the next commit.
+func (tree *Tree[K, V]) Clear() error {
+	if err := tree.release(tree.root); err != nil {
+		return err
+	}
+	tree.root = ref[K, V]{}
+	tree.size = 0
+	tree.modCount++
+	return nil
+}
+
+// Height returns the height of the tree.
+func (tree *Tree[K, V]) Height() (int, error) {
+	height := 0
+	for current := tree.root; !current.empty(); height++ {
+		node, err := tree.node(current)
+		if err != nil {
+			return 0, err
+		}
+		if node.isLeaf() {
+			return height + 1, nil
+		}
+		current = node.children[0]
+	}
+	return height, nil
+}
+
+// Commit writes all modified nodes into new pages and durably switches the tree to the new root.
+// Pages no longer used by the tree are handed back to the store afterwards.
+// If writing the nodes fails, their new pages are handed back too and the changes stay uncommitted,
+// so that Commit can be retried.
+func (tree *Tree[K, V]) Commit() error {
+	var written []*ref[K, V]
+	err := tree.write(&tree.root, &written)
+	if err == nil {
+		err = tree.store.Sync()
+	}
+	if err != nil {
+		// no meta record refers to the new pages yet
+		for _, r := range written {
+			tree.store.Free(r.page)
+			r.page = 0
+		}
+		return err
+	}
+	// from here on the new pages hold the nodes, as a failed meta write may still have reached the store
+	for _, r := range written {
+		r.node = nil
+	}
+	next := tree.committed
+	next.txid++
+	next.root = tree.root.page
+	next.size = tree.size
+	encodeMeta(next, tree.page)
+	if err := tree.store.WritePage(PageID(next.txid%metaPages), tree.page); err != nil {
+		return err
+	}
+	if err := tree.store.Sync(); err != nil {
+		return err
+	}
+	tree.committed = next
+	freed := tree.freed
+	tree.freed = nil
+	for _, id := range freed {
+		if err := tree.store.Free(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback discards all changes since the last commit.
+func (tree *Tree[K, V]) Rollback() {
+	tree.root = ref[K, V]{page: tree.committed.root}
+	tree.size = tree.committed.size
+	tree.freed = nil
+	tree.modCount++
+}
+
+// Close discards uncommitted changes and closes the store.
+func (tree *Tree[K, V]) Close() error {
+	tree.Rollback()
+	return tree.store.Close()
+}
+
+// String returns a string representation of container (for debugging purposes)
+func (tree *Tree[K, V]) String() string {
+	str := "PagedBTree\n"
+	keys, err := tree.Keys()
+	if err != nil {
+		return str + err.Error()
+	}
+	for i, key := range keys {
+		if i > 0 {
+			str += ", "
+		}
+		str += fmt.Sprintf("%v", key)
+	}
+	return str
+}
+
+// step is a node on the path from the root together with the index of the child that was descended into
+type step[K comparable, V any] struct {
+	node  *node[K, V]
+	index int
+}
+
+func (tree *Tree[K, V]) maxEntries() int {
+	return tree.m - 1
+}
+
+func (tree *Tree[K, V]) minEntries() int {
+	return (tree.m+1)/2 - 1 // ceil(m/2)-1
+}
+
+func (tree *Tree[K, V]) checkEntry(key K, value V) error {
+	encodedKey, err := tree.KeyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	encodedValue, err := tree.ValueCodec.Encode(value)
+	if err != nil {
+		return err
+	}
+	if entrySize(len(encodedKey), len(encodedValue)) > tree.maxEntrySize {
+		return ErrEntryTooLarge
+	}
+	return nil
+}
+
+// search searches only within the single node among its entries
+func (tree *Tree[K, V]) search(node *node[K, V], key K) (index int, found bool) {
+	low, high := 0, len(node.keys)-1
+	for low <= high {
+		mid := (high + low) / 2
+		compare := tree.Comparator(key, node.keys[mid])
+		switch {
+		case compare > 0:
+			low = mid + 1
+		case compare < 0:
+			high = mid - 1
+		default:
+			return mid, true
+		}
+	}
+	return low, false
+}
+
+// node returns the referenced node, reading it from its page if it was not modified.
+func (tree *Tree[K, V]) node(r ref[K, V]) (*node[K, V], error) {
+	if r.node != nil {
+		return r.node, nil
+	}
+	if err := tree.store.ReadPage(r.page, tree.page); err != nil {
+		return nil, err
+	}
+	return tree.decodeNode(tree.page)
+}
+
+// mutable returns the referenced node for modification. A committed node is copied into memory
+// and its page is freed by the next commit.
+func (tree *Tree[K, V]) mutable(r *ref[K, V]) (*node[K, V], error) {
+	if r.node != nil {
+		return r.node, nil
+	}
+	node, err := tree.node(*r)
+	if err != nil {
+		return nil, err
+	}
+	tree.freed = append(tree.freed, r.page)
+	r.page, r.node = 0, node
+	return node, nil
+}
+
+// split splits the overfull node and its ancestors along the path bottom-up.
+func (tree *Tree[K, V]) split(current *node[K, V], path []step[K, V]) {
+	for len(current.keys) > tree.maxEntries() {
+		middle := (tree.m - 1) / 2
+		right := &node[K, V]{
+			keys:   append([]K(nil), current.keys[middle+1:]...),
+			values: append([]V(nil), current.values[middle+1:]...),
+		}
+		if !current.isLeaf() {
+			right.children = append([]ref[K, V](nil), current.children[middle+1:]...)
+			current.children = append([]ref[K, V](nil), current.children[:middle+1]...)
+		}
+		key, value := current.keys[middle], current.values[middle]
+		current.keys = append([]K(nil), current.keys[:middle]...)
+		current.values = append([]V(nil), current.values[:middle]...)
+		if len(path) == 0 {
+			tree.root = ref[K, V]{node: &node[K, V]{
+				keys:     []K{key},
+				values:   []V{value},
+				children: []ref[K, V]{{node: current}, {node: right}},
+			}}
+			return
+		}
+		parent := path[len(path)-1]
+		path = path[:len(path)-1]
+		parent.node.keys = insertAt(parent.node.keys, parent.index, key)
+		parent.node.values = insertAt(parent.node.values, parent.index, value)
+		parent.node.children = insertAt(parent.node.children, parent.index+1, ref[K, V]{node: right})
+		current = parent.node
+	}
+}
+
+// rebalance refills the underfull node and its ancestors along the path bottom-up,
+// borrowing entries from siblings or merging with them.
+func (tree *Tree[K, V]) rebalance(node *node[K, V], path []step[K, V]) error {
+	for {
+		if len(path) == 0 {
+			if len(node.keys) == 0 {
+				if node.isLeaf() {
+					tree.root = ref[K, V]{}
+				} else {
+					tree.root = node.children[0]
+				}
+			}
+			return nil
+		}
+		if len(node.keys) >= tree.minEntries() {
+			return nil
+		}
+		parent, index := path[len(path)-1].node, path[len(path)-1].index
+		path = path[:len(path)-1]
+
+		// try to borrow from left sibling
+		if index > 0 {
+			left, err := tree.node(parent.children[index-1])
+			if err != nil {
+				return err
+			}
+			if len(left.keys) > tree.minEntries() {
+				if left, err = tree.mutable(&parent.children[index-1]); err != nil {
+					return err
+				}
+				last := len(left.keys) - 1
+				node.keys = insertAt(node.keys, 0, parent.keys[index-1])
+				node.values = insertAt(node.values, 0, parent.values[index-1])
+				parent.keys[index-1], parent.values[index-1] = left.keys[last], left.values[last]
+				left.keys, left.values = deleteAt(left.keys, last), deleteAt(left.values, last)
+				if !left.isLeaf() {
+					node.children = insertAt(node.children, 0, left.children[last+1])
+					left.children = deleteAt(left.children, last+1)
+				}
+				return nil
+			}
+		}
+
+		// try to borrow from right sibling
+		if index < len(parent.children)-1 {
+			right, err := tree.node(parent.children[index+1])
+			if err != nil {
+				return err
+			}
+			if len(right.keys) > tree.minEntries() {
+				if right, err = tree.mutable(&parent.children[index+1]); err != nil {
+					return err
+				}
+				node.keys = append(node.keys, parent.keys[index])
+				node.values = append(node.values, parent.values[index])
+				parent.keys[index], parent.values[index] = right.keys[0], right.values[0]
+				right.keys, right.values = deleteAt(right.keys, 0), deleteAt(right.values, 0)
+				if !right.isLeaf() {
+					node.children = append(node.children, right.children[0])
+					right.children = deleteAt(right.children, 0)
+				}
+				return nil
+			}
+		}
+
+		// merge with siblings
+		separator := index
+		left, right := node, node
+		var err error
+		if index > 0 {
+			separator = index - 1
+			left, err = tree.mutable(&parent.children[index-1])
+		} else {
+			right, err = tree.node(parent.children[index+1])
+			if err == nil && parent.children[index+1].node == nil {
+				tree.freed = append(tree.freed, parent.children[index+1].page)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		left.keys = append(append(left.keys, parent.keys[separator]), right.keys...)
+		left.values = append(append(left.values, parent.values[separator]), right.values...)
+		left.children = append(left.children, right.children...)
+		parent.keys = deleteAt(parent.keys, separator)
+		parent.values = deleteAt(parent.values, separator)
+		parent.children = deleteAt(parent.children, separator+1)
+		node = parent
+	}
+}
+
+// write writes the modified nodes of the subtree into newly allocated pages, children first,
+// and appends the references that got a page to written. The nodes stay in memory until the commit succeeds.
+func (tree *Tree[K, V]) write(r *ref[K, V], written *[]*ref[K, V]) error {
+	if r.node == nil {
+		return nil
+	}
+	for i := range r.node.children {
+		if err := tree.write(&r.node.children[i], written); err != nil {
+			return err
+		}
+	}
+	if err := tree.encodeNode(r.node, tree.page); err != nil {
+		return err
+	}
+	id, err := tree.store.Allocate()
+	if err != nil {
+		return err
+	}
+	r.page = id
+	*written = append(*written, r)
+	return tree.store.WritePage(id, tree.page)
+}
+
+// release schedules all committed pages of the subtree to be freed by the next commit.
+func (tree *Tree[K, V]) release(r ref[K, V]) error {
+	if r.empty() {
+		return nil
+	}
+	node, err := tree.node(r)
+	if err != nil {
+		return err
+	}
+	if r.node == nil {
+		tree.freed = append(tree.freed, r.page)
+	}
+	for _, child := range node.children {
+		if err := tree.release(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initialize writes the meta pages of an empty tree into an empty store.
+func (tree *Tree[K, V]) initialize() error {
+	tree.committed = meta{pageSize: tree.store.PageSize(), order: tree.m}
+	for i := 0; i < metaPages; i++ {
+		id, err := tree.store.Allocate()
+		if err != nil {
+			return err
+		}
+		if id != PageID(i) {
+			return fmt.Errorf("pagedbtree: expected page %d for meta record, store allocated page %d", i, id)
+		}
+		encodeMeta(tree.committed, tree.page)
+		if err := tree.store.WritePage(id, tree.page); err != nil {
+			return err
+		}
+	}
+	return tree.store.Sync()
+}
+
+// load opens the tree at the current meta record and frees all pages not reachable from its root.
+func (tree *Tree[K, V]) load() error {
+	found := false
+	for i := 0; i < metaPages; i++ {
+		if err := tree.store.ReadPage(PageID(i), tree.page); err != nil {
+			return err
+		}
+		if m, ok := decodeMeta(tree.page); ok && (!found || m.txid > tree.committed.txid) {
+			tree.committed, found = m, true
+		}
+	}
+	if !found {
+		return ErrCorruptPage
+	}
+	if tree.committed.pageSize != tree.store.PageSize() || tree.committed.order != tree.m {
+		return fmt.Errorf("pagedbtree: store holds a tree of order %d with page size %d, expected order %d with page size %d",
+			tree.committed.order, tree.committed.pageSize, tree.m, tree.store.PageSize())
+	}
+	tree.Rollback()
+
+	used := make(map[PageID]bool)
+	var visit func(id PageID) error
+	visit = func(id PageID) error {
+		if id >= PageID(tree.store.PageCount()) || used[id] {
+			return ErrCorruptPage
+		}
+		used[id] = true
+		node, err := tree.node(ref[K, V]{page: id})
+		if err != nil {
+			return err
+		}
+		for _, child := range node.children {
+			if err := visit(child.page); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if tree.root.page != 0 {
+		if err := visit(tree.root.page); err != nil {
+			return err
+		}
+	}
+	for id := PageID(metaPages); id < PageID(tree.store.PageCount()); id++ {
+		if !used[id] {
+			if err := tree.store.Free(id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func insertAt[T any](slice []T, index int, value T) []T {
+	var zero T
+	slice = append(slice, zero)
+	copy(slice[index+1:], slice[index:])
+	slice[index] = value
+	return slice
+}
+
+func deleteAt[T any](slice []T, index int) []T {
+	var zero T
+	copy(slice[index:], slice[index+1:])
+	slice[len(slice)-1] = zero
+	return slice[:len(slice)-1]
+}
@@ -0,0 +1,578 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pagedbtree_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/trees/pagedbtree"
+	"github.com/monitor1379/yagods/utils"
+)
+
+func open(t testing.TB, store pagedbtree.PageStore, order int) *pagedbtree.Tree[int, string] {
+	t.Helper()
+	tree, err := pagedbtree.Open[int, string](store, order, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string]())
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	return tree
+}
+
+func keys(t testing.TB, tree *pagedbtree.Tree[int, string]) string {
+	t.Helper()
+	keys, err := tree.Keys()
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	return fmt.Sprint(keys)
+}
+
+func TestPagedBTreePutGetRemove(t *testing.T) {
+	tree := open(t, pagedbtree.NewMemoryStore(256), 3)
+	for _, key := range []int{5, 1, 7, 3, 6, 2, 4} {
+		if err := tree.Put(key, fmt.Sprint(key)); err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+	tree.Put(1, "x")
+	if actualValue, expectedValue := tree.Size(), 7; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := keys(t, tree), "[1 2 3 4 5 6 7]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if values, _ := tree.Values(); fmt.Sprint(values) != "[x 2 3 4 5 6 7]" {
+		t.Errorf("Got %v expected %v", values, "[x 2 3 4 5 6 7]")
+	}
+	if height, _ := tree.Height(); height != 2 {
+		t.Errorf("Got %v expected %v", height, 2)
+	}
+
+	tests := [][]interface{}{
+		{0, "", false},
+		{1, "x", true},
+		{4, "4", true},
+		{7, "7", true},
+		{8, "", false},
+	}
+	for _, test := range tests {
+		if value, found, err := tree.Get(test[0].(int)); value != test[1] || found != test[2] || err != nil {
+			t.Errorf("Got %v,%v,%v expected %v,%v,%v", value, found, err, test[1], test[2], nil)
+		}
+	}
+
+	for _, key := range []int{4, 8, 1, 7} {
+		if err := tree.Remove(key); err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+	if actualValue, expectedValue := keys(t, tree), "[2 3 5 6]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := tree.Clear(); err != nil || !tree.Empty() {
+		t.Errorf("Got %v,%v expected %v,%v", err, tree.Empty(), nil, true)
+	}
+	if height, _ := tree.Height(); height != 0 {
+		t.Errorf("Got %v expected %v", height, 0)
+	}
+}
+
+func TestPagedBTreeRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for order := 3; order <= 7; order++ {
+		store := pagedbtree.NewMemoryStore(512)
+		tree := open(t, store, order)
+		expected, committed := map[int]string{}, map[int]string{}
+		for i := 0; i < 3000; i++ {
+			key := r.Intn(500)
+			switch r.Intn(20) {
+			case 0:
+				if err := tree.Commit(); err != nil {
+					t.Fatalf("Got error %v", err)
+				}
+				committed = copyMap(expected)
+			case 1:
+				tree.Rollback()
+				expected = copyMap(committed)
+			case 2, 3, 4, 5, 6, 7:
+				if err := tree.Remove(key); err != nil {
+					t.Fatalf("Got error %v", err)
+				}
+				delete(expected, key)
+			default:
+				if err := tree.Put(key, fmt.Sprint(i)); err != nil {
+					t.Fatalf("Got error %v", err)
+				}
+				expected[key] = fmt.Sprint(i)
+			}
+		}
+		assertEqual(t, tree, expected)
+
+		// pages are reused after commits and the committed state survives reopening the store
+		if err := tree.Commit(); err != nil {
+			t.Fatalf("Got error %v", err)
+		}
+		if actualValue, limit := store.PageCount(), uint64(3000); actualValue > limit {
+			t.Errorf("Got %v pages, expected at most %v", actualValue, limit)
+		}
+		assertEqual(t, open(t, store, order), expected)
+	}
+}
+
+func copyMap(m map[int]string) map[int]string {
+	c := make(map[int]string, len(m))
+	for key, value := range m {
+		c[key] = value
+	}
+	return c
+}
+
+func assertEqual(t *testing.T, tree *pagedbtree.Tree[int, string], expected map[int]string) {
+	t.Helper()
+	if actualValue, expectedValue := tree.Size(), len(expected); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	expectedKeys := make([]int, 0, len(expected))
+	for key := range expected {
+		expectedKeys = append(expectedKeys, key)
+	}
+	sort.Ints(expectedKeys)
+	if actualValue, expectedValue := keys(t, tree), fmt.Sprint(expectedKeys); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for key, value := range expected {
+		if actualValue, found, err := tree.Get(key); actualValue != value || !found || err != nil {
+			t.Errorf("Got %v,%v,%v expected %v,%v,%v", actualValue, found, err, value, true, nil)
+		}
+	}
+}
+
+func TestPagedBTreeFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+	store, err := pagedbtree.OpenFileStore(path, 4096)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	tree := open(t, pagedbtree.NewBufferPool(store, 16), 32)
+	for i := 0; i < 1000; i++ {
+		tree.Put(i, strings.Repeat("v", i%50))
+	}
+	if err := tree.Commit(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	tree.Put(1000, "uncommitted")
+	tree.Remove(0)
+	if err := tree.Close(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if store, err = pagedbtree.OpenFileStore(path, 4096); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	defer store.Close()
+	tree = open(t, store, 32)
+	if actualValue, expectedValue := tree.Size(), 1000; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found, _ := tree.Get(999); !found || value != strings.Repeat("v", 49) {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, strings.Repeat("v", 49), true)
+	}
+	if _, found, _ := tree.Get(1000); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+
+	if _, err := pagedbtree.Open[int, string](store, 16, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string]()); err == nil {
+		t.Errorf("Got %v expected error for order mismatch", err)
+	}
+}
+
+func TestPagedBTreeTornMetaPage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+	store, _ := pagedbtree.OpenFileStore(path, 256)
+	tree := open(t, store, 4)
+	tree.Put(1, "a")
+	tree.Commit() // meta page 1
+	tree.Put(2, "b")
+	tree.Commit() // meta page 0
+	store.Close()
+
+	// tear the meta record of the last commit
+	file, _ := os.OpenFile(path, os.O_RDWR, 0)
+	file.WriteAt([]byte{0xff, 0xff}, 20)
+	file.Close()
+
+	store, _ = pagedbtree.OpenFileStore(path, 256)
+	defer store.Close()
+	tree = open(t, store, 4)
+	if actualValue, expectedValue := keys(t, tree), "[1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestPagedBTreeCorruptNodePage(t *testing.T) {
+	store := pagedbtree.NewMemoryStore(256)
+	tree := open(t, store, 4)
+	tree.Put(1, "a")
+	tree.Commit()
+
+	page := make([]byte, store.PageSize())
+	store.ReadPage(2, page)
+	page[10] ^= 0xff
+	store.WritePage(2, page)
+
+	if _, err := pagedbtree.Open[int, string](store, 4, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string]()); !errors.Is(err, pagedbtree.ErrCorruptPage) {
+		t.Errorf("Got %v expected %v", err, pagedbtree.ErrCorruptPage)
+	}
+	if _, _, err := tree.Get(1); !errors.Is(err, pagedbtree.ErrCorruptPage) {
+		t.Errorf("Got %v expected %v", err, pagedbtree.ErrCorruptPage)
+	}
+}
+
+func TestPagedBTreeEntryTooLarge(t *testing.T) {
+	tree := open(t, pagedbtree.NewMemoryStore(256), 4)
+	value := strings.Repeat("v", tree.MaxEntrySize())
+	if err := tree.Put(1, value); !errors.Is(err, pagedbtree.ErrEntryTooLarge) {
+		t.Errorf("Got %v expected %v", err, pagedbtree.ErrEntryTooLarge)
+	}
+	if actualValue, expectedValue := tree.Size(), 0; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := tree.Put(1, value[:tree.MaxEntrySize()-10]); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if _, err := pagedbtree.Open[int, string](pagedbtree.NewMemoryStore(64), 16, utils.NumberComparator[int], containers.CodecFor[int](), containers.CodecFor[string]()); err == nil {
+		t.Errorf("Got %v expected error for page size too small", err)
+	}
+}
+
+func TestPagedBTreeIterator(t *testing.T) {
+	tree := open(t, pagedbtree.NewMemoryStore(256), 3)
+	for i := 10; i <= 100; i += 10 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+	tree.Commit()
+	tree.Put(55, "55") // mix modified and committed nodes
+
+	it := tree.Iterator()
+	var forward, backward []int
+	for it.Next() {
+		forward = append(forward, it.Key())
+	}
+	for it.Prev() {
+		backward = append(backward, it.Key())
+	}
+	if actualValue, expectedValue := fmt.Sprint(forward), "[10 20 30 40 50 55 60 70 80 90 100]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(backward), "[100 90 80 70 60 55 50 40 30 20 10]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	tests := [][]interface{}{
+		{5, 10, true, 0, false},
+		{10, 10, true, 10, true},
+		{51, 55, true, 50, true},
+		{56, 60, true, 55, true},
+		{95, 100, true, 90, true},
+		{105, 0, false, 100, true},
+	}
+	for _, test := range tests {
+		key := test[0].(int)
+		if found := it.Seek(key); found != test[2] || (found && it.Key() != test[1]) {
+			t.Errorf("Seek(%v) got %v expected %v,%v", key, found, test[1], test[2])
+		}
+		if found := it.SeekPrev(key); found != test[4] || (found && it.Key() != test[3]) {
+			t.Errorf("SeekPrev(%v) got %v expected %v,%v", key, found, test[3], test[4])
+		}
+	}
+
+	if it = tree.IteratorAt(35); it.Value() != "40" {
+		t.Errorf("Got %v expected %v", it.Value(), "40")
+	}
+	if !it.First() || it.Key() != 10 || !it.Last() || it.Key() != 100 {
+		t.Errorf("Got wrong first or last element")
+	}
+}
+
+func TestPagedBTreeIteratorRemove(t *testing.T) {
+	tree := open(t, pagedbtree.NewMemoryStore(256), 3)
+	for i := 1; i <= 20; i++ {
+		tree.Put(i, fmt.Sprint(i))
+	}
+	tree.Commit()
+	it := tree.Iterator()
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := keys(t, tree), "[1 3 5 7 9 11 13 15 17 19]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestPagedBTreeIteratorConcurrentModification(t *testing.T) {
+	tree := open(t, pagedbtree.NewMemoryStore(256), 3)
+	tree.Put(1, "a")
+	tree.Put(2, "b")
+	it := tree.Iterator()
+	it.Next()
+	tree.Put(3, "c")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; !errors.Is(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Next()
+}
+
+func TestPagedBTreeConformance(t *testing.T) {
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		tree := open(t, pagedbtree.NewMemoryStore(256), 3)
+		for _, key := range keys {
+			tree.Put(key, strconv.Itoa(key))
+		}
+		it := tree.Iterator()
+		return &it
+	})
+}
+
+// FuzzPagedBTree runs random changes, commits, rollbacks and crashes against a built-in map, three bytes per operation.
+// A crash reopens the store without closing the tree, which must then hold the committed entries.
+func FuzzPagedBTree(f *testing.F) {
+	f.Add([]byte{0, 1, 1, 0, 2, 2, 0, 3, 3, 4, 0, 0, 3, 2, 0, 0, 4, 4, 5, 0, 0, 6, 0, 0, 1, 5, 5, 4, 0, 0, 2, 1, 0, 7, 0, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		store := pagedbtree.NewMemoryStore(256)
+		tree := open(t, store, 3)
+		expected, committed := map[int]string{}, map[int]string{}
+		for i := 0; i+2 < len(data) && i < 3*128; i += 3 {
+			key, value := int(data[i+1]%16), fmt.Sprint(data[i+2])
+			var err error
+			switch data[i] % 8 {
+			case 0, 1:
+				err = tree.Put(key, value)
+				expected[key] = value
+			case 2, 3:
+				err = tree.Remove(key)
+				delete(expected, key)
+			case 4:
+				err = tree.Commit()
+				committed = copyMap(expected)
+			case 5:
+				tree.Rollback()
+				expected = copyMap(committed)
+			case 6:
+				err = tree.Clear()
+				expected = map[int]string{}
+			case 7:
+				tree = open(t, store, 3)
+				expected = copyMap(committed)
+			}
+			if err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			assertEqual(t, tree, expected)
+		}
+	})
+}
+
+func TestPagedBTreeBinarySerialization(t *testing.T) {
+	tree := open(t, pagedbtree.NewMemoryStore(256), 3)
+	for i := 1; i <= 20; i++ {
+		tree.Put(i, strconv.Itoa(i))
+	}
+	tree.Commit()
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+
+	restored := open(t, pagedbtree.NewMemoryStore(256), 4)
+	restored.Put(100, "100")
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := keys(t, restored), keys(t, tree); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if value, found, _ := restored.Get(20); value != "20" || !found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "20", true)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	var zero pagedbtree.Tree[int, string]
+	if err := zero.UnmarshalBinary(data); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+var errFailingStore = errors.New("failing store")
+
+// failingStore fails writing node pages, writing meta pages or syncing, as selected by failing
+type failingStore struct {
+	pagedbtree.PageStore
+	failing string
+}
+
+func (s *failingStore) WritePage(id pagedbtree.PageID, page []byte) error {
+	if (s.failing == "write" && id >= 2) || (s.failing == "meta" && id < 2) {
+		return errFailingStore
+	}
+	return s.PageStore.WritePage(id, page)
+}
+
+func (s *failingStore) Sync() error {
+	if s.failing == "sync" {
+		return errFailingStore
+	}
+	return s.PageStore.Sync()
+}
+
+func TestPagedBTreeCommitFailure(t *testing.T) {
+	for _, failing := range []string{"write", "sync", "meta"} {
+		referenceStore := pagedbtree.NewMemoryStore(256)
+		reference := open(t, referenceStore, 3)
+		store := &failingStore{PageStore: pagedbtree.NewMemoryStore(256)}
+		tree := open(t, store, 3)
+		for i := 0; i < 30; i++ {
+			reference.Put(i, fmt.Sprint(i))
+			tree.Put(i, fmt.Sprint(i))
+			if i == 19 {
+				reference.Commit()
+				tree.Commit()
+			}
+		}
+		store.failing = failing
+		if err := tree.Commit(); !errors.Is(err, errFailingStore) {
+			t.Errorf("Got %v expected %v", err, errFailingStore)
+		}
+		store.failing = ""
+		if err := tree.Commit(); err != nil {
+			t.Errorf("Got error %v", err)
+		}
+		reference.Commit()
+		// the pages written by the failed commit are reused instead of leaking
+		if actualValue, expectedValue := store.PageCount(), referenceStore.PageCount(); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v for %v", actualValue, expectedValue, failing)
+		}
+		tree = open(t, store, 3)
+		if actualValue, expectedValue := keys(t, tree), keys(t, reference); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v for %v", actualValue, expectedValue, failing)
+		}
+		if actualValue, expectedValue := tree.Size(), 30; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v for %v", actualValue, expectedValue, failing)
+		}
+	}
+}
+
+func TestPageStoreDoubleFree(t *testing.T) {
+	fileStore, err := pagedbtree.OpenFileStore(filepath.Join(t.TempDir(), "pages"), 8)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	defer fileStore.Close()
+	for _, store := range []pagedbtree.PageStore{pagedbtree.NewMemoryStore(8), fileStore} {
+		for i := 0; i < 2; i++ {
+			store.Allocate()
+		}
+		// a page freed twice is handed out once
+		store.Free(0)
+		store.Free(0)
+		store.Free(1)
+		store.Free(0)
+		var ids []pagedbtree.PageID
+		for i := 0; i < 3; i++ {
+			id, err := store.Allocate()
+			if err != nil {
+				t.Errorf("Got error %v", err)
+			}
+			ids = append(ids, id)
+		}
+		if actualValue, expectedValue := fmt.Sprint(ids), "[1 0 2]"; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if err := store.Free(3); !errors.Is(err, pagedbtree.ErrPageOutOfRange) {
+			t.Errorf("Got %v expected %v", err, pagedbtree.ErrPageOutOfRange)
+		}
+	}
+}
+
+func TestBufferPool(t *testing.T) {
+	pool := pagedbtree.NewBufferPool(pagedbtree.NewMemoryStore(8), 2)
+	page := make([]byte, 8)
+	for i := 0; i < 3; i++ {
+		id, _ := pool.Allocate()
+		page[0] = byte(i)
+		pool.WritePage(id, page)
+	}
+	// page 0 was evicted by page 2
+	for _, id := range []pagedbtree.PageID{2, 1, 0, 0} {
+		pool.ReadPage(id, page)
+		if actualValue, expectedValue := page[0], byte(id); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+	}
+	if hits, misses := pool.Stats(); hits != 3 || misses != 1 {
+		t.Errorf("Got %v,%v expected %v,%v", hits, misses, 3, 1)
+	}
+	if err := pool.ReadPage(3, page); !errors.Is(err, pagedbtree.ErrPageOutOfRange) {
+		t.Errorf("Got %v expected %v", err, pagedbtree.ErrPageOutOfRange)
+	}
+}
+
+func benchmarkGet(b *testing.B, tree *pagedbtree.Tree[int, string], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			tree.Get(n)
+		}
+	}
+}
+
+func benchmarkPut(b *testing.B, tree *pagedbtree.Tree[int, string], size int) {
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			tree.Put(n, "")
+		}
+		tree.Commit()
+	}
+}
+
+func BenchmarkPagedBTreeGet10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	tree := open(b, pagedbtree.NewBufferPool(pagedbtree.NewMemoryStore(4096), 1024), 128)
+	for n := 0; n < size; n++ {
+		tree.Put(n, "")
+	}
+	tree.Commit()
+	b.StartTimer()
+	benchmarkGet(b, tree, size)
+}
+
+func BenchmarkPagedBTreePut10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	tree := open(b, pagedbtree.NewMemoryStore(4096), 128)
+	b.StartTimer()
+	benchmarkPut(b, tree, size)
+}
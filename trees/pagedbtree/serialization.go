@@ -0,0 +1,69 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pagedbtree
+
+import (
+	"encoding"
+	"encoding/gob"
+	"errors"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+var _ encoding.BinaryMarshaler = (*Tree[int, string])(nil)
+var _ encoding.BinaryUnmarshaler = (*Tree[int, string])(nil)
+var _ gob.GobEncoder = (*Tree[int, string])(nil)
+var _ gob.GobDecoder = (*Tree[int, string])(nil)
+
+// errNotOpened is returned when unmarshaling into a tree that has no store.
+var errNotOpened = errors.New("pagedbtree: tree must be opened before unmarshaling")
+
+// MarshalBinary outputs the binary representation of the tree in key order, reading all of its pages.
+// Keys and values are encoded by the codecs of containers.CodecFor, not by the codecs of the tree,
+// so that the output can be read by any other container.
+func (tree *Tree[K, V]) MarshalBinary() ([]byte, error) {
+	keys, values := make([]K, 0, tree.size), make([]V, 0, tree.size)
+	it := tree.Iterator()
+	for it.Next() {
+		keys, values = append(keys, it.Key()), append(values, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return containers.EncodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), keys, values)
+}
+
+// UnmarshalBinary replaces the entries of the tree by the entries of the input binary representation.
+// Like any other change, the new entries are not durable until committed.
+// As the tree needs a store, its zero value cannot be unmarshaled; open the tree first.
+// On error, the tree may hold some of the new entries, which Rollback discards along with all other uncommitted changes.
+func (tree *Tree[K, V]) UnmarshalBinary(data []byte) error {
+	if tree.store == nil {
+		return errNotOpened
+	}
+	keys, values, err := containers.DecodeEntries(containers.CodecFor[K](), containers.CodecFor[V](), data)
+	if err != nil {
+		return err
+	}
+	if err := tree.Clear(); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if err := tree.Put(key, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GobEncode outputs the binary representation of the tree for encoding/gob, see MarshalBinary.
+func (tree *Tree[K, V]) GobEncode() ([]byte, error) {
+	return tree.MarshalBinary()
+}
+
+// GobDecode populates the tree from the binary representation for encoding/gob, see UnmarshalBinary.
+func (tree *Tree[K, V]) GobDecode(data []byte) error {
+	return tree.UnmarshalBinary(data)
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pagedbtree
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// PageID identifies a page within a PageStore. Pages are numbered from zero.
+type PageID uint64
+
+// PageStore stores fixed-size pages.
+//
+// Allocate hands out freed pages before growing the store. The set of free pages does not have to be
+// persisted, as the tree reports its unreachable pages through Free when it is opened.
+type PageStore interface {
+	// PageSize returns the size of every page in bytes.
+	PageSize() int
+	// PageCount returns the number of pages, i.e. one more than the highest page id in use.
+	PageCount() uint64
+	// Allocate returns the id of an unused page.
+	Allocate() (PageID, error)
+	// Free marks a page as unused, so that a later Allocate can return it.
+	// Freeing a page that is already unused does nothing, as reopening a tree frees its unreachable pages again.
+	Free(id PageID) error
+	// ReadPage reads the page into the buffer of PageSize() bytes.
+	ReadPage(id PageID, page []byte) error
+	// WritePage writes the buffer of PageSize() bytes into the page.
+	WritePage(id PageID, page []byte) error
+	// Sync makes all written pages durable.
+	Sync() error
+	// Close releases the resources of the store.
+	Close() error
+}
+
+// ErrPageOutOfRange is returned when reading or writing a page that was never allocated.
+var ErrPageOutOfRange = errors.New("pagedbtree: page out of range")
+
+var _ PageStore = (*MemoryStore)(nil)
+var _ PageStore = (*FileStore)(nil)
+
+// MemoryStore is a PageStore keeping all pages in memory, mostly useful for testing.
+type MemoryStore struct {
+	pageSize int
+	pages    [][]byte
+	free     freeList
+}
+
+// NewMemoryStore instantiates an empty in-memory store of pages of the given size.
+func NewMemoryStore(pageSize int) *MemoryStore {
+	return &MemoryStore{pageSize: pageSize}
+}
+
+// PageSize returns the size of every page in bytes.
+func (store *MemoryStore) PageSize() int {
+	return store.pageSize
+}
+
+// PageCount returns the number of pages.
+func (store *MemoryStore) PageCount() uint64 {
+	return uint64(len(store.pages))
+}
+
+// Allocate returns the id of an unused page.
+func (store *MemoryStore) Allocate() (PageID, error) {
+	if id, ok := store.free.pop(); ok {
+		return id, nil
+	}
+	store.pages = append(store.pages, make([]byte, store.pageSize))
+	return PageID(len(store.pages) - 1), nil
+}
+
+// Free marks a page as unused.
+func (store *MemoryStore) Free(id PageID) error {
+	if uint64(id) >= uint64(len(store.pages)) {
+		return ErrPageOutOfRange
+	}
+	store.free.push(id)
+	return nil
+}
+
+// ReadPage copies the page into the buffer.
+func (store *MemoryStore) ReadPage(id PageID, page []byte) error {
+	if uint64(id) >= uint64(len(store.pages)) {
+		return ErrPageOutOfRange
+	}
+	copy(page, store.pages[id])
+	return nil
+}
+
+// WritePage copies the buffer into the page.
+func (store *MemoryStore) WritePage(id PageID, page []byte) error {
+	if uint64(id) >= uint64(len(store.pages)) {
+		return ErrPageOutOfRange
+	}
+	copy(store.pages[id], page)
+	return nil
+}
+
+// Sync does nothing, as memory is not durable.
+func (store *MemoryStore) Sync() error {
+	return nil
+}
+
+// Close does nothing, the pages stay available until the store is garbage collected.
+func (store *MemoryStore) Close() error {
+	return nil
+}
+
+// FileStore is a PageStore keeping pages in a file, page i starting at offset i*PageSize().
+type FileStore struct {
+	file     *os.File
+	pageSize int
+	count    uint64
+	free     freeList
+}
+
+// OpenFileStore opens or creates the file at path as a store of pages of the given size.
+// The size of an existing file has to be a multiple of the page size.
+func OpenFileStore(path string, pageSize int) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size()%int64(pageSize) != 0 {
+		file.Close()
+		return nil, fmt.Errorf("pagedbtree: size %d of %s is not a multiple of page size %d", info.Size(), path, pageSize)
+	}
+	return &FileStore{file: file, pageSize: pageSize, count: uint64(info.Size() / int64(pageSize))}, nil
+}
+
+// PageSize returns the size of every page in bytes.
+func (store *FileStore) PageSize() int {
+	return store.pageSize
+}
+
+// PageCount returns the number of pages in the file.
+func (store *FileStore) PageCount() uint64 {
+	return store.count
+}
+
+// Allocate returns the id of a freed page or grows the file by one page.
+func (store *FileStore) Allocate() (PageID, error) {
+	if id, ok := store.free.pop(); ok {
+		return id, nil
+	}
+	if err := store.file.Truncate(int64(store.count+1) * int64(store.pageSize)); err != nil {
+		return 0, err
+	}
+	store.count++
+	return PageID(store.count - 1), nil
+}
+
+// Free marks a page as unused. The file never shrinks, freed pages are reused by Allocate.
+func (store *FileStore) Free(id PageID) error {
+	if uint64(id) >= store.count {
+		return ErrPageOutOfRange
+	}
+	store.free.push(id)
+	return nil
+}
+
+// ReadPage reads the page from the file into the buffer.
+func (store *FileStore) ReadPage(id PageID, page []byte) error {
+	if uint64(id) >= store.count {
+		return ErrPageOutOfRange
+	}
+	_, err := store.file.ReadAt(page[:store.pageSize], int64(id)*int64(store.pageSize))
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// WritePage writes the buffer into the page of the file.
+func (store *FileStore) WritePage(id PageID, page []byte) error {
+	if uint64(id) >= store.count {
+		return ErrPageOutOfRange
+	}
+	_, err := store.file.WriteAt(page[:store.pageSize], int64(id)*int64(store.pageSize))
+	return err
+}
+
+// Sync commits the file to stable storage.
+func (store *FileStore) Sync() error {
+	return store.file.Sync()
+}
+
+// Close closes the file.
+func (store *FileStore) Close() error {
+	return store.file.Close()
+}
+
+// freeList holds the free pages of a store, each page once however often it is freed.
+type freeList struct {
+	ids   []PageID
+	freed map[PageID]bool
+}
+
+// push adds the page unless it is free already.
+func (list *freeList) push(id PageID) {
+	if list.freed[id] {
+		return
+	}
+	if list.freed == nil {
+		list.freed = make(map[PageID]bool)
+	}
+	list.freed[id] = true
+	list.ids = append(list.ids, id)
+}
+
+// pop removes and returns the most recently freed page, if any.
+func (list *freeList) pop() (PageID, bool) {
+	n := len(list.ids)
+	if n == 0 {
+		return 0, false
+	}
+	id := list.ids[n-1]
+	list.ids = list.ids[:n-1]
+	delete(list.freed, id)
+	return id, true
+}
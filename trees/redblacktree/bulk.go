@@ -0,0 +1,60 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package redblacktree
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/utils"
+)
+
+// FromSorted instantiates a perfectly balanced red-black tree with the custom comparator
+// holding the keys and their values in O(n) time.
+// Returns an error wrapping containers.ErrNotSorted if keys are not in strictly ascending order,
+// or an error if there are not as many values as keys.
+func FromSorted[K comparable, V any](comparator utils.Comparator[K], keys []K, values []V) (*Tree[K, V], error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("redblacktree: got %d keys but %d values", len(keys), len(values))
+	}
+	for i := 1; i < len(keys); i++ {
+		if comparator(keys[i-1], keys[i]) >= 0 {
+			return nil, fmt.Errorf("redblacktree: key at index %d: %w", i, containers.ErrNotSorted)
+		}
+	}
+	tree := NewWith[K, V](comparator)
+	tree.Root = build(keys, values, nil, 0, bits.Len(uint(len(keys)))-1)
+	tree.size = len(keys)
+	return tree, nil
+}
+
+// BulkLoad instantiates a perfectly balanced red-black tree with the custom comparator holding
+// the remaining elements of the iterator in O(n) time. See FromSorted.
+func BulkLoad[K comparable, V any](comparator utils.Comparator[K], iterator containers.IteratorWithKey[K, V]) (*Tree[K, V], error) {
+	var keys []K
+	var values []V
+	for iterator.Next() {
+		keys = append(keys, iterator.Key())
+		values = append(values, iterator.Value())
+	}
+	return FromSorted(comparator, keys, values)
+}
+
+// build links the keys into a balanced subtree below the parent, coloring only the nodes at the
+// lowest level red, so that every path has the same number of black nodes.
+func build[K comparable, V any](keys []K, values []V, parent *Node[K, V], depth int, lowest int) *Node[K, V] {
+	if len(keys) == 0 {
+		return nil
+	}
+	middle := len(keys) / 2
+	node := &Node[K, V]{Key: keys[middle], Value: values[middle], color: black, Parent: parent}
+	if depth == lowest && depth > 0 {
+		node.color = red
+	}
+	node.Left = build(keys[:middle], values[:middle], node, depth+1, lowest)
+	node.Right = build(keys[middle+1:], values[middle+1:], node, depth+1, lowest)
+	return node
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package redblacktree
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Assert Graph implementation
+var _ containers.DOTWriter = (*Tree[string, int])(nil)
+var _ containers.MermaidWriter = (*Tree[string, int])(nil)
+
+// ToGraph returns the structure of the tree as a graph of its nodes, filled in their red or black color.
+// The label hook formats the label of a node from its entry, nil labels a node with its key.
+// A missing child of a node with one child is an invisible node, so that the other child stays on its side.
+func (tree *Tree[K, V]) ToGraph(label func(key K, value V) string) *containers.Graph {
+	if label == nil {
+		label = func(key K, value V) string { return fmt.Sprint(key) }
+	}
+	graph := &containers.Graph{Name: "redblacktree"}
+	var add func(node *Node[K, V]) int
+	add = func(node *Node[K, V]) int {
+		if node == nil {
+			return graph.AddNode(containers.GraphNode{Invisible: true})
+		}
+		color := "red"
+		if node.color == black {
+			color = "black"
+		}
+		index := graph.AddNode(containers.GraphNode{Label: label(node.Key, node.Value), Color: color, FontColor: "white"})
+		if node.Left != nil || node.Right != nil {
+			graph.AddEdge(index, add(node.Left))
+			graph.AddEdge(index, add(node.Right))
+		}
+		return index
+	}
+	if tree.Root != nil {
+		add(tree.Root)
+	}
+	return graph
+}
+
+// ToDOT writes the structure of the tree to the writer in the DOT language of Graphviz, see ToGraph.
+func (tree *Tree[K, V]) ToDOT(w io.Writer) error {
+	return tree.ToGraph(nil).WriteDOT(w)
+}
+
+// ToMermaid writes the structure of the tree to the writer as a Mermaid flowchart, see ToGraph.
+func (tree *Tree[K, V]) ToMermaid(w io.Writer) error {
+	return tree.ToGraph(nil).WriteMermaid(w)
+}
@@ -8,12 +8,15 @@ import "github.com/monitor1379/yagods/containers"
 
 var _ containers.IteratorWithKey[int, string] = (*Iterator[int, string])(nil)
 var _ containers.ReverseIteratorWithKey[int, string] = (*Iterator[int, string])(nil)
+var _ containers.FailFastIterator = (*Iterator[int, string])(nil)
 
 // Iterator holding the iterator's state
 type Iterator[K comparable, V any] struct {
 	tree     *Tree[K, V]
 	node     *Node[K, V]
 	position position
+	modCount int  // tree's modification count the iterator is synchronized with
+	removed  bool // current node was removed, node points to its predecessor
 }
 
 type position byte
@@ -24,12 +27,13 @@ const (
 
 // Iterator returns a stateful iterator whose elements are key/value pairs.
 func (tree *Tree[K, V]) Iterator() Iterator[K, V] {
-	return Iterator[K, V]{tree: tree, node: nil, position: begin}
+	return Iterator[K, V]{tree: tree, node: nil, position: begin, modCount: tree.modCount}
 }
 
 // IteratorAt returns a stateful iterator whose elements are key/value pairs that is initialised at a particular node.
+// To start at a key rather than a node use Iterator() followed by Seek() or SeekPrev().
 func (tree *Tree[K, V]) IteratorAt(node *Node[K, V]) Iterator[K, V] {
-	return Iterator[K, V]{tree: tree, node: node, position: between}
+	return Iterator[K, V]{tree: tree, node: node, position: between, modCount: tree.modCount}
 }
 
 // Next moves the iterator to the next element and returns true if there was a next element in the container.
@@ -37,6 +41,8 @@ func (tree *Tree[K, V]) IteratorAt(node *Node[K, V]) Iterator[K, V] {
 // If Next() was called for the first time, then it will point the iterator to the first element if it exists.
 // Modifies the state of the iterator.
 func (iterator *Iterator[K, V]) Next() bool {
+	iterator.checkModification()
+	iterator.removed = false
 	if iterator.position == end {
 		goto end
 	}
@@ -79,6 +85,11 @@ between:
 // If Prev() returns true, then previous element's key and value can be retrieved by Key() and Value().
 // Modifies the state of the iterator.
 func (iterator *Iterator[K, V]) Prev() bool {
+	iterator.checkModification()
+	if iterator.removed {
+		iterator.removed = false
+		return iterator.position == between
+	}
 	if iterator.position == begin {
 		goto begin
 	}
@@ -134,6 +145,8 @@ func (iterator *Iterator[K, V]) Key() K {
 func (iterator *Iterator[K, V]) Begin() {
 	iterator.node = nil
 	iterator.position = begin
+	iterator.modCount = iterator.tree.modCount
+	iterator.removed = false
 }
 
 // End moves the iterator past the last element (one-past-the-end).
@@ -141,6 +154,8 @@ func (iterator *Iterator[K, V]) Begin() {
 func (iterator *Iterator[K, V]) End() {
 	iterator.node = nil
 	iterator.position = end
+	iterator.modCount = iterator.tree.modCount
+	iterator.removed = false
 }
 
 // First moves the iterator to the first element and returns true if there was a first element in the container.
@@ -158,3 +173,75 @@ func (iterator *Iterator[K, V]) Last() bool {
 	iterator.End()
 	return iterator.Prev()
 }
+
+// Seek moves the iterator to the first element whose key is greater than or equal to the given key
+// and returns true if there was such an element in the container.
+// If Seek() returns true, then element's key and value can be retrieved by Key() and Value().
+// If Seek() returns false, then the iterator is moved past the last element (one-past-the-end).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) Seek(key K) bool {
+	iterator.End()
+	if node, found := iterator.tree.Ceiling(key); found {
+		iterator.node = node
+		iterator.position = between
+		return true
+	}
+	return false
+}
+
+// SeekPrev moves the iterator to the last element whose key is less than or equal to the given key
+// and returns true if there was such an element in the container.
+// If SeekPrev() returns true, then element's key and value can be retrieved by Key() and Value().
+// If SeekPrev() returns false, then the iterator is reset to its initial state (one-before-first).
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+// Modifies the state of the iterator.
+func (iterator *Iterator[K, V]) SeekPrev(key K) bool {
+	iterator.Begin()
+	if node, found := iterator.tree.Floor(key); found {
+		iterator.node = node
+		iterator.position = between
+		return true
+	}
+	return false
+}
+
+// Err returns containers.ErrConcurrentModification if the tree was structurally modified
+// since the iterator last synchronized with it, otherwise nil.
+// Does not modify the state of the iterator.
+func (iterator *Iterator[K, V]) Err() error {
+	if iterator.modCount != iterator.tree.modCount && (iterator.position != begin || iterator.removed) {
+		return containers.ErrConcurrentModification
+	}
+	return nil
+}
+
+// Remove removes the current node from the tree and keeps the iterator valid.
+// Next() moves to the node that followed the removed one and Prev() to the node that preceded it.
+// Does nothing if the iterator is not positioned on a node.
+func (iterator *Iterator[K, V]) Remove() {
+	iterator.checkModification()
+	if iterator.removed || iterator.position != between {
+		return
+	}
+	key := iterator.node.Key
+	iterator.tree.Remove(key)
+	iterator.modCount = iterator.tree.modCount
+	// removal may move keys between nodes, so look the predecessor up again
+	if floor, found := iterator.tree.Floor(key); found {
+		iterator.node = floor
+	} else {
+		iterator.node = nil
+		iterator.position = begin
+	}
+	iterator.removed = true
+}
+
+// checkModification panics if the tree was modified behind the iterator's back,
+// otherwise synchronizes the iterator with the tree.
+func (iterator *Iterator[K, V]) checkModification() {
+	if err := iterator.Err(); err != nil {
+		panic(err)
+	}
+	iterator.modCount = iterator.tree.modCount
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package redblacktree
+
+// RemoveRange removes all nodes whose keys are within [from, to) from the tree.
+// Runs in O(log n + k) time, k being the number of removed nodes.
+// Keys should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) RemoveRange(from K, to K) {
+	if tree.Root == nil || tree.Comparator(from, to) >= 0 {
+		return
+	}
+	left, leftHeight, rest, restHeight := tree.split(tree.Root, blackHeight(tree.Root), from)
+	middle, _, right, rightHeight := tree.split(rest, restHeight, to)
+	tree.Root, _ = tree.join(left, leftHeight, nil, right, rightHeight)
+	blacken(tree.Root)
+	if removed := count(middle); removed > 0 {
+		tree.size -= removed
+		tree.modCount++
+	}
+}
+
+// Split moves all nodes whose keys are less than the given key into the left tree and
+// all other nodes into the right tree. The tree itself is left empty.
+// Runs in O(log n + min(|left|, |right|)) time, as the size of the smaller part has to be counted.
+// Key should adhere to the comparator's type assertion, otherwise method panics.
+func (tree *Tree[K, V]) Split(key K) (left *Tree[K, V], right *Tree[K, V]) {
+	left, right = NewWith[K, V](tree.Comparator), NewWith[K, V](tree.Comparator)
+	left.descend, right.descend = tree.descend, tree.descend
+	if tree.Root != nil {
+		left.Root, _, right.Root, _ = tree.split(tree.Root, blackHeight(tree.Root), key)
+		blacken(detach(left.Root))
+		blacken(detach(right.Root))
+		left.size, right.size = countSmaller(left, right, tree.size)
+	}
+	tree.Clear()
+	return left, right
+}
+
+// Join moves all nodes of the left and the right tree into a new tree using left's comparator
+// and leaves both trees empty. All keys of the left tree should be less than all keys of the right tree,
+// otherwise method panics.
+// Runs in O(log n) time.
+func Join[K comparable, V any](left *Tree[K, V], right *Tree[K, V]) *Tree[K, V] {
+	tree := NewWith[K, V](left.Comparator)
+	tree.descend = left.descend
+	if !left.Empty() && !right.Empty() && tree.Comparator(left.Right().Key, right.Left().Key) >= 0 {
+		panic("Invalid join, all keys of left should be less than all keys of right")
+	}
+	tree.Root, _ = tree.join(left.Root, blackHeight(left.Root), nil, right.Root, blackHeight(right.Root))
+	blacken(tree.Root)
+	tree.size = left.size + right.size
+	left.Clear()
+	right.Clear()
+	return tree
+}
+
+// split splits the subtree rooted at node with the given black height into the subtrees
+// holding keys less than the key and keys greater than or equal to the key, returning their roots and black heights.
+func (tree *Tree[K, V]) split(node *Node[K, V], height int, key K) (*Node[K, V], int, *Node[K, V], int) {
+	if node == nil {
+		return nil, 0, nil, 0
+	}
+	childHeight := height
+	if node.color == black {
+		childHeight--
+	}
+	left, right := node.Left, node.Right
+	compare := tree.Comparator(key, node.Key)
+	switch {
+	case compare == 0:
+		right, rightHeight := tree.join(nil, 0, node, right, childHeight)
+		return left, childHeight, right, rightHeight
+	case compare < 0:
+		leftLeft, leftLeftHeight, leftRight, leftRightHeight := tree.split(left, childHeight, key)
+		right, rightHeight := tree.join(leftRight, leftRightHeight, node, right, childHeight)
+		return leftLeft, leftLeftHeight, right, rightHeight
+	default:
+		rightLeft, rightLeftHeight, rightRight, rightRightHeight := tree.split(right, childHeight, key)
+		left, leftHeight := tree.join(left, childHeight, node, rightLeft, rightLeftHeight)
+		return left, leftHeight, rightRight, rightRightHeight
+	}
+}
+
+// join links the left subtree, the middle node and the right subtree (keys in that order) into one
+// red-black tree and returns its root and black height. The middle node may be nil.
+func (tree *Tree[K, V]) join(left *Node[K, V], leftHeight int, middle *Node[K, V], right *Node[K, V], rightHeight int) (*Node[K, V], int) {
+	if middle == nil {
+		if left == nil {
+			return detach(right), rightHeight
+		}
+		if right == nil {
+			return detach(left), leftHeight
+		}
+		// use the minimum of the right subtree as the middle node
+		rest := &Tree[K, V]{Root: detach(right), Comparator: tree.Comparator}
+		middle = rest.Left()
+		rest.Remove(middle.Key)
+		right, rightHeight = rest.Root, blackHeight(rest.Root)
+	}
+	if left = detach(left); nodeColor(left) == red {
+		left.color = black
+		leftHeight++
+	}
+	if right = detach(right); nodeColor(right) == red {
+		right.color = black
+		rightHeight++
+	}
+	middle.Left, middle.Right, middle.Parent = nil, nil, nil
+	if leftHeight == rightHeight {
+		middle.Left, middle.Right = left, right
+		setParent(left, middle)
+		setParent(right, middle)
+		middle.color = black
+		return middle, leftHeight + 1
+	}
+
+	// descend along the inner spine of the higher subtree to a black node of the lower subtree's black height
+	// and put the red middle node in its place
+	var parent *Node[K, V]
+	sub := &Tree[K, V]{Comparator: tree.Comparator}
+	if leftHeight > rightHeight {
+		sub.Root = left
+		node, height := left, leftHeight
+		for nodeColor(node) == red || height != rightHeight {
+			if nodeColor(node) == black {
+				height--
+			}
+			parent, node = node, node.Right
+		}
+		middle.Left, middle.Right = node, right
+		parent.Right = middle
+	} else {
+		sub.Root = right
+		node, height := right, rightHeight
+		for nodeColor(node) == red || height != leftHeight {
+			if nodeColor(node) == black {
+				height--
+			}
+			parent, node = node, node.Left
+		}
+		middle.Left, middle.Right = left, node
+		parent.Left = middle
+		leftHeight = rightHeight
+	}
+	middle.Parent = parent
+	setParent(middle.Left, middle)
+	setParent(middle.Right, middle)
+	middle.color = red
+	if sub.joinFix(middle) {
+		leftHeight++
+	}
+	return sub.Root, leftHeight
+}
+
+// joinFix restores the red-black properties after join linked in a red node
+// and returns true if the black height of the tree grew.
+func (tree *Tree[K, V]) joinFix(node *Node[K, V]) bool {
+	for {
+		if node.Parent == nil {
+			node.color = black
+			return true
+		}
+		if nodeColor(node.Parent) == black {
+			return false
+		}
+		uncle := node.uncle()
+		if nodeColor(uncle) != red {
+			tree.insertCase4(node)
+			return false
+		}
+		node.Parent.color = black
+		uncle.color = black
+		node = node.grandparent()
+		node.color = red
+	}
+}
+
+// blackHeight returns the number of black nodes on any path from the node down to a leaf.
+func blackHeight[K comparable, V any](node *Node[K, V]) int {
+	height := 0
+	for ; node != nil; node = node.Left {
+		if node.color == black {
+			height++
+		}
+	}
+	return height
+}
+
+func detach[K comparable, V any](node *Node[K, V]) *Node[K, V] {
+	if node != nil {
+		node.Parent = nil
+	}
+	return node
+}
+
+func blacken[K comparable, V any](node *Node[K, V]) {
+	if node != nil {
+		node.color = black
+	}
+}
+
+func setParent[K comparable, V any](node *Node[K, V], parent *Node[K, V]) {
+	if node != nil {
+		node.Parent = parent
+	}
+}
+
+func count[K comparable, V any](node *Node[K, V]) int {
+	if node == nil {
+		return 0
+	}
+	return count(node.Left) + 1 + count(node.Right)
+}
+
+// countSmaller returns the sizes of two trees holding total nodes together,
+// counting only the nodes of the smaller one.
+func countSmaller[K comparable, V any](left *Tree[K, V], right *Tree[K, V], total int) (int, int) {
+	leftIterator, rightIterator := left.Iterator(), right.Iterator()
+	for n := 0; ; n++ {
+		if !leftIterator.Next() {
+			return n, total - n
+		}
+		if !rightIterator.Next() {
+			return total - n, n
+		}
+	}
+}
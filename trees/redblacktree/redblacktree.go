@@ -30,7 +30,9 @@ const (
 type Tree[K comparable, V any] struct {
 	Root       *Node[K, V]
 	size       int
-	Comparator utils.Comparator[K]
+	Comparator utils.Comparator[K]                              // key comparator, changed through SetComparator
+	modCount   int                                              // number of structural modifications, checked by iterators
+	descend    func(node *Node[K, V], key K) (*Node[K, V], int) // search comparing ordered keys directly, set by New and cleared by SetComparator
 }
 
 // Node is a single element within the tree
@@ -43,6 +45,12 @@ type Node[K comparable, V any] struct {
 	Parent *Node[K, V]
 }
 
+// New instantiates a red-black tree ordering the keys by the < and > operators, see utils.OrderedComparator.
+// Searching the tree compares the keys with the operators instead of calling the comparator.
+func New[K utils.Ordered, V any]() *Tree[K, V] {
+	return &Tree[K, V]{Comparator: utils.OrderedComparator[K], descend: descendOrdered[K, V]}
+}
+
 // NewWith instantiates a red-black tree with the custom comparator.
 func NewWith[K comparable, V any](comparator utils.Comparator[K]) *Tree[K, V] {
 	return &Tree[K, V]{Comparator: comparator}
@@ -58,6 +66,13 @@ func NewWithStringComparator[V any]() *Tree[string, V] {
 	return &Tree[string, V]{Comparator: utils.StringComparator}
 }
 
+// SetComparator replaces the comparator of the tree, which then calls it instead of comparing the keys with the operators.
+// The nodes are not reordered, so it should be called on an empty tree.
+func (tree *Tree[K, V]) SetComparator(comparator utils.Comparator[K]) {
+	tree.Comparator = comparator
+	tree.descend = nil
+}
+
 // Put inserts node into the tree.
 // Key should adhere to the comparator's type assertion, otherwise method panics.
 func (tree *Tree[K, V]) Put(key K, value V) {
@@ -68,37 +83,24 @@ func (tree *Tree[K, V]) Put(key K, value V) {
 		tree.Root = &Node[K, V]{Key: key, Value: value, color: red}
 		insertedNode = tree.Root
 	} else {
-		node := tree.Root
-		loop := true
-		for loop {
-			compare := tree.Comparator(key, node.Key)
-			switch {
-			case compare == 0:
-				node.Key = key
-				node.Value = value
-				return
-			case compare < 0:
-				if node.Left == nil {
-					node.Left = &Node[K, V]{Key: key, Value: value, color: red}
-					insertedNode = node.Left
-					loop = false
-				} else {
-					node = node.Left
-				}
-			case compare > 0:
-				if node.Right == nil {
-					node.Right = &Node[K, V]{Key: key, Value: value, color: red}
-					insertedNode = node.Right
-					loop = false
-				} else {
-					node = node.Right
-				}
-			}
+		node, compare := tree.search(key)
+		switch {
+		case compare == 0:
+			node.Key = key
+			node.Value = value
+			return
+		case compare < 0:
+			node.Left = &Node[K, V]{Key: key, Value: value, color: red}
+			insertedNode = node.Left
+		default:
+			node.Right = &Node[K, V]{Key: key, Value: value, color: red}
+			insertedNode = node.Right
 		}
 		insertedNode.Parent = node
 	}
 	tree.insertCase1(insertedNode)
 	tree.size++
+	tree.modCount++
 }
 
 // Get searches the node in the tree by key and returns its value or nil if key is not found in tree.
@@ -143,6 +145,7 @@ func (tree *Tree[K, V]) Remove(key K) {
 		}
 	}
 	tree.size--
+	tree.modCount++
 }
 
 // Empty returns true if tree does not contain any nodes
@@ -269,6 +272,7 @@ func (tree *Tree[K, V]) Ceiling(key K) (ceiling *Node[K, V], found bool) {
 func (tree *Tree[K, V]) Clear() {
 	tree.Root = nil
 	tree.size = 0
+	tree.modCount++
 }
 
 // String returns a string representation of container
@@ -313,19 +317,54 @@ func output[K comparable, V any](node *Node[K, V], prefix string, isTail bool, s
 }
 
 func (tree *Tree[K, V]) lookup(key K) *Node[K, V] {
-	node := tree.Root
-	for node != nil {
-		compare := tree.Comparator(key, node.Key)
+	if node, compare := tree.search(key); compare == 0 {
+		return node
+	}
+	return nil
+}
+
+// search descends from the root towards the key. It returns the node holding the key and 0 if there is one,
+// otherwise the last node visited and the comparison of the key to the key of that node, i.e. the parent
+// of a node with the key. The node is nil if the tree is empty.
+func (tree *Tree[K, V]) search(key K) (*Node[K, V], int) {
+	if tree.descend != nil {
+		return tree.descend(tree.Root, key)
+	}
+	var last *Node[K, V]
+	compare := 0
+	for node := tree.Root; node != nil; {
+		last = node
+		compare = tree.Comparator(key, node.Key)
 		switch {
 		case compare == 0:
-			return node
+			return node, 0
 		case compare < 0:
 			node = node.Left
 		case compare > 0:
 			node = node.Right
 		}
 	}
-	return nil
+	return last, compare
+}
+
+// descendOrdered is the search of trees instantiated by New, comparing the keys as utils.OrderedComparator does.
+func descendOrdered[K utils.Ordered, V any](node *Node[K, V], key K) (*Node[K, V], int) {
+	var last *Node[K, V]
+	compare := 0
+	for node != nil {
+		last = node
+		switch {
+		case key < node.Key:
+			compare = -1
+			node = node.Left
+		case key > node.Key:
+			compare = 1
+			node = node.Right
+		default:
+			return node, 0
+		}
+	}
+	return last, compare
 }
 
 func (node *Node[K, V]) grandparent() *Node[K, V] {
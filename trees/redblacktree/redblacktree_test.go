@@ -5,10 +5,21 @@
 package redblacktree_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/monitor1379/yagods/containers"
+	"github.com/monitor1379/yagods/containers/containertest"
+	"github.com/monitor1379/yagods/maps"
 	"github.com/monitor1379/yagods/trees/redblacktree"
+	"github.com/monitor1379/yagods/utils"
 )
 
 func TestRedBlackTreePut(t *testing.T) {
@@ -559,6 +570,267 @@ func TestRedBlackTreeIteratorLast(t *testing.T) {
 	}
 }
 
+func TestRedBlackTreeIteratorRemove(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	for i := 1; i <= 10; i++ {
+		tree.Put(i, fmt.Sprint(i))
+	}
+	it := tree.Iterator()
+	for it.Next() {
+		if it.Key()%2 == 0 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[1 3 5 7 9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for it.End(); it.Prev(); {
+		if it.Key() > 4 {
+			it.Remove()
+		}
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[1 3]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+}
+
+func TestRedBlackTreeIteratorConcurrentModification(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	tree.Put(1, "a")
+	tree.Put(2, "b")
+	it := tree.Iterator()
+	it.Next()
+	tree.Put(1, "x")
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+	tree.Put(3, "c")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Next()
+	t.Errorf("Shouldn't reach here")
+}
+
+func TestRedBlackTreeIteratorConcurrentModificationAtEnd(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	tree.Put(1, "a")
+	tree.Put(2, "b")
+	it := tree.Iterator()
+	tree.Put(3, "c") // a new iterator starts over from the first element
+	if err := it.Err(); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+	for it.Next() {
+	}
+	tree.Remove(3)
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	// so does an iterator whose first element was removed through it
+	it.First()
+	it.Remove()
+	tree.Put(4, "d")
+	if actualValue, expectedValue := it.Err(), containers.ErrConcurrentModification; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it.End()
+	tree.Remove(4)
+	defer func() {
+		if r := recover(); r != containers.ErrConcurrentModification {
+			t.Errorf("Got %v expected %v", r, containers.ErrConcurrentModification)
+		}
+	}()
+	it.Prev()
+	t.Errorf("Shouldn't reach here")
+}
+
+func TestRedBlackTreeIteratorSeek(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	for i := 1; i <= 9; i += 2 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+	it := tree.Iterator()
+	tests := []struct {
+		key, seek, seekPrev int
+		found, foundPrev    bool
+	}{
+		{0, 1, 0, true, false},
+		{1, 1, 1, true, true},
+		{4, 5, 3, true, true},
+		{9, 9, 9, true, true},
+		{10, 0, 9, false, true},
+	}
+	for _, test := range tests {
+		if actualValue := it.Seek(test.key); actualValue != test.found {
+			t.Errorf("Got %v expected %v", actualValue, test.found)
+		}
+		if test.found && it.Key() != test.seek {
+			t.Errorf("Got %v expected %v", it.Key(), test.seek)
+		}
+		if actualValue := it.SeekPrev(test.key); actualValue != test.foundPrev {
+			t.Errorf("Got %v expected %v", actualValue, test.foundPrev)
+		}
+		if test.foundPrev && it.Key() != test.seekPrev {
+			t.Errorf("Got %v expected %v", it.Key(), test.seekPrev)
+		}
+	}
+	it.Seek(4)
+	var keys []int
+	for ok := true; ok; ok = it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if actualValue, expectedValue := fmt.Sprint(keys), "[5 7 9]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	it.Seek(10)
+	if actualValue := it.Prev(); actualValue != true || it.Key() != 9 {
+		t.Errorf("Got %v expected %v", it.Key(), 9)
+	}
+	it.SeekPrev(0)
+	if actualValue := it.Next(); actualValue != true || it.Key() != 1 {
+		t.Errorf("Got %v expected %v", it.Key(), 1)
+	}
+	it = tree.Iterator()
+	it.SeekPrev(6)
+	if actualValue := it.Prev(); actualValue != true || it.Key() != 3 {
+		t.Errorf("Got %v expected %v", it.Key(), 3)
+	}
+}
+
+func TestRedBlackTreeRemoveRange(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[int]()
+	for i := 0; i < 100; i++ {
+		tree.Put(i, i)
+	}
+	tree.RemoveRange(10, 95)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[0 1 2 3 4 5 6 7 8 9 95 96 97 98 99]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := tree.Size(), 15; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	tree.RemoveRange(50, 20)
+	tree.RemoveRange(-5, 3)
+	tree.RemoveRange(97, 1000)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[3 4 5 6 7 8 9 95 96]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	tree.Put(50, 50)
+	tree.Remove(4)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[3 5 6 7 8 9 50 95 96]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestRedBlackTreeSplitJoin(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[int]()
+	for i := 0; i < 1000; i++ {
+		tree.Put(i, i)
+	}
+	for _, test := range [][2]int{{-1, 0}, {0, 0}, {1, 1}, {333, 333}, {999, 999}, {1000, 1000}} {
+		left, right := tree.Split(test[0])
+		if actualValue := tree.Size(); actualValue != 0 {
+			t.Errorf("Got %v expected %v", actualValue, 0)
+		}
+		if actualValue, expectedValue := left.Size(), test[1]; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue, expectedValue := right.Size(), 1000-left.Size(); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if !left.Empty() && left.Right().Key != left.Size()-1 {
+			t.Errorf("Got %v expected %v", left.Right().Key, left.Size()-1)
+		}
+		if !right.Empty() && right.Left().Key != left.Size() {
+			t.Errorf("Got %v expected %v", right.Left().Key, left.Size())
+		}
+		tree = redblacktree.Join(left, right)
+		if actualValue, expectedValue := tree.Size(), 1000; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+		if actualValue := left.Size() + right.Size(); actualValue != 0 {
+			t.Errorf("Got %v expected %v", actualValue, 0)
+		}
+		for i, key := range tree.Keys() {
+			if key != i {
+				t.Errorf("Got %v expected %v", key, i)
+			}
+		}
+	}
+}
+
+func TestRedBlackTreeJoinOverlapping(t *testing.T) {
+	left, right := redblacktree.NewWithIntComparator[int](), redblacktree.NewWithIntComparator[int]()
+	left.Put(2, 2)
+	right.Put(1, 1)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected panic")
+		}
+	}()
+	redblacktree.Join(left, right)
+}
+
+func TestRedBlackTreeFromSorted(t *testing.T) {
+	keys := make([]int, 100)
+	values := make([]string, 100)
+	for i := range keys {
+		keys[i], values[i] = i*2, fmt.Sprint(i*2)
+	}
+	tree, err := redblacktree.FromSorted(utils.NumberComparator[int], keys, values)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := tree.Size(), 100; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), fmt.Sprint(keys); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, found := tree.Get(42); actualValue != "42" || !found {
+		t.Errorf("Got %v expected %v", actualValue, "42")
+	}
+	tree.Put(1, "1")
+	tree.Remove(42)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()[:4]), "[0 1 2 4]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if _, err := redblacktree.FromSorted(utils.NumberComparator[int], []int{1, 3, 2}, []string{"a", "b", "c"}); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+	if _, err := redblacktree.FromSorted(utils.NumberComparator[int], []int{1, 2, 2}, []string{"a", "b", "c"}); !errors.Is(err, containers.ErrNotSorted) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNotSorted)
+	}
+	if _, err := redblacktree.FromSorted(utils.NumberComparator[int], []int{1, 2}, []string{"a"}); err == nil {
+		t.Errorf("Got %v expected error", err)
+	}
+}
+
+func TestRedBlackTreeBulkLoad(t *testing.T) {
+	source := redblacktree.NewWithIntComparator[string]()
+	for i := 0; i < 10; i++ {
+		source.Put(i, fmt.Sprint(i))
+	}
+	it := source.Iterator()
+	tree, err := redblacktree.BulkLoad[int, string](utils.NumberComparator[int], &it)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys(), tree.Values()), fmt.Sprint(source.Keys(), source.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestRedBlackTreeSerialization(t *testing.T) {
 	tree := redblacktree.NewWithStringComparator[string]()
 	tree.Put("c", "3")
@@ -590,6 +862,370 @@ func TestRedBlackTreeSerialization(t *testing.T) {
 	assert()
 }
 
+func TestRedBlackTreeBinarySerialization(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	tree.Put(3, "c")
+	tree.Put(1, "a")
+	tree.Put(2, "b")
+
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored := redblacktree.NewWithIntComparator[string]()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(tree); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	restored = redblacktree.NewWithIntComparator[string]()
+	if err := gob.NewDecoder(&buffer).Decode(restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	zero := &redblacktree.Tree[int, string]{}
+	if err := zero.UnmarshalBinary(data); err != nil || zero.Size() != restored.Size() {
+		t.Errorf("Got %v, %v expected %v", zero.Size(), err, restored.Size())
+	}
+	if err := (&redblacktree.Tree[[2]int, string]{}).UnmarshalBinary(data); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestRedBlackTreeJSONMarshaler(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	tree.Put(2, "b")
+	tree.Put(1, "a")
+	tree.Put(10, "c")
+
+	data, err := json.Marshal(struct {
+		Tree *redblacktree.Tree[int, string]
+	}{tree})
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := string(data), `{"Tree":{"1":"a","2":"b","10":"c"}}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored struct {
+		Tree redblacktree.Tree[int, string]
+	}
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Tree.Keys(), restored.Tree.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := json.Unmarshal([]byte(`{"Tree":"x"}`), &restored); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := (&redblacktree.Tree[[2]int, string]{}).UnmarshalJSON([]byte("{}")); !errors.Is(err, containers.ErrNoComparator) {
+		t.Errorf("Got %v expected %v", err, containers.ErrNoComparator)
+	}
+}
+
+func TestRedBlackTreeJSONStream(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	tree.Put(2, "b")
+	tree.Put(1, "a")
+	tree.Put(10, "c")
+
+	var buffer bytes.Buffer
+	if err := tree.WriteJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := buffer.String(), `{"1":"a","2":"b","10":"c"}`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	var restored redblacktree.Tree[int, string]
+	if err := restored.ReadJSON(&buffer); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := restored.ReadJSON(strings.NewReader(`["a"]`)); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := restored.ReadJSON(strings.NewReader("null")); err != nil || !restored.Empty() {
+		t.Errorf("Got %v, %v expected an empty container", restored.Size(), err)
+	}
+}
+
+func TestRedBlackTreeStructure(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	for i := 0; i < 1000; i++ {
+		tree.Put((i*7919)%1000, fmt.Sprint(i))
+	}
+	for i := 0; i < 1000; i += 3 {
+		tree.Remove((i * 7) % 1000)
+	}
+
+	data, err := tree.MarshalStructure()
+	if err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	var restored redblacktree.Tree[int, string]
+	if err := restored.UnmarshalStructure(data); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	if actualValue, expectedValue := restored.String(), tree.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, expectedValue := fmt.Sprint(restored.Keys(), restored.Values()), fmt.Sprint(tree.Keys(), tree.Values()); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	restoredData, _ := restored.MarshalStructure()
+	if !bytes.Equal(restoredData, data) {
+		t.Errorf("Got %v expected %v", restoredData, data)
+	}
+	restored.Put(-1, "x")
+	if actualValue, expectedValue := restored.Size(), tree.Size()+1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	reversed := redblacktree.NewWith[int, string](func(a, b int) int { return b - a })
+	if err := reversed.UnmarshalStructure(data); !errors.Is(err, containers.ErrInvalidStructure) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidStructure)
+	}
+	if actualValue := reversed.Size(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+	if err := restored.UnmarshalStructure(data[:len(data)-1]); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	if err := restored.UnmarshalStructure(append(data[:len(data):len(data)], 0)); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+	binary, _ := tree.MarshalBinary()
+	if err := restored.UnmarshalStructure(binary); !errors.Is(err, containers.ErrInvalidBinary) {
+		t.Errorf("Got %v expected %v", err, containers.ErrInvalidBinary)
+	}
+
+	// 2 is black with the red children 1 and 3
+	small := redblacktree.NewWithIntComparator[string]()
+	small.Put(1, "a")
+	small.Put(2, "b")
+	small.Put(3, "c")
+	data, _ = small.MarshalStructure()
+	// version, count, header of 2, entry of 2, header of 1 ...
+	redRoot := append([]byte(nil), data...)
+	redRoot[2] |= 4
+	blackChild := append([]byte(nil), data...)
+	blackChild[7] = 0
+	for _, test := range [][]byte{redRoot, blackChild} {
+		if err := restored.UnmarshalStructure(test); !errors.Is(err, containers.ErrInvalidStructure) {
+			t.Errorf("Got %v expected %v", err, containers.ErrInvalidStructure)
+		}
+	}
+	if actualValue, expectedValue := restored.Size(), tree.Size()+1; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestRedBlackTreeValidate(t *testing.T) {
+	var zero redblacktree.Tree[int, string]
+	if err := zero.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	tree := redblacktree.NewWithIntComparator[string]()
+	for i := 0; i < 1000; i++ {
+		tree.Put((i*7919)%1000, fmt.Sprint(i))
+	}
+	for i := 0; i < 1000; i += 3 {
+		tree.Remove((i * 7) % 1000)
+		if err := tree.Validate(); err != nil {
+			t.Errorf("Got error %v", err)
+		}
+	}
+
+	tests := []struct {
+		corrupt func(tree *redblacktree.Tree[int, string])
+		message string
+	}{
+		{func(tree *redblacktree.Tree[int, string]) { tree.Root.Left.Key = 5 }, "key 2 follows key 5"},
+		{func(tree *redblacktree.Tree[int, string]) { tree.Root.Right.Parent = nil }, "node 3 does not point to its parent"},
+		{func(tree *redblacktree.Tree[int, string]) { tree.Root = tree.Root.Left }, "root 1 is red"},
+		{func(tree *redblacktree.Tree[int, string]) { tree.Root.Left = nil }, "2 nodes but a size of 3"},
+	}
+	for _, test := range tests {
+		// 2 is black with the red children 1 and 3
+		small := redblacktree.NewWithIntComparator[string]()
+		small.Put(1, "a")
+		small.Put(2, "b")
+		small.Put(3, "c")
+		test.corrupt(small)
+		err := small.Validate()
+		if !errors.Is(err, containers.ErrInvalidStructure) {
+			t.Errorf("Got %v expected %v", err, containers.ErrInvalidStructure)
+		}
+		if err != nil && !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Got %v expected %v", err, test.message)
+		}
+	}
+
+	// 2 is black with the black child 1 and the red child 4
+	tree.Clear()
+	for i := 1; i <= 7; i++ {
+		tree.Put(i, "")
+	}
+	tree.Root.Left = nil
+	if err := tree.Validate(); err == nil || !strings.Contains(err.Error(), "node 2 has black heights 0 and 1") {
+		t.Errorf("Got %v expected %v", err, "node 2 has black heights 0 and 1")
+	}
+}
+
+func TestRedBlackTreeGraph(t *testing.T) {
+	tree := redblacktree.NewWithIntComparator[string]()
+	for i := 1; i <= 4; i++ {
+		tree.Put(i, fmt.Sprint("v", i))
+	}
+
+	var builder strings.Builder
+	if err := tree.ToDOT(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue := `digraph redblacktree {
+	ordering=out;
+	n0 [label="2", style=filled, fillcolor="black", fontcolor="white"];
+	n1 [label="1", style=filled, fillcolor="black", fontcolor="white"];
+	n2 [label="3", style=filled, fillcolor="black", fontcolor="white"];
+	n3 [label="", style=invis];
+	n4 [label="4", style=filled, fillcolor="red", fontcolor="white"];
+	n0 -> n1;
+	n2 -> n3 [style=invis];
+	n2 -> n4;
+	n0 -> n2;
+}
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	builder.Reset()
+	if err := tree.ToMermaid(&builder); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	expectedValue = `flowchart TD
+	%% redblacktree
+	n0["2"]
+	n1["1"]
+	n2["3"]
+	n4["4"]
+	n0 --> n1
+	n2 --> n4
+	n0 --> n2
+	style n0 fill:black,color:white
+	style n1 fill:black,color:white
+	style n2 fill:black,color:white
+	style n4 fill:red,color:white
+`
+	if actualValue := builder.String(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	graph := tree.ToGraph(func(key int, value string) string { return fmt.Sprintf("%d: %s", key, value) })
+	if actualValue, expectedValue := graph.Nodes[4].Label, "4: v4"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	tree.Clear()
+	if actualValue := len(tree.ToGraph(nil).Nodes); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+}
+
+func TestRedBlackTreeNew(t *testing.T) {
+	tree := redblacktree.New[time.Duration, string]()
+	tree.Put(time.Minute, "minute")
+	tree.Put(time.Second, "second")
+	tree.Put(time.Hour, "hour")
+	tree.Put(-time.Second, "minus second")
+	tree.Put(time.Second, "one second")
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[-1s 1s 1m0s 1h0m0s]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if actualValue, found := tree.Get(time.Second); actualValue != "one second" || !found {
+		t.Errorf("Got %v expected %v", actualValue, "one second")
+	}
+	if _, found := tree.Get(time.Millisecond); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if actualValue := tree.Comparator(time.Second, time.Minute); actualValue != -1 {
+		t.Errorf("Got %v expected %v", actualValue, -1)
+	}
+	tree.Remove(time.Minute)
+	tree.Remove(time.Millisecond)
+	left, right := tree.Split(time.Second)
+	left.Put(-time.Hour, "minus hour")
+	right.Put(time.Minute, "minute")
+	if _, found := right.Get(time.Minute); !found {
+		t.Errorf("Got %v expected %v", found, true)
+	}
+	tree = redblacktree.Join(left, right)
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[-1h0m0s -1s 1s 1m0s 1h0m0s]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return redblacktree.New[int, string]() })
+}
+
+func TestRedBlackTreeSetComparator(t *testing.T) {
+	tree := redblacktree.New[int, string]()
+	tree.SetComparator(utils.Reverse(tree.Comparator))
+	for _, key := range []int{3, 1, 5, 2, 4} {
+		tree.Put(key, fmt.Sprint(key))
+	}
+	if actualValue, expectedValue := fmt.Sprint(tree.Keys()), "[5 4 3 2 1]"; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+	for _, key := range []int{1, 2, 3, 4, 5} {
+		if actualValue, found := tree.Get(key); actualValue != fmt.Sprint(key) || !found {
+			t.Errorf("Got %v expected %v", actualValue, key)
+		}
+	}
+	tree.Remove(3)
+	if _, found := tree.Get(3); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Errorf("Got error %v", err)
+	}
+}
+
+func TestRedBlackTreeConformance(t *testing.T) {
+	containertest.TestSortedMap(t, func() maps.Map[int, string] { return redblacktree.NewWithIntComparator[string]() })
+	containertest.TestReverseIteratorWithKey(t, func(keys []int) containers.ReverseIteratorWithKey[int, string] {
+		tree := redblacktree.NewWithIntComparator[string]()
+		for _, key := range keys {
+			tree.Put(key, strconv.Itoa(key))
+		}
+		it := tree.Iterator()
+		return &it
+	})
+}
+
+func FuzzRedBlackTree(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return redblacktree.NewWithIntComparator[string]() }, containertest.SortedOrder)
+}
+
+func FuzzRedBlackTreeNew(f *testing.F) {
+	containertest.FuzzMap(f, func() maps.Map[int, string] { return redblacktree.New[int, string]() }, containertest.SortedOrder)
+}
+
 func toInterfaces[T any](ts []T) []interface{} {
 	is := make([]interface{}, 0)
 	for _, t := range ts {
@@ -750,3 +1386,51 @@ func BenchmarkRedBlackTreeRemove100000(b *testing.B) {
 	b.StartTimer()
 	benchmarkRemove(b, tree, size)
 }
+
+func BenchmarkRedBlackTreeNewGet10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	tree := redblacktree.New[int, struct{}]()
+	for n := 0; n < size; n++ {
+		tree.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkGet(b, tree, size)
+}
+
+func BenchmarkRedBlackTreeNewPut10000(b *testing.B) {
+	b.StopTimer()
+	size := 10000
+	tree := redblacktree.New[int, struct{}]()
+	for n := 0; n < size; n++ {
+		tree.Put(n, struct{}{})
+	}
+	b.StartTimer()
+	benchmarkPut(b, tree, size)
+}
+
+func benchmarkStructureTree(size int) *redblacktree.Tree[int, string] {
+	tree := redblacktree.NewWithIntComparator[string]()
+	for n := 0; n < size; n++ {
+		tree.Put((n*7919)%size, "value")
+	}
+	return tree
+}
+
+func BenchmarkRedBlackTreeUnmarshalBinary10000(b *testing.B) {
+	data, _ := benchmarkStructureTree(10000).MarshalBinary()
+	tree := redblacktree.NewWithIntComparator[string]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.UnmarshalBinary(data)
+	}
+}
+
+func BenchmarkRedBlackTreeUnmarshalStructure10000(b *testing.B) {
+	data, _ := benchmarkStructureTree(10000).MarshalStructure()
+	tree := redblacktree.NewWithIntComparator[string]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.UnmarshalStructure(data)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package redblacktree
+
+import (
+	"fmt"
+
+	"github.com/monitor1379/yagods/containers"
+)
+
+// Validate checks the invariants of the tree: keys in strictly ascending order for the comparator,
+// a black root, no red node with a red child, the same number of black nodes on every path,
+// parent pointers matching the children and a size matching the number of nodes.
+// Returns nil for a valid tree, otherwise an error wrapping containers.ErrInvalidStructure naming the offending node.
+func (tree *Tree[K, V]) Validate() error {
+	v := &validator[K, V]{tree: tree}
+	if tree.Root != nil {
+		if tree.Root.color == red {
+			return fmt.Errorf("redblacktree: root %v is red: %w", tree.Root.Key, containers.ErrInvalidStructure)
+		}
+		if _, err := v.validate(tree.Root, nil); err != nil {
+			return err
+		}
+	}
+	if v.count != tree.size {
+		return fmt.Errorf("redblacktree: %d nodes but a size of %d: %w", v.count, tree.size, containers.ErrInvalidStructure)
+	}
+	return nil
+}
+
+type validator[K comparable, V any] struct {
+	tree  *Tree[K, V]
+	count int         // number of nodes visited so far
+	last  *Node[K, V] // last node in order so far
+}
+
+// validate checks the subtree of the node below the parent and returns its black height.
+func (v *validator[K, V]) validate(node *Node[K, V], parent *Node[K, V]) (int, error) {
+	if node == nil {
+		return 0, nil
+	}
+	v.count++
+	if node.Parent != parent {
+		return 0, fmt.Errorf("redblacktree: node %v does not point to its parent: %w", node.Key, containers.ErrInvalidStructure)
+	}
+	if node.color == red && (nodeColor(node.Left) == red || nodeColor(node.Right) == red) {
+		return 0, fmt.Errorf("redblacktree: red node %v has a red child: %w", node.Key, containers.ErrInvalidStructure)
+	}
+	leftHeight, err := v.validate(node.Left, node)
+	if err != nil {
+		return 0, err
+	}
+	if v.last != nil && v.tree.Comparator(v.last.Key, node.Key) >= 0 {
+		return 0, fmt.Errorf("redblacktree: key %v follows key %v: %w", node.Key, v.last.Key, containers.ErrInvalidStructure)
+	}
+	v.last = node
+	rightHeight, err := v.validate(node.Right, node)
+	if err != nil {
+		return 0, err
+	}
+	if leftHeight != rightHeight {
+		return 0, fmt.Errorf("redblacktree: node %v has black heights %d and %d: %w", node.Key, leftHeight, rightHeight, containers.ErrInvalidStructure)
+	}
+	if node.color == black {
+		leftHeight++
+	}
+	return leftHeight, nil
+}
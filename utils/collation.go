@@ -0,0 +1,83 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// CollationStringComparator orders strings as a dictionary does rather than by their bytes, in three levels:
+// first by their letters ignoring case and accents, then by their accents and last by their case,
+// so that "apple" < "Apple" < "äpple" < "apples" < "Banana". Strings equal at all three levels are ordered by their bytes.
+//
+// Accents are known for the letters of Latin-1 and Latin Extended-A, which cover most European languages.
+// All other runes, including ligatures such as "æ" and "ß", are compared by their lowercase code points.
+// For full Unicode collation see the Unicode Collation Algorithm, e.g. golang.org/x/text/collate.
+func CollationStringComparator(a, b string) int {
+	for level := primaryLevel; level <= tertiaryLevel; level++ {
+		if c := collate(a, b, level); c != 0 {
+			return c
+		}
+	}
+	return StringComparator(a, b)
+}
+
+type collationLevel int
+
+const (
+	primaryLevel   collationLevel = iota // letters ignoring case and accents
+	secondaryLevel                       // accents
+	tertiaryLevel                        // case
+)
+
+// collate compares the strings rune by rune by the weights of the level.
+func collate(a, b string, level collationLevel) int {
+	for a != "" && b != "" {
+		r1, n1 := utf8.DecodeRuneInString(a)
+		r2, n2 := utf8.DecodeRuneInString(b)
+		if w1, w2 := collationWeight(r1, level), collationWeight(r2, level); w1 != w2 {
+			return NumberComparator(w1, w2)
+		}
+		a, b = a[n1:], b[n2:]
+	}
+	return NumberComparator(len(a), len(b))
+}
+
+// collationWeight returns the weight of the rune at the level: its lowercase base letter at the primary level,
+// its lowercase accented letter or zero if it has no accent at the secondary level,
+// and zero for lowercase or one for uppercase at the tertiary level.
+func collationWeight(r rune, level collationLevel) rune {
+	lower := unicode.ToLower(r)
+	switch level {
+	case primaryLevel:
+		if base, ok := latinBases[lower]; ok {
+			return base
+		}
+		return lower
+	case secondaryLevel:
+		if _, ok := latinBases[lower]; ok {
+			return lower
+		}
+		return 0
+	}
+	if lower != r {
+		return 1
+	}
+	return 0
+}
+
+// latinBases maps the lowercase accented letters of Latin-1 and Latin Extended-A to their base letters.
+var latinBases = func() map[rune]rune {
+	const pairs = "àaáaâaãaäaåaçcèeéeêeëeìiíiîiïiñnòoóoôoõoöoøoùuúuûuüuýyÿy" +
+		"āaăaąaćcĉcċcčcďdđdēeĕeėeęeěeĝgğgġgģgĥhħhĩiīiĭiįiıiĵjķkĺlļlľlłlńnņnňnōoŏoőoŕrŗrřr" +
+		"śsŝsşsšsţtťtŧtũuūuŭuůuűuųuŵwŷyźzżzžz"
+	bases := make(map[rune]rune)
+	runes := []rune(pairs)
+	for i := 0; i+1 < len(runes); i += 2 {
+		bases[runes[i]] = runes[i+1]
+	}
+	return bases
+}()
@@ -4,7 +4,12 @@
 
 package utils
 
-import "time"
+import (
+	"reflect"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
 
 // Comparator will make type assertion (see NumberComparator for example),
 // which will panic if a or b are not of the asserted type.
@@ -18,11 +23,18 @@ type Comparator[T any] func(a, b T) int
 var _ Comparator[int32] = NumberComparator[int32]
 var _ Comparator[string] = StringComparator
 var _ Comparator[time.Time] = TimeComparator
+var _ Comparator[string] = CaseInsensitiveStringComparator
+var _ Comparator[string] = CollationStringComparator
 
 type Number interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
 }
 
+// Ordered is a constraint that permits any type supporting the operators < <= >= >, like cmp.Ordered.
+type Ordered interface {
+	Number | ~string
+}
+
 func NumberComparator[T Number](a, b T) int {
 	switch {
 	case a > b:
@@ -71,3 +83,147 @@ func TimeComparator(a, b time.Time) int {
 		return 0
 	}
 }
+
+// OrderedComparator compares values of any ordered type with the < and > operators.
+func OrderedComparator[T Ordered](a, b T) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	}
+	return 0
+}
+
+// Reverse returns a comparator ordering values in the reverse order of the comparator.
+func Reverse[T any](comparator Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		return comparator(b, a)
+	}
+}
+
+// ThenComparing returns a comparator ordering values by the first comparator,
+// then values the first one finds equal by the next comparators in turn.
+func ThenComparing[T any](first Comparator[T], next ...Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if c := first(a, b); c != 0 {
+			return c
+		}
+		for _, comparator := range next {
+			if c := comparator(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// ComparingBy returns a comparator ordering values by the keys the function extracts from them,
+// such as a field of a struct, the keys being compared by the comparator.
+func ComparingBy[T any, K any](key func(T) K, comparator Comparator[K]) Comparator[T] {
+	return func(a, b T) int {
+		return comparator(key(a), key(b))
+	}
+}
+
+// NilsFirst returns a comparator of pointers ordering nil before all other pointers,
+// which are ordered by the values they point to using the comparator.
+func NilsFirst[T any](comparator Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		case b == nil:
+			return 1
+		}
+		return comparator(*a, *b)
+	}
+}
+
+// NilsLast returns a comparator of pointers ordering nil after all other pointers,
+// which are ordered by the values they point to using the comparator.
+func NilsLast[T any](comparator Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		}
+		return comparator(*a, *b)
+	}
+}
+
+// Lexicographic returns a comparator of slices comparing their elements pairwise by the comparator
+// until the first difference. A slice that is a prefix of another is ordered first.
+func Lexicographic[T any](comparator Comparator[T]) Comparator[[]T] {
+	return func(a, b []T) int {
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if c := comparator(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+		return NumberComparator(len(a), len(b))
+	}
+}
+
+// CaseInsensitiveStringComparator compares strings rune by rune ignoring case, so that "go" and "Go" are equal.
+// Runes are compared by their lowercase form after conversion to uppercase, which maps all case variants
+// of a letter to the same rune.
+func CaseInsensitiveStringComparator(a, b string) int {
+	for a != "" && b != "" {
+		r1, n1 := utf8.DecodeRuneInString(a)
+		r2, n2 := utf8.DecodeRuneInString(b)
+		if r1, r2 = foldCase(r1), foldCase(r2); r1 != r2 {
+			return NumberComparator(r1, r2)
+		}
+		a, b = a[n1:], b[n2:]
+	}
+	return NumberComparator(len(a), len(b))
+}
+
+func foldCase(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// DefaultComparator returns a comparator for time.Time and for all types whose underlying type is ordered
+// (see Ordered), or nil for all other types.
+// Containers use it to order keys when they were not instantiated by a constructor, e.g. when unmarshaled
+// into the zero value.
+func DefaultComparator[T any]() Comparator[T] {
+	var comparator interface{}
+	switch any(*new(T)).(type) {
+	case int:
+		comparator = Comparator[int](NumberComparator[int])
+	case string:
+		comparator = Comparator[string](StringComparator)
+	case time.Time:
+		comparator = Comparator[time.Time](TimeComparator)
+	}
+	if comparator != nil {
+		return comparator.(Comparator[T])
+	}
+	switch reflect.TypeOf((*T)(nil)).Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) int {
+			return NumberComparator(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) int {
+			return NumberComparator(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) int {
+			return NumberComparator(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}
+	case reflect.String:
+		return func(a, b T) int {
+			return StringComparator(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@
 package utils_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -112,3 +113,192 @@ func TestCustomComparator(t *testing.T) {
 		}
 	}
 }
+
+func TestOrderedComparator(t *testing.T) {
+
+	// i1,i2,expected
+	tests := [][]interface{}{
+		{"a", "a", 0},
+		{"a", "b", -1},
+		{"b", "a", 1},
+		{"", "a", -1},
+	}
+
+	for _, test := range tests {
+		actual := utils.OrderedComparator(test[0].(string), test[1].(string))
+		expected := test[2]
+		if actual != expected {
+			t.Errorf("Got %v expected %v", actual, expected)
+		}
+	}
+
+	if actual, expected := utils.OrderedComparator(1.5, 2.5), -1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	comparator := utils.Reverse(utils.NumberComparator[int])
+	if actual, expected := comparator(1, 2), 1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := comparator(2, 1), -1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := comparator(1, 1), 0; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+}
+
+func TestThenComparingAndComparingBy(t *testing.T) {
+	type user struct {
+		name string
+		age  int
+		id   int
+	}
+	users := []user{{"b", 30, 1}, {"a", 40, 2}, {"b", 20, 3}, {"a", 40, 0}, {"c", 10, 4}}
+	comparator := utils.ThenComparing(
+		utils.ComparingBy(func(u user) string { return u.name }, utils.StringComparator),
+		utils.Reverse(utils.ComparingBy(func(u user) int { return u.age }, utils.NumberComparator[int])),
+		utils.ComparingBy(func(u user) int { return u.id }, utils.NumberComparator[int]),
+	)
+	utils.Sort(users, comparator)
+	if actual, expected := fmt.Sprint(users), "[{a 40 0} {a 40 2} {b 30 1} {b 20 3} {c 10 4}]"; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := comparator(user{"a", 1, 1}, user{"a", 1, 1}), 0; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := utils.ThenComparing(utils.NumberComparator[int])(1, 2), -1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+}
+
+func TestNilsFirstNilsLast(t *testing.T) {
+	one, two := 1, 2
+	tests := []struct {
+		a, b      *int
+		nilsFirst int
+		nilsLast  int
+	}{
+		{nil, nil, 0, 0},
+		{nil, &one, -1, 1},
+		{&one, nil, 1, -1},
+		{&one, &two, -1, -1},
+		{&two, &one, 1, 1},
+		{&one, &one, 0, 0},
+	}
+	nilsFirst, nilsLast := utils.NilsFirst(utils.NumberComparator[int]), utils.NilsLast(utils.NumberComparator[int])
+	for _, test := range tests {
+		if actual := nilsFirst(test.a, test.b); actual != test.nilsFirst {
+			t.Errorf("Got %v expected %v", actual, test.nilsFirst)
+		}
+		if actual := nilsLast(test.a, test.b); actual != test.nilsLast {
+			t.Errorf("Got %v expected %v", actual, test.nilsLast)
+		}
+	}
+}
+
+func TestLexicographic(t *testing.T) {
+	comparator := utils.Lexicographic(utils.NumberComparator[int])
+	tests := []struct {
+		a, b     []int
+		expected int
+	}{
+		{nil, nil, 0},
+		{nil, []int{}, 0},
+		{[]int{}, []int{1}, -1},
+		{[]int{1, 2}, []int{1, 2}, 0},
+		{[]int{1, 2}, []int{1, 3}, -1},
+		{[]int{1, 3}, []int{1, 2, 4}, 1},
+		{[]int{1, 2}, []int{1, 2, 0}, -1},
+		{[]int{2}, []int{1, 9, 9}, 1},
+	}
+	for _, test := range tests {
+		if actual := comparator(test.a, test.b); actual != test.expected {
+			t.Errorf("Got %v expected %v for %v and %v", actual, test.expected, test.a, test.b)
+		}
+	}
+}
+
+func TestCaseInsensitiveStringComparator(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"go", "Go", 0},
+		{"GO", "go", 0},
+		{"Straße", "STRASSE", 1}, // ß has no single-rune uppercase
+		{"ÄRGER", "ärger", 0},
+		{"a", "B", -1},
+		{"B", "a", 1},
+		{"_", "a", -1},
+		{"ab", "AbC", -1},
+		{"ſ", "S", 0}, // long s
+	}
+	for _, test := range tests {
+		if actual := utils.CaseInsensitiveStringComparator(test.a, test.b); actual != test.expected {
+			t.Errorf("Got %v expected %v for %q and %q", actual, test.expected, test.a, test.b)
+		}
+	}
+}
+
+func TestCollationStringComparator(t *testing.T) {
+	values := []string{"Banana", "apples", "äpple", "Apple", "apple", "Äpple", "cote", "côte", "Côte", "coté", "b", "Zoë", "zoe", "Łódź", "lodz", "1", ""}
+	utils.Sort(values, utils.CollationStringComparator)
+	expected := "[ 1 apple Apple äpple Äpple apples b Banana cote coté côte Côte lodz Łódź zoe Zoë]"
+	if actual := fmt.Sprint(values); actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"apple", "apple", 0},
+		{"apple", "Apple", -1},
+		{"Apple", "äpple", -1},
+		{"äpple", "apples", -1},
+		{"İ", "I", 1}, // equal at all levels, ordered by bytes
+	}
+	for _, test := range tests {
+		if actual := utils.CollationStringComparator(test.a, test.b); actual != test.expected {
+			t.Errorf("Got %v expected %v for %q and %q", actual, test.expected, test.a, test.b)
+		}
+	}
+}
+
+func TestDefaultComparator(t *testing.T) {
+	type celsius float64
+	type name string
+
+	now := time.Now()
+	if actual, expected := utils.DefaultComparator[int]()(1, 2), -1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := utils.DefaultComparator[string]()("b", "a"), 1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := utils.DefaultComparator[time.Time]()(now, now.Add(time.Hour)), -1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := utils.DefaultComparator[int8]()(-1, -1), 0; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := utils.DefaultComparator[uint64]()(1<<63, 1), 1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := utils.DefaultComparator[celsius]()(-40, 36.6), -1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if actual, expected := utils.DefaultComparator[name]()("x", "y"), -1; actual != expected {
+		t.Errorf("Got %v expected %v", actual, expected)
+	}
+	if comparator := utils.DefaultComparator[[2]int](); comparator != nil {
+		t.Errorf("Got a comparator expected %v", nil)
+	}
+	if comparator := utils.DefaultComparator[struct{ id int }](); comparator != nil {
+		t.Errorf("Got a comparator expected %v", nil)
+	}
+}
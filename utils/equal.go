@@ -0,0 +1,25 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+// Equal reports whether a and b are equal.
+//
+// Containers that search their values, such as lists, take one in their NewWithEqual constructors,
+// so that they can hold values which are not comparable with ==, like slices, maps and functions.
+type Equal[T any] func(a, b T) bool
+
+var _ Equal[int] = ComparableEqual[int]
+
+// ComparableEqual reports whether a and b are equal with the == operator.
+func ComparableEqual[T comparable](a, b T) bool {
+	return a == b
+}
+
+// InterfaceEqual reports whether a and b are equal with the == operator on their interface values,
+// which panics if their dynamic type is not comparable.
+// Containers search their values with it when they were not instantiated by a constructor.
+func InterfaceEqual[T any](a, b T) bool {
+	return any(a) == any(b)
+}
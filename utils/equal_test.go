@@ -0,0 +1,43 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/monitor1379/yagods/utils"
+)
+
+func TestComparableEqual(t *testing.T) {
+	type point struct{ x, y int }
+	tests := []struct {
+		a, b     point
+		expected bool
+	}{
+		{point{1, 2}, point{1, 2}, true},
+		{point{1, 2}, point{2, 1}, false},
+		{point{}, point{}, true},
+	}
+	for _, test := range tests {
+		if actualValue := utils.ComparableEqual(test.a, test.b); actualValue != test.expected {
+			t.Errorf("Got %v expected %v", actualValue, test.expected)
+		}
+	}
+}
+
+func TestInterfaceEqual(t *testing.T) {
+	if actualValue := utils.InterfaceEqual("a", "a"); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := utils.InterfaceEqual[interface{}](1, "1"); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Got no panic comparing slices")
+		}
+	}()
+	utils.InterfaceEqual([]int{1}, []int{1})
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"unicode/utf8"
+)
+
+// Hasher hashes keys and tells whether two keys are equal.
+//
+// Containers that hash their keys by a hasher given to their constructor, such as customhashmap,
+// can hold keys which are not comparable with ==, like byte slices, or compare keys other than by ==,
+// like strings regardless of their case.
+//
+// Keys that are equal for Equal must have the same hash.
+type Hasher[K any] interface {
+	Hash(key K) uint64
+	Equal(a, b K) bool
+}
+
+type funcHasher[K any] struct {
+	hash  func(key K) uint64
+	equal Equal[K]
+}
+
+func (h funcHasher[K]) Hash(key K) uint64 {
+	return h.hash(key)
+}
+
+func (h funcHasher[K]) Equal(a, b K) bool {
+	return h.equal(a, b)
+}
+
+// NewHasher returns a hasher made of the hash and equality functions.
+func NewHasher[K any](hash func(key K) uint64, equal Equal[K]) Hasher[K] {
+	return funcHasher[K]{hash: hash, equal: equal}
+}
+
+// NewSeededHasher returns a hasher that hashes a key by writing it to a maphash.Hash with a new random seed,
+// so that hashes differ between hashers and between runs of the program.
+// The write function must write the same bytes for keys that are equal for the equality function.
+func NewSeededHasher[K any](write func(hash *maphash.Hash, key K), equal Equal[K]) Hasher[K] {
+	seed := maphash.MakeSeed()
+	return NewHasher(func(key K) uint64 {
+		var hash maphash.Hash
+		hash.SetSeed(seed)
+		write(&hash, key)
+		return hash.Sum64()
+	}, equal)
+}
+
+// NewStringHasher returns a seeded hasher of strings.
+func NewStringHasher() Hasher[string] {
+	return NewSeededHasher(func(hash *maphash.Hash, key string) {
+		hash.WriteString(key)
+	}, ComparableEqual[string])
+}
+
+// NewBytesHasher returns a seeded hasher of byte slices, which are equal if they hold the same bytes
+// as by bytes.Equal, so that a nil slice and an empty slice are the same key.
+func NewBytesHasher() Hasher[[]byte] {
+	return NewSeededHasher(func(hash *maphash.Hash, key []byte) {
+		hash.Write(key)
+	}, bytes.Equal)
+}
+
+// NewCaseInsensitiveStringHasher returns a seeded hasher of strings that are equal regardless of their case,
+// consistently with CaseInsensitiveStringComparator.
+func NewCaseInsensitiveStringHasher() Hasher[string] {
+	return NewSeededHasher(func(hash *maphash.Hash, key string) {
+		var buf [utf8.UTFMax]byte
+		for _, r := range key {
+			hash.Write(buf[:utf8.EncodeRune(buf[:], foldCase(r))])
+		}
+	}, func(a, b string) bool {
+		return CaseInsensitiveStringComparator(a, b) == 0
+	})
+}
+
+// NewComparableHasher returns a seeded hasher of any comparable type, whose keys are equal as by ==.
+// Keys are hashed by their contents as the == operator compares them: pointers and channels by their address,
+// interfaces by their dynamic value, arrays and structs field by field.
+func NewComparableHasher[K comparable]() Hasher[K] {
+	return NewSeededHasher(func(hash *maphash.Hash, key K) {
+		switch k := any(key).(type) {
+		case string:
+			hash.WriteString(k)
+		case int:
+			writeUint64(hash, uint64(k))
+		case int64:
+			writeUint64(hash, uint64(k))
+		case uint64:
+			writeUint64(hash, k)
+		default:
+			writeValue(hash, reflect.ValueOf(&key).Elem())
+		}
+	}, ComparableEqual[K])
+}
+
+func writeUint64(hash *maphash.Hash, n uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], n)
+	hash.Write(buf[:])
+}
+
+func writeFloat(hash *maphash.Hash, f float64) {
+	if f == 0 {
+		f = 0 // -0 == +0
+	}
+	writeUint64(hash, math.Float64bits(f))
+}
+
+// writeValue writes the contents of a comparable value to the hash.
+func writeValue(hash *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			hash.WriteByte(1)
+		} else {
+			hash.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(hash, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(hash, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(hash, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(hash, real(v.Complex()))
+		writeFloat(hash, imag(v.Complex()))
+	case reflect.String:
+		writeUint64(hash, uint64(v.Len()))
+		hash.WriteString(v.String())
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(hash, uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeValue(hash, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Name != "_" { // == ignores blank fields
+				writeValue(hash, v.Field(i))
+			}
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			hash.WriteByte(0)
+		} else {
+			writeValue(hash, v.Elem())
+		}
+	default:
+		panic("utils: hash of incomparable type " + v.Type().String())
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2022, Zhenpeng Deng & Emir Pasic. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/monitor1379/yagods/utils"
+)
+
+// checkHasher checks that the hasher finds the pairs of keys equal or not and that equal keys hash equally.
+func checkHasher[K any](t *testing.T, hasher utils.Hasher[K], tests []struct {
+	a, b     K
+	expected bool
+}) {
+	t.Helper()
+	for _, test := range tests {
+		if actualValue := hasher.Equal(test.a, test.b); actualValue != test.expected {
+			t.Errorf("Got %v expected %v for %v and %v", actualValue, test.expected, test.a, test.b)
+		}
+		if test.expected && hasher.Hash(test.a) != hasher.Hash(test.b) {
+			t.Errorf("Got different hashes for equal keys %v and %v", test.a, test.b)
+		}
+		if !test.expected && hasher.Hash(test.a) == hasher.Hash(test.b) {
+			t.Errorf("Got the same hash for keys %v and %v", test.a, test.b)
+		}
+		if hasher.Hash(test.a) != hasher.Hash(test.a) {
+			t.Errorf("Got different hashes for key %v", test.a)
+		}
+	}
+}
+
+func TestNewHasher(t *testing.T) {
+	hasher := utils.NewHasher(func(key int) uint64 { return uint64(key % 10) }, func(a, b int) bool { return a%10 == b%10 })
+	checkHasher(t, hasher, []struct {
+		a, b     int
+		expected bool
+	}{
+		{1, 11, true},
+		{1, 2, false},
+	})
+}
+
+func TestNewStringHasher(t *testing.T) {
+	checkHasher(t, utils.NewStringHasher(), []struct {
+		a, b     string
+		expected bool
+	}{
+		{"", "", true},
+		{"abc", "abc", true},
+		{"abc", "ABC", false},
+		{"abc", "abd", false},
+	})
+	// seeds differ between hashers
+	if utils.NewStringHasher().Hash("abc") == utils.NewStringHasher().Hash("abc") {
+		t.Errorf("Got the same hash from two hashers")
+	}
+}
+
+func TestNewBytesHasher(t *testing.T) {
+	checkHasher(t, utils.NewBytesHasher(), []struct {
+		a, b     []byte
+		expected bool
+	}{
+		{nil, []byte{}, true},
+		{[]byte("abc"), []byte("abc"), true},
+		{[]byte("abc"), []byte("ab"), false},
+	})
+}
+
+func TestNewCaseInsensitiveStringHasher(t *testing.T) {
+	checkHasher(t, utils.NewCaseInsensitiveStringHasher(), []struct {
+		a, b     string
+		expected bool
+	}{
+		{"abc", "ABC", true},
+		{"Straße", "STRASSE", false},
+		{"ǅ", "ǆ", true},
+		{"abc", "abcd", false},
+	})
+}
+
+func TestNewComparableHasher(t *testing.T) {
+	type point struct {
+		x, y float64
+		_    int
+		name string
+	}
+	checkHasher(t, utils.NewComparableHasher[point](), []struct {
+		a, b     point
+		expected bool
+	}{
+		{point{x: 1, y: 2, name: "a"}, point{x: 1, y: 2, name: "a"}, true},
+		{point{x: math.Copysign(0, -1)}, point{}, true},
+		{point{x: 1, y: 2}, point{x: 2, y: 1}, false},
+		{point{name: "a"}, point{name: "b"}, false},
+	})
+
+	type ref struct {
+		pointer *int
+		pair    [2]string
+		flag    bool
+	}
+	a, b := new(int), new(int)
+	checkHasher(t, utils.NewComparableHasher[ref](), []struct {
+		a, b     ref
+		expected bool
+	}{
+		{ref{}, ref{}, true},
+		{ref{pointer: a}, ref{pointer: a}, true},
+		{ref{pointer: a}, ref{pointer: b}, false},
+		{ref{pair: [2]string{"a", "b"}}, ref{pair: [2]string{"a", "b"}}, true},
+		{ref{pair: [2]string{"a", "b"}}, ref{pair: [2]string{"ab", ""}}, false},
+		{ref{flag: true}, ref{flag: true}, true},
+		{ref{flag: true}, ref{}, false},
+	})
+
+	checkHasher(t, utils.NewComparableHasher[int](), []struct {
+		a, b     int
+		expected bool
+	}{
+		{1, 1, true},
+		{1, -1, false},
+	})
+}
@@ -7,6 +7,9 @@
 // Provided functionalities:
 // - sorting
 // - comparators
+// - equality functions
+// - hashers
+// - codecs
 package utils
 
 import (